@@ -0,0 +1,514 @@
+package ddcloud
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Computed attributes describing an X.509 certificate.
+type certificateAttributes struct {
+	NotBefore         string
+	NotAfter          string
+	Subject           string
+	SANs              []string
+	FingerprintSHA256 string
+}
+
+// Schema keys for the computed attributes describing an X.509 certificate (shared by SSL resources).
+const (
+	resourceKeyCertificateNotBefore         = "not_before"
+	resourceKeyCertificateNotAfter          = "not_after"
+	resourceKeyCertificateSubject           = "subject"
+	resourceKeyCertificateSANs              = "sans"
+	resourceKeyCertificateFingerprintSHA256 = "fingerprint_sha256"
+)
+
+// Parse one or more PEM-encoded X.509 certificates (in the order they appear).
+func parsePEMCertificates(certificatesPEM string) (certificates []*x509.Certificate, err error) {
+	remaining := []byte(certificatesPEM)
+	for {
+		var block *pem.Block
+		block, remaining = pem.Decode(remaining)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block of type '%s' (expected 'CERTIFICATE')", block.Type)
+		}
+
+		var certificate *x509.Certificate
+		certificate, err = x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate #%d: %s", len(certificates)+1, err)
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no PEM-encoded certificates found")
+	}
+	if len(bytes.TrimSpace(remaining)) != 0 {
+		return nil, fmt.Errorf("unexpected data after certificate #%d", len(certificates))
+	}
+
+	return
+}
+
+// Parse a PEM-encoded RSA private key (PKCS #1 or PKCS #8).
+//
+// CloudControl only supports RSA keys, so other key types (e.g. EC) are rejected.
+// A "PRIVATE KEY" block containing a PKCS #1 key (as produced by ddcloud_pfx) is also accepted.
+func parsePEMPrivateKey(privateKeyPEM string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM-encoded private key found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			rsaPrivateKey, pkcs1Err := x509.ParsePKCS1PrivateKey(block.Bytes)
+			if pkcs1Err != nil {
+				return nil, err
+			}
+
+			return rsaPrivateKey, nil
+		}
+
+		rsaPrivateKey, ok := privateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T (only RSA keys are supported by CloudControl)", privateKey)
+		}
+
+		return rsaPrivateKey, nil
+	case "EC PRIVATE KEY":
+		return nil, fmt.Errorf("unsupported EC private key (only RSA keys are supported by CloudControl)")
+	default:
+		return nil, fmt.Errorf("unexpected PEM block of type '%s' (expected an RSA private key)", block.Type)
+	}
+}
+
+// Verify that a private key matches a certificate's public key.
+func validateCertificatePrivateKey(certificate *x509.Certificate, privateKey crypto.Signer) error {
+	certificatePublicKey, err := x509.MarshalPKIXPublicKey(certificate.PublicKey)
+	if err != nil {
+		return err
+	}
+	privateKeyPublicKey, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(certificatePublicKey, privateKeyPublicKey) {
+		return fmt.Errorf("private key does not match certificate '%s'", certificate.Subject)
+	}
+
+	return nil
+}
+
+// Verify that a certificate is currently valid.
+func validateCertificateValidity(certificate *x509.Certificate, now time.Time) error {
+	if now.Before(certificate.NotBefore) {
+		return fmt.Errorf("certificate '%s' is not valid until %s", certificate.Subject, certificate.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if now.After(certificate.NotAfter) {
+		return fmt.Errorf("certificate '%s' expired at %s", certificate.Subject, certificate.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Verify that each certificate in a chain was issued by the next certificate in the chain.
+//
+// If leaf is not nil, also verify that it was issued by the first certificate in the chain.
+func validateCertificateChain(leaf *x509.Certificate, chain []*x509.Certificate) error {
+	if leaf != nil && len(chain) > 0 {
+		err := validateCertificateIssuer(leaf, chain[0])
+		if err != nil {
+			return fmt.Errorf("chain does not link to certificate: %s", err)
+		}
+	}
+
+	for index := 0; index < len(chain)-1; index++ {
+		err := validateCertificateIssuer(chain[index], chain[index+1])
+		if err != nil {
+			return fmt.Errorf("chain is not in order (certificate #%d is not issued by certificate #%d): %s", index+1, index+2, err)
+		}
+	}
+
+	return nil
+}
+
+// Verify that a certificate was issued (and signed) by the specified issuer.
+func validateCertificateIssuer(certificate *x509.Certificate, issuer *x509.Certificate) error {
+	if !bytes.Equal(certificate.RawIssuer, issuer.RawSubject) {
+		return fmt.Errorf("certificate '%s' was issued by '%s', not '%s'", certificate.Subject, certificate.Issuer, issuer.Subject)
+	}
+
+	return certificate.CheckSignatureFrom(issuer)
+}
+
+// Get the computed attributes for a certificate.
+func getCertificateAttributes(certificate *x509.Certificate) certificateAttributes {
+	fingerprint := sha256.Sum256(certificate.Raw)
+
+	attributes := certificateAttributes{
+		NotBefore:         certificate.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:          certificate.NotAfter.UTC().Format(time.RFC3339),
+		Subject:           certificate.Subject.String(),
+		SANs:              make([]string, 0),
+		FingerprintSHA256: hex.EncodeToString(fingerprint[:]),
+	}
+	attributes.SANs = append(attributes.SANs, certificate.DNSNames...)
+	for _, ipAddress := range certificate.IPAddresses {
+		attributes.SANs = append(attributes.SANs, ipAddress.String())
+	}
+	attributes.SANs = append(attributes.SANs, certificate.EmailAddresses...)
+	for _, uri := range certificate.URIs {
+		attributes.SANs = append(attributes.SANs, uri.String())
+	}
+
+	return attributes
+}
+
+// ToMap converts the certificate attributes to a map of schema keys to values.
+func (attributes certificateAttributes) ToMap() map[string]interface{} {
+	sans := make([]interface{}, len(attributes.SANs))
+	for index, san := range attributes.SANs {
+		sans[index] = san
+	}
+
+	return map[string]interface{}{
+		resourceKeyCertificateNotBefore:         attributes.NotBefore,
+		resourceKeyCertificateNotAfter:          attributes.NotAfter,
+		resourceKeyCertificateSubject:           attributes.Subject,
+		resourceKeyCertificateSANs:              sans,
+		resourceKeyCertificateFingerprintSHA256: attributes.FingerprintSHA256,
+	}
+}
+
+// Get the computed attributes for a certificate chain.
+//
+// NotBefore / NotAfter represent the period during which all certificates in the chain are valid; Subject and FingerprintSHA256 are those of the first certificate in the chain.
+func getCertificateChainAttributes(chain []*x509.Certificate) certificateAttributes {
+	attributes := getCertificateAttributes(chain[0])
+
+	notBefore := chain[0].NotBefore
+	notAfter := chain[0].NotAfter
+	for _, certificate := range chain[1:] {
+		if certificate.NotBefore.After(notBefore) {
+			notBefore = certificate.NotBefore
+		}
+		if certificate.NotAfter.Before(notAfter) {
+			notAfter = certificate.NotAfter
+		}
+	}
+	attributes.NotBefore = notBefore.UTC().Format(time.RFC3339)
+	attributes.NotAfter = notAfter.UTC().Format(time.RFC3339)
+
+	return attributes
+}
+
+// Add the computed attributes describing an X.509 certificate to a resource schema.
+func withCertificateAttributes(certificateDescription string, resourceSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	attributesSchema := map[string]*schema.Schema{
+		resourceKeyCertificateNotBefore: &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: fmt.Sprintf("The date / time (RFC3339) from which %s is valid.", certificateDescription),
+		},
+		resourceKeyCertificateNotAfter: &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: fmt.Sprintf("The date / time (RFC3339) at which %s expires.", certificateDescription),
+		},
+		resourceKeyCertificateSubject: &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: fmt.Sprintf("The subject of %s.", certificateDescription),
+		},
+		resourceKeyCertificateSANs: &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: fmt.Sprintf("The subject alternative names (if any) of %s.", certificateDescription),
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		resourceKeyCertificateFingerprintSHA256: &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: fmt.Sprintf("The SHA-256 fingerprint (hex-encoded) of %s.", certificateDescription),
+		},
+	}
+	for key, attributeSchema := range attributesSchema {
+		resourceSchema[key] = attributeSchema
+	}
+
+	return resourceSchema
+}
+
+// Validate the certificate and private key for an SSL domain certificate, returning the certificate's computed attributes.
+func validateSSLDomainCertificate(certificatePEM string, privateKeyPEM string, now time.Time) (attributes certificateAttributes, err error) {
+	certificates, err := parsePEMCertificates(certificatePEM)
+	if err != nil {
+		return
+	}
+	if len(certificates) != 1 {
+		err = fmt.Errorf("found %d certificates (expected exactly 1; intermediate certificates should be supplied via ddcloud_ssl_certificate_chain)", len(certificates))
+
+		return
+	}
+	certificate := certificates[0]
+
+	privateKey, err := parsePEMPrivateKey(privateKeyPEM)
+	if err != nil {
+		err = fmt.Errorf("invalid private key: %s", err)
+
+		return
+	}
+	err = validateCertificatePrivateKey(certificate, privateKey)
+	if err != nil {
+		return
+	}
+
+	err = validateCertificateValidity(certificate, now)
+	if err != nil {
+		return
+	}
+
+	attributes = getCertificateAttributes(certificate)
+
+	return
+}
+
+// Validate the certificates for an SSL certificate chain, returning the chain's computed attributes.
+func validateSSLCertificateChain(chainPEM string, now time.Time) (attributes certificateAttributes, err error) {
+	chain, err := parsePEMCertificates(chainPEM)
+	if err != nil {
+		return
+	}
+
+	err = validateCertificateChain(nil, chain)
+	if err != nil {
+		return
+	}
+
+	for _, certificate := range chain {
+		err = validateCertificateValidity(certificate, now)
+		if err != nil {
+			return
+		}
+	}
+
+	attributes = getCertificateChainAttributes(chain)
+
+	return
+}
+
+// Validate an SSL domain certificate, its private key, and the certificate chain (if any) that links it to its root.
+func validateSSLCertificateAndChain(certificatePEM string, privateKeyPEM string, chainPEM string, now time.Time) error {
+	_, err := validateSSLDomainCertificate(certificatePEM, privateKeyPEM, now)
+	if err != nil {
+		return fmt.Errorf("invalid certificate: %s", err)
+	}
+	if chainPEM == "" {
+		return nil
+	}
+
+	_, err = validateSSLCertificateChain(chainPEM, now)
+	if err != nil {
+		return fmt.Errorf("invalid certificate chain: %s", err)
+	}
+
+	// Both have already been parsed successfully.
+	certificates, _ := parsePEMCertificates(certificatePEM)
+	chain, _ := parsePEMCertificates(chainPEM)
+
+	return validateCertificateChain(certificates[0], chain)
+}
+
+// Order CA certificates so that they form a chain from the specified certificate's issuer towards the root.
+//
+// Certificates that are not part of the chain are appended (in their original order).
+func orderCertificateChain(certificate *x509.Certificate, caCertificates []*x509.Certificate) (chain []*x509.Certificate) {
+	remaining := append([]*x509.Certificate{}, caCertificates...)
+
+	current := certificate
+	for len(remaining) > 0 {
+		issuerIndex := -1
+		for index, caCertificate := range remaining {
+			if bytes.Equal(current.RawIssuer, caCertificate.RawSubject) && !bytes.Equal(current.Raw, caCertificate.Raw) {
+				issuerIndex = index
+
+				break
+			}
+		}
+		if issuerIndex == -1 {
+			break
+		}
+
+		current = remaining[issuerIndex]
+		chain = append(chain, current)
+		remaining = append(remaining[:issuerIndex], remaining[issuerIndex+1:]...)
+	}
+
+	return append(chain, remaining...)
+}
+
+// Options for generating a certificate.
+type certificateGenerationOptions struct {
+	CommonName    string
+	Organization  string
+	DNSNames      []string
+	IPAddresses   []string
+	Validity      time.Duration
+	RSAKeyBits    int
+	CACertificate string // If not specified, the certificate is self-signed.
+	CAPrivateKey  string
+}
+
+// Generate an RSA private key and a certificate for it (signed by the specified CA, or self-signed).
+func generateCertificate(options certificateGenerationOptions, now time.Time) (certificatePEM string, privateKeyPEM string, err error) {
+	template, err := newCertificateTemplate(options, now)
+	if err != nil {
+		return
+	}
+
+	var (
+		issuer    = template
+		issuerKey crypto.Signer
+	)
+	if options.CACertificate != "" {
+		issuer, issuerKey, err = parseCertificateAuthority(options.CACertificate, options.CAPrivateKey)
+		if err != nil {
+			return
+		}
+
+		err = validateCertificateValidity(issuer, now)
+		if err != nil {
+			err = fmt.Errorf("invalid CA certificate: %s", err)
+
+			return
+		}
+		err = validateCertificateAuthorityExpiry(issuer, template.NotAfter)
+		if err != nil {
+			return
+		}
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, options.RSAKeyBits)
+	if err != nil {
+		return
+	}
+	if issuerKey == nil {
+		issuerKey = privateKey // Self-signed
+	}
+
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, issuer, privateKey.Public(), issuerKey)
+	if err != nil {
+		return
+	}
+
+	certificatePEM, err = pemToString(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: certificateBytes,
+	})
+	if err != nil {
+		return
+	}
+	privateKeyPEM, err = pemToString(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+
+	return
+}
+
+// Create the template for a generated certificate.
+func newCertificateTemplate(options certificateGenerationOptions, now time.Time) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName: options.CommonName,
+		},
+		DNSNames:              options.DNSNames,
+		NotBefore:             now.Add(-5 * time.Minute).UTC(), // Allow for clock skew.
+		NotAfter:              now.Add(options.Validity).UTC(),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	if options.Organization != "" {
+		template.Subject.Organization = []string{options.Organization}
+	}
+	for _, ipAddress := range options.IPAddresses {
+		parsedIPAddress := net.ParseIP(ipAddress)
+		if parsedIPAddress == nil {
+			return nil, fmt.Errorf("invalid IP address '%s'", ipAddress)
+		}
+
+		template.IPAddresses = append(template.IPAddresses, parsedIPAddress)
+	}
+
+	return template, nil
+}
+
+// Parse the certificate and private key for a certificate authority.
+func parseCertificateAuthority(caCertificatePEM string, caPrivateKeyPEM string) (*x509.Certificate, crypto.Signer, error) {
+	caCertificates, err := parsePEMCertificates(caCertificatePEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CA certificate: %s", err)
+	}
+	caCertificate := caCertificates[0]
+	if !caCertificate.IsCA {
+		return nil, nil, fmt.Errorf("certificate '%s' is not a CA certificate", caCertificate.Subject)
+	}
+
+	caPrivateKey, err := parsePEMPrivateKey(caPrivateKeyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CA private key: %s", err)
+	}
+	err = validateCertificatePrivateKey(caCertificate, caPrivateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CA private key: %s", err)
+	}
+
+	return caCertificate, caPrivateKey, nil
+}
+
+// Verify that a CA certificate does not expire before a certificate (valid until the specified time) that it signs.
+func validateCertificateAuthorityExpiry(caCertificate *x509.Certificate, notAfter time.Time) error {
+	if notAfter.After(caCertificate.NotAfter) {
+		return fmt.Errorf("certificate would be valid until %s, but CA certificate '%s' expires at %s", notAfter.UTC().Format(time.RFC3339), caCertificate.Subject, caCertificate.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Determine whether a certificate that expires at the specified time is due for renewal.
+func isCertificateRenewalDue(notAfter time.Time, renewalWindow time.Duration, now time.Time) bool {
+	return !now.Add(renewalWindow).Before(notAfter)
+}
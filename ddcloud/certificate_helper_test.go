@@ -0,0 +1,224 @@
+package ddcloud
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+)
+
+/*
+ * Unit tests.
+ */
+
+// Unit test - a valid certificate, private key, and chain.
+func TestValidateSSLCertificateAndChainValid(test *testing.T) {
+	certificates := testGenerateCertificates(test, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	assert := assert.ForTest(test)
+
+	err := validateSSLCertificateAndChain(certificates.LeafPEM, certificates.LeafKeyPEM, certificates.ChainPEM(), time.Now())
+	assert.IsTrue("err == nil", err == nil)
+
+	attributes, err := validateSSLDomainCertificate(certificates.LeafPEM, certificates.LeafKeyPEM, time.Now())
+	assert.IsTrue("err == nil", err == nil)
+	assert.EqualsString("Subject", "CN=www.example.com", attributes.Subject)
+	assert.EqualsInt("len(SANs)", 2, len(attributes.SANs))
+	assert.EqualsString("SANs[0]", "www.example.com", attributes.SANs[0])
+	assert.EqualsString("SANs[1]", "10.0.0.1", attributes.SANs[1])
+	assert.EqualsInt("len(FingerprintSHA256)", 64, len(attributes.FingerprintSHA256))
+
+	chainAttributes, err := validateSSLCertificateChain(certificates.ChainPEM(), time.Now())
+	assert.IsTrue("err == nil", err == nil)
+	assert.EqualsString("Chain subject", "CN=Test Intermediate CA", chainAttributes.Subject)
+}
+
+// Unit test - a private key that does not match the certificate.
+func TestValidateSSLDomainCertificateMismatchedKey(test *testing.T) {
+	certificates := testGenerateCertificates(test, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	_, err := validateSSLDomainCertificate(certificates.LeafPEM, certificates.IntermediateKeyPEM, time.Now())
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("err != nil", err != nil)
+	assert.IsTrue("error mentions key", strings.Contains(err.Error(), "private key does not match"))
+}
+
+// Unit test - an expired certificate.
+func TestValidateSSLDomainCertificateExpired(test *testing.T) {
+	certificates := testGenerateCertificates(test, time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour))
+
+	_, err := validateSSLDomainCertificate(certificates.LeafPEM, certificates.LeafKeyPEM, time.Now())
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("err != nil", err != nil)
+	assert.IsTrue("error mentions expiry", strings.Contains(err.Error(), "expired"))
+}
+
+// Unit test - a chain whose certificates are in the wrong order.
+func TestValidateSSLCertificateChainWrongOrder(test *testing.T) {
+	certificates := testGenerateCertificates(test, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	_, err := validateSSLCertificateChain(certificates.RootPEM+certificates.IntermediatePEM, time.Now())
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("err != nil", err != nil)
+	assert.IsTrue("error mentions order", strings.Contains(err.Error(), "not in order"))
+}
+
+// Unit test - a chain that does not link to the certificate.
+func TestValidateSSLCertificateAndChainUnrelated(test *testing.T) {
+	certificates := testGenerateCertificates(test, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	otherCertificates := testGenerateCertificates(test, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	err := validateSSLCertificateAndChain(certificates.LeafPEM, certificates.LeafKeyPEM, otherCertificates.ChainPEM(), time.Now())
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("err != nil", err != nil)
+	assert.IsTrue("error mentions link", strings.Contains(err.Error(), "does not link"))
+}
+
+// Unit test - an EC private key is rejected (CloudControl only supports RSA keys).
+func TestParsePEMPrivateKeyEC(test *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	assert := assert.ForTest(test)
+
+	ecKeyBytes, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		test.Fatal(err)
+	}
+	_, err = parsePEMPrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecKeyBytes})))
+	assert.IsTrue("err != nil (EC)", err != nil)
+	assert.IsTrue("error mentions RSA (EC)", strings.Contains(err.Error(), "only RSA keys"))
+
+	pkcs8KeyBytes, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		test.Fatal(err)
+	}
+	_, err = parsePEMPrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8KeyBytes})))
+	assert.IsTrue("err != nil (PKCS #8)", err != nil)
+	assert.IsTrue("error mentions RSA (PKCS #8)", strings.Contains(err.Error(), "only RSA keys"))
+}
+
+// Unit test - an RSA private key in PKCS #8 format is accepted.
+func TestParsePEMPrivateKeyPKCS8RSA(test *testing.T) {
+	key := testGenerateKey(test)
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	parsedKey, err := parsePEMPrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})))
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("err == nil", err == nil)
+	assert.IsTrue("parsedKey.Equal(key)", parsedKey != nil && parsedKey.Equal(key))
+}
+
+// Unit test - data that is not PEM-encoded.
+func TestParsePEMCertificatesInvalid(test *testing.T) {
+	_, err := parsePEMCertificates("not a certificate")
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("err != nil", err != nil)
+}
+
+// PEM-encoded test certificates (root CA -> intermediate CA -> leaf).
+type testCertificates struct {
+	RootPEM            string
+	IntermediatePEM    string
+	IntermediateKeyPEM string
+	LeafPEM            string
+	LeafKeyPEM         string
+}
+
+// ChainPEM returns the certificate chain for the leaf certificate (intermediate, then root).
+func (certificates testCertificates) ChainPEM() string {
+	return certificates.IntermediatePEM + certificates.RootPEM
+}
+
+// Generate test certificates (the leaf certificate is valid for the specified period).
+func testGenerateCertificates(test *testing.T, leafNotBefore time.Time, leafNotAfter time.Time) (certificates testCertificates) {
+	rootKey := testGenerateKey(test)
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	root, rootPEM := testCreateCertificate(test, rootTemplate, rootTemplate, rootKey, rootKey)
+	certificates.RootPEM = rootPEM
+
+	intermediateKey := testGenerateKey(test)
+	intermediateTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "Test Intermediate CA"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	intermediate, intermediatePEM := testCreateCertificate(test, intermediateTemplate, root, intermediateKey, rootKey)
+	certificates.IntermediatePEM = intermediatePEM
+	certificates.IntermediateKeyPEM = testEncodeKey(test, intermediateKey)
+
+	leafKey := testGenerateKey(test)
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		DNSNames:     []string{"www.example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("10.0.0.1")},
+		NotBefore:    leafNotBefore,
+		NotAfter:     leafNotAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	_, certificates.LeafPEM = testCreateCertificate(test, leafTemplate, intermediate, leafKey, intermediateKey)
+	certificates.LeafKeyPEM = testEncodeKey(test, leafKey)
+
+	return
+}
+
+func testGenerateKey(test *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	return key
+}
+
+func testEncodeKey(test *testing.T, key *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func testCreateCertificate(test *testing.T, template *x509.Certificate, issuer *x509.Certificate, key *rsa.PrivateKey, issuerKey *rsa.PrivateKey) (*x509.Certificate, string) {
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), issuerKey)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	certificate, err := x509.ParseCertificate(certificateBytes)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	return certificate, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateBytes}))
+}
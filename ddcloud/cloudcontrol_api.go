@@ -0,0 +1,239 @@
+package ddcloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+)
+
+// The version of the CloudControl API used for operations that are not (yet) supported by the client library.
+const cloudControlAPIVersion = "2.10"
+
+// The base path for version 1 of the CloudControl API (still used by Cloud Backup).
+const cloudControlAPIV1BasePath = "oec/0.9"
+
+// The HTTP client used for operations that are not (yet) supported by the client library.
+//
+// Each attempt is limited by the client's timeout; retries are limited by the provider's retry timeout.
+var cloudControlAPIClient = &http.Client{
+	Timeout: 2 * time.Minute,
+}
+
+// Invoke a CloudControl API operation that is not (yet) supported by the CloudControl client library.
+//
+// relativeURI is relative to the organisation (e.g. "server/addDisk").
+// If the API responds with anything other than a CloudControl API response, an error is returned; otherwise, the caller is responsible for checking the response code.
+func invokeCloudControlAPI(ctx context.Context, providerState *providerState, method string, relativeURI string, requestBody interface{}) (*compute.APIResponseV2, error) {
+	statusCode, responseBody, err := executeCloudControlAPIRequest(ctx, providerState, method, relativeURI, requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return readCloudControlAPIResponse(method, relativeURI, statusCode, responseBody)
+}
+
+// Retrieve a CloudControl resource (as JSON) that is not (yet) fully supported by the CloudControl client library.
+//
+// relativeURI is relative to the organisation (e.g. "server/server/{id}").
+// Returns false if the resource was not found.
+func readCloudControlAPI(ctx context.Context, providerState *providerState, relativeURI string, resource interface{}) (found bool, err error) {
+	statusCode, responseBody, err := executeCloudControlAPIRequest(ctx, providerState, http.MethodGet, relativeURI, nil)
+	if err != nil {
+		return false, err
+	}
+
+	if statusCode == http.StatusOK {
+		err = json.Unmarshal(responseBody, resource)
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	apiResponse, err := readCloudControlAPIResponse(http.MethodGet, relativeURI, statusCode, responseBody)
+	if err != nil {
+		return false, err
+	}
+	if apiResponse.ResponseCode == compute.ResponseCodeResourceNotFound {
+		return false, nil
+	}
+
+	return false, apiResponse.ToError("request to '%s' failed with status code %d (%s): %s", relativeURI, statusCode, apiResponse.ResponseCode, apiResponse.Message)
+}
+
+// Retrieve a version 1 CloudControl resource (as XML) that is not (yet) fully supported by the CloudControl client library.
+//
+// relativeURI is relative to the organisation (e.g. "server/{id}/backup").
+// If CloudControl responds with a status (rather than the resource), that status is returned and the caller is responsible for checking its result code.
+func readCloudControlAPIV1(ctx context.Context, providerState *providerState, relativeURI string, resource interface{}) (*compute.APIResponseV1, error) {
+	statusCode, responseBody, err := executeCloudControlAPIV1Request(ctx, providerState, http.MethodGet, relativeURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode == http.StatusOK {
+		return nil, xml.Unmarshal(responseBody, resource)
+	}
+
+	return readCloudControlAPIV1Response(http.MethodGet, relativeURI, statusCode, responseBody)
+}
+
+// Invoke a version 1 CloudControl API operation that is not (yet) supported by the CloudControl client library.
+//
+// relativeURI is relative to the organisation (e.g. "server/{id}/backup/client/{clientId}?backupNow").
+// The caller is responsible for checking the result of the returned status.
+func invokeCloudControlAPIV1(ctx context.Context, providerState *providerState, method string, relativeURI string) (*compute.APIResponseV1, error) {
+	statusCode, responseBody, err := executeCloudControlAPIV1Request(ctx, providerState, method, relativeURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return readCloudControlAPIV1Response(method, relativeURI, statusCode, responseBody)
+}
+
+// Execute a CloudControl API request (with a JSON body, if requestBody is not nil).
+//
+// Returns the response status code and body.
+func executeCloudControlAPIRequest(ctx context.Context, providerState *providerState, method string, relativeURI string, requestBody interface{}) (statusCode int, responseBody []byte, err error) {
+	account, err := providerState.Client().GetAccount()
+	if err != nil {
+		return
+	}
+
+	requestURI := fmt.Sprintf("%s/caas/%s/%s/%s",
+		providerState.Settings().EndPoint,
+		cloudControlAPIVersion,
+		url.PathEscape(account.OrganizationID),
+		relativeURI,
+	)
+
+	var requestJSON []byte
+	if requestBody != nil {
+		requestJSON, err = json.Marshal(requestBody)
+		if err != nil {
+			return
+		}
+	}
+
+	return sendCloudControlAPIRequest(ctx, providerState, method, requestURI, "application/json", requestJSON)
+}
+
+// Execute a version 1 CloudControl API request (version 1 operations used by the provider do not have a request body).
+//
+// Returns the response status code and body.
+func executeCloudControlAPIV1Request(ctx context.Context, providerState *providerState, method string, relativeURI string) (statusCode int, responseBody []byte, err error) {
+	account, err := providerState.Client().GetAccount()
+	if err != nil {
+		return
+	}
+
+	requestURI := fmt.Sprintf("%s/%s/%s/%s",
+		providerState.Settings().EndPoint,
+		cloudControlAPIV1BasePath,
+		url.PathEscape(account.OrganizationID),
+		relativeURI,
+	)
+
+	return sendCloudControlAPIRequest(ctx, providerState, method, requestURI, "text/xml", nil)
+}
+
+// Send a CloudControl API request, retrying if it fails due to a network error, or because CloudControl is temporarily unavailable or is throttling requests.
+//
+// Returns the response status code and body.
+func sendCloudControlAPIRequest(ctx context.Context, providerState *providerState, method string, requestURI string, mediaType string, requestBody []byte) (statusCode int, responseBody []byte, err error) {
+	settings := providerState.Settings()
+
+	operationDescription := fmt.Sprintf("'%s' request to '%s'", method, requestURI)
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		request, requestError := http.NewRequestWithContext(ctx, method, requestURI, bytes.NewReader(requestBody))
+		if requestError != nil {
+			context.Fail(requestError)
+
+			return
+		}
+		request.SetBasicAuth(settings.Username, settings.Password)
+		request.Header.Set("Accept", mediaType)
+		if requestBody != nil {
+			request.Header.Set("Content-Type", mediaType)
+		}
+
+		log.Printf("Invoking %s...", operationDescription)
+
+		response, requestError := cloudControlAPIClient.Do(request)
+		if requestError != nil {
+			if ctx.Err() != nil {
+				context.Fail(requestError)
+			} else {
+				log.Printf("%s failed (will retry): %s", operationDescription, requestError)
+
+				context.Retry()
+			}
+
+			return
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusServiceUnavailable {
+			log.Printf("%s failed with status code %d (will retry).", operationDescription, response.StatusCode)
+
+			context.Retry()
+
+			return
+		}
+
+		responseBody, requestError = ioutil.ReadAll(response.Body)
+		if requestError != nil {
+			log.Printf("Failed to read response for %s (will retry): %s", operationDescription, requestError)
+
+			context.Retry()
+
+			return
+		}
+		statusCode = response.StatusCode
+	})
+
+	return
+}
+
+// Read a CloudControl API response.
+func readCloudControlAPIResponse(method string, relativeURI string, statusCode int, responseBody []byte) (*compute.APIResponseV2, error) {
+	apiResponse := &compute.APIResponseV2{}
+	err := json.Unmarshal(responseBody, apiResponse)
+	if err != nil || apiResponse.ResponseCode == "" {
+		return nil, fmt.Errorf("'%s' request to '%s' failed with status code %d (unexpected response: '%s')",
+			method,
+			relativeURI,
+			statusCode,
+			string(responseBody),
+		)
+	}
+
+	return apiResponse, nil
+}
+
+// Read a version 1 CloudControl API response.
+func readCloudControlAPIV1Response(method string, relativeURI string, statusCode int, responseBody []byte) (*compute.APIResponseV1, error) {
+	apiResponse := &compute.APIResponseV1{}
+	err := xml.Unmarshal(responseBody, apiResponse)
+	if err != nil || apiResponse.Result == "" {
+		return nil, fmt.Errorf("'%s' request to '%s' failed with status code %d (unexpected response: '%s')",
+			method,
+			relativeURI,
+			statusCode,
+			string(responseBody),
+		)
+	}
+
+	return apiResponse, nil
+}
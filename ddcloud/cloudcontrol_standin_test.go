@@ -0,0 +1,607 @@
+package ddcloud
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+)
+
+/*
+ * A local stand-in for the CloudControl API.
+ */
+
+const testCloudControlOrganizationID = "test-org"
+
+// testCloudControlStandIn is a minimal stand-in for the parts of the CloudControl API used by unit tests.
+type testCloudControlStandIn struct {
+	Server *httptest.Server
+
+	// The server returned by the API (if any).
+	ComputeServer *compute.Server
+
+	// The server's SATA and IDE controllers (the client library's compute.Server only represents SCSI controllers).
+	SATAControllers []serverStorageController
+	IDEControllers  []serverStorageController
+
+	// The bodies of the add-disk requests received by the stand-in.
+	AddDiskRequests []map[string]interface{}
+
+	// The customer image returned by the API (if any).
+	Image *compute.CustomerImage
+
+	// The state that the customer image will have once it has been requested (or an import / export has been requested).
+	ImageStateAfterRequest string
+
+	// The bodies of the import / export requests received by the stand-in.
+	ImportRequests []map[string]interface{}
+	ExportRequests []map[string]interface{}
+
+	// The bodies of the clone-server / delete-customer-image requests received by the stand-in.
+	CloneRequests       []map[string]interface{}
+	DeleteImageRequests []map[string]interface{}
+
+	// The server's snapshot service (if enabled), and the server's snapshots.
+	SnapshotService *serverSnapshotService
+	Snapshots       []serverSnapshot
+
+	// The bodies of the snapshot-service requests received by the stand-in (keyed by operation name, e.g. "enableSnapshotService").
+	SnapshotRequests map[string][]map[string]interface{}
+
+	// Backup job information for the server's backup clients (reported by the version 1 API).
+	BackupJobClients []backupClientJobDetails
+
+	// The status that backup jobs will have once they have finished.
+	BackupJobStatusAfterRequest string
+
+	// The Ids of the backup clients for which backup jobs have been requested.
+	BackupNowRequests []string
+
+	// The tags applied to the server, and the tag keys defined in the organisation.
+	ServerTags []compute.Tag
+	TagKeys    []string
+
+	// The bodies of the edit-virtual-listener requests received by the stand-in.
+	EditVirtualListenerRequests []map[string]interface{}
+
+	// The bodies of the create / delete VIP node requests received by the stand-in (requests to add pool members always fail).
+	CreateVIPNodeRequests []map[string]interface{}
+	DeleteVIPNodeRequests []map[string]interface{}
+
+	// The virtual listener, VIP pool, and VIP pool members returned by the API (if any).
+	VirtualListener *compute.VirtualListener
+	VIPPool         *compute.VIPPool
+	VIPPoolMembers  []compute.VIPPoolMember
+
+	// The bodies of the remove-pool-member requests received by the stand-in (these requests always fail).
+	RemoveVIPPoolMemberRequests []map[string]interface{}
+
+	lock sync.Mutex
+}
+
+func newTestCloudControlStandIn() *testCloudControlStandIn {
+	standIn := &testCloudControlStandIn{
+		ImageStateAfterRequest: compute.ResourceStatusNormal,
+		SnapshotRequests:       make(map[string][]map[string]interface{}),
+
+		BackupJobStatusAfterRequest: backupJobStatusCompleted,
+	}
+	standIn.Server = httptest.NewServer(http.HandlerFunc(standIn.handle))
+
+	return standIn
+}
+
+// Create a providerState whose API client uses the stand-in.
+func (standIn *testCloudControlStandIn) NewProviderState() *providerState {
+	client := compute.NewClientWithBaseAddress(standIn.Server.URL, "test-user", "test-password")
+
+	return newProvider(client, &ProviderSettings{
+		RetryDelay:   1 * time.Second,
+		RetryTimeout: 10 * time.Second,
+		EndPoint:     standIn.Server.URL,
+		Username:     "test-user",
+		Password:     "test-password",
+	})
+}
+
+func (standIn *testCloudControlStandIn) Close() {
+	standIn.Server.Close()
+}
+
+func (standIn *testCloudControlStandIn) handle(writer http.ResponseWriter, request *http.Request) {
+	standIn.lock.Lock()
+	defer standIn.lock.Unlock()
+
+	path := request.URL.Path
+	switch {
+	case strings.HasSuffix(path, "/myaccount"):
+		writer.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(writer, "<Account><userName>test-user</userName><orgId>%s</orgId></Account>", testCloudControlOrganizationID)
+
+	case strings.HasSuffix(path, "/infrastructure/datacenter"):
+		standIn.writeJSON(writer, http.StatusOK, map[string]interface{}{
+			"datacenter": []map[string]interface{}{
+				{
+					"id":       request.URL.Query().Get("id"),
+					"ftpsHost": testCloudControlFTPSHost,
+				},
+			},
+			"pageNumber": 1,
+			"pageCount":  1,
+			"totalCount": 1,
+			"pageSize":   250,
+		})
+
+	case strings.HasSuffix(path, "/image/importImage"):
+		body := standIn.readJSON(request)
+		standIn.ImportRequests = append(standIn.ImportRequests, body)
+		standIn.Image = &compute.CustomerImage{
+			ID:           "test-image",
+			Name:         body["name"].(string),
+			Description:  body["description"].(string),
+			DataCenterID: body["datacenterId"].(string),
+			State:        standIn.ImageStateAfterRequest,
+		}
+		standIn.Image.Guest.OSCustomization = body["guestOsCustomization"].(bool)
+		standIn.Image.Guest.OperatingSystem.Family = "UNIX"
+
+		standIn.writeInProgress(writer, "imageId", standIn.Image.ID)
+
+	case strings.HasSuffix(path, "/image/exportImage"):
+		body := standIn.readJSON(request)
+		standIn.ExportRequests = append(standIn.ExportRequests, body)
+		if standIn.Image != nil {
+			standIn.Image.State = standIn.ImageStateAfterRequest
+		}
+
+		standIn.writeInProgress(writer, "imageExportId", "test-export")
+
+	case strings.HasSuffix(path, "/server/cloneServer"):
+		body := standIn.readJSON(request)
+		standIn.CloneRequests = append(standIn.CloneRequests, body)
+		standIn.Image = &compute.CustomerImage{
+			ID:           "test-image",
+			Name:         body["imageName"].(string),
+			Description:  body["description"].(string),
+			DataCenterID: "AU9",
+			State:        standIn.ImageStateAfterRequest,
+		}
+		standIn.Image.Guest.OSCustomization = body["guestOsCustomization"].(bool)
+		standIn.Image.Guest.OperatingSystem.Family = "UNIX"
+
+		standIn.writeInProgress(writer, "imageId", standIn.Image.ID)
+
+	case strings.HasSuffix(path, "/image/deleteCustomerImage"):
+		body := standIn.readJSON(request)
+		standIn.DeleteImageRequests = append(standIn.DeleteImageRequests, body)
+		if standIn.Image != nil && body["id"] == standIn.Image.ID {
+			standIn.Image = nil
+		}
+
+		standIn.writeInProgress(writer, "imageId", body["id"].(string))
+
+	case strings.Contains(path, "/image/customerImage/"):
+		if standIn.Image == nil || !strings.HasSuffix(path, "/"+standIn.Image.ID) {
+			standIn.writeNotFound(writer, "Customer image not found.")
+
+			return
+		}
+
+		standIn.writeJSON(writer, http.StatusOK, standIn.Image)
+
+	case strings.HasSuffix(path, "/snapshot/enableSnapshotService"), strings.HasSuffix(path, "/snapshot/changeSnapshotServicePlan"):
+		body := standIn.recordSnapshotRequest(request)
+		standIn.SnapshotService = &serverSnapshotService{
+			ServicePlan: body["servicePlan"].(string),
+			State:       compute.ResourceStatusNormal,
+		}
+
+		standIn.writeJSON(writer, http.StatusOK, &compute.APIResponseV2{
+			ResponseCode: compute.ResponseCodeOK,
+			Message:      "Snapshot service has been updated.",
+		})
+
+	case strings.HasSuffix(path, "/snapshot/disableSnapshotService"):
+		standIn.recordSnapshotRequest(request)
+		standIn.SnapshotService = nil
+
+		standIn.writeJSON(writer, http.StatusOK, &compute.APIResponseV2{
+			ResponseCode: compute.ResponseCodeOK,
+			Message:      "Snapshot service has been disabled.",
+		})
+
+	case strings.HasSuffix(path, "/snapshot/initiateManualSnapshot"):
+		body := standIn.recordSnapshotRequest(request)
+		snapshot := serverSnapshot{
+			ID:               fmt.Sprintf("test-snapshot-%d", len(standIn.SnapshotRequests["initiateManualSnapshot"])),
+			ServerID:         body["serverId"].(string),
+			Type:             "MANUAL",
+			State:            compute.ResourceStatusNormal,
+			StartTime:        "2026-10-18T00:00:00.000Z",
+			ConsistencyLevel: "CRASH_CONSISTENT",
+		}
+		standIn.Snapshots = append(standIn.Snapshots, snapshot)
+
+		standIn.writeInProgress(writer, "snapshotId", snapshot.ID)
+
+	case strings.HasSuffix(path, "/snapshot/deleteManualSnapshot"):
+		body := standIn.recordSnapshotRequest(request)
+		snapshots := make([]serverSnapshot, 0, len(standIn.Snapshots))
+		for _, snapshot := range standIn.Snapshots {
+			if snapshot.ID != body["id"] {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+		standIn.Snapshots = snapshots
+
+		standIn.writeInProgress(writer, "snapshotId", body["id"].(string))
+
+	case strings.HasSuffix(path, "/snapshot/createSnapshotPreviewServer"):
+		body := standIn.recordSnapshotRequest(request)
+		standIn.ComputeServer = &compute.Server{
+			ID:          "test-restored-server",
+			Name:        body["serverName"].(string),
+			Description: body["serverDescription"].(string),
+			Started:     body["serverStarted"].(bool),
+			State:       compute.ResourceStatusNormal,
+		}
+
+		standIn.writeInProgress(writer, "serverId", standIn.ComputeServer.ID)
+
+	case strings.HasSuffix(path, "/snapshot/migrateSnapshotPreviewServer"):
+		standIn.recordSnapshotRequest(request)
+
+		standIn.writeJSON(writer, http.StatusOK, &compute.APIResponseV2{
+			ResponseCode: compute.ResponseCodeOK,
+			Message:      "Preview server has been migrated.",
+		})
+
+	case strings.Contains(path, "/snapshot/snapshot/"):
+		for _, snapshot := range standIn.Snapshots {
+			if strings.HasSuffix(path, "/"+snapshot.ID) {
+				standIn.writeJSON(writer, http.StatusOK, snapshot)
+
+				return
+			}
+		}
+
+		standIn.writeNotFound(writer, "Snapshot not found.")
+
+	case strings.HasSuffix(path, "/snapshot/snapshot"):
+		// All snapshots are returned on the first page.
+		snapshots := &serverSnapshotPage{
+			Snapshots: make([]serverSnapshot, 0),
+		}
+		if request.URL.Query().Get("pageNumber") == "1" {
+			for _, snapshot := range standIn.Snapshots {
+				if snapshot.ServerID == request.URL.Query().Get("serverId") {
+					snapshots.Snapshots = append(snapshots.Snapshots, snapshot)
+				}
+			}
+			snapshots.PageNumber = 1
+			snapshots.PageCount = len(snapshots.Snapshots)
+		}
+
+		standIn.writeJSON(writer, http.StatusOK, snapshots)
+
+	case strings.HasSuffix(path, "/networkDomainVip/editVirtualListener"):
+		standIn.EditVirtualListenerRequests = append(standIn.EditVirtualListenerRequests, standIn.readJSON(request))
+
+		standIn.writeJSON(writer, http.StatusOK, &compute.APIResponseV2{
+			ResponseCode: compute.ResponseCodeOK,
+			Message:      "Virtual Listener has been edited.",
+		})
+
+	case strings.HasSuffix(path, "/networkDomainVip/createNode"):
+		standIn.CreateVIPNodeRequests = append(standIn.CreateVIPNodeRequests, standIn.readJSON(request))
+
+		standIn.writeJSON(writer, http.StatusOK, &compute.APIResponseV2{
+			ResponseCode: compute.ResponseCodeOK,
+			Message:      "Node has been created.",
+			FieldMessages: []compute.FieldMessage{
+				{FieldName: "nodeId", Message: fmt.Sprintf("test-node-%d", len(standIn.CreateVIPNodeRequests))},
+			},
+		})
+
+	case strings.HasSuffix(path, "/networkDomainVip/deleteNode"):
+		standIn.DeleteVIPNodeRequests = append(standIn.DeleteVIPNodeRequests, standIn.readJSON(request))
+
+		standIn.writeJSON(writer, http.StatusOK, &compute.APIResponseV2{
+			ResponseCode: compute.ResponseCodeOK,
+			Message:      "Node has been deleted.",
+		})
+
+	case strings.Contains(path, "/backup/client/") && request.URL.Query()["backupNow"] != nil:
+		clientID := path[strings.LastIndex(path, "/")+1:]
+		standIn.BackupNowRequests = append(standIn.BackupNowRequests, clientID)
+
+		jobID := fmt.Sprintf("test-backup-job-%d", len(standIn.BackupNowRequests))
+		for index := range standIn.BackupJobClients {
+			clientJobDetails := &standIn.BackupJobClients[index]
+			if clientJobDetails.ID == clientID {
+				clientJobDetails.RunningJob = nil
+				clientJobDetails.LastJob = &backupJob{
+					ID:        jobID,
+					Name:      "Backup",
+					Status:    standIn.BackupJobStatusAfterRequest,
+					StartTime: "2026-10-18T10:00:00.000Z",
+					EndTime:   "2026-10-18T10:05:00.000Z",
+				}
+			}
+		}
+
+		standIn.writeXML(writer, http.StatusOK, &compute.APIResponseV1{
+			Operation: "Backup Now",
+			Result:    compute.ResultSuccess,
+			Message:   "Backup job has been started.",
+			AdditionalInformation: []compute.APIResponseAdditionalInformationV1{
+				{Name: "backupJob.id", Value: jobID},
+			},
+		})
+
+	case strings.Contains(path, "/oec/0.9/") && strings.HasSuffix(path, "/backup"):
+		standIn.writeXML(writer, http.StatusOK, &serverBackupJobDetails{
+			Clients: standIn.BackupJobClients,
+		})
+
+	case strings.HasSuffix(path, "/backup/client/storagePolicy"):
+		writer.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(writer, `<BackupStoragePolicies xmlns="http://oec.api.opsource.net/schemas/backup"><storagePolicy name="14 Day Storage Policy" retentionPeriodInDays="14"/></BackupStoragePolicies>`)
+
+	case strings.HasSuffix(path, "/backup/client/schedulePolicy"):
+		writer.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(writer, `<BackupSchedulePolicies xmlns="http://oec.api.opsource.net/schemas/backup"><schedulePolicy name="12AM - 6AM" description="Daily backup"/></BackupSchedulePolicies>`)
+
+	case strings.Contains(path, "/server/server/"):
+		if standIn.ComputeServer == nil || !strings.HasSuffix(path, "/"+standIn.ComputeServer.ID) {
+			standIn.writeNotFound(writer, "Server not found.")
+
+			return
+		}
+
+		standIn.writeServer(writer)
+
+	case strings.HasSuffix(path, "/server/addDisk"):
+		addDiskRequest := standIn.readJSON(request)
+		standIn.AddDiskRequests = append(standIn.AddDiskRequests, addDiskRequest)
+
+		diskID := fmt.Sprintf("test-disk-%d", len(standIn.AddDiskRequests))
+		if !standIn.addControllerDisk(diskID, addDiskRequest) {
+			standIn.writeNotFound(writer, "Controller not found.")
+
+			return
+		}
+
+		standIn.writeJSON(writer, http.StatusOK, &compute.APIResponseV2{
+			ResponseCode: compute.ResponseCodeInProgress,
+			Message:      "Request to add disk has been accepted.",
+			FieldMessages: []compute.FieldMessage{
+				{FieldName: "diskId", Message: diskID},
+			},
+		})
+
+	case strings.HasSuffix(path, "/tag/tag"):
+		// All tags are returned on the first page.
+		tags := &compute.TagDetails{}
+		if request.URL.Query().Get("pageNumber") == "1" {
+			for _, tag := range standIn.ServerTags {
+				tags.Items = append(tags.Items, compute.TagDetail{Name: tag.Name, Value: tag.Value})
+			}
+			tags.PageNumber = 1
+			tags.PageCount = len(tags.Items)
+		}
+
+		standIn.writeJSON(writer, http.StatusOK, tags)
+
+	case strings.HasSuffix(path, "/tag/applyTags"):
+		body := standIn.readJSON(request)
+		for _, item := range body["tag"].([]interface{}) {
+			tagData := item.(map[string]interface{})
+			standIn.removeServerTag(tagData["tagKeyName"].(string))
+			standIn.ServerTags = append(standIn.ServerTags, compute.Tag{
+				Name:  tagData["tagKeyName"].(string),
+				Value: tagData["value"].(string),
+			})
+		}
+
+		standIn.writeJSON(writer, http.StatusOK, &compute.APIResponseV2{
+			ResponseCode: compute.ResponseCodeOK,
+			Message:      "Tags have been applied.",
+		})
+
+	case strings.HasSuffix(path, "/tag/removeTags"):
+		body := standIn.readJSON(request)
+		for _, tagName := range body["tagKeyName"].([]interface{}) {
+			standIn.removeServerTag(tagName.(string))
+		}
+
+		standIn.writeJSON(writer, http.StatusOK, &compute.APIResponseV2{
+			ResponseCode: compute.ResponseCodeOK,
+			Message:      "Tags have been removed.",
+		})
+
+	case strings.HasSuffix(path, "/tag/tagKey"):
+		// All tag keys are returned on the first page.
+		tagKeys := make([]map[string]interface{}, 0)
+		if request.URL.Query().Get("pageNumber") == "1" {
+			for _, tagKeyName := range standIn.TagKeys {
+				tagKeys = append(tagKeys, map[string]interface{}{"id": "tag-key-" + tagKeyName, "name": tagKeyName})
+			}
+		}
+
+		standIn.writeJSON(writer, http.StatusOK, map[string]interface{}{
+			"tagKey":     tagKeys,
+			"pageNumber": 1,
+			"pageCount":  len(tagKeys),
+		})
+
+	case strings.HasSuffix(path, "/tag/createTagKey"):
+		tagKeyName := standIn.readJSON(request)["name"].(string)
+		standIn.TagKeys = append(standIn.TagKeys, tagKeyName)
+
+		standIn.writeJSON(writer, http.StatusOK, &compute.APIResponseV2{
+			ResponseCode: compute.ResponseCodeOK,
+			Message:      "Tag key has been created.",
+			FieldMessages: []compute.FieldMessage{
+				{FieldName: "tagKeyId", Message: "tag-key-" + tagKeyName},
+			},
+		})
+
+	case strings.HasSuffix(path, "/networkDomainVip/removePoolMember"):
+		standIn.RemoveVIPPoolMemberRequests = append(standIn.RemoveVIPPoolMemberRequests, standIn.readJSON(request))
+
+		standIn.writeJSON(writer, http.StatusBadRequest, &compute.APIResponseV2{
+			ResponseCode: "UNEXPECTED_ERROR",
+			Message:      "Pool member could not be removed.",
+		})
+
+	case strings.Contains(path, "/networkDomainVip/virtualListener/"):
+		if standIn.VirtualListener == nil || !strings.HasSuffix(path, "/"+standIn.VirtualListener.ID) {
+			standIn.writeNotFound(writer, "Virtual listener not found.")
+
+			return
+		}
+
+		standIn.writeJSON(writer, http.StatusOK, standIn.VirtualListener)
+
+	case strings.Contains(path, "/networkDomainVip/pool/"):
+		if standIn.VIPPool == nil || !strings.HasSuffix(path, "/"+standIn.VIPPool.ID) {
+			standIn.writeNotFound(writer, "VIP pool not found.")
+
+			return
+		}
+
+		standIn.writeJSON(writer, http.StatusOK, standIn.VIPPool)
+
+	case strings.HasSuffix(path, "/networkDomainVip/poolMember"):
+		// All members are returned on the first page.
+		poolMembers := &compute.VIPPoolMembers{}
+		if request.URL.Query().Get("pageNumber") == "1" {
+			poolMembers.Items = standIn.VIPPoolMembers
+			poolMembers.PageNumber = 1
+			poolMembers.PageCount = len(standIn.VIPPoolMembers)
+		}
+
+		standIn.writeJSON(writer, http.StatusOK, poolMembers)
+
+	default:
+		standIn.writeJSON(writer, http.StatusBadRequest, &compute.APIResponseV2{
+			ResponseCode: "UNEXPECTED_ERROR",
+			Message:      fmt.Sprintf("Unexpected request: %s %s", request.Method, path),
+		})
+	}
+}
+
+func (standIn *testCloudControlStandIn) removeServerTag(tagName string) {
+	serverTags := make([]compute.Tag, 0, len(standIn.ServerTags))
+	for _, tag := range standIn.ServerTags {
+		if tag.Name != tagName {
+			serverTags = append(serverTags, tag)
+		}
+	}
+	standIn.ServerTags = serverTags
+}
+
+// Write the server (including its SATA and IDE controllers, and its snapshot service).
+func (standIn *testCloudControlStandIn) writeServer(writer http.ResponseWriter) {
+	serverJSON, _ := json.Marshal(standIn.ComputeServer)
+
+	server := make(map[string]interface{})
+	json.Unmarshal(serverJSON, &server)
+	server["sataController"] = standIn.SATAControllers
+	server["ideController"] = standIn.IDEControllers
+	if standIn.SnapshotService != nil {
+		server["snapshotService"] = standIn.SnapshotService
+	}
+
+	standIn.writeJSON(writer, http.StatusOK, server)
+}
+
+// Add a disk to the SATA or IDE controller targeted by an add-disk request.
+func (standIn *testCloudControlStandIn) addControllerDisk(diskID string, addDiskRequest map[string]interface{}) bool {
+	disk := serverStorageControllerDisk{
+		ID:    diskID,
+		State: compute.ResourceStatusNormal,
+	}
+	if sizeGB, ok := addDiskRequest["sizeGb"].(float64); ok {
+		disk.SizeGB = int(sizeGB)
+	}
+	disk.Speed, _ = addDiskRequest["speed"].(string)
+
+	controllers := standIn.SATAControllers
+	controllerRequest, ok := addDiskRequest["sataController"].(map[string]interface{})
+	if ok {
+		sataID, _ := controllerRequest["sataId"].(float64)
+		disk.SATAID = int(sataID)
+	} else {
+		controllers = standIn.IDEControllers
+		controllerRequest, ok = addDiskRequest["ideController"].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		driveNumber, _ := controllerRequest["driveNumber"].(float64)
+		disk.DriveNumber = int(driveNumber)
+	}
+
+	for index := range controllers {
+		if controllers[index].ID == controllerRequest["controllerId"] {
+			controllers[index].Disks = append(controllers[index].Disks, disk)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Record the body of a snapshot-service request (keyed by the name of the operation).
+func (standIn *testCloudControlStandIn) recordSnapshotRequest(request *http.Request) map[string]interface{} {
+	body := standIn.readJSON(request)
+
+	operationName := request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:]
+	standIn.SnapshotRequests[operationName] = append(standIn.SnapshotRequests[operationName], body)
+
+	return body
+}
+
+func (standIn *testCloudControlStandIn) readJSON(request *http.Request) map[string]interface{} {
+	body := make(map[string]interface{})
+	json.NewDecoder(request.Body).Decode(&body)
+
+	return body
+}
+
+func (standIn *testCloudControlStandIn) writeInProgress(writer http.ResponseWriter, fieldName string, fieldValue string) {
+	standIn.writeJSON(writer, http.StatusOK, &compute.APIResponseV2{
+		ResponseCode: compute.ResponseCodeInProgress,
+		Message:      "Request to perform operation has been accepted.",
+		FieldMessages: []compute.FieldMessage{
+			{FieldName: fieldName, Message: fieldValue},
+		},
+	})
+}
+
+func (standIn *testCloudControlStandIn) writeNotFound(writer http.ResponseWriter, message string) {
+	standIn.writeJSON(writer, http.StatusBadRequest, &compute.APIResponseV2{
+		ResponseCode: compute.ResponseCodeResourceNotFound,
+		Message:      message,
+	})
+}
+
+func (standIn *testCloudControlStandIn) writeXML(writer http.ResponseWriter, statusCode int, body interface{}) {
+	writer.Header().Set("Content-Type", "application/xml")
+	writer.WriteHeader(statusCode)
+	xml.NewEncoder(writer).Encode(body)
+}
+
+func (standIn *testCloudControlStandIn) writeJSON(writer http.ResponseWriter, statusCode int, body interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
+	json.NewEncoder(writer).Encode(body)
+}
@@ -1,16 +1,17 @@
 package ddcloud
 
 import (
-	"fmt"
+	"context"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceAddressList() *schema.Resource {
 	return &schema.Resource{
 
-		Read: dataSourceAddressListRead,
+		ReadContext: dataSourceAddressListRead,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyAddressListNetworkDomainID: &schema.Schema{
@@ -39,7 +40,7 @@ func dataSourceAddressList() *schema.Resource {
 }
 
 // Read a address list data source.
-func dataSourceAddressListRead(data *schema.ResourceData, provider interface{}) error {
+func dataSourceAddressListRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	name := data.Get(resourceKeyAddressListName).(string)
 	domainId := data.Get(resourceKeyAddressListNetworkDomainID).(string)
 	log.Printf("Read address list '%s' from network domain '%s'.", name, domainId)
@@ -49,7 +50,7 @@ func dataSourceAddressListRead(data *schema.ResourceData, provider interface{})
 	addressList, err := apiClient.GetIPAddressListByName(name, domainId)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if addressList != nil {
@@ -62,7 +63,7 @@ func dataSourceAddressListRead(data *schema.ResourceData, provider interface{})
 		data.Set(resourceKeyAddressListIPVersion, addressList.IPVersion)
 
 	} else {
-		return fmt.Errorf("failed to find Addresslist with name '%s'", name)
+		return diag.Errorf("failed to find Addresslist with name '%s'", name)
 	}
 
 	return nil
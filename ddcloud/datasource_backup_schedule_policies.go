@@ -0,0 +1,86 @@
+package ddcloud
+
+import (
+	"context"
+	"log"
+
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	dataSourceKeyBackupSchedulePoliciesServerID          = "server"
+	dataSourceKeyBackupSchedulePoliciesPolicies          = "policies"
+	dataSourceKeyBackupSchedulePoliciesPolicyName        = "name"
+	dataSourceKeyBackupSchedulePoliciesPolicyDescription = "description"
+)
+
+func dataSourceBackupSchedulePolicies() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceBackupSchedulePoliciesRead,
+
+		Schema: map[string]*schema.Schema{
+			dataSourceKeyBackupSchedulePoliciesServerID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The Id of the server (with Cloud Backup enabled) whose available schedule policies are listed",
+			},
+			dataSourceKeyBackupSchedulePoliciesPolicies: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The schedule policies available for the server's backup service plan",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						dataSourceKeyBackupSchedulePoliciesPolicyName: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The schedule policy name (as used by ddcloud_server_backup)",
+						},
+						dataSourceKeyBackupSchedulePoliciesPolicyDescription: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A description of the schedule policy",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read a backup schedule policies data source.
+func dataSourceBackupSchedulePoliciesRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	serverID := data.Get(dataSourceKeyBackupSchedulePoliciesServerID).(string)
+	log.Printf("Read backup schedule policies available for server '%s'.", serverID)
+
+	apiClient := provider.(*providerState).Client()
+
+	schedulePolicies, err := apiClient.GetServerBackupSchedulePolicies(serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Found %d backup schedule policies for server '%s'.", len(schedulePolicies.Items), serverID)
+
+	data.SetId(serverID)
+	err = data.Set(dataSourceKeyBackupSchedulePoliciesPolicies, flattenBackupSchedulePolicies(schedulePolicies.Items))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// Convert backup schedule policies to their state representation.
+func flattenBackupSchedulePolicies(schedulePolicies []compute.BackupSchedulePolicy) []interface{} {
+	schedulePolicyProperties := make([]interface{}, len(schedulePolicies))
+	for index, schedulePolicy := range schedulePolicies {
+		schedulePolicyProperties[index] = map[string]interface{}{
+			dataSourceKeyBackupSchedulePoliciesPolicyName:        schedulePolicy.Name,
+			dataSourceKeyBackupSchedulePoliciesPolicyDescription: schedulePolicy.Description,
+		}
+	}
+
+	return schedulePolicyProperties
+}
@@ -0,0 +1,93 @@
+package ddcloud
+
+import (
+	"context"
+	"log"
+
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	dataSourceKeyBackupStoragePoliciesServerID                    = "server"
+	dataSourceKeyBackupStoragePoliciesPolicies                    = "policies"
+	dataSourceKeyBackupStoragePoliciesPolicyName                  = "name"
+	dataSourceKeyBackupStoragePoliciesPolicyRetentionPeriodInDays = "retention_period_days"
+	dataSourceKeyBackupStoragePoliciesPolicySecondaryLocation     = "secondary_location"
+)
+
+func dataSourceBackupStoragePolicies() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceBackupStoragePoliciesRead,
+
+		Schema: map[string]*schema.Schema{
+			dataSourceKeyBackupStoragePoliciesServerID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The Id of the server (with Cloud Backup enabled) whose available storage policies are listed",
+			},
+			dataSourceKeyBackupStoragePoliciesPolicies: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The storage policies available for the server's backup service plan",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						dataSourceKeyBackupStoragePoliciesPolicyName: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The storage policy name (as used by ddcloud_server_backup)",
+						},
+						dataSourceKeyBackupStoragePoliciesPolicyRetentionPeriodInDays: &schema.Schema{
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The storage policy's backup retention period (in days)",
+						},
+						dataSourceKeyBackupStoragePoliciesPolicySecondaryLocation: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The secondary location (if any) where backups are stored",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read a backup storage policies data source.
+func dataSourceBackupStoragePoliciesRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	serverID := data.Get(dataSourceKeyBackupStoragePoliciesServerID).(string)
+	log.Printf("Read backup storage policies available for server '%s'.", serverID)
+
+	apiClient := provider.(*providerState).Client()
+
+	storagePolicies, err := apiClient.GetServerBackupStoragePolicies(serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Found %d backup storage policies for server '%s'.", len(storagePolicies.Items), serverID)
+
+	data.SetId(serverID)
+	err = data.Set(dataSourceKeyBackupStoragePoliciesPolicies, flattenBackupStoragePolicies(storagePolicies.Items))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// Convert backup storage policies to their state representation.
+func flattenBackupStoragePolicies(storagePolicies []compute.BackupStoragePolicy) []interface{} {
+	storagePolicyProperties := make([]interface{}, len(storagePolicies))
+	for index, storagePolicy := range storagePolicies {
+		storagePolicyProperties[index] = map[string]interface{}{
+			dataSourceKeyBackupStoragePoliciesPolicyName:                  storagePolicy.Name,
+			dataSourceKeyBackupStoragePoliciesPolicyRetentionPeriodInDays: storagePolicy.RetentionPeriodInDays,
+			dataSourceKeyBackupStoragePoliciesPolicySecondaryLocation:     storagePolicy.SecondaryLocation,
+		}
+	}
+
+	return storagePolicyProperties
+}
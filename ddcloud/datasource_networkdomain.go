@@ -1,15 +1,17 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceNetworkDomain() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceNetworkDomainRead,
+		ReadContext: dataSourceNetworkDomainRead,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyNetworkDomainName: &schema.Schema{
@@ -47,7 +49,7 @@ func dataSourceNetworkDomain() *schema.Resource {
 }
 
 // Read a network domain data source.
-func dataSourceNetworkDomainRead(data *schema.ResourceData, provider interface{}) error {
+func dataSourceNetworkDomainRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	name := data.Get(resourceKeyNetworkDomainName).(string)
 	dataCenterID := data.Get(resourceKeyNetworkDomainDataCenter).(string)
 
@@ -57,7 +59,7 @@ func dataSourceNetworkDomainRead(data *schema.ResourceData, provider interface{}
 
 	networkDomain, err := apiClient.GetNetworkDomainByName(name, dataCenterID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if networkDomain != nil {
@@ -73,7 +75,7 @@ func dataSourceNetworkDomainRead(data *schema.ResourceData, provider interface{}
 			networkDomain.OutsideTransitVLANIPv4Subnet.PrefixSize,
 		))
 	} else {
-		return fmt.Errorf("failed to find network domain '%s' in data center '%s'", name, dataCenterID)
+		return diag.Errorf("failed to find network domain '%s' in data center '%s'", name, dataCenterID)
 	}
 
 	return nil
@@ -2,30 +2,49 @@ package ddcloud
 
 import (
 	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"strings"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-	"golang.org/x/crypto/pkcs12"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"software.sslmate.com/src/go-pkcs12"
 )
 
 const (
-	resourceKeyPFXFile        = "file"
-	resourceKeyPFXPassword    = "password"
-	resourceKeyPFXCertificate = "certificate"
-	resourceKeyPFXPrivateKey  = "private_key"
+	resourceKeyPFXFile                     = "file"
+	resourceKeyPFXContent                  = "content"
+	resourceKeyPFXPassword                 = "password"
+	resourceKeyPFXCertificate              = "certificate"
+	resourceKeyPFXPrivateKey               = "private_key"
+	resourceKeyPFXIntermediateCertificates = "intermediate_certificates"
+	resourceKeyPFXCertificateChain         = "certificate_chain"
+	resourceKeyPFXCertificates             = "certificates"
 )
 
 func dataSourcePFX() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourcePFXRead,
+		ReadContext: dataSourcePFXRead,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyPFXFile: &schema.Schema{
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the PFX file",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The name of the PFX file",
+				ExactlyOneOf: []string{resourceKeyPFXFile, resourceKeyPFXContent},
+			},
+			resourceKeyPFXContent: &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				Description:  "The content of the PFX file (Base64-encoded)",
+				ExactlyOneOf: []string{resourceKeyPFXFile, resourceKeyPFXContent},
 			},
 			resourceKeyPFXPassword: &schema.Schema{
 				Type:        schema.TypeString,
@@ -36,68 +55,140 @@ func dataSourcePFX() *schema.Resource {
 			resourceKeyPFXCertificate: &schema.Schema{
 				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "The (first) certificate in the PFX file",
+				Description: "The certificate (in PEM format) that corresponds to the private key in the PFX file",
 			},
 			resourceKeyPFXPrivateKey: &schema.Schema{
 				Type:        schema.TypeString,
 				Computed:    true,
 				Sensitive:   true,
-				Description: "The (first) private key in the PFX file",
+				Description: "The RSA private key (in PEM format) in the PFX file",
+			},
+			resourceKeyPFXIntermediateCertificates: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The other certificates (in PEM format) in the PFX file, ordered from the certificate's issuer towards the root",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			resourceKeyPFXCertificateChain: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The intermediate certificates (in PEM format), concatenated in order (suitable for use with ddcloud_ssl_certificate_chain)",
+			},
+			resourceKeyPFXCertificates: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "All certificates (in PEM format) in the PFX file (the certificate first, followed by the intermediate certificates)",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
 			},
 		},
 	}
 }
 
-// Read a network domain data source.
-func dataSourcePFXRead(data *schema.ResourceData, provider interface{}) error {
+// Read a PFX data source.
+func dataSourcePFXRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	var (
+		pfxData   []byte
+		pfxSource string
+		err       error
+	)
+
 	fileName := data.Get(resourceKeyPFXFile).(string)
+	if fileName != "" {
+		pfxSource = fmt.Sprintf("'%s'", fileName)
 
-	pfxData, err := ioutil.ReadFile(fileName)
-	if err != nil {
-		log.Printf("Failed to read PFX data from '%s': %s", fileName, err.Error())
+		pfxData, err = ioutil.ReadFile(fileName)
+		if err != nil {
+			log.Printf("Failed to read PFX data from %s: %s", pfxSource, err.Error())
 
-		return err
+			return diag.FromErr(err)
+		}
+	} else {
+		pfxSource = "content"
+
+		pfxData, err = decodePFXContent(data.Get(resourceKeyPFXContent).(string))
+		if err != nil {
+			log.Printf("Failed to read PFX data from %s: %s", pfxSource, err.Error())
+
+			return diag.FromErr(err)
+		}
 	}
 
-	log.Printf("Read PFX data from '%s'.", fileName)
+	log.Printf("Read PFX data from %s.", pfxSource)
 
 	pfxPassword := data.Get(resourceKeyPFXPassword).(string)
-	pemBlocks, err := pkcs12.ToPEM(pfxData, pfxPassword)
+	privateKey, certificate, caCertificates, err := pkcs12.DecodeChain(pfxData, pfxPassword)
 	if err != nil {
-		log.Printf("Failed to decode PFX data from '%s': %s", fileName, err.Error())
+		log.Printf("Failed to decode PFX data from %s: %s", pfxSource, err.Error())
 
-		return err
+		return diag.Errorf("failed to decode PFX data from %s: %s", pfxSource, err)
 	}
 
-	var (
-		certificatePEM string
-		privateKeyPEM  string
-	)
-	for _, pemBlock := range pemBlocks {
-		switch pemBlock.Type {
-		case "CERTIFICATE":
-			if certificatePEM == "" {
-				certificatePEM, err = pemToString(pemBlock)
-				if err != nil {
-					return err
-				}
-			}
-		case "PRIVATE KEY":
-			if privateKeyPEM == "" {
-				privateKeyPEM, err = pemToString(pemBlock)
-				if err != nil {
-					return err
-				}
-			}
+	rsaPrivateKey, ok := privateKey.(*rsa.PrivateKey)
+	if !ok {
+		return diag.Errorf("unsupported private key type %T in PFX data from %s (only RSA keys are supported by CloudControl)", privateKey, pfxSource)
+	}
+
+	// Same encoding as previous versions of this data source (PKCS #1 key, "PRIVATE KEY" PEM header), so existing values do not change.
+	privateKeyPEM, err := pemToString(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(rsaPrivateKey),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	certificatePEM, err := certificateToPEM(certificate)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	certificatesPEM := []interface{}{certificatePEM}
+	intermediateCertificatesPEM := make([]interface{}, 0)
+	certificateChainPEM := ""
+	for _, caCertificate := range orderCertificateChain(certificate, caCertificates) {
+		caCertificatePEM, err := certificateToPEM(caCertificate)
+		if err != nil {
+			return diag.FromErr(err)
 		}
+
+		certificatesPEM = append(certificatesPEM, caCertificatePEM)
+		intermediateCertificatesPEM = append(intermediateCertificatesPEM, caCertificatePEM)
+		certificateChainPEM += caCertificatePEM
 	}
 
+	data.SetId(getCertificateAttributes(certificate).FingerprintSHA256)
 	data.Set(resourceKeyPFXCertificate, certificatePEM)
 	data.Set(resourceKeyPFXPrivateKey, privateKeyPEM)
+	data.Set(resourceKeyPFXIntermediateCertificates, intermediateCertificatesPEM)
+	data.Set(resourceKeyPFXCertificateChain, certificateChainPEM)
+	data.Set(resourceKeyPFXCertificates, certificatesPEM)
 
 	return nil
 }
 
+// Decode Base64-encoded PFX content (line breaks and other whitespace are ignored).
+func decodePFXContent(content string) ([]byte, error) {
+	content = strings.Join(strings.Fields(content), "")
+
+	pfxData, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return nil, fmt.Errorf("PFX content is not valid Base64: %s", err)
+	}
+
+	return pfxData, nil
+}
+
+func certificateToPEM(certificate *x509.Certificate) (string, error) {
+	return pemToString(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: certificate.Raw,
+	})
+}
+
 func pemToString(pemBlock *pem.Block) (string, error) {
 	var buffer bytes.Buffer
 	err := pem.Encode(&buffer, pemBlock)
@@ -0,0 +1,98 @@
+package ddcloud
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+/*
+ * Unit tests.
+ */
+
+// Unit test - read a modern (AES-256 / SHA-256 MAC) PFX from inline content, with intermediates out of order.
+func TestDataSourcePFXReadModernContent(test *testing.T) {
+	testDataSourcePFXRead(test, pkcs12.Modern2023)
+}
+
+// Unit test - read a legacy (RC2 / SHA-1 MAC) PFX from inline content.
+func TestDataSourcePFXReadLegacyContent(test *testing.T) {
+	testDataSourcePFXRead(test, pkcs12.LegacyRC2)
+}
+
+// Unit test - inline content that is not valid Base64.
+func TestDecodePFXContentInvalid(test *testing.T) {
+	_, err := decodePFXContent("not base64!")
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("err != nil", err != nil)
+}
+
+func testDataSourcePFXRead(test *testing.T, encoder *pkcs12.Encoder) {
+	certificates := testGenerateCertificates(test, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	leaf := testParseCertificate(test, certificates.LeafPEM)
+	intermediate := testParseCertificate(test, certificates.IntermediatePEM)
+	root := testParseCertificate(test, certificates.RootPEM)
+	privateKey, err := parsePEMPrivateKey(certificates.LeafKeyPEM)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	pfxData, err := encoder.Encode(privateKey, leaf, []*x509.Certificate{root, intermediate}, "Hello123")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	// Break the content across multiple lines (as it would be in a file).
+	content := base64.StdEncoding.EncodeToString(pfxData)
+	content = content[:40] + "\n" + content[40:]
+
+	data := schema.TestResourceDataRaw(test, dataSourcePFX().Schema, map[string]interface{}{
+		resourceKeyPFXContent:  content,
+		resourceKeyPFXPassword: "Hello123",
+	})
+
+	diagnostics := dataSourcePFXRead(context.Background(), data, nil)
+	if diagnostics.HasError() {
+		test.Fatalf("Read failed: %+v", diagnostics)
+	}
+
+	assert := assert.ForTest(test)
+	assert.EqualsString("certificate", certificates.LeafPEM, data.Get(resourceKeyPFXCertificate).(string))
+	assert.EqualsString("certificate_chain", certificates.ChainPEM(), data.Get(resourceKeyPFXCertificateChain).(string))
+
+	intermediateCertificates := data.Get(resourceKeyPFXIntermediateCertificates).([]interface{})
+	assert.EqualsInt("len(intermediate_certificates)", 2, len(intermediateCertificates))
+	assert.EqualsString("intermediate_certificates[0]", certificates.IntermediatePEM, intermediateCertificates[0].(string))
+	assert.EqualsString("intermediate_certificates[1]", certificates.RootPEM, intermediateCertificates[1].(string))
+
+	assert.EqualsInt("len(certificates)", 3, len(data.Get(resourceKeyPFXCertificates).([]interface{})))
+
+	extractedKey, err := parsePEMPrivateKey(data.Get(resourceKeyPFXPrivateKey).(string))
+	assert.IsTrue("err == nil", err == nil)
+	assert.IsTrue("private key matches", validateCertificatePrivateKey(leaf, extractedKey) == nil)
+
+	// Same encoding as previous versions of the data source (PKCS #1 key, "PRIVATE KEY" PEM header).
+	privateKeyBlock, _ := pem.Decode([]byte(data.Get(resourceKeyPFXPrivateKey).(string)))
+	assert.IsTrue("privateKeyBlock != nil", privateKeyBlock != nil)
+	assert.EqualsString("privateKeyBlock.Type", "PRIVATE KEY", privateKeyBlock.Type)
+	assert.IsTrue("privateKeyBlock.Bytes is PKCS #1", bytes.Equal(x509.MarshalPKCS1PrivateKey(privateKey), privateKeyBlock.Bytes))
+}
+
+func testParseCertificate(test *testing.T, certificatePEM string) *x509.Certificate {
+	certificates, err := parsePEMCertificates(certificatePEM)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	return certificates[0]
+}
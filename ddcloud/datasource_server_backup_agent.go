@@ -0,0 +1,303 @@
+package ddcloud
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	dataSourceKeyServerBackupAgentServerID          = "server"
+	dataSourceKeyServerBackupAgentOSFamily          = "os_family"
+	dataSourceKeyServerBackupAgentAssetID           = "asset_id"
+	dataSourceKeyServerBackupAgentScriptType        = "script_type"
+	dataSourceKeyServerBackupAgentScripts           = "scripts"
+	dataSourceKeyServerBackupAgentClients           = "client"
+	dataSourceKeyServerBackupAgentClientID          = "id"
+	dataSourceKeyServerBackupAgentClientType        = "type"
+	dataSourceKeyServerBackupAgentClientStatus      = "status"
+	dataSourceKeyServerBackupAgentClientDownloadURL = "download_url"
+	dataSourceKeyServerBackupAgentClientScript      = "script"
+
+	backupAgentScriptTypePowerShell = "powershell"
+	backupAgentScriptTypeShell      = "shell"
+)
+
+func dataSourceServerBackupAgent() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerBackupAgentRead,
+
+		Schema: map[string]*schema.Schema{
+			dataSourceKeyServerBackupAgentServerID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The Id of the server (with Cloud Backup enabled) for which backup agent install scripts are rendered",
+			},
+			dataSourceKeyServerBackupAgentOSFamily: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The server's operating system family (WINDOWS or UNIX)",
+			},
+			dataSourceKeyServerBackupAgentAssetID: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The asset Id assigned to the server by Cloud Backup",
+			},
+			dataSourceKeyServerBackupAgentScriptType: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the rendered install scripts (powershell or shell)",
+			},
+			dataSourceKeyServerBackupAgentScripts: &schema.Schema{
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The rendered install scripts, keyed by client type (with '.' replaced by '_')",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			dataSourceKeyServerBackupAgentClients: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The server's backup clients and their install scripts",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						dataSourceKeyServerBackupAgentClientID: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The Id assigned to the backup client by Cloud Backup",
+						},
+						dataSourceKeyServerBackupAgentClientType: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The backup client type (e.g. FA.Linux)",
+						},
+						dataSourceKeyServerBackupAgentClientStatus: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The backup client status (e.g. Unregistered, Active)",
+						},
+						dataSourceKeyServerBackupAgentClientDownloadURL: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The URL where the backup client installer can be downloaded",
+						},
+						dataSourceKeyServerBackupAgentClientScript: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The script that downloads and installs the backup client (empty if no download URL is available)",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read a server backup agent data source.
+func dataSourceServerBackupAgentRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	serverID := data.Get(dataSourceKeyServerBackupAgentServerID).(string)
+	log.Printf("Read backup agent install scripts for server '%s'.", serverID)
+
+	apiClient := provider.(*providerState).Client()
+
+	server, err := apiClient.GetServer(serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if server == nil {
+		return diag.Errorf("cannot find server '%s'", serverID)
+	}
+
+	osFamily := server.OperatingSystem.Family
+	scriptType, err := getBackupAgentScriptType(osFamily)
+	if err != nil {
+		return diag.Errorf("cannot render backup agent install scripts for server '%s': %s", serverID, err)
+	}
+
+	backupDetails, err := apiClient.GetServerBackupDetails(serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if backupDetails == nil {
+		return diag.Errorf("Cloud Backup is not enabled for server '%s'", serverID)
+	}
+
+	log.Printf("Render %s install scripts for %d backup clients of server '%s'.", scriptType, len(backupDetails.Clients), serverID)
+
+	clients := make([]interface{}, len(backupDetails.Clients))
+	scripts := make(map[string]interface{})
+	for index, clientDetail := range backupDetails.Clients {
+		script, err := renderBackupAgentInstallScript(scriptType, server.ID, clientDetail)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		clients[index] = map[string]interface{}{
+			dataSourceKeyServerBackupAgentClientID:          clientDetail.ID,
+			dataSourceKeyServerBackupAgentClientType:        clientDetail.Type,
+			dataSourceKeyServerBackupAgentClientStatus:      clientDetail.Status,
+			dataSourceKeyServerBackupAgentClientDownloadURL: clientDetail.DownloadURL,
+			dataSourceKeyServerBackupAgentClientScript:      script,
+		}
+
+		clientType := strings.Replace(clientDetail.Type, ".", "_", -1)
+		scripts[clientType] = script
+	}
+
+	data.SetId(serverID)
+	data.Set(dataSourceKeyServerBackupAgentOSFamily, osFamily)
+	data.Set(dataSourceKeyServerBackupAgentAssetID, backupDetails.AssetID)
+	data.Set(dataSourceKeyServerBackupAgentScriptType, scriptType)
+	data.Set(dataSourceKeyServerBackupAgentScripts, scripts)
+	err = data.Set(dataSourceKeyServerBackupAgentClients, clients)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// Determine the type of install script to render for a server's operating system family.
+func getBackupAgentScriptType(osFamily string) (string, error) {
+	switch strings.ToUpper(osFamily) {
+	case "WINDOWS":
+		return backupAgentScriptTypePowerShell, nil
+	case "UNIX":
+		return backupAgentScriptTypeShell, nil
+	default:
+		return "", fmt.Errorf("Cloud Backup agents are not available for operating system family '%s'", osFamily)
+	}
+}
+
+// The values used to render a backup agent install script.
+type backupAgentInstallScriptData struct {
+	ServerID    string
+	ClientType  string
+	DownloadURL string
+}
+
+// Render the install script for a backup client.
+//
+// Returns an empty script if CloudControl has not supplied a download URL for the client.
+// The script fails (rather than running the installer interactively) if the installer is not a package that can be installed unattended (MSI on Windows, RPM or Debian on Linux).
+func renderBackupAgentInstallScript(scriptType string, serverID string, clientDetail compute.BackupClientDetail) (string, error) {
+	if clientDetail.DownloadURL == "" {
+		log.Printf("No download URL is available for backup client '%s' (type '%s') of server '%s'; no install script will be rendered.",
+			clientDetail.ID, clientDetail.Type, serverID,
+		)
+
+		return "", nil
+	}
+
+	var scriptTemplate *template.Template
+	switch scriptType {
+	case backupAgentScriptTypePowerShell:
+		scriptTemplate = backupAgentPowerShellScriptTemplate
+	case backupAgentScriptTypeShell:
+		scriptTemplate = backupAgentShellScriptTemplate
+	default:
+		return "", fmt.Errorf("unsupported backup agent script type '%s'", scriptType)
+	}
+
+	script := &bytes.Buffer{}
+	err := scriptTemplate.Execute(script, &backupAgentInstallScriptData{
+		ServerID:    serverID,
+		ClientType:  clientDetail.Type,
+		DownloadURL: clientDetail.DownloadURL,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render install script for backup client '%s' (type '%s') of server '%s': %s",
+			clientDetail.ID, clientDetail.Type, serverID, err,
+		)
+	}
+
+	return script.String(), nil
+}
+
+// Quote a value as a single-quoted shell string.
+func quoteShellString(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+// Quote a value as a single-quoted PowerShell string.
+func quotePowerShellString(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+var backupAgentShellScriptTemplate = template.Must(
+	template.New("shell").Funcs(template.FuncMap{"quote": quoteShellString}).Parse(`#!/bin/sh
+# Install the Cloud Backup client {{ .ClientType }} on server {{ .ServerID }}.
+set -e
+
+CLIENT_TYPE={{ quote .ClientType }}
+DOWNLOAD_URL={{ quote .DownloadURL }}
+
+INSTALL_DIR="$(mktemp -d)"
+trap 'rm -rf "$INSTALL_DIR"' EXIT
+
+INSTALLER_NAME="$(basename "${DOWNLOAD_URL%%\?*}")"
+INSTALLER="$INSTALL_DIR/${INSTALLER_NAME:-installer}"
+
+echo "Downloading Cloud Backup client $CLIENT_TYPE from $DOWNLOAD_URL..."
+if command -v curl >/dev/null 2>&1; then
+	curl -fsSL -o "$INSTALLER" "$DOWNLOAD_URL"
+else
+	wget -q -O "$INSTALLER" "$DOWNLOAD_URL"
+fi
+
+echo "Installing Cloud Backup client $CLIENT_TYPE..."
+case "$INSTALLER" in
+	*.rpm)
+		rpm -Uvh "$INSTALLER"
+		;;
+	*.deb)
+		dpkg -i "$INSTALLER"
+		;;
+	*)
+		echo "The Cloud Backup client installer ($DOWNLOAD_URL) is not an RPM or Debian package, so it cannot be installed unattended; download and run it manually." >&2
+		exit 1
+		;;
+esac
+
+echo "Installed Cloud Backup client $CLIENT_TYPE."
+`),
+)
+
+var backupAgentPowerShellScriptTemplate = template.Must(
+	template.New("powershell").Funcs(template.FuncMap{"quote": quotePowerShellString}).Parse(`# Install the Cloud Backup client {{ .ClientType }} on server {{ .ServerID }}.
+$ErrorActionPreference = 'Stop'
+
+$clientType = {{ quote .ClientType }}
+$downloadUrl = {{ quote .DownloadURL }}
+
+$installerName = [System.IO.Path]::GetFileName(([Uri]$downloadUrl).AbsolutePath)
+if ($installerName -notlike '*.msi') {
+	throw "The Cloud Backup client installer ($downloadUrl) is not an MSI package, so it cannot be installed unattended; download and run it manually."
+}
+$installer = Join-Path $env:TEMP $installerName
+
+Write-Host "Downloading Cloud Backup client $clientType from $downloadUrl..."
+[Net.ServicePointManager]::SecurityProtocol = [Net.SecurityProtocolType]::Tls12
+Invoke-WebRequest -Uri $downloadUrl -OutFile $installer -UseBasicParsing
+
+try {
+	Write-Host "Installing Cloud Backup client $clientType..."
+	$process = Start-Process -FilePath 'msiexec.exe' -ArgumentList @('/i', "` + "`" + `"$installer` + "`" + `"", '/qn', '/norestart') -Wait -PassThru
+	if ($process.ExitCode -ne 0) {
+		throw "Cloud Backup client installer exited with code $($process.ExitCode)."
+	}
+} finally {
+	Remove-Item -Path $installer -Force -ErrorAction SilentlyContinue
+}
+
+Write-Host "Installed Cloud Backup client $clientType."
+`),
+)
@@ -0,0 +1,104 @@
+package ddcloud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+)
+
+/*
+ * Unit tests.
+ */
+
+func testBackupClientDetail(clientType string, downloadURL string) compute.BackupClientDetail {
+	return compute.BackupClientDetail{
+		ID:          "client-1",
+		Type:        clientType,
+		Status:      "Unregistered",
+		DownloadURL: downloadURL,
+	}
+}
+
+// The script type is determined by the server's operating system family.
+func TestGetBackupAgentScriptType(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	scriptType, err := getBackupAgentScriptType("WINDOWS")
+	testAssert.IsTrue("err == nil", err == nil)
+	testAssert.EqualsString("WINDOWS", backupAgentScriptTypePowerShell, scriptType)
+
+	scriptType, err = getBackupAgentScriptType("UNIX")
+	testAssert.IsTrue("err == nil", err == nil)
+	testAssert.EqualsString("UNIX", backupAgentScriptTypeShell, scriptType)
+
+	_, err = getBackupAgentScriptType("OTHERUNIX")
+	testAssert.IsTrue("err != nil", err != nil)
+}
+
+// Linux servers get a shell script that downloads and installs the package.
+func TestRenderBackupAgentInstallScriptShell(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	script, err := renderBackupAgentInstallScript(backupAgentScriptTypeShell, "server-1",
+		testBackupClientDetail("FA.Linux", "https://backup.example.com/clients/agent.rpm?token=abc"),
+	)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	testAssert.IsTrue("starts with shebang", strings.HasPrefix(script, "#!/bin/sh\n"))
+	testAssert.IsTrue("includes client type", strings.Contains(script, "CLIENT_TYPE='FA.Linux'\n"))
+	testAssert.IsFalse("does not export variables", strings.Contains(script, "export "))
+	testAssert.IsTrue("fails for other installers", strings.Contains(script, "cannot be installed unattended"))
+	testAssert.IsTrue("includes download URL", strings.Contains(script, "DOWNLOAD_URL='https://backup.example.com/clients/agent.rpm?token=abc'\n"))
+}
+
+// Windows servers get a PowerShell script that downloads and installs the MSI package.
+func TestRenderBackupAgentInstallScriptPowerShell(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	script, err := renderBackupAgentInstallScript(backupAgentScriptTypePowerShell, "server-1",
+		testBackupClientDetail("FA.Win", "https://backup.example.com/clients/agent.msi"),
+	)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	testAssert.IsTrue("includes client type", strings.Contains(script, "$clientType = 'FA.Win'\n"))
+	testAssert.IsFalse("does not set environment variables", strings.Contains(script, "$env:DDCLOUD"))
+	testAssert.IsTrue("fails for other installers", strings.Contains(script, "-notlike '*.msi'"))
+	testAssert.IsTrue("includes download URL", strings.Contains(script, "$downloadUrl = 'https://backup.example.com/clients/agent.msi'\n"))
+	testAssert.IsTrue("quotes msiexec argument", strings.Contains(script, "@('/i', \"`\"$installer`\"\", '/qn', '/norestart')"))
+}
+
+// Values are quoted so that they cannot break out of the script's string literals.
+func TestRenderBackupAgentInstallScriptQuoting(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	clientDetail := testBackupClientDetail("FA.Linux", "https://backup.example.com/it's/agent.bin")
+
+	script, err := renderBackupAgentInstallScript(backupAgentScriptTypeShell, "server-1", clientDetail)
+	if err != nil {
+		test.Fatal(err)
+	}
+	testAssert.IsTrue("shell quoting", strings.Contains(script, `DOWNLOAD_URL='https://backup.example.com/it'\''s/agent.bin'`))
+
+	script, err = renderBackupAgentInstallScript(backupAgentScriptTypePowerShell, "server-1", clientDetail)
+	if err != nil {
+		test.Fatal(err)
+	}
+	testAssert.IsTrue("PowerShell quoting", strings.Contains(script, `$downloadUrl = 'https://backup.example.com/it''s/agent.bin'`))
+}
+
+// No script is rendered for a client without a download URL.
+func TestRenderBackupAgentInstallScriptNoDownloadURL(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	script, err := renderBackupAgentInstallScript(backupAgentScriptTypeShell, "server-1",
+		testBackupClientDetail("FA.Linux", ""),
+	)
+	testAssert.IsTrue("err == nil", err == nil)
+	testAssert.EqualsString("script", "", script)
+}
@@ -0,0 +1,111 @@
+package ddcloud
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	dataSourceKeyServerSnapshotsServerID                 = "server"
+	dataSourceKeyServerSnapshotsSnapshots                = "snapshots"
+	dataSourceKeyServerSnapshotsSnapshotID               = "id"
+	dataSourceKeyServerSnapshotsSnapshotType             = "type"
+	dataSourceKeyServerSnapshotsSnapshotState            = "state"
+	dataSourceKeyServerSnapshotsSnapshotStartTime        = "start_time"
+	dataSourceKeyServerSnapshotsSnapshotExpiryTime       = "expiry_time"
+	dataSourceKeyServerSnapshotsSnapshotConsistencyLevel = "consistency_level"
+)
+
+func dataSourceServerSnapshots() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerSnapshotsRead,
+
+		Schema: map[string]*schema.Schema{
+			dataSourceKeyServerSnapshotsServerID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The Id of the server whose snapshots are listed",
+			},
+			dataSourceKeyServerSnapshotsSnapshots: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The server's snapshots (most recent first)",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						dataSourceKeyServerSnapshotsSnapshotID: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The snapshot Id (as used by restore_from_snapshot on ddcloud_server)",
+						},
+						dataSourceKeyServerSnapshotsSnapshotType: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The snapshot type (MANUAL or SYSTEM)",
+						},
+						dataSourceKeyServerSnapshotsSnapshotState: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The snapshot's current state",
+						},
+						dataSourceKeyServerSnapshotsSnapshotStartTime: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The date / time when the snapshot was started",
+						},
+						dataSourceKeyServerSnapshotsSnapshotExpiryTime: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The date / time when the snapshot will expire",
+						},
+						dataSourceKeyServerSnapshotsSnapshotConsistencyLevel: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The snapshot's consistency level",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read a server snapshots data source.
+func dataSourceServerSnapshotsRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	serverID := data.Get(dataSourceKeyServerSnapshotsServerID).(string)
+	log.Printf("Read snapshots of server '%s'.", serverID)
+
+	snapshots, err := listServerSnapshots(ctx, provider.(*providerState), serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Found %d snapshots of server '%s'.", len(snapshots), serverID)
+
+	data.SetId(serverID)
+	err = data.Set(dataSourceKeyServerSnapshotsSnapshots, flattenServerSnapshots(snapshots))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// Convert server snapshots to their state representation.
+func flattenServerSnapshots(snapshots []serverSnapshot) []interface{} {
+	snapshotProperties := make([]interface{}, len(snapshots))
+	for index, snapshot := range snapshots {
+		snapshotProperties[index] = map[string]interface{}{
+			dataSourceKeyServerSnapshotsSnapshotID:               snapshot.ID,
+			dataSourceKeyServerSnapshotsSnapshotType:             snapshot.Type,
+			dataSourceKeyServerSnapshotsSnapshotState:            snapshot.State,
+			dataSourceKeyServerSnapshotsSnapshotStartTime:        snapshot.StartTime,
+			dataSourceKeyServerSnapshotsSnapshotExpiryTime:       snapshot.ExpiryTime,
+			dataSourceKeyServerSnapshotsSnapshotConsistencyLevel: snapshot.ConsistencyLevel,
+		}
+	}
+
+	return snapshotProperties
+}
@@ -0,0 +1,122 @@
+package ddcloud
+
+import (
+	"context"
+	"log"
+
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	dataSourceKeyVIPHealthMonitorsNetworkDomainID      = "networkdomain"
+	dataSourceKeyVIPHealthMonitorsHealthMonitors       = "health_monitors"
+	dataSourceKeyVIPHealthMonitorsHealthMonitorID      = "id"
+	dataSourceKeyVIPHealthMonitorsHealthMonitorName    = "name"
+	dataSourceKeyVIPHealthMonitorsHealthMonitorForNode = "node_compatible"
+	dataSourceKeyVIPHealthMonitorsHealthMonitorForPool = "pool_compatible"
+)
+
+func dataSourceVIPHealthMonitors() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVIPHealthMonitorsRead,
+
+		Schema: map[string]*schema.Schema{
+			dataSourceKeyVIPHealthMonitorsNetworkDomainID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The Id of the network domain whose available health monitors are listed",
+			},
+			dataSourceKeyVIPHealthMonitorsHealthMonitors: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The health monitors available in the network domain",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						dataSourceKeyVIPHealthMonitorsHealthMonitorID: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The health monitor Id",
+						},
+						dataSourceKeyVIPHealthMonitorsHealthMonitorName: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The health monitor name (as used by ddcloud_vip_node and ddcloud_vip_pool)",
+						},
+						dataSourceKeyVIPHealthMonitorsHealthMonitorForNode: &schema.Schema{
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Can the health monitor be used by a VIP node?",
+						},
+						dataSourceKeyVIPHealthMonitorsHealthMonitorForPool: &schema.Schema{
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Can the health monitor be used by a VIP pool?",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read a VIP health monitors data source.
+func dataSourceVIPHealthMonitorsRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	networkDomainID := data.Get(dataSourceKeyVIPHealthMonitorsNetworkDomainID).(string)
+	log.Printf("Read health monitors available in network domain '%s'.", networkDomainID)
+
+	apiClient := provider.(*providerState).Client()
+
+	healthMonitors, err := listHealthMonitors(networkDomainID, apiClient)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Found %d health monitors in network domain '%s'.", len(healthMonitors), networkDomainID)
+
+	data.SetId(networkDomainID)
+	err = data.Set(dataSourceKeyVIPHealthMonitorsHealthMonitors, flattenHealthMonitors(healthMonitors))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// List all health monitors available in the specified network domain.
+func listHealthMonitors(networkDomainID string, apiClient *compute.Client) ([]compute.HealthMonitor, error) {
+	var healthMonitors []compute.HealthMonitor
+
+	page := compute.DefaultPaging()
+	for {
+		healthMonitorPage, err := apiClient.ListDefaultHealthMonitors(networkDomainID, page)
+		if err != nil {
+			return nil, err
+		}
+		if healthMonitorPage.IsEmpty() {
+			break
+		}
+
+		healthMonitors = append(healthMonitors, healthMonitorPage.Items...)
+
+		page.Next()
+	}
+
+	return healthMonitors, nil
+}
+
+// Convert health monitors to their state representation.
+func flattenHealthMonitors(healthMonitors []compute.HealthMonitor) []interface{} {
+	healthMonitorProperties := make([]interface{}, len(healthMonitors))
+	for index, healthMonitor := range healthMonitors {
+		healthMonitorProperties[index] = map[string]interface{}{
+			dataSourceKeyVIPHealthMonitorsHealthMonitorID:      healthMonitor.ID,
+			dataSourceKeyVIPHealthMonitorsHealthMonitorName:    healthMonitor.Name,
+			dataSourceKeyVIPHealthMonitorsHealthMonitorForNode: healthMonitor.IsNodeCompatible,
+			dataSourceKeyVIPHealthMonitorsHealthMonitorForPool: healthMonitor.IsPoolCompatible,
+		}
+	}
+
+	return healthMonitorProperties
+}
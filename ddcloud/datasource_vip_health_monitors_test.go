@@ -0,0 +1,78 @@
+package ddcloud
+
+import (
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+/*
+ * Acceptance-test configurations.
+ */
+
+func testAccDDCloudVIPHealthMonitorsDSBasic() string {
+	return `
+		provider "ddcloud" {
+			region		= "AU"
+		}
+
+		resource "ddcloud_networkdomain" "acc_test_domain" {
+			name		= "acc-test-networkdomain"
+			description	= "Network domain for Terraform acceptance test."
+			datacenter	= "AU9"
+
+			plan		= "ADVANCED"
+		}
+
+		data "ddcloud_vip_health_monitors" "acc_ds_test_monitors" {
+			networkdomain	= "${ddcloud_networkdomain.acc_test_domain.id}"
+		}`
+}
+
+/*
+ * Acceptance tests.
+ */
+
+// Acceptance test for ddcloud_vip_health_monitors data-source (basic):
+//
+// Create a network domain and list its available health monitors.
+func TestAccVIPHealthMonitorsDSBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckDDCloudNetworkDomainDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDDCloudVIPHealthMonitorsDSBasic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.ddcloud_vip_health_monitors.acc_ds_test_monitors", "health_monitors.0.id"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.ddcloud_vip_health_monitors.acc_ds_test_monitors", "health_monitors.*", map[string]string{
+						"name": "CCDEFAULT.Http",
+					}),
+				),
+			},
+		},
+	})
+}
+
+/*
+ * Unit tests.
+ */
+
+// Unit test - health monitors are converted to their state representation.
+func TestFlattenHealthMonitors(test *testing.T) {
+	healthMonitorProperties := flattenHealthMonitors([]compute.HealthMonitor{
+		compute.HealthMonitor{ID: "monitor-1", Name: "CCDEFAULT.Http", IsNodeCompatible: false, IsPoolCompatible: true},
+		compute.HealthMonitor{ID: "monitor-2", Name: "CCDEFAULT.Icmp", IsNodeCompatible: true, IsPoolCompatible: false},
+	})
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("HealthMonitors.Length", 2, len(healthMonitorProperties))
+
+	healthMonitor := healthMonitorProperties[1].(map[string]interface{})
+	assert.Equals("HealthMonitors[1].id", "monitor-2", healthMonitor[dataSourceKeyVIPHealthMonitorsHealthMonitorID])
+	assert.Equals("HealthMonitors[1].name", "CCDEFAULT.Icmp", healthMonitor[dataSourceKeyVIPHealthMonitorsHealthMonitorName])
+	assert.Equals("HealthMonitors[1].node_compatible", true, healthMonitor[dataSourceKeyVIPHealthMonitorsHealthMonitorForNode])
+	assert.Equals("HealthMonitors[1].pool_compatible", false, healthMonitor[dataSourceKeyVIPHealthMonitorsHealthMonitorForPool])
+}
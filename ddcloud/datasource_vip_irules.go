@@ -0,0 +1,168 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	dataSourceKeyVIPIRulesNetworkDomainID              = "networkdomain"
+	dataSourceKeyVIPIRulesIRules                       = "irules"
+	dataSourceKeyVIPIRulesIRuleID                      = "id"
+	dataSourceKeyVIPIRulesIRuleName                    = "name"
+	dataSourceKeyVIPIRulesIRuleVirtualListenerType     = "virtual_listener_type"
+	dataSourceKeyVIPIRulesIRuleVirtualListenerProtocol = "virtual_listener_protocol"
+)
+
+func dataSourceVIPIRules() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVIPIRulesRead,
+
+		Schema: map[string]*schema.Schema{
+			dataSourceKeyVIPIRulesNetworkDomainID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The Id of the network domain whose available iRules are listed",
+			},
+			dataSourceKeyVIPIRulesIRules: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The iRules available in the network domain",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						dataSourceKeyVIPIRulesIRuleID: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The iRule Id",
+						},
+						dataSourceKeyVIPIRulesIRuleName: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The iRule name (as used by ddcloud_virtual_listener)",
+						},
+						dataSourceKeyVIPIRulesIRuleVirtualListenerType: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of virtual listener that the iRule is compatible with (empty if not restricted)",
+						},
+						dataSourceKeyVIPIRulesIRuleVirtualListenerProtocol: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The virtual listener protocol that the iRule is compatible with (empty if not restricted)",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read a VIP iRules data source.
+func dataSourceVIPIRulesRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	networkDomainID := data.Get(dataSourceKeyVIPIRulesNetworkDomainID).(string)
+	log.Printf("Read iRules available in network domain '%s'.", networkDomainID)
+
+	apiClient := provider.(*providerState).Client()
+
+	iRules, err := listIRules(networkDomainID, apiClient)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Found %d iRules in network domain '%s'.", len(iRules), networkDomainID)
+
+	data.SetId(networkDomainID)
+	err = data.Set(dataSourceKeyVIPIRulesIRules, flattenIRules(iRules))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// List all iRules available in the specified network domain.
+func listIRules(networkDomainID string, apiClient *compute.Client) ([]compute.IRule, error) {
+	var iRules []compute.IRule
+
+	page := compute.DefaultPaging()
+	for {
+		iRulePage, err := apiClient.ListDefaultIRules(networkDomainID, page)
+		if err != nil {
+			return nil, err
+		}
+		if iRulePage.IsEmpty() {
+			break
+		}
+
+		iRules = append(iRules, iRulePage.Items...)
+
+		page.Next()
+	}
+
+	return iRules, nil
+}
+
+// Find the iRules with the specified names.
+//
+// Returns the names of any iRules that could not be found.
+func findIRulesByName(iRules []compute.IRule, names []string) (matchingIRules []compute.IRule, missingNames []string) {
+	iRulesByName := make(map[string]compute.IRule)
+	for _, iRule := range iRules {
+		iRulesByName[iRule.Name] = iRule
+	}
+
+	for _, name := range names {
+		iRule, ok := iRulesByName[name]
+		if !ok {
+			missingNames = append(missingNames, name)
+
+			continue
+		}
+
+		matchingIRules = append(matchingIRules, iRule)
+	}
+
+	return
+}
+
+// Validate that the named iRules are available, and are compatible with a virtual listener's type and protocol.
+func validateVirtualListenerIRules(names []string, iRules []compute.IRule, listenerType string, listenerProtocol string) (errors []error) {
+	matchingIRules, missingNames := findIRulesByName(iRules, names)
+	for _, missingName := range missingNames {
+		errors = append(errors, fmt.Errorf("%s: no iRule named '%s' is available in the network domain",
+			resourceKeyVirtualListenerIRuleNames, missingName,
+		))
+	}
+
+	for _, iRule := range matchingIRules {
+		if !isVirtualListenerCompatible(listenerType, listenerProtocol, iRule.VirtualListenerType, iRule.VirtualListenerProtocol) {
+			errors = append(errors, fmt.Errorf("%s: iRule '%s' is only compatible with %s virtual listeners using protocol %s (listener is %s, using protocol %s)",
+				resourceKeyVirtualListenerIRuleNames, iRule.Name,
+				iRule.VirtualListenerType, iRule.VirtualListenerProtocol,
+				listenerType, listenerProtocol,
+			))
+		}
+	}
+
+	return
+}
+
+// Convert iRules to their state representation.
+func flattenIRules(iRules []compute.IRule) []interface{} {
+	iRuleProperties := make([]interface{}, len(iRules))
+	for index, iRule := range iRules {
+		iRuleProperties[index] = map[string]interface{}{
+			dataSourceKeyVIPIRulesIRuleID:                      iRule.ID,
+			dataSourceKeyVIPIRulesIRuleName:                    iRule.Name,
+			dataSourceKeyVIPIRulesIRuleVirtualListenerType:     iRule.VirtualListenerType,
+			dataSourceKeyVIPIRulesIRuleVirtualListenerProtocol: iRule.VirtualListenerProtocol,
+		}
+	}
+
+	return iRuleProperties
+}
@@ -0,0 +1,43 @@
+package ddcloud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+)
+
+// Unit test - iRules must exist and be compatible with the virtual listener.
+func TestValidateVirtualListenerIRules(test *testing.T) {
+	iRules := []compute.IRule{
+		compute.IRule{ID: "irule-1", Name: "CCDEFAULT.HttpsRedirect", VirtualListenerType: compute.VirtualListenerTypeStandard, VirtualListenerProtocol: "HTTP"},
+		compute.IRule{ID: "irule-2", Name: "CCDEFAULT.IpProtocolTimers", VirtualListenerType: compute.VirtualListenerTypeStandard},
+	}
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Compatible iRules", 0, len(
+		validateVirtualListenerIRules([]string{"CCDEFAULT.HttpsRedirect", "CCDEFAULT.IpProtocolTimers"}, iRules, compute.VirtualListenerTypeStandard, "HTTP"),
+	))
+
+	errors := validateVirtualListenerIRules([]string{"CCDEFAULT.HttpsRedirect", "CCDEFAULT.IpProtocolTimers", "CCDEFAULT.Missing"}, iRules, compute.VirtualListenerTypePerformanceLayer4, "HTTP")
+	assert.EqualsInt("Errors.Length", 3, len(errors))
+	assert.IsTrue("Errors[0] is for missing iRule", strings.Contains(errors[0].Error(), "no iRule named 'CCDEFAULT.Missing'"))
+	assert.IsTrue("Errors[1] is for incompatible iRule", strings.Contains(errors[1].Error(), "iRule 'CCDEFAULT.HttpsRedirect' is only compatible"))
+}
+
+// Unit test - iRules are looked up by name.
+func TestFindIRulesByName(test *testing.T) {
+	iRules := []compute.IRule{
+		compute.IRule{ID: "irule-1", Name: "CCDEFAULT.HttpsRedirect"},
+		compute.IRule{ID: "irule-2", Name: "CCDEFAULT.IpProtocolTimers"},
+	}
+
+	matchingIRules, missingNames := findIRulesByName(iRules, []string{"CCDEFAULT.IpProtocolTimers", "CCDEFAULT.Missing"})
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("MatchingIRules.Length", 1, len(matchingIRules))
+	assert.EqualsString("MatchingIRules[0].ID", "irule-2", matchingIRules[0].ID)
+	assert.EqualsInt("MissingNames.Length", 1, len(missingNames))
+	assert.EqualsString("MissingNames[0]", "CCDEFAULT.Missing", missingNames[0])
+}
@@ -0,0 +1,172 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	dataSourceKeyVIPPersistenceProfilesNetworkDomainID                = "networkdomain"
+	dataSourceKeyVIPPersistenceProfilesProfiles                       = "persistence_profiles"
+	dataSourceKeyVIPPersistenceProfilesProfileID                      = "id"
+	dataSourceKeyVIPPersistenceProfilesProfileName                    = "name"
+	dataSourceKeyVIPPersistenceProfilesProfileFallbackCompatible      = "fallback_compatible"
+	dataSourceKeyVIPPersistenceProfilesProfileVirtualListenerType     = "virtual_listener_type"
+	dataSourceKeyVIPPersistenceProfilesProfileVirtualListenerProtocol = "virtual_listener_protocol"
+)
+
+func dataSourceVIPPersistenceProfiles() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVIPPersistenceProfilesRead,
+
+		Schema: map[string]*schema.Schema{
+			dataSourceKeyVIPPersistenceProfilesNetworkDomainID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The Id of the network domain whose available persistence profiles are listed",
+			},
+			dataSourceKeyVIPPersistenceProfilesProfiles: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The persistence profiles available in the network domain",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						dataSourceKeyVIPPersistenceProfilesProfileID: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The persistence profile Id",
+						},
+						dataSourceKeyVIPPersistenceProfilesProfileName: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The persistence profile name (as used by ddcloud_virtual_listener)",
+						},
+						dataSourceKeyVIPPersistenceProfilesProfileFallbackCompatible: &schema.Schema{
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Can the persistence profile be used as a fallback persistence profile?",
+						},
+						dataSourceKeyVIPPersistenceProfilesProfileVirtualListenerType: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of virtual listener that the persistence profile is compatible with (empty if not restricted)",
+						},
+						dataSourceKeyVIPPersistenceProfilesProfileVirtualListenerProtocol: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The virtual listener protocol that the persistence profile is compatible with (empty if not restricted)",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read a VIP persistence profiles data source.
+func dataSourceVIPPersistenceProfilesRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	networkDomainID := data.Get(dataSourceKeyVIPPersistenceProfilesNetworkDomainID).(string)
+	log.Printf("Read persistence profiles available in network domain '%s'.", networkDomainID)
+
+	apiClient := provider.(*providerState).Client()
+
+	persistenceProfiles, err := listPersistenceProfiles(networkDomainID, apiClient)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Found %d persistence profiles in network domain '%s'.", len(persistenceProfiles), networkDomainID)
+
+	data.SetId(networkDomainID)
+	err = data.Set(dataSourceKeyVIPPersistenceProfilesProfiles, flattenPersistenceProfiles(persistenceProfiles))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// List all persistence profiles available in the specified network domain.
+func listPersistenceProfiles(networkDomainID string, apiClient *compute.Client) ([]compute.PersistenceProfile, error) {
+	var persistenceProfiles []compute.PersistenceProfile
+
+	page := compute.DefaultPaging()
+	for {
+		persistenceProfilePage, err := apiClient.ListDefaultPersistenceProfiles(networkDomainID, page)
+		if err != nil {
+			return nil, err
+		}
+		if persistenceProfilePage.IsEmpty() {
+			break
+		}
+
+		persistenceProfiles = append(persistenceProfiles, persistenceProfilePage.Items...)
+
+		page.Next()
+	}
+
+	return persistenceProfiles, nil
+}
+
+// Find the persistence profile with the specified name (nil if not found).
+func findPersistenceProfileByName(persistenceProfiles []compute.PersistenceProfile, name string) *compute.PersistenceProfile {
+	for index := range persistenceProfiles {
+		if persistenceProfiles[index].Name == name {
+			return &persistenceProfiles[index]
+		}
+	}
+
+	return nil
+}
+
+// Validate that the named persistence profile is available, and is compatible with a virtual listener's type and protocol.
+func validateVirtualListenerPersistenceProfile(name string, persistenceProfiles []compute.PersistenceProfile, listenerType string, listenerProtocol string) error {
+	persistenceProfile := findPersistenceProfileByName(persistenceProfiles, name)
+	if persistenceProfile == nil {
+		return fmt.Errorf("%s: no persistence profile named '%s' is available in the network domain",
+			resourceKeyVirtualListenerPersistenceProfileName, name,
+		)
+	}
+
+	if !isVirtualListenerCompatible(listenerType, listenerProtocol, persistenceProfile.VirtualListenerType, persistenceProfile.VirtualListenerProtocol) {
+		return fmt.Errorf("%s: persistence profile '%s' is only compatible with %s virtual listeners using protocol %s (listener is %s, using protocol %s)",
+			resourceKeyVirtualListenerPersistenceProfileName, name,
+			persistenceProfile.VirtualListenerType, persistenceProfile.VirtualListenerProtocol,
+			listenerType, listenerProtocol,
+		)
+	}
+
+	return nil
+}
+
+// Determine whether a virtual listener's type and protocol match the type and protocol supported by a persistence profile or iRule.
+//
+// An empty compatible type or protocol means that the type or protocol is not restricted.
+func isVirtualListenerCompatible(listenerType string, listenerProtocol string, compatibleType string, compatibleProtocol string) bool {
+	if compatibleType != "" && compatibleType != listenerType {
+		return false
+	}
+
+	return compatibleProtocol == "" || compatibleProtocol == listenerProtocol
+}
+
+// Convert persistence profiles to their state representation.
+func flattenPersistenceProfiles(persistenceProfiles []compute.PersistenceProfile) []interface{} {
+	persistenceProfileProperties := make([]interface{}, len(persistenceProfiles))
+	for index, persistenceProfile := range persistenceProfiles {
+		persistenceProfileProperties[index] = map[string]interface{}{
+			dataSourceKeyVIPPersistenceProfilesProfileID:                      persistenceProfile.ID,
+			dataSourceKeyVIPPersistenceProfilesProfileName:                    persistenceProfile.Name,
+			dataSourceKeyVIPPersistenceProfilesProfileFallbackCompatible:      persistenceProfile.IsFallbackCompatible,
+			dataSourceKeyVIPPersistenceProfilesProfileVirtualListenerType:     persistenceProfile.VirtualListenerType,
+			dataSourceKeyVIPPersistenceProfilesProfileVirtualListenerProtocol: persistenceProfile.VirtualListenerProtocol,
+		}
+	}
+
+	return persistenceProfileProperties
+}
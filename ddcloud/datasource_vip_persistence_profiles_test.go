@@ -0,0 +1,31 @@
+package ddcloud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+)
+
+// Unit test - persistence profiles must exist and be compatible with the virtual listener.
+func TestValidateVirtualListenerPersistenceProfile(test *testing.T) {
+	persistenceProfiles := []compute.PersistenceProfile{
+		compute.PersistenceProfile{ID: "profile-1", Name: "CCDEFAULT.Cookie", VirtualListenerType: compute.VirtualListenerTypeStandard, VirtualListenerProtocol: "HTTP"},
+		compute.PersistenceProfile{ID: "profile-2", Name: "CCDEFAULT.SourceAddress"},
+	}
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("Compatible profile is valid",
+		validateVirtualListenerPersistenceProfile("CCDEFAULT.Cookie", persistenceProfiles, compute.VirtualListenerTypeStandard, "HTTP") == nil,
+	)
+	assert.IsTrue("Unrestricted profile is valid",
+		validateVirtualListenerPersistenceProfile("CCDEFAULT.SourceAddress", persistenceProfiles, compute.VirtualListenerTypePerformanceLayer4, "TCP") == nil,
+	)
+
+	err := validateVirtualListenerPersistenceProfile("CCDEFAULT.Cookie", persistenceProfiles, compute.VirtualListenerTypeStandard, "TCP")
+	assert.IsTrue("Incompatible profile is invalid", err != nil && strings.Contains(err.Error(), "only compatible with STANDARD virtual listeners using protocol HTTP"))
+
+	err = validateVirtualListenerPersistenceProfile("CCDEFAULT.Missing", persistenceProfiles, compute.VirtualListenerTypeStandard, "HTTP")
+	assert.IsTrue("Unknown profile is invalid", err != nil && strings.HasPrefix(err.Error(), "persistence_profile: no persistence profile named 'CCDEFAULT.Missing'"))
+}
@@ -1,15 +1,16 @@
 package ddcloud
 
 import (
-	"fmt"
+	"context"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceVLAN() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVLANRead,
+		ReadContext: dataSourceVLANRead,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyVLANName: &schema.Schema{
@@ -62,7 +63,7 @@ func dataSourceVLAN() *schema.Resource {
 }
 
 // Read a network domain data source.
-func dataSourceVLANRead(data *schema.ResourceData, provider interface{}) error {
+func dataSourceVLANRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	name := data.Get(resourceKeyVLANName).(string)
 	networkDomainID := data.Get(resourceKeyVLANNetworkDomainID).(string)
 
@@ -72,7 +73,7 @@ func dataSourceVLANRead(data *schema.ResourceData, provider interface{}) error {
 
 	vlan, err := apiClient.GetVLANByName(name, networkDomainID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if vlan != nil {
@@ -85,7 +86,7 @@ func dataSourceVLANRead(data *schema.ResourceData, provider interface{}) error {
 		data.Set(resourceKeyVLANIPv6GatewayAddress, vlan.IPv6GatewayAddress)
 
 	} else {
-		return fmt.Errorf("failed to find VLAN '%s' in network domain '%s'", name, networkDomainID)
+		return diag.Errorf("failed to find VLAN '%s' in network domain '%s'", name, networkDomainID)
 	}
 
 	return nil
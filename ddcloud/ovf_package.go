@@ -0,0 +1,237 @@
+package ddcloud
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+	"github.com/pkg/errors"
+)
+
+const ovfPackageTransferTimeout = 30 * time.Second
+
+// ovfPackageTransfer represents a file-transfer end-point (e.g. a datacenter's FTPS host) used to upload / download OVF packages.
+type ovfPackageTransfer interface {
+	// List the names of the files on the end-point.
+	List() ([]string, error)
+
+	// Upload a local file to the end-point.
+	Upload(localFile string, remoteFileName string) error
+
+	// Download a file from the end-point.
+	Download(remoteFileName string, localFile string) error
+
+	// Close the connection to the end-point.
+	Close() error
+}
+
+// Create an ovfPackageTransfer for the specified host.
+//
+// Overridden in tests to use a local stand-in for the FTPS host.
+var newOVFPackageTransfer = newFTPSOVFPackageTransfer
+
+// getOVFPackageManifestFileName gets the name of the manifest file for the OVF package with the specified prefix.
+func getOVFPackageManifestFileName(ovfPackagePrefix string) string {
+	return ovfPackagePrefix + ".mf"
+}
+
+// isOVFPackageFile determines whether the specified file name belongs to the OVF package with the specified prefix.
+//
+// Package files are named "<prefix>.<extension>" or "<prefix>-<suffix>" (so, for example, "image10.ovf" does not belong to the package "image1").
+func isOVFPackageFile(fileName string, ovfPackagePrefix string) bool {
+	if strings.HasSuffix(fileName, "/") {
+		return false
+	}
+
+	return strings.HasPrefix(fileName, ovfPackagePrefix+".") || strings.HasPrefix(fileName, ovfPackagePrefix+"-")
+}
+
+// findLocalOVFPackageFiles finds the names of the files in a local directory that belong to the OVF package with the specified prefix.
+//
+// Returns an error if the package's manifest (<prefix>.mf) is not present.
+func findLocalOVFPackageFiles(directory string, ovfPackagePrefix string) (fileNames []string, err error) {
+	entries, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	haveManifest := false
+	for _, entry := range entries {
+		if entry.IsDir() || !isOVFPackageFile(entry.Name(), ovfPackagePrefix) {
+			continue
+		}
+		if entry.Name() == getOVFPackageManifestFileName(ovfPackagePrefix) {
+			haveManifest = true
+		}
+
+		fileNames = append(fileNames, entry.Name())
+	}
+	if !haveManifest {
+		return nil, fmt.Errorf("cannot find manifest '%s' for OVF package '%s' in directory '%s'",
+			getOVFPackageManifestFileName(ovfPackagePrefix),
+			ovfPackagePrefix,
+			directory,
+		)
+	}
+	sort.Strings(fileNames)
+
+	return
+}
+
+// uploadOVFPackage uploads the files for the OVF package with the specified prefix from a local directory.
+func uploadOVFPackage(transfer ovfPackageTransfer, directory string, ovfPackagePrefix string) (uploadedFileNames []string, err error) {
+	fileNames, err := findLocalOVFPackageFiles(directory, ovfPackagePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, fileName := range fileNames {
+		log.Printf("Uploading file '%s' (%d of %d) for OVF package '%s'...", fileName, index+1, len(fileNames), ovfPackagePrefix)
+
+		err = transfer.Upload(filepath.Join(directory, fileName), fileName)
+		if err != nil {
+			return uploadedFileNames, errors.Wrapf(err, "failed to upload file '%s' for OVF package '%s'", fileName, ovfPackagePrefix)
+		}
+
+		uploadedFileNames = append(uploadedFileNames, fileName)
+	}
+
+	log.Printf("Uploaded %d files for OVF package '%s'.", len(uploadedFileNames), ovfPackagePrefix)
+
+	return
+}
+
+// downloadOVFPackage downloads the files for the OVF package with the specified prefix to a local directory.
+func downloadOVFPackage(transfer ovfPackageTransfer, ovfPackagePrefix string, directory string) (downloadedFileNames []string, err error) {
+	remoteFileNames, err := transfer.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var fileNames []string
+	for _, remoteFileName := range remoteFileNames {
+		if isOVFPackageFile(remoteFileName, ovfPackagePrefix) {
+			fileNames = append(fileNames, remoteFileName)
+		}
+	}
+	if len(fileNames) == 0 {
+		return nil, fmt.Errorf("cannot find any files for OVF package '%s'", ovfPackagePrefix)
+	}
+	sort.Strings(fileNames)
+
+	err = os.MkdirAll(directory, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, fileName := range fileNames {
+		log.Printf("Downloading file '%s' (%d of %d) for OVF package '%s'...", fileName, index+1, len(fileNames), ovfPackagePrefix)
+
+		err = transfer.Download(fileName, filepath.Join(directory, fileName))
+		if err != nil {
+			return downloadedFileNames, errors.Wrapf(err, "failed to download file '%s' for OVF package '%s'", fileName, ovfPackagePrefix)
+		}
+
+		downloadedFileNames = append(downloadedFileNames, fileName)
+	}
+
+	log.Printf("Downloaded %d files for OVF package '%s'.", len(downloadedFileNames), ovfPackagePrefix)
+
+	return
+}
+
+// ftpsOVFPackageTransfer uploads / downloads OVF packages to / from a datacenter's FTPS host.
+type ftpsOVFPackageTransfer struct {
+	connection *ftp.ServerConn
+}
+
+// Connect to a datacenter's FTPS host (using explicit TLS).
+func newFTPSOVFPackageTransfer(host string, username string, password string) (ovfPackageTransfer, error) {
+	log.Printf("Connecting to FTPS host '%s'...", host)
+
+	address := host
+	if !strings.Contains(address, ":") {
+		address += ":21"
+	}
+
+	connection, err := ftp.Dial(address,
+		ftp.DialWithTimeout(ovfPackageTransferTimeout),
+		ftp.DialWithExplicitTLS(&tls.Config{
+			ServerName: host,
+		}),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to FTPS host '%s'", host)
+	}
+
+	err = connection.Login(username, password)
+	if err != nil {
+		connection.Quit()
+
+		return nil, errors.Wrapf(err, "failed to log in to FTPS host '%s'", host)
+	}
+
+	return &ftpsOVFPackageTransfer{
+		connection: connection,
+	}, nil
+}
+
+// List the names of the files on the FTPS host.
+func (transfer *ftpsOVFPackageTransfer) List() ([]string, error) {
+	entries, err := transfer.connection.List("")
+	if err != nil {
+		return nil, err
+	}
+
+	var fileNames []string
+	for _, entry := range entries {
+		if entry.Type == ftp.EntryTypeFile {
+			fileNames = append(fileNames, entry.Name)
+		}
+	}
+
+	return fileNames, nil
+}
+
+// Upload a local file to the FTPS host.
+func (transfer *ftpsOVFPackageTransfer) Upload(localFile string, remoteFileName string) error {
+	file, err := os.Open(localFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return transfer.connection.Stor(remoteFileName, file)
+}
+
+// Download a file from the FTPS host.
+func (transfer *ftpsOVFPackageTransfer) Download(remoteFileName string, localFile string) error {
+	response, err := transfer.connection.Retr(remoteFileName)
+	if err != nil {
+		return err
+	}
+	defer response.Close()
+
+	file, err := os.Create(localFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, response)
+
+	return err
+}
+
+// Close the connection to the FTPS host.
+func (transfer *ftpsOVFPackageTransfer) Close() error {
+	return transfer.connection.Quit()
+}
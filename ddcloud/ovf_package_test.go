@@ -0,0 +1,146 @@
+package ddcloud
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+)
+
+/*
+ * A local stand-in for a datacenter's FTPS host.
+ */
+
+const testCloudControlFTPSHost = "ftps-test.example.com"
+
+// testOVFPackageTransfer is an in-memory stand-in for a datacenter's FTPS host.
+type testOVFPackageTransfer struct {
+	Host  string
+	Files map[string]string
+}
+
+func newTestOVFPackageTransfer() *testOVFPackageTransfer {
+	return &testOVFPackageTransfer{
+		Files: make(map[string]string),
+	}
+}
+
+// Use the stand-in (instead of connecting to a real FTPS host) until the returned function is called.
+func (transfer *testOVFPackageTransfer) Install() (restore func()) {
+	previous := newOVFPackageTransfer
+	newOVFPackageTransfer = func(host string, username string, password string) (ovfPackageTransfer, error) {
+		transfer.Host = host
+
+		return transfer, nil
+	}
+
+	return func() {
+		newOVFPackageTransfer = previous
+	}
+}
+
+func (transfer *testOVFPackageTransfer) List() (fileNames []string, err error) {
+	for fileName := range transfer.Files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	return
+}
+
+func (transfer *testOVFPackageTransfer) Upload(localFile string, remoteFileName string) error {
+	content, err := ioutil.ReadFile(localFile)
+	if err != nil {
+		return err
+	}
+	transfer.Files[remoteFileName] = string(content)
+
+	return nil
+}
+
+func (transfer *testOVFPackageTransfer) Download(remoteFileName string, localFile string) error {
+	content, ok := transfer.Files[remoteFileName]
+	if !ok {
+		return fmt.Errorf("file '%s' not found", remoteFileName)
+	}
+
+	return ioutil.WriteFile(localFile, []byte(content), 0644)
+}
+
+func (transfer *testOVFPackageTransfer) Close() error {
+	return nil
+}
+
+var _ ovfPackageTransfer = &testOVFPackageTransfer{}
+
+// Create files with the specified names (content is the file name) in a directory.
+func testCreateFiles(test *testing.T, directory string, fileNames ...string) {
+	for _, fileName := range fileNames {
+		err := ioutil.WriteFile(filepath.Join(directory, fileName), []byte(fileName), 0644)
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+}
+
+/*
+ * Unit tests.
+ */
+
+// Unit test - only files matching the OVF package prefix are included.
+func TestFindLocalOVFPackageFiles(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	directory := test.TempDir()
+	testCreateFiles(test, directory, "pkg.ovf", "pkg.mf", "pkg-disk1.vmdk", "other.mf")
+	os.Mkdir(filepath.Join(directory, "pkg-subdirectory"), 0755)
+
+	fileNames, err := findLocalOVFPackageFiles(directory, "pkg")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	testAssert.Equals("fileNames", []string{"pkg-disk1.vmdk", "pkg.mf", "pkg.ovf"}, fileNames)
+}
+
+// Unit test - files belonging to another package whose name starts with the prefix are not included.
+func TestIsOVFPackageFile(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	testAssert.IsTrue("image1.mf", isOVFPackageFile("image1.mf", "image1"))
+	testAssert.IsTrue("image1-disk1.vmdk", isOVFPackageFile("image1-disk1.vmdk", "image1"))
+	testAssert.IsFalse("image10.mf", isOVFPackageFile("image10.mf", "image1"))
+	testAssert.IsFalse("image1", isOVFPackageFile("image1", "image1"))
+	testAssert.IsFalse("image1.d/", isOVFPackageFile("image1.d/", "image1"))
+}
+
+// Unit test - an OVF package without a manifest cannot be uploaded.
+func TestFindLocalOVFPackageFilesMissingManifest(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	directory := test.TempDir()
+	testCreateFiles(test, directory, "pkg.ovf", "pkg-disk1.vmdk")
+
+	_, err := findLocalOVFPackageFiles(directory, "pkg")
+	testAssert.IsTrue("err != nil", err != nil)
+	testAssert.IsTrue("error mentions manifest", strings.Contains(err.Error(), "pkg.mf"))
+}
+
+// Unit test - OVF package prefixes must be file names, not paths.
+func TestValidateOVFPackagePrefix(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	_, errors := validateOVFPackagePrefix("pkg", "ovf_package_prefix")
+	testAssert.EqualsInt("len(errors)", 0, len(errors))
+
+	_, errors = validateOVFPackagePrefix("", "ovf_package_prefix")
+	testAssert.EqualsInt("len(errors)", 1, len(errors))
+
+	_, errors = validateOVFPackagePrefix("dir/pkg", "ovf_package_prefix")
+	testAssert.EqualsInt("len(errors)", 1, len(errors))
+}
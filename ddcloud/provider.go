@@ -1,6 +1,7 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -11,12 +12,13 @@ import (
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // Provider creates the Dimension Data Cloud resource provider.
-func Provider() terraform.ResourceProvider {
+func Provider() *schema.Provider {
 	return &schema.Provider{
 		// Provider settings schema
 		Schema: map[string]*schema.Schema{
@@ -79,15 +81,36 @@ func Provider() terraform.ResourceProvider {
 			// A storage controller (e.g. SCSI controller) in a server
 			"ddcloud_storage_controller": resourceStorageController(),
 
+			// A disk in a server (independent of the disks declared on ddcloud_server or ddcloud_storage_controller).
+			"ddcloud_disk": resourceDisk(),
+
 			// A network adapter.
 			"ddcloud_network_adapter": resourceNetworkAdapter(),
 
 			// A server anti-affinity rule.
 			"ddcloud_server_anti_affinity": resourceAntiAffinityRule(),
 
+			// A group of servers that are kept apart by pairwise anti-affinity rules.
+			"ddcloud_server_anti_affinity_group": resourceServerAntiAffinityGroup(),
+
 			// Cloud Backup configuration for a server.
 			"ddcloud_server_backup": resourceServerBackup(),
 
+			// An on-demand backup job for a server's backup client.
+			"ddcloud_backup_job": resourceBackupJob(),
+
+			// A manual snapshot of a server.
+			"ddcloud_server_snapshot": resourceServerSnapshot(),
+
+			// A customer image (created by cloning a server).
+			"ddcloud_customer_image": resourceCustomerImage(),
+
+			// A customer image imported from an OVF package.
+			"ddcloud_customer_image_import": resourceCustomerImageImport(),
+
+			// An export of a customer image to an OVF package.
+			"ddcloud_customer_image_export": resourceCustomerImageExport(),
+
 			// A Network Address Translation (NAT) rule.
 			"ddcloud_nat": resourceNAT(),
 
@@ -115,6 +138,9 @@ func Provider() terraform.ResourceProvider {
 			// A virtual listener is the top-level entity for load-balancing functionality.
 			"ddcloud_virtual_listener": resourceVirtualListener(),
 
+			// A load balancer (virtual listener, VIP pool, nodes, and optional SSL offload, managed as a single unit).
+			"ddcloud_load_balancer": resourceLoadBalancer(),
+
 			// An SSL-offload profile for a virtual listener.
 			"ddcloud_ssl_offload_profile": resourceSSLOffloadProfile(),
 
@@ -143,16 +169,37 @@ func Provider() terraform.ResourceProvider {
 
 			// A addresslist.
 			"ddcloud_addresslist": dataSourceAddressList(),
+
+			// The snapshots of a server.
+			"ddcloud_server_snapshots": dataSourceServerSnapshots(),
+
+			// The health monitors available for VIP nodes and pools in a network domain.
+			"ddcloud_vip_health_monitors": dataSourceVIPHealthMonitors(),
+
+			// The persistence profiles available for virtual listeners in a network domain.
+			"ddcloud_vip_persistence_profiles": dataSourceVIPPersistenceProfiles(),
+
+			// The iRules available for virtual listeners in a network domain.
+			"ddcloud_vip_irules": dataSourceVIPIRules(),
+
+			// The Cloud Backup storage policies available for a server.
+			"ddcloud_backup_storage_policies": dataSourceBackupStoragePolicies(),
+
+			// The Cloud Backup schedule policies available for a server.
+			"ddcloud_backup_schedule_policies": dataSourceBackupSchedulePolicies(),
+
+			// Install scripts for a server's Cloud Backup agents.
+			"ddcloud_server_backup_agent": dataSourceServerBackupAgent(),
 		},
 
 		// Provider configuration
-		ConfigureFunc: configureProvider,
+		ConfigureContextFunc: configureProvider,
 	}
 }
 
 // Configure the provider.
 // Returns the provider's compute API client.
-func configureProvider(providerSettings *schema.ResourceData) (interface{}, error) {
+func configureProvider(ctx context.Context, providerSettings *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	// Log provider version (for diagnostic purposes).
 	// log.Print("ddcloud provider version is " + ProviderVersion)
 
@@ -163,14 +210,28 @@ func configureProvider(providerSettings *schema.ResourceData) (interface{}, erro
 	)
 	customEndPoint := providerSettings.Get("cloudcontrol_endpoint").(string)
 	if region == "" && customEndPoint == "" {
-		return nil, fmt.Errorf("neither the 'region' nor the 'cloudcontrol_endpoint' provider properties were specified (the 'ddcloud' provider requires exactly one of these properties to be configured)")
+		return nil, diag.Diagnostics{
+			diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "No CloudControl end-point was configured",
+				Detail:        "neither the 'region' nor the 'cloudcontrol_endpoint' provider properties were specified (the 'ddcloud' provider requires exactly one of these properties to be configured)",
+				AttributePath: cty.GetAttrPath("region"),
+			},
+		}
 	}
 
 	username := providerSettings.Get("username").(string)
 	if isEmpty(username) {
 		username = os.Getenv("MCP_USER")
 		if isEmpty(username) {
-			return nil, fmt.Errorf("the 'username' property was not specified for the 'ddcloud' provider, and the 'MCP_USER' environment variable is not present. Please supply either one of these to configure the user name used to authenticate to Dimension Data CloudControl")
+			return nil, diag.Diagnostics{
+				diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "No CloudControl user name was configured",
+					Detail:        "the 'username' property was not specified for the 'ddcloud' provider, and the 'MCP_USER' environment variable is not present. Please supply either one of these to configure the user name used to authenticate to Dimension Data CloudControl",
+					AttributePath: cty.GetAttrPath("username"),
+				},
+			}
 		}
 	}
 
@@ -178,16 +239,22 @@ func configureProvider(providerSettings *schema.ResourceData) (interface{}, erro
 	if isEmpty(password) {
 		password = os.Getenv("MCP_PASSWORD")
 		if isEmpty(password) {
-			return nil, fmt.Errorf("the 'password' property was not specified for the 'ddcloud' provider, and the 'MCP_PASSWORD' environment variable is not present. Please supply either one of these to configure the password used to authenticate to Dimension Data CloudControl")
+			return nil, diag.Diagnostics{
+				diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "No CloudControl password was configured",
+					Detail:        "the 'password' property was not specified for the 'ddcloud' provider, and the 'MCP_PASSWORD' environment variable is not present. Please supply either one of these to configure the password used to authenticate to Dimension Data CloudControl",
+					AttributePath: cty.GetAttrPath("password"),
+				},
+			}
 		}
 	}
 
-	var client *compute.Client
+	endPoint := customEndPoint
 	if region != "" {
-		client = compute.NewClient(region, username, password)
-	} else {
-		client = compute.NewClientWithBaseAddress(customEndPoint, username, password)
+		endPoint = fmt.Sprintf("https://api-%s.dimensiondata.com", region)
 	}
+	client := compute.NewClientWithBaseAddress(endPoint, username, password)
 
 	// Configure retry, if required.
 	retryCount := 0
@@ -209,6 +276,9 @@ func configureProvider(providerSettings *schema.ResourceData) (interface{}, erro
 		RetryDelay:         time.Duration(providerSettings.Get("retry_delay").(int)) * time.Second,
 		RetryTimeout:       time.Duration(providerSettings.Get("retry_timeout").(int)) * time.Second,
 		AllowServerReboots: providerSettings.Get("allow_server_reboot").(bool),
+		EndPoint:           endPoint,
+		Username:           username,
+		Password:           password,
 	}
 
 	// Override server reboot behaviour with environment variables, if required.
@@ -234,6 +304,15 @@ type ProviderSettings struct {
 
 	// The period of time before retrying of asynchronous operations time out.
 	RetryTimeout time.Duration
+
+	// The base address of the CloudControl API end-point (used for operations that are not yet supported by the CloudControl client library).
+	EndPoint string
+
+	// The user name used to authenticate to CloudControl (also used to upload / download OVF packages via FTPS).
+	Username string
+
+	// The password used to authenticate to CloudControl (also used to upload / download OVF packages via FTPS).
+	Password string
 }
 
 type providerState struct {
@@ -251,6 +330,9 @@ type providerState struct {
 
 	// Provider-global retry executor for asynchronous operations.
 	retry retry.Do
+
+	// The IPv4 addresses of virtual listeners that are being replaced, keyed by network domain Id and listener name.
+	replacedVirtualListenerIPv4Addresses map[string]string
 }
 
 func newProvider(client *compute.Client, settings *ProviderSettings) *providerState {
@@ -260,6 +342,8 @@ func newProvider(client *compute.Client, settings *ProviderSettings) *providerSt
 		stateLock:          &sync.Mutex{},
 		asyncOperationLock: &sync.Mutex{},
 		retry:              retry.NewDo(settings.RetryDelay),
+
+		replacedVirtualListenerIPv4Addresses: make(map[string]string),
 	}
 
 	return state
@@ -285,16 +369,17 @@ func (state *providerState) Retry() retry.Do {
 
 // RetryAction performs an action with retry using the provider's shared operation-retry executor.
 //
+// ctx is the context for the operation (if ctx is cancelled, no further attempts will be made).
 // description is a short description of the function used for logging.
 // timeout is the period of time before the process
 // action is the action function to invoke
 //
-// Returns the error (if any) passed to Context.Fail or caused by the operation timing out.
+// Returns the error (if any) passed to Context.Fail or caused by the operation timing out or being cancelled.
 //
 // Note that, for performance reasons, the executor is shared by all actions in the provider.
 // This means that the retry period is shared across all actions being perfomed.
-func (state *providerState) RetryAction(description string, action retry.ActionFunc) error {
-	return state.Retry().Action(description, state.Settings().RetryTimeout, action)
+func (state *providerState) RetryAction(ctx context.Context, description string, action retry.ActionFunc) error {
+	return state.Retry().Action(ctx, description, state.Settings().RetryTimeout, action)
 }
 
 // AcquireAsyncOperationLock acquires (locks) the global lock used to synchronise initiation of global operations.
@@ -1,10 +1,13 @@
 package ddcloud
 
 import (
+	"context"
+
 	"fmt"
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
 	"log"
 	"strings"
@@ -22,11 +25,10 @@ const (
 
 func resourceAddress() *schema.Resource {
 	return &schema.Resource{
-		Exists: resourceAddressExists,
-		Create: resourceAddressCreate,
-		Read:   resourceAddressRead,
-		Update: resourceAddressUpdate,
-		Delete: resourceAddressDelete,
+		CreateContext: resourceAddressCreate,
+		ReadContext:   resourceAddressRead,
+		UpdateContext: resourceAddressUpdate,
+		DeleteContext: resourceAddressDelete,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyAddressNetworkDomainID: &schema.Schema{
@@ -65,37 +67,8 @@ func resourceAddress() *schema.Resource {
 	}
 }
 
-// Check if an address list resource exists.
-func resourceAddressExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	log.Printf("resourceAddressExists")
-	client := provider.(*providerState).Client()
-	networkDomainId := data.Get(resourceKeyAddressNetworkDomainID).(string)
-	addressListName := data.Get(resourceKeyAddressAddressListName).(string)
-	addrList, _ := client.GetIPAddressListByName(addressListName, networkDomainId)
-
-	var addressListId string
-	if addrList == nil {
-		return false, nil
-	} else {
-		addressListId = addrList.ID
-	}
-
-	begin, okBegin := data.GetOk(resourceKeyAddressBegin)
-	network, okNetwork := data.GetOk(resourceKeyAddressNetwork)
-
-	// Value been deleted in dd cloud manually
-	if okBegin || okNetwork {
-		if !client.CheckAddressExists(addressListId, begin.(string), network.(string)) {
-			data.SetId("") // Mark as deleted.
-			return false, nil
-		}
-	}
-
-	return true, nil
-}
-
 // Create an address list resource.
-func resourceAddressCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceAddressCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 
 	log.Printf("resourceAddressCreate")
 
@@ -113,7 +86,7 @@ func resourceAddressCreate(data *schema.ResourceData, provider interface{}) erro
 
 	var addressListId string
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	} else {
 		addressListId = addrList.ID
 	}
@@ -144,13 +117,13 @@ func resourceAddressCreate(data *schema.ResourceData, provider interface{}) erro
 		if networkOk || prefixOk {
 			err := errors.New("INPUT ERROR: You must define one type of Address ONLY; Either single IP (begin) " +
 				"or IP range (begin and end) or Subnet (begin and prefix_size)")
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	operationDescription := fmt.Sprintf("Add Address to FW  Address List'")
 
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock("Create Address begin:'%s' end:%s network:%s prefix:%d",
 			valBegin, valEnd, valNetwork, valPrefix)
@@ -170,7 +143,7 @@ func resourceAddressCreate(data *schema.ResourceData, provider interface{}) erro
 	})
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	var ip string
@@ -182,11 +155,11 @@ func resourceAddressCreate(data *schema.ResourceData, provider interface{}) erro
 
 	data.SetId(ip)
 
-	return resourceAddressRead(data, provider)
+	return resourceAddressRead(ctx, data, provider)
 }
 
 // Read an address resource.
-func resourceAddressRead(data *schema.ResourceData, provider interface{}) error {
+func resourceAddressRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	log.Printf("resourceAddressRead")
 
 	begin, _ := data.GetOk(resourceKeyAddressBegin)
@@ -200,7 +173,11 @@ func resourceAddressRead(data *schema.ResourceData, provider interface{}) error
 
 	var addressListId string
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	} else if addrList == nil {
+		// Address list has been deleted in cloud
+		data.SetId("")
+		return nil
 	} else {
 		addressListId = addrList.ID
 	}
@@ -209,7 +186,7 @@ func resourceAddressRead(data *schema.ResourceData, provider interface{}) error
 	addr, addrOk := client.GetAddressOk(addressListId, begin.(string), network.(string))
 	if !addrOk {
 		// Address has been deleted in cloud
-		// data.SetId("")
+		data.SetId("")
 		return nil
 	}
 
@@ -232,7 +209,7 @@ func resourceAddressRead(data *schema.ResourceData, provider interface{}) error
 }
 
 // Update an address list resource.
-func resourceAddressUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceAddressUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 
 	log.Printf("resourceAddressUpdate")
 
@@ -253,7 +230,7 @@ func resourceAddressUpdate(data *schema.ResourceData, provider interface{}) erro
 
 	var addressListId string
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	} else {
 		addressListId = addrList.ID
 	}
@@ -291,7 +268,7 @@ func resourceAddressUpdate(data *schema.ResourceData, provider interface{}) erro
 
 	operationDescription := fmt.Sprintf("Delete Address in FW Address List'")
 
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock("Delete Address '%s'", valBegin)
 		defer asyncLock.Release()
@@ -319,14 +296,14 @@ func resourceAddressUpdate(data *schema.ResourceData, provider interface{}) erro
 	})
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return resourceAddressRead(data, provider)
+	return resourceAddressRead(ctx, data, provider)
 }
 
 // Delete an address list resource.
-func resourceAddressDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceAddressDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	log.Printf("resourceAddressDelete")
 
 	providerState := provider.(*providerState)
@@ -348,14 +325,14 @@ func resourceAddressDelete(data *schema.ResourceData, provider interface{}) erro
 
 	var addressListId string
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	} else {
 		addressListId = addrList.ID
 	}
 
 	operationDescription := fmt.Sprintf("Delete Address in FW Address List'")
 
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock("Delete Address '%s'", begin)
 		defer asyncLock.Release()
@@ -373,7 +350,7 @@ func resourceAddressDelete(data *schema.ResourceData, provider interface{}) erro
 	})
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId("")
@@ -1,9 +1,14 @@
 package ddcloud
 
 import (
-	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"context"
+	"fmt"
 	"log"
+
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -22,11 +27,10 @@ const (
 
 func resourceAddressList() *schema.Resource {
 	return &schema.Resource{
-		Exists: resourceAddressListExists,
-		Create: resourceAddressListCreate,
-		Read:   resourceAddressListRead,
-		Update: resourceAddressListUpdate,
-		Delete: resourceAddressListDelete,
+		CreateContext: resourceAddressListCreate,
+		ReadContext:   resourceAddressListRead,
+		UpdateContext: resourceAddressListUpdate,
+		DeleteContext: resourceAddressListDelete,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyAddressListNetworkDomainID: &schema.Schema{
@@ -74,19 +78,11 @@ func resourceAddressList() *schema.Resource {
 							Type:        schema.TypeString,
 							Optional:    true,
 							Description: "The base address for an IP network",
-							ConflictsWith: []string{
-								resourceKeyAddressListAddress + "." + resourceKeyAddressListAddressBegin,
-								resourceKeyAddressListAddress + "." + resourceKeyAddressListAddressEnd,
-							},
 						},
 						resourceKeyAddressListAddressPrefixSize: &schema.Schema{
 							Type:        schema.TypeInt,
 							Optional:    true,
 							Description: "The prefix size for an IP network",
-							ConflictsWith: []string{
-								resourceKeyAddressListAddress + "." + resourceKeyAddressListAddressBegin,
-								resourceKeyAddressListAddress + "." + resourceKeyAddressListAddressEnd,
-							},
 						},
 					},
 				},
@@ -115,21 +111,8 @@ func resourceAddressList() *schema.Resource {
 	}
 }
 
-// Check if an address list resource exists.
-func resourceAddressListExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	log.Printf("ResourceAddressListExists")
-	addressListID := data.Id()
-
-	client := provider.(*providerState).Client()
-
-	addressList, err := client.GetIPAddressList(addressListID)
-	exists := addressList != nil
-
-	return exists, err
-}
-
 // Create an address list resource.
-func resourceAddressListCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceAddressListCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	log.Printf("resourceAddressListCreate")
 	propertyHelper := propertyHelper(data)
 
@@ -139,6 +122,11 @@ func resourceAddressListCreate(data *schema.ResourceData, provider interface{})
 	ipVersion := data.Get(resourceKeyAddressListIPVersion).(string)
 	childListIDs := propertyHelper.GetStringSetItems(resourceKeyAddressListChildIDs)
 
+	diagnostics := validateAddressListAddresses(data)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
 	var addressListEntries []compute.IPAddressListEntry
 	if propertyHelper.HasProperty(resourceKeyAddressListAddresses) {
 		// Address list entries from a simple set of IP addresses.
@@ -158,7 +146,7 @@ func resourceAddressListCreate(data *schema.ResourceData, provider interface{})
 	client := provider.(*providerState).Client()
 	addressListID, err := client.CreateIPAddressList(name, description, ipVersion, networkDomainID, addressListEntries, childListIDs)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(addressListID)
@@ -169,18 +157,20 @@ func resourceAddressListCreate(data *schema.ResourceData, provider interface{})
 }
 
 // Read an address list resource.
-func resourceAddressListRead(data *schema.ResourceData, provider interface{}) error {
+func resourceAddressListRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	addressListID := data.Id()
 
 	client := provider.(*providerState).Client()
 
 	addressList, err := client.GetIPAddressList(addressListID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if addressList == nil {
 		data.SetId("") // Mark as deleted.
+
+		return nil
 	}
 
 	childListIDs := make([]string, len(addressList.ChildLists))
@@ -213,17 +203,22 @@ func resourceAddressListRead(data *schema.ResourceData, provider interface{}) er
 }
 
 // Update an address list resource.
-func resourceAddressListUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceAddressListUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	log.Printf("resourceAddressListUpdate")
 	addressListID := data.Id()
 	networkDomainID := data.Get(resourceKeyAddressListNetworkDomainID).(string)
 
 	log.Printf("Update address list '%s' in network domain '%s'.", addressListID, networkDomainID)
 
+	diagnostics := validateAddressListAddresses(data)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
 	client := provider.(*providerState).Client()
 	addressList, err := client.GetIPAddressList(addressListID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if addressList == nil {
@@ -263,7 +258,7 @@ func resourceAddressListUpdate(data *schema.ResourceData, provider interface{})
 
 	err = client.EditIPAddressList(editRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Updated address list '%s'.", addressListID)
@@ -272,7 +267,7 @@ func resourceAddressListUpdate(data *schema.ResourceData, provider interface{})
 }
 
 // Delete an address list resource.
-func resourceAddressListDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceAddressListDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	log.Printf("resourceAddressListDelete")
 	addressListID := data.Id()
 	networkDomainID := data.Get(resourceKeyAddressListNetworkDomainID).(string)
@@ -280,7 +275,7 @@ func resourceAddressListDelete(data *schema.ResourceData, provider interface{})
 	client := provider.(*providerState).Client()
 	addressList, err := client.GetIPAddressList(addressListID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if addressList == nil {
@@ -291,10 +286,48 @@ func resourceAddressListDelete(data *schema.ResourceData, provider interface{})
 
 	err = client.DeleteIPAddressList(addressListID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Successfully deleted address list '%s' in network domain '%s'.", addressListID, networkDomainID)
 
 	return nil
 }
+
+// Validate the complex addresses configured for an address list.
+//
+// Each address must specify either an address (or address range) or an IP network, but not both.
+func validateAddressListAddresses(data *schema.ResourceData) (diagnostics diag.Diagnostics) {
+	value, ok := data.GetOk(resourceKeyAddressListAddress)
+	if !ok {
+		return
+	}
+
+	for index, item := range value.([]interface{}) {
+		entryProperties, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		begin, _ := entryProperties[resourceKeyAddressListAddressBegin].(string)
+		end, _ := entryProperties[resourceKeyAddressListAddressEnd].(string)
+		network, _ := entryProperties[resourceKeyAddressListAddressNetwork].(string)
+		prefixSize, _ := entryProperties[resourceKeyAddressListAddressPrefixSize].(int)
+
+		isAddress := begin != "" || end != ""
+		isNetwork := network != "" || prefixSize != 0
+		if isAddress && isNetwork {
+			diagnostics = append(diagnostics, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("%s.%d specifies both an address and a network", resourceKeyAddressListAddress, index),
+				Detail: fmt.Sprintf("Specify either '%s' (and, optionally, '%s') or '%s' and '%s', but not both.",
+					resourceKeyAddressListAddressBegin, resourceKeyAddressListAddressEnd,
+					resourceKeyAddressListAddressNetwork, resourceKeyAddressListAddressPrefixSize,
+				),
+				AttributePath: cty.GetAttrPath(resourceKeyAddressListAddress).IndexInt(index).GetAttr(resourceKeyAddressListAddressNetwork),
+			})
+		}
+	}
+
+	return
+}
@@ -0,0 +1,317 @@
+package ddcloud
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	resourceKeyBackupJobServerID   = "server"
+	resourceKeyBackupJobClientID   = "client"
+	resourceKeyBackupJobTriggers   = "triggers"
+	resourceKeyBackupJobStatus     = "status"
+	resourceKeyBackupJobStartTime  = "start_time"
+	resourceKeyBackupJobEndTime    = "end_time"
+	resourceCreateTimeoutBackupJob = 4 * time.Hour
+	backupJobPollInterval          = 15 * time.Second
+
+	// The status of a backup job that completed successfully.
+	backupJobStatusCompleted = "Completed"
+)
+
+/*
+ * Backup jobs only appear in the version 1 (XML) Cloud Backup API, as the runningJob / lastBackupJob elements of a server's backup details.
+ */
+
+// Backup job information for a server's backup clients (the client library's compute.ServerBackupDetails does not include it).
+type serverBackupJobDetails struct {
+	XMLName xml.Name                 `xml:"http://oec.api.opsource.net/schemas/backup BackupDetails"`
+	Clients []backupClientJobDetails `xml:"http://oec.api.opsource.net/schemas/backup backupClient"`
+}
+
+// Backup job information for a backup client.
+type backupClientJobDetails struct {
+	ID             string     `xml:"id,attr"`
+	RunningJob     *backupJob `xml:"http://oec.api.opsource.net/schemas/backup runningJob,omitempty"`
+	LastJob        *backupJob `xml:"http://oec.api.opsource.net/schemas/backup lastBackupJob,omitempty"`
+	NextBackupTime string     `xml:"http://oec.api.opsource.net/schemas/backup nextBackupTime,omitempty"`
+}
+
+// A backup job.
+type backupJob struct {
+	ID                 string `xml:"id,attr"`
+	Name               string `xml:"name,attr,omitempty"`
+	Status             string `xml:"status,attr"`
+	PercentageComplete int    `xml:"percentageComplete,attr,omitempty"`
+	StartTime          string `xml:"startTime,attr,omitempty"`
+	EndTime            string `xml:"endTime,attr,omitempty"`
+}
+
+// GetClientByID retrieves the job information (if any) for the backup client with the specified Id.
+func (jobDetails *serverBackupJobDetails) GetClientByID(clientID string) *backupClientJobDetails {
+	for index := range jobDetails.Clients {
+		clientJobDetails := &jobDetails.Clients[index]
+		if clientJobDetails.ID == clientID {
+			return clientJobDetails
+		}
+	}
+
+	return nil
+}
+
+func resourceBackupJob() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceBackupJobCreate,
+		ReadContext:   resourceBackupJobRead,
+		DeleteContext: resourceBackupJobDelete,
+
+		Schema: map[string]*schema.Schema{
+			resourceKeyBackupJobServerID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The Id of the server to back up",
+			},
+			resourceKeyBackupJobClientID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The Id of the backup client that will run the backup job",
+			},
+			resourceKeyBackupJobTriggers: &schema.Schema{
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that, when changed, will cause a new backup job to be run",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			resourceKeyBackupJobStatus: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The final status of the backup job",
+			},
+			resourceKeyBackupJobStartTime: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date / time when the backup job started",
+			},
+			resourceKeyBackupJobEndTime: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date / time when the backup job finished",
+			},
+		},
+	}
+}
+
+// Create a ddcloud_backup_job resource (run a backup job and wait for it to finish).
+func resourceBackupJobCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	serverID := data.Get(resourceKeyBackupJobServerID).(string)
+	clientID := data.Get(resourceKeyBackupJobClientID).(string)
+
+	log.Printf("Start backup job for client '%s' on server '%s'.", clientID, serverID)
+
+	providerState := provider.(*providerState)
+
+	operationDescription := fmt.Sprintf("Start backup job for client '%s' on server '%s'", clientID, serverID)
+	relativeURI := fmt.Sprintf("server/%s/backup/client/%s?backupNow",
+		url.PathEscape(serverID),
+		url.PathEscape(clientID),
+	)
+
+	var apiResponse *compute.APIResponseV1
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release()
+
+		var invokeError error
+		apiResponse, invokeError = invokeCloudControlAPIV1(ctx, providerState, http.MethodPost, relativeURI)
+		if invokeError != nil {
+			context.Fail(invokeError)
+		} else if apiResponse.ResultCode == compute.ResultCodeResourceBusy {
+			context.Retry()
+		} else if apiResponse.ResultCode == compute.ResultCodeBackupJobInProgress {
+			context.Fail(
+				apiResponse.ToError("cannot start backup job for client '%s' on server '%s' because the client already has a backup job in progress", clientID, serverID),
+			)
+		} else if apiResponse.Result != compute.ResultSuccess {
+			context.Fail(
+				apiResponse.ToError("%s failed (%s): %s", operationDescription, apiResponse.ResultCode, apiResponse.Message),
+			)
+		}
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	jobID := apiResponse.GetAdditionalInformation("backupJob.id")
+	if jobID == nil {
+		return diag.FromErr(
+			apiResponse.ToError("Received an unexpected response (missing 'backupJob.id') with result code '%s': %s", apiResponse.ResultCode, apiResponse.Message),
+		)
+	}
+	data.SetId(*jobID)
+
+	log.Printf("Backup job '%s' is running for client '%s' on server '%s'...", *jobID, clientID, serverID)
+
+	job, err := waitForBackupJob(ctx, providerState, serverID, clientID, *jobID, resourceCreateTimeoutBackupJob)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	setBackupJobProperties(data, job)
+
+	// A failed backup job is left in state (so it can be inspected), but Terraform will mark it as tainted and run it again on the next apply.
+	if job.Status != backupJobStatusCompleted {
+		return diag.Errorf("backup job '%s' for client '%s' on server '%s' finished with status '%s'", job.ID, clientID, serverID, job.Status)
+	}
+
+	log.Printf("Backup job '%s' for client '%s' on server '%s' has completed.", job.ID, clientID, serverID)
+
+	return nil
+}
+
+// Read a ddcloud_backup_job resource.
+func resourceBackupJobRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+	serverID := data.Get(resourceKeyBackupJobServerID).(string)
+	clientID := data.Get(resourceKeyBackupJobClientID).(string)
+
+	log.Printf("Read backup job '%s' for client '%s' on server '%s'.", id, clientID, serverID)
+
+	jobDetails, err := getServerBackupJobDetails(ctx, provider.(*providerState), serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var clientJobDetails *backupClientJobDetails
+	if jobDetails != nil {
+		clientJobDetails = jobDetails.GetClientByID(clientID)
+	}
+	if clientJobDetails == nil {
+		log.Printf("Backup client '%s' no longer exists on server '%s' (will treat backup job '%s' as deleted).", clientID, serverID, id)
+
+		data.SetId("")
+
+		return nil
+	}
+
+	// Cloud Backup only reports the client's most recent job; once a later job has run, the last-known details are retained.
+	if clientJobDetails.LastJob != nil && clientJobDetails.LastJob.ID == id {
+		setBackupJobProperties(data, clientJobDetails.LastJob)
+	}
+
+	return nil
+}
+
+// Delete a ddcloud_backup_job resource.
+//
+// The backup itself is retained (as determined by the client's storage policy), so this only removes the job from state.
+func resourceBackupJobDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	log.Printf("Remove backup job '%s' from state (the backup will be retained according to the client's storage policy).", data.Id())
+
+	return nil
+}
+
+func setBackupJobProperties(data *schema.ResourceData, job *backupJob) {
+	data.Set(resourceKeyBackupJobStatus, job.Status)
+	data.Set(resourceKeyBackupJobStartTime, job.StartTime)
+	data.Set(resourceKeyBackupJobEndTime, job.EndTime)
+}
+
+// Wait for a backup client's job to finish.
+//
+// Returns the finished job (which may have failed).
+func waitForBackupJob(ctx context.Context, providerState *providerState, serverID string, clientID string, jobID string, timeout time.Duration) (*backupJob, error) {
+	waitTimeout := time.NewTimer(timeout)
+	defer waitTimeout.Stop()
+
+	for {
+		jobDetails, err := getServerBackupJobDetails(ctx, providerState, serverID)
+		if err != nil {
+			return nil, err
+		}
+		if jobDetails == nil {
+			return nil, fmt.Errorf("backup is no longer enabled for server '%s'", serverID)
+		}
+
+		clientJobDetails := jobDetails.GetClientByID(clientID)
+		if clientJobDetails == nil {
+			return nil, fmt.Errorf("backup client '%s' no longer exists on server '%s'", clientID, serverID)
+		}
+
+		if clientJobDetails.RunningJob != nil && clientJobDetails.RunningJob.ID == jobID {
+			log.Printf("Backup job '%s' for client '%s' is still running (%s, %d%% complete)...", jobID, clientID, clientJobDetails.RunningJob.Status, clientJobDetails.RunningJob.PercentageComplete)
+		} else if clientJobDetails.LastJob != nil && clientJobDetails.LastJob.ID == jobID {
+			return clientJobDetails.LastJob, nil
+		} else {
+			log.Printf("Backup job '%s' for client '%s' has not been reported by Cloud Backup yet...", jobID, clientID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-waitTimeout.C:
+			return nil, fmt.Errorf("timed out after waiting %d seconds for backup job '%s' for client '%s' on server '%s'", timeout/time.Second, jobID, clientID, serverID)
+		case <-time.After(backupJobPollInterval):
+		}
+	}
+}
+
+// Retrieve backup job information for a server's backup clients.
+//
+// Returns nil if backup is not enabled for the server.
+func getServerBackupJobDetails(ctx context.Context, providerState *providerState, serverID string) (*serverBackupJobDetails, error) {
+	relativeURI := fmt.Sprintf("server/%s/backup", url.PathEscape(serverID))
+
+	jobDetails := &serverBackupJobDetails{}
+	apiResponse, err := readCloudControlAPIV1(ctx, providerState, relativeURI, jobDetails)
+	if err != nil {
+		return nil, err
+	}
+	if apiResponse == nil {
+		return jobDetails, nil
+	}
+
+	switch apiResponse.ResultCode {
+	case compute.ResultCodeBackupNotEnabledForServer,
+		compute.ResultCodeBackupEnablementInProgressForServer,
+		compute.ResultCodeBackupNotEnabledForOrg,
+		compute.ResultCodeBackupNotEnabledForOrgInDatacenter:
+
+		return nil, nil
+	default:
+		return nil, apiResponse.ToError("failed to retrieve backup job details for server '%s' (%s): %s", serverID, apiResponse.ResultCode, apiResponse.Message)
+	}
+}
+
+// Update backup clients with job information from Cloud Backup.
+func applyBackupClientJobDetails(backupClients models.ServerBackupClients, jobDetails *serverBackupJobDetails) {
+	for index := range backupClients {
+		backupClient := &backupClients[index]
+
+		clientJobDetails := jobDetails.GetClientByID(backupClient.ID)
+		if clientJobDetails == nil {
+			continue
+		}
+
+		if clientJobDetails.LastJob != nil {
+			backupClient.LastJobTime = clientJobDetails.LastJob.EndTime
+			backupClient.LastJobStatus = clientJobDetails.LastJob.Status
+		}
+		backupClient.NextJobTime = clientJobDetails.NextBackupTime
+	}
+}
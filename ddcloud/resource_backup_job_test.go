@@ -0,0 +1,90 @@
+package ddcloud
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+/*
+ * Unit tests.
+ */
+
+func testBackupJobData(test *testing.T) *schema.ResourceData {
+	return schema.TestResourceDataRaw(test, resourceBackupJob().Schema, map[string]interface{}{
+		resourceKeyBackupJobServerID: "server-1",
+		resourceKeyBackupJobClientID: "client-1",
+	})
+}
+
+// Unit test - creating the resource runs a backup job for the client and waits for it to complete.
+func TestBackupJobCreate(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.BackupJobClients = []backupClientJobDetails{
+		{ID: "client-1"},
+		{ID: "client-2"},
+	}
+
+	data := testBackupJobData(test)
+	diagnostics := resourceBackupJobCreate(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	testAssert.EqualsInt("len(BackupNowRequests)", 1, len(standIn.BackupNowRequests))
+	testAssert.EqualsString("BackupNowRequests[0]", "client-1", standIn.BackupNowRequests[0])
+	testAssert.EqualsString("Id", "test-backup-job-1", data.Id())
+	testAssert.EqualsString(resourceKeyBackupJobStatus, backupJobStatusCompleted, data.Get(resourceKeyBackupJobStatus).(string))
+	testAssert.EqualsString(resourceKeyBackupJobEndTime, "2026-10-18T10:05:00.000Z", data.Get(resourceKeyBackupJobEndTime).(string))
+}
+
+// Unit test - a backup job that fails is reported as an error (and its status is recorded).
+func TestBackupJobCreateFailed(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.BackupJobClients = []backupClientJobDetails{
+		{ID: "client-1"},
+	}
+	standIn.BackupJobStatusAfterRequest = "Failed"
+
+	data := testBackupJobData(test)
+	diagnostics := resourceBackupJobCreate(context.Background(), data, standIn.NewProviderState())
+	testAssert.IsTrue("diagnostics.HasError()", diagnostics.HasError())
+	testAssert.IsTrue("error mentions status", strings.Contains(diagnostics[0].Summary, "finished with status 'Failed'"))
+	testAssert.EqualsString(resourceKeyBackupJobStatus, "Failed", data.Get(resourceKeyBackupJobStatus).(string))
+}
+
+// Unit test - backup clients are updated with their last and next job details.
+func TestApplyBackupClientJobDetails(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	backupClients := models.ServerBackupClients{
+		models.ServerBackupClient{ID: "client-1", Type: "FA.Linux"},
+		models.ServerBackupClient{ID: "client-2", Type: "MySQL"},
+	}
+	applyBackupClientJobDetails(backupClients, &serverBackupJobDetails{
+		Clients: []backupClientJobDetails{
+			{
+				ID:             "client-1",
+				LastJob:        &backupJob{ID: "job-1", Status: "Completed", StartTime: "2026-10-18T02:00:00.000Z", EndTime: "2026-10-18T02:20:00.000Z"},
+				NextBackupTime: "2026-10-19T02:00:00.000Z",
+			},
+		},
+	})
+
+	testAssert.EqualsString("client-1.LastJobTime", "2026-10-18T02:20:00.000Z", backupClients[0].LastJobTime)
+	testAssert.EqualsString("client-1.LastJobStatus", "Completed", backupClients[0].LastJobStatus)
+	testAssert.EqualsString("client-1.NextJobTime", "2026-10-19T02:00:00.000Z", backupClients[0].NextJobTime)
+	testAssert.EqualsString("client-2.LastJobStatus", "", backupClients[1].LastJobStatus)
+}
@@ -0,0 +1,272 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	resourceKeyCustomerImageServerID             = "server"
+	resourceKeyCustomerImageName                 = "name"
+	resourceKeyCustomerImageDescription          = "description"
+	resourceKeyCustomerImageGuestOSCustomization = "guest_os_customization"
+	resourceKeyCustomerImageClusterID            = "cluster"
+	resourceKeyCustomerImageDatacenterID         = "datacenter"
+	resourceKeyCustomerImageOSFamily             = "os_family"
+	resourceKeyCustomerImageCreateTime           = "create_time"
+	resourceCreateTimeoutCustomerImage           = 60 * time.Minute
+	resourceDeleteTimeoutCustomerImage           = 15 * time.Minute
+)
+
+func resourceCustomerImage() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCustomerImageCreate,
+		ReadContext:   resourceCustomerImageRead,
+		DeleteContext: resourceCustomerImageDelete,
+
+		Schema: map[string]*schema.Schema{
+			resourceKeyCustomerImageServerID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The Id of the server to clone.",
+			},
+			resourceKeyCustomerImageName: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the customer image.",
+			},
+			resourceKeyCustomerImageDescription: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "",
+				Description: "A description of the customer image.",
+			},
+			resourceKeyCustomerImageGuestOSCustomization: &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Should servers deployed from the customer image have guest OS customisation performed?",
+			},
+			resourceKeyCustomerImageClusterID: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "",
+				Description: "The Id of the cluster (in the server's datacenter) where the customer image will be created (only applicable to datacenters with more than one cluster).",
+			},
+			resourceKeyCustomerImageDatacenterID: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Id of the datacenter where the customer image is located.",
+			},
+			resourceKeyCustomerImageOSFamily: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The customer image's operating system family (e.g. WINDOWS or UNIX).",
+			},
+			resourceKeyCustomerImageCreateTime: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date / time that the customer image was created.",
+			},
+		},
+	}
+}
+
+// Create a ddcloud_customer_image resource.
+func resourceCustomerImageCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	var err error
+
+	serverID := data.Get(resourceKeyCustomerImageServerID).(string)
+	name := data.Get(resourceKeyCustomerImageName).(string)
+	description := data.Get(resourceKeyCustomerImageDescription).(string)
+	guestOSCustomization := data.Get(resourceKeyCustomerImageGuestOSCustomization).(bool)
+	clusterID := data.Get(resourceKeyCustomerImageClusterID).(string)
+
+	log.Printf("Create customer image '%s' by cloning server '%s'.", name, serverID)
+
+	providerState := provider.(*providerState)
+	apiClient := providerState.Client()
+
+	server, err := apiClient.GetServer(serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if server == nil {
+		return diag.Errorf("cannot find server with Id '%s'", serverID)
+	}
+
+	var (
+		imageID    string
+		cloneError error
+	)
+
+	operationDescription := fmt.Sprintf("Clone server '%s' to create customer image '%s'", serverID, name)
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release() // Released at the end of the current attempt.
+
+		if clusterID != "" {
+			imageID, cloneError = cloneServerToCluster(ctx, providerState, serverID, name, description, guestOSCustomization, clusterID)
+		} else {
+			imageID, cloneError = apiClient.CloneServer(serverID, name, description, !guestOSCustomization)
+		}
+		if cloneError != nil {
+			if compute.IsResourceBusyError(cloneError) {
+				context.Retry()
+			} else {
+				context.Fail(cloneError)
+			}
+		}
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(imageID)
+	log.Printf("Customer image '%s' is being created from server '%s'...", imageID, serverID)
+
+	_, err = apiClient.WaitForServerClone(imageID, resourceCreateTimeoutCustomerImage)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Successfully created customer image '%s' from server '%s'.", imageID, serverID)
+
+	return resourceCustomerImageRead(ctx, data, provider)
+}
+
+// Read a ddcloud_customer_image resource.
+func resourceCustomerImageRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+	serverID := data.Get(resourceKeyCustomerImageServerID).(string)
+
+	log.Printf("Read customer image '%s' (cloned from server '%s').", id, serverID)
+
+	apiClient := provider.(*providerState).Client()
+
+	image, err := apiClient.GetCustomerImage(id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if image == nil {
+		log.Printf("Customer image '%s' has been deleted.", id)
+
+		data.SetId("")
+
+		return nil
+	}
+
+	data.Set(resourceKeyCustomerImageName, image.Name)
+	data.Set(resourceKeyCustomerImageDescription, image.Description)
+	data.Set(resourceKeyCustomerImageGuestOSCustomization, image.RequiresCustomization())
+	data.Set(resourceKeyCustomerImageDatacenterID, image.DataCenterID)
+	data.Set(resourceKeyCustomerImageOSFamily, image.GetOS().Family)
+	data.Set(resourceKeyCustomerImageCreateTime, image.CreateTime)
+
+	return nil
+}
+
+// Delete a ddcloud_customer_image resource.
+func resourceCustomerImageDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+	name := data.Get(resourceKeyCustomerImageName).(string)
+
+	log.Printf("Delete customer image '%s' ('%s').", name, id)
+
+	err := deleteCustomerImage(ctx, id, name, provider.(*providerState))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// Clone a server to create a customer image in a specific cluster.
+//
+// The CloudControl client library does not (yet) support specifying the target cluster, so the API is invoked directly.
+func cloneServerToCluster(ctx context.Context, providerState *providerState, serverID string, name string, description string, guestOSCustomization bool, clusterID string) (imageID string, err error) {
+	apiResponse, err := invokeCloudControlAPI(ctx, providerState, http.MethodPost, "server/cloneServer", map[string]interface{}{
+		"id":                   serverID,
+		"imageName":            name,
+		"description":          description,
+		"clusterId":            clusterID,
+		"guestOsCustomization": guestOSCustomization,
+	})
+	if err != nil {
+		return "", err
+	}
+	if apiResponse.ResponseCode != compute.ResponseCodeInProgress {
+		return "", apiResponse.ToError("Request to clone server '%s' failed with response code '%s': %s", serverID, apiResponse.ResponseCode, apiResponse.Message)
+	}
+
+	imageIDMessage := apiResponse.GetFieldMessage("imageId")
+	if imageIDMessage == nil {
+		return "", apiResponse.ToError("Received an unexpected response (missing 'imageId') with status code '%s': %s", apiResponse.ResponseCode, apiResponse.Message)
+	}
+
+	return *imageIDMessage, nil
+}
+
+// Delete a customer image, and wait for the deletion to complete.
+//
+// The CloudControl client library does not (yet) support deleting customer images, so the API is invoked directly.
+func deleteCustomerImage(ctx context.Context, id string, name string, providerState *providerState) error {
+	apiClient := providerState.Client()
+
+	image, err := apiClient.GetCustomerImage(id)
+	if err != nil {
+		return err
+	}
+	if image == nil {
+		log.Printf("Customer image '%s' has already been deleted.", id)
+
+		return nil
+	}
+
+	operationDescription := fmt.Sprintf("Delete customer image '%s' ('%s')", name, id)
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release()
+
+		apiResponse, deleteError := invokeCloudControlAPI(ctx, providerState, http.MethodPost, "image/deleteCustomerImage", map[string]interface{}{
+			"id": id,
+		})
+		if deleteError == nil && apiResponse.ResponseCode != compute.ResponseCodeInProgress {
+			deleteError = apiResponse.ToError("Request to delete customer image '%s' failed with response code '%s': %s", id, apiResponse.ResponseCode, apiResponse.Message)
+		}
+
+		if compute.IsResourceBusyError(deleteError) {
+			context.Retry()
+		} else if deleteError != nil {
+			context.Fail(deleteError)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Customer image '%s' ('%s') is being deleted...", name, id)
+
+	err = apiClient.WaitForDelete(compute.ResourceTypeCustomerImage, id, resourceDeleteTimeoutCustomerImage)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Deleted customer image '%s' ('%s').", name, id)
+
+	return nil
+}
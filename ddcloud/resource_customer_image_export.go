@@ -0,0 +1,173 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	resourceKeyCustomerImageExportImageID              = "image"
+	resourceKeyCustomerImageExportOVFPackagePrefix     = "ovf_package_prefix"
+	resourceKeyCustomerImageExportDestinationDirectory = "destination_directory"
+	resourceKeyCustomerImageExportDatacenterID         = "datacenter"
+	resourceKeyCustomerImageExportFTPSHost             = "ftps_host"
+	resourceKeyCustomerImageExportDownloadedFiles      = "downloaded_files"
+	resourceCreateTimeoutCustomerImageExport           = 2 * time.Hour
+)
+
+func resourceCustomerImageExport() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCustomerImageExportCreate,
+		ReadContext:   resourceCustomerImageExportRead,
+		DeleteContext: resourceCustomerImageExportDelete,
+
+		Schema: map[string]*schema.Schema{
+			resourceKeyCustomerImageExportImageID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The Id of the customer image to export.",
+			},
+			resourceKeyCustomerImageExportOVFPackagePrefix: &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The prefix (i.e. file name without extension) for the exported OVF package's files.",
+				ValidateFunc: validateOVFPackagePrefix,
+			},
+			resourceKeyCustomerImageExportDestinationDirectory: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "",
+				Description: "If specified, the local directory to which the OVF package's files will be downloaded (via FTPS) once the image has been exported.",
+			},
+			resourceKeyCustomerImageExportDatacenterID: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Id of the datacenter where the customer image is located.",
+			},
+			resourceKeyCustomerImageExportFTPSHost: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The FTPS host from which the exported OVF package can be downloaded.",
+			},
+			resourceKeyCustomerImageExportDownloadedFiles: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the OVF package files downloaded by the provider (if any).",
+			},
+		},
+	}
+}
+
+// Create a ddcloud_customer_image_export resource.
+func resourceCustomerImageExportCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	var err error
+
+	imageID := data.Get(resourceKeyCustomerImageExportImageID).(string)
+	ovfPackagePrefix := data.Get(resourceKeyCustomerImageExportOVFPackagePrefix).(string)
+	destinationDirectory := data.Get(resourceKeyCustomerImageExportDestinationDirectory).(string)
+
+	log.Printf("Export customer image '%s' to OVF package '%s'.", imageID, ovfPackagePrefix)
+
+	providerState := provider.(*providerState)
+	apiClient := providerState.Client()
+
+	image, err := apiClient.GetCustomerImage(imageID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if image == nil {
+		return diag.Errorf("cannot find customer image '%s'", imageID)
+	}
+	data.Set(resourceKeyCustomerImageExportDatacenterID, image.DataCenterID)
+
+	ftpsHost, err := getDatacenterFTPSHost(image.DataCenterID, apiClient)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	data.Set(resourceKeyCustomerImageExportFTPSHost, ftpsHost)
+
+	var (
+		exportID    string
+		exportError error
+	)
+
+	operationDescription := fmt.Sprintf("Export customer image '%s' to OVF package '%s'", imageID, ovfPackagePrefix)
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release() // Released at the end of the current attempt.
+
+		exportID, exportError = apiClient.ExportCustomerImage(imageID, ovfPackagePrefix)
+		if exportError != nil {
+			if compute.IsResourceBusyError(exportError) {
+				context.Retry()
+			} else {
+				context.Fail(exportError)
+			}
+		}
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(exportID)
+	log.Printf("Customer image '%s' is being exported to OVF package '%s' (export Id '%s')...", imageID, ovfPackagePrefix, exportID)
+
+	_, err = apiClient.WaitForChange(compute.ResourceTypeCustomerImage, imageID, "Export", resourceCreateTimeoutCustomerImageExport)
+	if err != nil {
+		return diag.Errorf("failed to export customer image '%s' to OVF package '%s': %s", imageID, ovfPackagePrefix, err)
+	}
+
+	log.Printf("Successfully exported customer image '%s' to OVF package '%s'.", imageID, ovfPackagePrefix)
+
+	if destinationDirectory != "" {
+		settings := providerState.Settings()
+
+		var transfer ovfPackageTransfer
+		transfer, err = newOVFPackageTransfer(ftpsHost, settings.Username, settings.Password)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer transfer.Close()
+
+		var downloadedFileNames []string
+		downloadedFileNames, err = downloadOVFPackage(transfer, ovfPackagePrefix, destinationDirectory)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		data.Set(resourceKeyCustomerImageExportDownloadedFiles, downloadedFileNames)
+	}
+
+	return nil
+}
+
+// Read a ddcloud_customer_image_export resource.
+func resourceCustomerImageExportRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+	imageID := data.Get(resourceKeyCustomerImageExportImageID).(string)
+
+	log.Printf("Read export '%s' of customer image '%s' (nothing to do).", id, imageID)
+
+	return nil
+}
+
+// Delete a ddcloud_customer_image_export resource.
+func resourceCustomerImageExportDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+	imageID := data.Get(resourceKeyCustomerImageExportImageID).(string)
+
+	log.Printf("Delete export '%s' of customer image '%s' (nothing to do; exported OVF package files are not removed).", id, imageID)
+
+	return nil
+}
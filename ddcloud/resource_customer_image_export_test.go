@@ -0,0 +1,83 @@
+package ddcloud
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+/*
+ * Unit tests.
+ */
+
+func testCustomerImageExportData(test *testing.T, imageID string, destinationDirectory string) *schema.ResourceData {
+	return schema.TestResourceDataRaw(test, resourceCustomerImageExport().Schema, map[string]interface{}{
+		resourceKeyCustomerImageExportImageID:              imageID,
+		resourceKeyCustomerImageExportOVFPackagePrefix:     "pkg",
+		resourceKeyCustomerImageExportDestinationDirectory: destinationDirectory,
+	})
+}
+
+// Unit test - the OVF package is downloaded once the image has been exported.
+func TestCustomerImageExportCreate(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+	standIn.Image = &compute.CustomerImage{
+		ID:           "test-image",
+		Name:         "MyImage",
+		DataCenterID: "AU9",
+		State:        compute.ResourceStatusNormal,
+	}
+
+	transfer := newTestOVFPackageTransfer()
+	defer transfer.Install()()
+	transfer.Files["pkg.mf"] = "manifest"
+	transfer.Files["pkg.ovf"] = "descriptor"
+	transfer.Files["other.ovf"] = "unrelated"
+
+	destinationDirectory := filepath.Join(test.TempDir(), "export")
+
+	data := testCustomerImageExportData(test, "test-image", destinationDirectory)
+	diagnostics := resourceCustomerImageExportCreate(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	testAssert.EqualsString("Id", "test-export", data.Id())
+	testAssert.EqualsString(resourceKeyCustomerImageExportDatacenterID, "AU9", data.Get(resourceKeyCustomerImageExportDatacenterID).(string))
+	testAssert.EqualsString(resourceKeyCustomerImageExportFTPSHost, testCloudControlFTPSHost, data.Get(resourceKeyCustomerImageExportFTPSHost).(string))
+
+	testAssert.EqualsInt("len(ExportRequests)", 1, len(standIn.ExportRequests))
+	testAssert.Equals("imageId", "test-image", standIn.ExportRequests[0]["imageId"])
+	testAssert.Equals("ovfPackagePrefix", "pkg", standIn.ExportRequests[0]["ovfPackagePrefix"])
+
+	testAssert.Equals("downloaded_files",
+		[]interface{}{"pkg.mf", "pkg.ovf"},
+		data.Get(resourceKeyCustomerImageExportDownloadedFiles),
+	)
+	content, err := ioutil.ReadFile(filepath.Join(destinationDirectory, "pkg.ovf"))
+	if err != nil {
+		test.Fatal(err)
+	}
+	testAssert.EqualsString("pkg.ovf", "descriptor", string(content))
+}
+
+// Unit test - exporting an image that does not exist fails without requesting an export.
+func TestCustomerImageExportCreateImageNotFound(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	data := testCustomerImageExportData(test, "missing-image", "")
+	diagnostics := resourceCustomerImageExportCreate(context.Background(), data, standIn.NewProviderState())
+	testAssert.IsTrue("diagnostics.HasError()", diagnostics.HasError())
+	testAssert.EqualsInt("len(ExportRequests)", 0, len(standIn.ExportRequests))
+}
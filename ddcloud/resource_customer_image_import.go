@@ -0,0 +1,246 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	resourceKeyCustomerImageImportDatacenterID         = "datacenter"
+	resourceKeyCustomerImageImportName                 = "name"
+	resourceKeyCustomerImageImportDescription          = "description"
+	resourceKeyCustomerImageImportGuestOSCustomization = "guest_os_customization"
+	resourceKeyCustomerImageImportOVFPackagePrefix     = "ovf_package_prefix"
+	resourceKeyCustomerImageImportSourceDirectory      = "source_directory"
+	resourceKeyCustomerImageImportFTPSHost             = "ftps_host"
+	resourceKeyCustomerImageImportUploadedFiles        = "uploaded_files"
+	resourceKeyCustomerImageImportOSFamily             = "os_family"
+	resourceCreateTimeoutCustomerImageImport           = 2 * time.Hour
+)
+
+func resourceCustomerImageImport() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCustomerImageImportCreate,
+		ReadContext:   resourceCustomerImageImportRead,
+		DeleteContext: resourceCustomerImageImportDelete,
+
+		Schema: map[string]*schema.Schema{
+			resourceKeyCustomerImageImportDatacenterID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The Id of the datacenter into which the customer image will be imported.",
+			},
+			resourceKeyCustomerImageImportName: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the customer image.",
+			},
+			resourceKeyCustomerImageImportDescription: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "",
+				Description: "A description of the customer image.",
+			},
+			resourceKeyCustomerImageImportGuestOSCustomization: &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Should servers deployed from the customer image have guest OS customisation performed?",
+			},
+			resourceKeyCustomerImageImportOVFPackagePrefix: &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The prefix (i.e. file name without extension) of the OVF package's files.",
+				ValidateFunc: validateOVFPackagePrefix,
+			},
+			resourceKeyCustomerImageImportSourceDirectory: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "",
+				Description: "If specified, the local directory from which the OVF package's files will be uploaded (via FTPS) before the image is imported.",
+			},
+			resourceKeyCustomerImageImportFTPSHost: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The FTPS host used to upload OVF packages to the datacenter.",
+			},
+			resourceKeyCustomerImageImportUploadedFiles: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the OVF package files uploaded by the provider (if any).",
+			},
+			resourceKeyCustomerImageImportOSFamily: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The customer image's operating system family (e.g. WINDOWS or UNIX).",
+			},
+		},
+	}
+}
+
+// Create a ddcloud_customer_image_import resource.
+func resourceCustomerImageImportCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	var err error
+
+	datacenterID := data.Get(resourceKeyCustomerImageImportDatacenterID).(string)
+	name := data.Get(resourceKeyCustomerImageImportName).(string)
+	description := data.Get(resourceKeyCustomerImageImportDescription).(string)
+	guestOSCustomization := data.Get(resourceKeyCustomerImageImportGuestOSCustomization).(bool)
+	ovfPackagePrefix := data.Get(resourceKeyCustomerImageImportOVFPackagePrefix).(string)
+	sourceDirectory := data.Get(resourceKeyCustomerImageImportSourceDirectory).(string)
+
+	log.Printf("Import customer image '%s' into datacenter '%s' from OVF package '%s'.", name, datacenterID, ovfPackagePrefix)
+
+	providerState := provider.(*providerState)
+	apiClient := providerState.Client()
+
+	ftpsHost, err := getDatacenterFTPSHost(datacenterID, apiClient)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	data.Set(resourceKeyCustomerImageImportFTPSHost, ftpsHost)
+
+	if sourceDirectory != "" {
+		settings := providerState.Settings()
+
+		var transfer ovfPackageTransfer
+		transfer, err = newOVFPackageTransfer(ftpsHost, settings.Username, settings.Password)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer transfer.Close()
+
+		var uploadedFileNames []string
+		uploadedFileNames, err = uploadOVFPackage(transfer, sourceDirectory, ovfPackagePrefix)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		data.Set(resourceKeyCustomerImageImportUploadedFiles, uploadedFileNames)
+	}
+
+	var (
+		imageID     string
+		importError error
+	)
+
+	operationDescription := fmt.Sprintf("Import customer image '%s' into datacenter '%s'", name, datacenterID)
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release() // Released at the end of the current attempt.
+
+		imageID, importError = apiClient.ImportCustomerImage(name, description, !guestOSCustomization, ovfPackagePrefix, datacenterID)
+		if importError != nil {
+			if compute.IsResourceBusyError(importError) {
+				context.Retry()
+			} else {
+				context.Fail(importError)
+			}
+		}
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(imageID)
+	log.Printf("Customer image '%s' is being imported from OVF package '%s'...", imageID, ovfPackagePrefix)
+
+	_, err = apiClient.WaitForAdd(compute.ResourceTypeCustomerImage, imageID, "Import", resourceCreateTimeoutCustomerImageImport)
+	if err != nil {
+		return diag.Errorf("failed to import customer image '%s' from OVF package '%s': %s", name, ovfPackagePrefix, err)
+	}
+
+	log.Printf("Successfully imported customer image '%s' from OVF package '%s'.", imageID, ovfPackagePrefix)
+
+	return resourceCustomerImageImportRead(ctx, data, provider)
+}
+
+// Read a ddcloud_customer_image_import resource.
+func resourceCustomerImageImportRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+	datacenterID := data.Get(resourceKeyCustomerImageImportDatacenterID).(string)
+
+	log.Printf("Read imported customer image '%s' in datacenter '%s'.", id, datacenterID)
+
+	apiClient := provider.(*providerState).Client()
+
+	image, err := apiClient.GetCustomerImage(id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if image == nil {
+		log.Printf("Customer image '%s' has been deleted.", id)
+
+		data.SetId("")
+
+		return nil
+	}
+
+	data.Set(resourceKeyCustomerImageImportName, image.Name)
+	data.Set(resourceKeyCustomerImageImportDescription, image.Description)
+	data.Set(resourceKeyCustomerImageImportGuestOSCustomization, image.RequiresCustomization())
+	data.Set(resourceKeyCustomerImageImportOSFamily, image.GetOS().Family)
+
+	return nil
+}
+
+// Delete a ddcloud_customer_image_import resource.
+func resourceCustomerImageImportDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+	name := data.Get(resourceKeyCustomerImageImportName).(string)
+
+	log.Printf("Delete imported customer image '%s' ('%s').", name, id)
+
+	err := deleteCustomerImage(ctx, id, name, provider.(*providerState))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// Get the name of the FTPS host used to upload / download OVF packages to / from the specified datacenter.
+func getDatacenterFTPSHost(datacenterID string, apiClient *compute.Client) (string, error) {
+	datacenter, err := apiClient.GetDatacenter(datacenterID)
+	if err != nil {
+		return "", err
+	}
+	if datacenter == nil {
+		return "", fmt.Errorf("cannot find datacenter '%s'", datacenterID)
+	}
+	if datacenter.FTPSHost == "" {
+		return "", fmt.Errorf("datacenter '%s' does not have an FTPS host for OVF packages", datacenterID)
+	}
+
+	return datacenter.FTPSHost, nil
+}
+
+func validateOVFPackagePrefix(value interface{}, propertyName string) (messages []string, errors []error) {
+	ovfPackagePrefix := value.(string)
+	if ovfPackagePrefix == "" {
+		errors = append(errors,
+			fmt.Errorf("'%s' must not be empty", propertyName),
+		)
+	} else if strings.ContainsAny(ovfPackagePrefix, `/\`) {
+		errors = append(errors,
+			fmt.Errorf("invalid OVF package prefix '%s' for '%s' (must be a file name, not a path)", ovfPackagePrefix, propertyName),
+		)
+	}
+
+	return
+}
@@ -0,0 +1,138 @@
+package ddcloud
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+/*
+ * Unit tests.
+ */
+
+func testCustomerImageImportData(test *testing.T, sourceDirectory string) *schema.ResourceData {
+	return schema.TestResourceDataRaw(test, resourceCustomerImageImport().Schema, map[string]interface{}{
+		resourceKeyCustomerImageImportDatacenterID:         "AU9",
+		resourceKeyCustomerImageImportName:                 "ImportedImage",
+		resourceKeyCustomerImageImportDescription:          "Imported from OVF",
+		resourceKeyCustomerImageImportGuestOSCustomization: false,
+		resourceKeyCustomerImageImportOVFPackagePrefix:     "pkg",
+		resourceKeyCustomerImageImportSourceDirectory:      sourceDirectory,
+	})
+}
+
+// Unit test - the OVF package is uploaded before the image is imported.
+func TestCustomerImageImportCreate(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	transfer := newTestOVFPackageTransfer()
+	defer transfer.Install()()
+
+	sourceDirectory := test.TempDir()
+	testCreateFiles(test, sourceDirectory, "pkg.mf", "pkg.ovf", "pkg-disk1.vmdk", "unrelated.txt")
+
+	data := testCustomerImageImportData(test, sourceDirectory)
+	diagnostics := resourceCustomerImageImportCreate(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	testAssert.EqualsString("Id", "test-image", data.Id())
+	testAssert.EqualsString("FTPS host", testCloudControlFTPSHost, transfer.Host)
+	testAssert.EqualsString(resourceKeyCustomerImageImportFTPSHost, testCloudControlFTPSHost, data.Get(resourceKeyCustomerImageImportFTPSHost).(string))
+	testAssert.EqualsInt("len(transfer.Files)", 3, len(transfer.Files))
+	testAssert.EqualsInt("len(uploaded_files)", 3, len(data.Get(resourceKeyCustomerImageImportUploadedFiles).([]interface{})))
+	testAssert.EqualsString(resourceKeyCustomerImageImportOSFamily, "UNIX", data.Get(resourceKeyCustomerImageImportOSFamily).(string))
+
+	testAssert.EqualsInt("len(ImportRequests)", 1, len(standIn.ImportRequests))
+	importRequest := standIn.ImportRequests[0]
+	testAssert.Equals("ovfPackage", "pkg.mf", importRequest["ovfPackage"])
+	testAssert.Equals("name", "ImportedImage", importRequest["name"])
+	testAssert.Equals("datacenterId", "AU9", importRequest["datacenterId"])
+	testAssert.Equals("guestOsCustomization", false, importRequest["guestOsCustomization"])
+}
+
+// Unit test - a failed import is reported as an error.
+func TestCustomerImageImportCreateFailed(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+	standIn.ImageStateAfterRequest = "FAILED_ADD"
+
+	data := testCustomerImageImportData(test, "")
+	diagnostics := resourceCustomerImageImportCreate(context.Background(), data, standIn.NewProviderState())
+	testAssert.IsTrue("diagnostics.HasError()", diagnostics.HasError())
+	testAssert.IsTrue("error describes import failure",
+		strings.Contains(diagnostics[0].Summary, "failed to import customer image 'ImportedImage' from OVF package 'pkg'"),
+	)
+	testAssert.IsTrue("error includes image state",
+		strings.Contains(diagnostics[0].Summary, "FAILED_ADD"),
+	)
+}
+
+// Unit test - the image is not imported if the OVF package cannot be uploaded.
+func TestCustomerImageImportCreateMissingManifest(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	transfer := newTestOVFPackageTransfer()
+	defer transfer.Install()()
+
+	sourceDirectory := test.TempDir()
+	testCreateFiles(test, sourceDirectory, "pkg.ovf")
+
+	data := testCustomerImageImportData(test, sourceDirectory)
+	diagnostics := resourceCustomerImageImportCreate(context.Background(), data, standIn.NewProviderState())
+	testAssert.IsTrue("diagnostics.HasError()", diagnostics.HasError())
+	testAssert.EqualsInt("len(transfer.Files)", 0, len(transfer.Files))
+	testAssert.EqualsInt("len(ImportRequests)", 0, len(standIn.ImportRequests))
+	testAssert.EqualsString("Id", "", data.Id())
+}
+
+// Unit test - an imported image that has been deleted is removed from state.
+func TestCustomerImageImportReadDeleted(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	data := testCustomerImageImportData(test, "")
+	data.SetId("test-image")
+
+	diagnostics := resourceCustomerImageImportRead(context.Background(), data, standIn.NewProviderState())
+	testAssert.IsFalse("diagnostics.HasError()", diagnostics.HasError())
+	testAssert.EqualsString("Id", "", data.Id())
+}
+
+// Unit test - deleting the resource deletes the imported image in CloudControl.
+func TestCustomerImageImportDelete(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+	standIn.Image = &compute.CustomerImage{
+		ID:           "test-image",
+		Name:         "ImportedImage",
+		DataCenterID: "AU9",
+		State:        compute.ResourceStatusNormal,
+	}
+
+	data := testCustomerImageImportData(test, "")
+	data.SetId("test-image")
+
+	diagnostics := resourceCustomerImageImportDelete(context.Background(), data, standIn.NewProviderState())
+	testAssert.IsFalse("diagnostics.HasError()", diagnostics.HasError())
+	testAssert.EqualsInt("len(diagnostics)", 0, len(diagnostics))
+	testAssert.EqualsInt("len(DeleteImageRequests)", 1, len(standIn.DeleteImageRequests))
+	testAssert.IsTrue("Image == nil", standIn.Image == nil)
+}
@@ -0,0 +1,126 @@
+package ddcloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+/*
+ * Unit tests.
+ */
+
+func testCustomerImageData(test *testing.T, clusterID string) *schema.ResourceData {
+	return schema.TestResourceDataRaw(test, resourceCustomerImage().Schema, map[string]interface{}{
+		resourceKeyCustomerImageServerID:             "server-1",
+		resourceKeyCustomerImageName:                 "GoldenImage",
+		resourceKeyCustomerImageDescription:          "Golden image",
+		resourceKeyCustomerImageGuestOSCustomization: true,
+		resourceKeyCustomerImageClusterID:            clusterID,
+	})
+}
+
+// Unit test - the target cluster is passed to CloudControl when cloning the server.
+func TestCustomerImageCreateInCluster(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.ComputeServer = &compute.Server{
+		ID:   "server-1",
+		Name: "template",
+	}
+
+	data := testCustomerImageData(test, "AU9-01")
+	diagnostics := resourceCustomerImageCreate(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	testAssert.EqualsString("Id", "test-image", data.Id())
+	testAssert.EqualsInt("len(CloneRequests)", 1, len(standIn.CloneRequests))
+
+	cloneRequest := standIn.CloneRequests[0]
+	testAssert.Equals("id", "server-1", cloneRequest["id"])
+	testAssert.Equals("imageName", "GoldenImage", cloneRequest["imageName"])
+	testAssert.Equals("clusterId", "AU9-01", cloneRequest["clusterId"])
+	testAssert.Equals("guestOsCustomization", true, cloneRequest["guestOsCustomization"])
+}
+
+// Unit test - reading the image refreshes its attributes (or removes it from state, if it has been deleted).
+func TestCustomerImageRead(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.Image = &compute.CustomerImage{
+		ID:           "test-image",
+		Name:         "RenamedImage",
+		Description:  "Golden image",
+		DataCenterID: "AU9",
+		CreateTime:   "2026-10-18T00:00:00.000Z",
+		State:        compute.ResourceStatusNormal,
+	}
+	standIn.Image.Guest.OperatingSystem.Family = "WINDOWS"
+
+	data := testCustomerImageData(test, "")
+	data.SetId("test-image")
+
+	diagnostics := resourceCustomerImageRead(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	testAssert.EqualsString("Id", "test-image", data.Id())
+	testAssert.EqualsString(resourceKeyCustomerImageName, "RenamedImage", data.Get(resourceKeyCustomerImageName).(string))
+	testAssert.EqualsString(resourceKeyCustomerImageDatacenterID, "AU9", data.Get(resourceKeyCustomerImageDatacenterID).(string))
+	testAssert.EqualsString(resourceKeyCustomerImageOSFamily, "WINDOWS", data.Get(resourceKeyCustomerImageOSFamily).(string))
+
+	standIn.Image = nil
+
+	diagnostics = resourceCustomerImageRead(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	testAssert.EqualsString("Id", "", data.Id())
+}
+
+// Unit test - deleting the resource deletes the image in CloudControl.
+func TestCustomerImageDelete(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.Image = &compute.CustomerImage{
+		ID:    "test-image",
+		Name:  "GoldenImage",
+		State: compute.ResourceStatusNormal,
+	}
+
+	data := testCustomerImageData(test, "")
+	data.SetId("test-image")
+
+	diagnostics := resourceCustomerImageDelete(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	testAssert.EqualsInt("len(DeleteImageRequests)", 1, len(standIn.DeleteImageRequests))
+	testAssert.Equals("id", "test-image", standIn.DeleteImageRequests[0]["id"])
+	testAssert.IsTrue("Image == nil", standIn.Image == nil)
+
+	// Deleting an image that has already been deleted is not an error.
+	diagnostics = resourceCustomerImageDelete(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	testAssert.EqualsInt("len(DeleteImageRequests)", 1, len(standIn.DeleteImageRequests))
+}
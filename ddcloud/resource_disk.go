@@ -0,0 +1,676 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	resourceKeyDiskServerID  = "server"
+	resourceKeyDiskBusNumber = "scsi_bus_number"
+	resourceKeyDiskUnitID    = "scsi_unit_id"
+	resourceKeyDiskSizeGB    = "size_gb"
+	resourceKeyDiskSpeed     = "speed"
+	resourceKeyDiskIops      = "iops"
+
+	// The highest SCSI unit ID available on a SCSI bus.
+	maxSCSIUnitID = 15
+
+	// The SCSI unit ID used by the SCSI controller itself (not available for disks).
+	reservedSCSIUnitID = 7
+
+	// The name of the server tag that records the SCSI paths (e.g. "0:1,1:0") of the server's disks that are managed by ddcloud_disk resources.
+	diskResourcesTagName = "ddcloud_disks"
+)
+
+/*
+ * A ddcloud_disk manages a single disk, independently of the disk lists on ddcloud_server and ddcloud_storage_controller.
+ *
+ * Each ddcloud_disk records its SCSI path in the server's "ddcloud_disks" tag; ddcloud_server and ddcloud_storage_controller
+ * ignore disks at those paths (see models.Disks.Managed), so they are not treated as drift (or removed) by their diffing.
+ * Any other disks (e.g. those added outside of Terraform) are still reported as drift.
+ */
+
+func resourceDisk() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDiskCreate,
+		ReadContext:   resourceDiskRead,
+		UpdateContext: resourceDiskUpdate,
+		DeleteContext: resourceDiskDelete,
+		CustomizeDiff: resourceDiskCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDiskImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			resourceKeyDiskServerID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The Id of the server that the disk is attached to",
+			},
+			resourceKeyDiskBusNumber: &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				ForceNew:    true,
+				Description: "The SCSI bus number of the storage controller that the disk is attached to",
+			},
+			resourceKeyDiskUnitID: &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The SCSI Logical Unit Number (LUN) for the disk (if not specified, the next free unit ID on the SCSI bus will be used)",
+				ValidateFunc: func(value interface{}, propertyName string) (messages []string, errors []error) {
+					unitID := value.(int)
+					if unitID < 0 || unitID > maxSCSIUnitID || unitID == reservedSCSIUnitID {
+						errors = append(errors,
+							fmt.Errorf("invalid value %d for '%s' (must be between 0 and %d, and cannot be %d)", unitID, propertyName, maxSCSIUnitID, reservedSCSIUnitID),
+						)
+					}
+
+					return
+				},
+			},
+			resourceKeyDiskSizeGB: &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "The size (in GB) of the disk",
+			},
+			resourceKeyDiskSpeed: &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      compute.ServerDiskSpeedStandard,
+				StateFunc:    normalizeSpeed,
+				Description:  "The disk speed",
+				ValidateFunc: validateDiskSpeed,
+			},
+			resourceKeyDiskIops: &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "The disk IOPS (only applicable when speed is PROVISIONEDIOPS)",
+			},
+		},
+	}
+}
+
+// Check a disk resource's planned changes.
+//
+// Values that are not yet known are not validated.
+func resourceDiskCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, provider interface{}) error {
+	if diff.NewValueKnown(resourceKeyDiskSizeGB) && diff.NewValueKnown(resourceKeyDiskSpeed) && diff.NewValueKnown(resourceKeyDiskIops) {
+		err := validateDiskIops(models.Disk{
+			SizeGB: diff.Get(resourceKeyDiskSizeGB).(int),
+			Speed:  diff.Get(resourceKeyDiskSpeed).(string),
+			Iops:   diff.Get(resourceKeyDiskIops).(int),
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %s", resourceKeyDiskIops, err)
+		}
+	}
+
+	if diff.Id() == "" {
+		return nil
+	}
+
+	if diff.HasChange(resourceKeyDiskSizeGB) {
+		oldSizeGB, newSizeGB := diff.GetChange(resourceKeyDiskSizeGB)
+		if newSizeGB.(int) < oldSizeGB.(int) {
+			return fmt.Errorf("cannot resize disk '%s' from %d GB to %d GB (disks can only be expanded)",
+				diff.Id(),
+				oldSizeGB.(int),
+				newSizeGB.(int),
+			)
+		}
+	}
+
+	if diff.HasChange(resourceKeyDiskSpeed) {
+		oldSpeed, newSpeed := diff.GetChange(resourceKeyDiskSpeed)
+		if isDiskPowerCycleRequired(oldSpeed.(string), newSpeed.(string)) {
+			serverID := diff.Get(resourceKeyDiskServerID).(string)
+
+			return validateServerPowerCycle(provider.(*providerState), serverID,
+				fmt.Sprintf("changing the speed of disk '%s' from %s to %s", diff.Id(), oldSpeed, newSpeed),
+			)
+		}
+	}
+
+	return nil
+}
+
+// Determine whether changing a disk's speed requires its server to be powered off.
+func isDiskPowerCycleRequired(oldSpeed string, newSpeed string) bool {
+	return models.Disk{Speed: newSpeed}.RequiresPowerCycle(models.Disk{Speed: oldSpeed})
+}
+
+// Create a disk resource.
+func resourceDiskCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	serverID := data.Get(resourceKeyDiskServerID).(string)
+	busNumber := data.Get(resourceKeyDiskBusNumber).(int)
+	sizeGB := data.Get(resourceKeyDiskSizeGB).(int)
+	speed := normalizeSpeed(data.Get(resourceKeyDiskSpeed))
+
+	iops := 0
+	if speed == compute.ServerDiskSpeedProvisionedIops {
+		iops = data.Get(resourceKeyDiskIops).(int)
+	}
+
+	unitID := -1
+	if value, ok := data.GetOkExists(resourceKeyDiskUnitID); ok {
+		unitID = value.(int)
+	}
+
+	providerState := provider.(*providerState)
+	apiClient := providerState.Client()
+
+	var diskID string
+	operationDescription := fmt.Sprintf("Add disk (SCSI bus %d) to server '%s'", busNumber, serverID)
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release()
+
+		// Re-read the server every time so we don't pick a unit ID that was claimed since our last attempt.
+		server, getServerError := apiClient.GetServer(serverID)
+		if getServerError != nil {
+			context.Fail(getServerError)
+
+			return
+		}
+		if server == nil {
+			context.Fail(fmt.Errorf("cannot find server '%s'", serverID))
+
+			return
+		}
+
+		targetController := server.SCSIControllers.GetByBusNumber(busNumber)
+		if targetController == nil {
+			context.Fail(fmt.Errorf("server '%s' does not have a storage controller for SCSI bus %d", serverID, busNumber))
+
+			return
+		}
+
+		targetUnitID := unitID
+		if targetUnitID == -1 {
+			var unitIDError error
+			targetUnitID, unitIDError = getNextFreeSCSIUnitID(targetController)
+			if unitIDError != nil {
+				context.Fail(fmt.Errorf("cannot add disk to SCSI bus %d in server '%s': %s", busNumber, serverID, unitIDError))
+
+				return
+			}
+		} else if targetController.GetDiskByUnitID(targetUnitID) != nil {
+			context.Fail(fmt.Errorf("server '%s' already has a disk with SCSI unit ID %d on SCSI bus %d", serverID, targetUnitID, busNumber))
+
+			return
+		}
+
+		var addDiskError error
+		diskID, addDiskError = apiClient.AddDiskToServer(serverID, &busNumber, &targetUnitID, sizeGB, speed, iops)
+		if compute.IsResourceBusyError(addDiskError) {
+			context.Retry()
+		} else if addDiskError != nil {
+			context.Fail(addDiskError)
+		}
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(diskID)
+
+	log.Printf("Adding disk '%s' (%dGB, speed = '%s') on SCSI bus %d to server '%s'...",
+		diskID,
+		sizeGB,
+		speed,
+		busNumber,
+		serverID,
+	)
+
+	_, err = apiClient.WaitForChange(
+		compute.ResourceTypeServer,
+		serverID,
+		fmt.Sprintf("Add disk '%s'", diskID),
+		resourceUpdateTimeoutServer,
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Added disk '%s' on SCSI bus %d to server '%s'.", diskID, busNumber, serverID)
+
+	err = recordDiskResource(ctx, data, providerState)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDiskRead(ctx, data, provider)
+}
+
+// Read a disk resource.
+func resourceDiskRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	diskID := data.Id()
+	serverID := data.Get(resourceKeyDiskServerID).(string)
+
+	apiClient := provider.(*providerState).Client()
+
+	server, err := apiClient.GetServer(serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if server == nil {
+		log.Printf("Cannot find server '%s' for disk '%s'; will treat the disk as deleted.", serverID, diskID)
+
+		data.SetId("")
+
+		return nil
+	}
+
+	disk, busNumber := findServerDisk(server, diskID)
+	if disk == nil {
+		log.Printf("Cannot find disk '%s' in server '%s'; will treat the disk as deleted.", diskID, serverID)
+
+		data.SetId("")
+
+		return nil
+	}
+
+	data.Set(resourceKeyDiskBusNumber, busNumber)
+	data.Set(resourceKeyDiskUnitID, disk.SCSIUnitID)
+	data.Set(resourceKeyDiskSizeGB, disk.SizeGB)
+	data.Set(resourceKeyDiskSpeed, disk.Speed)
+	data.Set(resourceKeyDiskIops, disk.Iops)
+
+	return nil
+}
+
+// Update a disk resource.
+func resourceDiskUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	diskID := data.Id()
+	serverID := data.Get(resourceKeyDiskServerID).(string)
+
+	providerState := provider.(*providerState)
+	apiClient := providerState.Client()
+
+	if data.HasChange(resourceKeyDiskSizeGB) {
+		sizeGB := data.Get(resourceKeyDiskSizeGB).(int)
+
+		log.Printf("Expanding disk '%s' in server '%s' to %d GB...", diskID, serverID, sizeGB)
+
+		operationDescription := fmt.Sprintf("Expand disk '%s' in server '%s'", diskID, serverID)
+		err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+			asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+			defer asyncLock.Release()
+
+			response, resizeError := apiClient.ExpandDisk(diskID, sizeGB)
+			if compute.IsResourceBusyError(resizeError) {
+				context.Retry()
+			} else if resizeError != nil {
+				context.Fail(resizeError)
+			} else if response.ResponseCode != compute.ResponseCodeInProgress {
+				context.Fail(response.ToError("unexpected response code '%s' when expanding disk '%s' in server '%s'",
+					response.ResponseCode,
+					diskID,
+					serverID,
+				))
+			}
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		_, err = apiClient.WaitForChange(
+			compute.ResourceTypeServer,
+			serverID,
+			fmt.Sprintf("Expand disk '%s'", diskID),
+			resourceUpdateTimeoutServer,
+		)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		log.Printf("Expanded disk '%s' in server '%s' to %d GB.", diskID, serverID, sizeGB)
+	}
+
+	speed := normalizeSpeed(data.Get(resourceKeyDiskSpeed))
+	iops := data.Get(resourceKeyDiskIops).(int)
+	if data.HasChange(resourceKeyDiskSpeed) {
+		oldSpeed, _ := data.GetChange(resourceKeyDiskSpeed)
+
+		changeSpeed := func() error {
+			log.Printf("Changing speed of disk '%s' in server '%s' to '%s'...", diskID, serverID, speed)
+
+			operationDescription := fmt.Sprintf("Change speed of disk '%s' in server '%s'", diskID, serverID)
+			err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+				asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+				defer asyncLock.Release()
+
+				var speedError error
+				if speed == compute.ServerDiskSpeedProvisionedIops {
+					_, speedError = apiClient.ChangeServerDiskSpeed(serverID, diskID, speed, &iops)
+				} else {
+					_, speedError = apiClient.ChangeServerDiskSpeed(serverID, diskID, speed, nil)
+				}
+				if compute.IsResourceBusyError(speedError) {
+					context.Retry()
+				} else if speedError != nil {
+					context.Fail(speedError)
+				}
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = apiClient.WaitForChange(
+				compute.ResourceTypeServer,
+				serverID,
+				fmt.Sprintf("Change speed of disk '%s'", diskID),
+				resourceUpdateTimeoutServer,
+			)
+			if err != nil {
+				return err
+			}
+
+			log.Printf("Changed speed of disk '%s' in server '%s' to '%s'.", diskID, serverID, speed)
+
+			return nil
+		}
+
+		var err error
+		if isDiskPowerCycleRequired(oldSpeed.(string), speed) {
+			err = withServerPoweredOff(ctx, providerState, serverID, changeSpeed)
+		} else {
+			err = changeSpeed()
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else if data.HasChange(resourceKeyDiskIops) && speed == compute.ServerDiskSpeedProvisionedIops {
+		log.Printf("Changing IOPS of disk '%s' in server '%s' to %d...", diskID, serverID, iops)
+
+		operationDescription := fmt.Sprintf("Change IOPS of disk '%s' in server '%s'", diskID, serverID)
+		err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+			asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+			defer asyncLock.Release()
+
+			_, iopsError := apiClient.ChangeServerDiskIops(diskID, iops)
+			if compute.IsResourceBusyError(iopsError) {
+				context.Retry()
+			} else if iopsError != nil {
+				context.Fail(iopsError)
+			}
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		_, err = apiClient.WaitForChange(
+			compute.ResourceTypeServer,
+			serverID,
+			fmt.Sprintf("Change IOPS of disk '%s'", diskID),
+			resourceUpdateTimeoutServer,
+		)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		log.Printf("Changed IOPS of disk '%s' in server '%s' to %d.", diskID, serverID, iops)
+	}
+
+	return resourceDiskRead(ctx, data, provider)
+}
+
+// Delete a disk resource.
+func resourceDiskDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	diskID := data.Id()
+	serverID := data.Get(resourceKeyDiskServerID).(string)
+
+	providerState := provider.(*providerState)
+	apiClient := providerState.Client()
+
+	server, err := apiClient.GetServer(serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if server == nil {
+		log.Printf("Cannot find server '%s' for disk '%s'; will treat the disk as deleted.", serverID, diskID)
+
+		return nil
+	}
+	disk, busNumber := findServerDisk(server, diskID)
+	if disk == nil {
+		log.Printf("Cannot find disk '%s' in server '%s'; will treat the disk as deleted.", diskID, serverID)
+
+		return nil
+	}
+	scsiPath := fmt.Sprintf("%d:%d", busNumber, disk.SCSIUnitID)
+
+	log.Printf("Removing disk '%s' from server '%s'...", diskID, serverID)
+
+	operationDescription := fmt.Sprintf("Remove disk '%s' from server '%s'", diskID, serverID)
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release()
+
+		removeError := apiClient.RemoveDiskFromServer(diskID)
+		if compute.IsResourceBusyError(removeError) {
+			context.Retry()
+		} else if removeError != nil {
+			context.Fail(removeError)
+		}
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_, err = apiClient.WaitForChange(
+		compute.ResourceTypeServer,
+		serverID,
+		fmt.Sprintf("Remove disk '%s'", diskID),
+		resourceUpdateTimeoutServer,
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Removed disk '%s' from server '%s'.", diskID, serverID)
+
+	return diag.FromErr(
+		updateDiskResourceSCSIPaths(providerState, serverID, scsiPath, false),
+	)
+}
+
+// Import data for an existing disk.
+//
+// The import Id is "<server-id>/<disk-id>" (a disk's Id cannot be used to look up its server).
+func resourceDiskImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+	providerState := provider.(*providerState)
+	apiClient := providerState.Client()
+
+	diskID := data.Id()
+	serverID := data.Get(resourceKeyDiskServerID).(string)
+	if importIDParts := strings.SplitN(diskID, "/", 2); len(importIDParts) == 2 {
+		serverID = importIDParts[0]
+		diskID = importIDParts[1]
+	}
+	log.Printf("Import disk '%s' in server '%s'.", diskID, serverID)
+
+	server, err := apiClient.GetServer(serverID)
+	if err != nil {
+		return
+	}
+	if server == nil {
+		err = fmt.Errorf("Server '%s' not found", serverID)
+
+		return
+	}
+	disk, busNumber := findServerDisk(server, diskID)
+	if disk == nil {
+		err = fmt.Errorf("Disk '%s' not found in server '%s'", diskID, serverID)
+
+		return
+	}
+
+	data.SetId(diskID)
+	data.Set(resourceKeyDiskServerID, serverID)
+	data.Set(resourceKeyDiskBusNumber, busNumber)
+	data.Set(resourceKeyDiskUnitID, disk.SCSIUnitID)
+	data.Set(resourceKeyDiskSizeGB, disk.SizeGB)
+	data.Set(resourceKeyDiskSpeed, disk.Speed)
+	data.Set(resourceKeyDiskIops, disk.Iops)
+
+	// From now on, ddcloud_server and ddcloud_storage_controller will ignore this disk.
+	err = recordDiskResource(ctx, data, providerState)
+	if err != nil {
+		return
+	}
+
+	importedData = []*schema.ResourceData{data}
+
+	return
+}
+
+// Find a disk (by Id) in any of the server's SCSI controllers.
+//
+// Returns the disk (nil if not found) and the bus number of the SCSI controller it is attached to.
+func findServerDisk(server *compute.Server, diskID string) (*compute.VirtualMachineDisk, int) {
+	for _, controller := range server.SCSIControllers {
+		for index := range controller.Disks {
+			disk := &controller.Disks[index]
+			if disk.ID == diskID {
+				return disk, controller.BusNumber
+			}
+		}
+	}
+
+	return nil, -1
+}
+
+// Get the lowest SCSI unit ID that is not in use on the specified controller.
+func getNextFreeSCSIUnitID(controller *compute.VirtualMachineSCSIController) (int, error) {
+	for unitID := 0; unitID <= maxSCSIUnitID; unitID++ {
+		if unitID == reservedSCSIUnitID {
+			continue
+		}
+
+		if controller.GetDiskByUnitID(unitID) == nil {
+			return unitID, nil
+		}
+	}
+
+	return -1, fmt.Errorf("no free SCSI unit IDs on SCSI bus %d", controller.BusNumber)
+}
+
+// Record (in the server's "ddcloud_disks" tag) that the disk is managed by a ddcloud_disk resource.
+func recordDiskResource(ctx context.Context, data *schema.ResourceData, providerState *providerState) error {
+	serverID := data.Get(resourceKeyDiskServerID).(string)
+
+	server, err := providerState.Client().GetServer(serverID)
+	if err != nil {
+		return err
+	}
+	if server == nil {
+		return fmt.Errorf("cannot find server '%s'", serverID)
+	}
+
+	disk, busNumber := findServerDisk(server, data.Id())
+	if disk == nil {
+		return fmt.Errorf("cannot find disk '%s' in server '%s'", data.Id(), serverID)
+	}
+
+	return updateDiskResourceSCSIPaths(providerState, serverID,
+		fmt.Sprintf("%d:%d", busNumber, disk.SCSIUnitID),
+		true,
+	)
+}
+
+// Get the SCSI paths of the server's disks that are managed by ddcloud_disk resources (from the server's "ddcloud_disks" tag).
+func getDiskResourceSCSIPaths(apiClient *compute.Client, serverID string) (map[string]bool, error) {
+	tags, err := getTags(apiClient, serverID, compute.AssetTypeServer)
+	if err != nil {
+		return nil, err
+	}
+
+	scsiPaths := make(map[string]bool)
+	for _, tag := range tags {
+		if tag.Name != diskResourcesTagName {
+			continue
+		}
+
+		for _, scsiPath := range strings.Split(tag.Value, ",") {
+			if scsiPath != "" {
+				scsiPaths[scsiPath] = true
+			}
+		}
+	}
+
+	return scsiPaths, nil
+}
+
+// Add or remove a SCSI path in the server's "ddcloud_disks" tag.
+func updateDiskResourceSCSIPaths(providerState *providerState, serverID string, scsiPath string, isDiskResource bool) error {
+	// Serialise read-modify-write of the tag (multiple ddcloud_disk resources for the same server may be created or destroyed concurrently).
+	providerState.stateLock.Lock()
+	defer providerState.stateLock.Unlock()
+
+	apiClient := providerState.Client()
+
+	scsiPaths, err := getDiskResourceSCSIPaths(apiClient, serverID)
+	if err != nil {
+		return err
+	}
+	if scsiPaths[scsiPath] == isDiskResource {
+		return nil
+	}
+
+	if isDiskResource {
+		scsiPaths[scsiPath] = true
+	} else {
+		delete(scsiPaths, scsiPath)
+	}
+
+	var response *compute.APIResponseV2
+	if len(scsiPaths) == 0 {
+		log.Printf("Removing tag '%s' from server '%s'...", diskResourcesTagName, serverID)
+
+		response, err = apiClient.RemoveAssetTags(serverID, compute.AssetTypeServer, diskResourcesTagName)
+	} else {
+		sortedSCSIPaths := make([]string, 0, len(scsiPaths))
+		for path := range scsiPaths {
+			sortedSCSIPaths = append(sortedSCSIPaths, path)
+		}
+		sort.Strings(sortedSCSIPaths)
+
+		tag := compute.Tag{
+			Name:  diskResourcesTagName,
+			Value: strings.Join(sortedSCSIPaths, ","),
+		}
+
+		err = ensureTagKeysAreDefined(apiClient, []compute.Tag{tag})
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Setting tag '%s' on server '%s' to '%s'...", tag.Name, serverID, tag.Value)
+
+		response, err = apiClient.ApplyAssetTags(serverID, compute.AssetTypeServer, tag)
+	}
+	if err != nil {
+		return err
+	}
+	if response.ResponseCode != compute.ResponseCodeOK {
+		return response.ToError("Failed to update tag '%s' on server '%s' (response code '%s'): %s", diskResourcesTagName, serverID, response.ResponseCode, response.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,353 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+/*
+ * Acceptance-test configurations.
+ */
+
+// A server with its image disk declared inline, and an additional disk declared via ddcloud_disk (using the next free SCSI unit ID).
+func testAccDDCloudDiskWithInlineServerDisk(sizeGB int) string {
+	return fmt.Sprintf(`
+		provider "ddcloud" {
+			region		= "AU"
+		}
+
+		resource "ddcloud_networkdomain" "acc_test_domain" {
+			name		= "acc-test-networkdomain"
+			description	= "Network domain for Terraform acceptance test."
+			datacenter	= "AU9"
+		}
+
+		resource "ddcloud_vlan" "acc_test_vlan" {
+			name				= "acc-test-vlan"
+			description 		= "VLAN for Terraform acceptance test."
+
+			networkdomain 		= "${ddcloud_networkdomain.acc_test_domain.id}"
+
+			ipv4_base_address	= "192.168.17.0"
+			ipv4_prefix_size	= 24
+		}
+
+		resource "ddcloud_server" "acc_test_server" {
+			name				= "AccTestDiskServer"
+			description 		= "Server for disk acceptance test"
+			admin_password		= "Snausages!1234"
+
+			memory_gb			= 8
+
+			networkdomain 		= "${ddcloud_networkdomain.acc_test_domain.id}"
+
+			primary_network_adapter {
+				vlan            = "${ddcloud_vlan.acc_test_vlan.id}"
+				ipv4            = "192.168.17.20"
+			}
+
+			dns_primary			= "8.8.8.8"
+			dns_secondary		= "8.8.4.4"
+
+			image				= "CentOS 7 64-bit 2 CPU"
+
+			disk {
+				scsi_unit_id    = 0
+				size_gb         = 10
+				speed           = "STANDARD"
+			}
+		}
+
+		resource "ddcloud_disk" "acc_test_disk" {
+			server				= "${ddcloud_server.acc_test_server.id}"
+			size_gb				= %d
+			speed				= "STANDARD"
+		}
+	`, sizeGB)
+}
+
+/*
+ * Acceptance tests.
+ */
+
+// Acceptance test for ddcloud_disk (alongside an inline server disk):
+//
+// Create the disk, verify that the server's inline disk configuration does not try to remove it, then expand it.
+func TestAccDiskWithInlineServerDiskCreateAndExpand(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testCheckDDCloudDiskDestroy,
+			testCheckDDCloudServerDestroy,
+			testCheckDDCloudVLANDestroy,
+			testCheckDDCloudNetworkDomainDestroy,
+		),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDDCloudDiskWithInlineServerDisk(20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ddcloud_disk.acc_test_disk", "scsi_bus_number", "0"),
+					resource.TestCheckResourceAttr("ddcloud_disk.acc_test_disk", "scsi_unit_id", "1"),
+					testCheckDDCloudServerDiskMatches("ddcloud_server.acc_test_server",
+						testDisk(0, 0, 10, "STANDARD"),
+						testDisk(0, 1, 20, "STANDARD"),
+					),
+				),
+			},
+			resource.TestStep{
+				Config: testAccDDCloudDiskWithInlineServerDisk(30),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ddcloud_disk.acc_test_disk", "size_gb", "30"),
+					testCheckDDCloudServerDiskMatches("ddcloud_server.acc_test_server",
+						testDisk(0, 0, 10, "STANDARD"),
+						testDisk(0, 1, 30, "STANDARD"),
+					),
+				),
+			},
+		},
+	})
+}
+
+// Check all disks specified in the configuration have been destroyed.
+func testCheckDDCloudDiskDestroy(state *terraform.State) error {
+	for resourceName, resource := range state.RootModule().Resources {
+		if resource.Type != "ddcloud_disk" {
+			continue
+		}
+
+		diskID := resource.Primary.ID
+		serverID := resource.Primary.Attributes[resourceKeyDiskServerID]
+
+		client := testAccProvider.Meta().(*providerState).Client()
+		server, err := client.GetServer(serverID)
+		if err != nil {
+			return nil
+		}
+		if server == nil {
+			continue
+		}
+
+		if disk, _ := findServerDisk(server, diskID); disk != nil {
+			return fmt.Errorf("bad %s: disk '%s' still exists in server '%s'", resourceName, diskID, serverID)
+		}
+	}
+
+	return nil
+}
+
+/*
+ * Unit tests.
+ */
+
+// Unit test - getNextFreeSCSIUnitID skips used unit IDs and the controller's reserved unit ID.
+func TestGetNextFreeSCSIUnitID(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	controller := &compute.VirtualMachineSCSIController{
+		BusNumber: 1,
+	}
+	unitID, err := getNextFreeSCSIUnitID(controller)
+	assert.IsTrue("err == nil", err == nil)
+	assert.EqualsInt("UnitID", 0, unitID)
+
+	for usedUnitID := 0; usedUnitID < 7; usedUnitID++ {
+		controller.Disks = append(controller.Disks, compute.VirtualMachineDisk{
+			ID:         fmt.Sprintf("disk%d", usedUnitID),
+			SCSIUnitID: usedUnitID,
+		})
+	}
+	unitID, err = getNextFreeSCSIUnitID(controller)
+	assert.IsTrue("err == nil", err == nil)
+	assert.EqualsInt("UnitID", 8, unitID)
+
+	for usedUnitID := 8; usedUnitID <= maxSCSIUnitID; usedUnitID++ {
+		controller.Disks = append(controller.Disks, compute.VirtualMachineDisk{
+			ID:         fmt.Sprintf("disk%d", usedUnitID),
+			SCSIUnitID: usedUnitID,
+		})
+	}
+	_, err = getNextFreeSCSIUnitID(controller)
+	assert.IsTrue("err != nil", err != nil)
+}
+
+// Unit test - findServerDisk locates disks on any SCSI controller.
+func TestFindServerDisk(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	server := &compute.Server{
+		SCSIControllers: compute.VirtualMachineSCSIControllers{
+			compute.VirtualMachineSCSIController{
+				BusNumber: 0,
+				Disks: compute.VirtualMachineDisks{
+					compute.VirtualMachineDisk{ID: "disk0", SCSIUnitID: 0},
+				},
+			},
+			compute.VirtualMachineSCSIController{
+				BusNumber: 1,
+				Disks: compute.VirtualMachineDisks{
+					compute.VirtualMachineDisk{ID: "disk1", SCSIUnitID: 2},
+				},
+			},
+		},
+	}
+
+	disk, busNumber := findServerDisk(server, "disk1")
+	assert.IsTrue("disk != nil", disk != nil)
+	assert.EqualsInt("BusNumber", 1, busNumber)
+	assert.EqualsInt("SCSIUnitID", 2, disk.SCSIUnitID)
+
+	disk, _ = findServerDisk(server, "disk2")
+	assert.IsTrue("disk == nil", disk == nil)
+}
+
+// Unit test - import a disk using "<server-id>/<disk-id>".
+func TestDiskImport(test *testing.T) {
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.ComputeServer = &compute.Server{
+		ID: "server-1",
+		SCSIControllers: compute.VirtualMachineSCSIControllers{
+			compute.VirtualMachineSCSIController{
+				BusNumber: 1,
+				Disks: compute.VirtualMachineDisks{
+					compute.VirtualMachineDisk{ID: "disk1", SCSIUnitID: 2, SizeGB: 20, Speed: compute.ServerDiskSpeedStandard},
+				},
+			},
+		},
+	}
+
+	data := resourceDisk().Data(nil)
+	data.SetId("server-1/disk1")
+
+	importedData, err := resourceDiskImport(context.Background(), data, standIn.NewProviderState())
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("err == nil", err == nil)
+	assert.EqualsInt("len(importedData)", 1, len(importedData))
+	assert.EqualsString("Id", "disk1", data.Id())
+	assert.EqualsString("server", "server-1", data.Get(resourceKeyDiskServerID).(string))
+	assert.EqualsInt("scsi_bus_number", 1, data.Get(resourceKeyDiskBusNumber).(int))
+	assert.EqualsInt("scsi_unit_id", 2, data.Get(resourceKeyDiskUnitID).(int))
+	assert.EqualsInt("size_gb", 20, data.Get(resourceKeyDiskSizeGB).(int))
+
+	// The imported disk is now ignored by ddcloud_server / ddcloud_storage_controller.
+	assert.EqualsInt("len(ServerTags)", 1, len(standIn.ServerTags))
+	assert.EqualsString("ServerTags[0].Name", diskResourcesTagName, standIn.ServerTags[0].Name)
+	assert.EqualsString("ServerTags[0].Value", "1:2", standIn.ServerTags[0].Value)
+
+	data = resourceDisk().Data(nil)
+	data.SetId("server-1/disk2")
+
+	_, err = resourceDiskImport(context.Background(), data, standIn.NewProviderState())
+	assert.IsTrue("err != nil (unknown disk)", err != nil)
+}
+
+// Unit test - record and remove the SCSI paths of disks managed by ddcloud_disk resources.
+func TestDiskResourceSCSIPaths(test *testing.T) {
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.ServerTags = []compute.Tag{
+		{Name: "role", Value: "web"},
+	}
+	standIn.TagKeys = []string{"role"}
+
+	providerState := standIn.NewProviderState()
+
+	assert := assert.ForTest(test)
+
+	err := updateDiskResourceSCSIPaths(providerState, "server-1", "1:0", true)
+	assert.IsTrue("err == nil (add 1:0)", err == nil)
+	err = updateDiskResourceSCSIPaths(providerState, "server-1", "0:1", true)
+	assert.IsTrue("err == nil (add 0:1)", err == nil)
+
+	assert.EqualsInt("len(TagKeys)", 2, len(standIn.TagKeys))
+	assert.EqualsString("TagKeys[1]", diskResourcesTagName, standIn.TagKeys[1])
+	assert.EqualsInt("len(ServerTags)", 2, len(standIn.ServerTags))
+	assert.EqualsString("ServerTags[1].Value", "0:1,1:0", standIn.ServerTags[1].Value)
+
+	scsiPaths, err := getDiskResourceSCSIPaths(providerState.Client(), "server-1")
+	assert.IsTrue("err == nil (get)", err == nil)
+	assert.EqualsInt("len(scsiPaths)", 2, len(scsiPaths))
+	assert.IsTrue("scsiPaths[0:1]", scsiPaths["0:1"])
+	assert.IsTrue("scsiPaths[1:0]", scsiPaths["1:0"])
+
+	err = updateDiskResourceSCSIPaths(providerState, "server-1", "1:0", false)
+	assert.IsTrue("err == nil (remove 1:0)", err == nil)
+	assert.EqualsString("ServerTags[1].Value", "0:1", standIn.ServerTags[1].Value)
+
+	err = updateDiskResourceSCSIPaths(providerState, "server-1", "0:1", false)
+	assert.IsTrue("err == nil (remove 0:1)", err == nil)
+	assert.EqualsInt("len(ServerTags)", 1, len(standIn.ServerTags))
+	assert.EqualsString("ServerTags[0].Name", "role", standIn.ServerTags[0].Name)
+}
+
+// Compute the diff for changing an existing disk (via a provider that allows server reboots).
+func testDiskChangeDiff(sizeGB int, speed string, iops int) (*terraform.InstanceDiff, error) {
+	state := &terraform.InstanceState{
+		ID: "disk-1",
+		Attributes: map[string]string{
+			"id":                     "disk-1",
+			resourceKeyDiskServerID:  "server-1",
+			resourceKeyDiskBusNumber: "0",
+			resourceKeyDiskUnitID:    "1",
+			resourceKeyDiskSizeGB:    "20",
+			resourceKeyDiskSpeed:     "STANDARD",
+			resourceKeyDiskIops:      "0",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeyDiskServerID: "server-1",
+		resourceKeyDiskSizeGB:   sizeGB,
+		resourceKeyDiskSpeed:    speed,
+		resourceKeyDiskIops:     iops,
+	})
+
+	providerState := &providerState{
+		settings: &ProviderSettings{
+			AllowServerReboots: true,
+		},
+	}
+
+	return resourceDisk().Diff(context.Background(), state, config, providerState)
+}
+
+// Unit test - ddcloud_disk plan accepts expansion and a (power-cycling) change to provisioned IOPS.
+func TestDiskCustomizeDiffValidChange(test *testing.T) {
+	diff, err := testDiskChangeDiff(30, "PROVISIONEDIOPS", 300)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("diff != nil", diff != nil)
+	assert.IsFalse("diff.RequiresNew", diff.RequiresNew())
+	assert.IsTrue("isDiskPowerCycleRequired", isDiskPowerCycleRequired("STANDARD", "PROVISIONEDIOPS"))
+	assert.IsFalse("!isDiskPowerCycleRequired", isDiskPowerCycleRequired("STANDARD", "ECONOMY"))
+}
+
+// Unit test - ddcloud_disk plan rejects shrinking the disk.
+func TestDiskCustomizeDiffShrink(test *testing.T) {
+	_, err := testDiskChangeDiff(10, "STANDARD", 0)
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("Diff fails", err != nil)
+	assert.IsTrue("Error mentions expansion", strings.Contains(err.Error(), "can only be expanded"))
+}
+
+// Unit test - ddcloud_disk plan rejects a provisioned-IOPS disk that does not specify its IOPS.
+func TestDiskCustomizeDiffMissingIops(test *testing.T) {
+	_, err := testDiskChangeDiff(20, "PROVISIONEDIOPS", 0)
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("Diff fails", err != nil)
+	assert.IsTrue("Error mentions iops", strings.Contains(err.Error(), "iops:"))
+}
@@ -1,6 +1,7 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strconv"
@@ -9,7 +10,8 @@ import (
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -41,12 +43,12 @@ const computedPropertyDescription = "<computed>"
 
 func resourceFirewallRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceFirewallRuleCreate,
-		Read:   resourceFirewallRuleRead,
-		Update: resourceFirewallRuleUpdate,
-		Delete: resourceFirewallRuleDelete,
+		CreateContext: resourceFirewallRuleCreate,
+		ReadContext:   resourceFirewallRuleRead,
+		UpdateContext: resourceFirewallRuleUpdate,
+		DeleteContext: resourceFirewallRuleDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceFirewallRuleImport,
+			StateContext: resourceFirewallRuleImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -198,7 +200,7 @@ func resourceFirewallRule() *schema.Resource {
 }
 
 // Create a firewall rule resource.
-func resourceFirewallRuleCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceFirewallRuleCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	var err error
 
 	propertyHelper := propertyHelper(data)
@@ -228,11 +230,11 @@ func resourceFirewallRuleCreate(data *schema.ResourceData, provider interface{})
 
 	err = configureSourceScope(propertyHelper, configuration)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	err = configureDestinationScope(propertyHelper, configuration)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Create firewall rule '%s' in network domain '%s'.", configuration.Name, configuration.NetworkDomainID)
@@ -246,7 +248,7 @@ func resourceFirewallRuleCreate(data *schema.ResourceData, provider interface{})
 		createError error
 	)
 	operationDescription := fmt.Sprintf("Create firewall rule '%s'", configuration.Name)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
@@ -263,18 +265,18 @@ func resourceFirewallRuleCreate(data *schema.ResourceData, provider interface{})
 		asyncLock.Release()
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(ruleID)
 
 	_, err = apiClient.WaitForDeploy(compute.ResourceTypeFirewallRule, ruleID, resourceCreateTimeoutFirewallRule)
 
-	return err
+	return diag.FromErr(err)
 }
 
 // Read a firewall rule resource.
-func resourceFirewallRuleRead(data *schema.ResourceData, provider interface{}) error {
+func resourceFirewallRuleRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeyFirewallRuleNetworkDomainID).(string)
 
@@ -284,7 +286,7 @@ func resourceFirewallRuleRead(data *schema.ResourceData, provider interface{}) e
 
 	rule, err := apiClient.GetFirewallRule(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if rule == nil {
 		log.Printf("Firewall rule '%s' has been deleted.", id)
@@ -300,7 +302,7 @@ func resourceFirewallRuleRead(data *schema.ResourceData, provider interface{}) e
 }
 
 // Update a firewall rule resource.
-func resourceFirewallRuleUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceFirewallRuleUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeyFirewallRuleNetworkDomainID).(string)
 
@@ -320,7 +322,7 @@ func resourceFirewallRuleUpdate(data *schema.ResourceData, provider interface{})
 
 		err := apiClient.EditFirewallRule(id, enable)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		log.Printf("Updated configuration for firewall rule '%s'.", id)
@@ -330,7 +332,7 @@ func resourceFirewallRuleUpdate(data *schema.ResourceData, provider interface{})
 }
 
 // Delete a firewall rule resource.
-func resourceFirewallRuleDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceFirewallRuleDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeyFirewallRuleNetworkDomainID).(string)
 
@@ -341,7 +343,7 @@ func resourceFirewallRuleDelete(data *schema.ResourceData, provider interface{})
 
 	var deleteError error
 	operationDescription := fmt.Sprintf("Delete firewall rule '%s'", id)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
@@ -358,14 +360,14 @@ func resourceFirewallRuleDelete(data *schema.ResourceData, provider interface{})
 		asyncLock.Release()
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return apiClient.WaitForDelete(compute.ResourceTypeFirewallRule, id, resourceDeleteTimeoutFirewallRule)
+	return diag.FromErr(apiClient.WaitForDelete(compute.ResourceTypeFirewallRule, id, resourceDeleteTimeoutFirewallRule))
 }
 
 // Import data for an existing firewall rule.
-func resourceFirewallRuleImport(data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+func resourceFirewallRuleImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
@@ -1,13 +1,14 @@
 package ddcloud
 
 import (
+	"fmt"
 	"log"
 	"strconv"
 	"strings"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // resourcePropertyHelper provides commonly-used functionality for working with Terraform's schema.ResourceData.
@@ -153,10 +154,6 @@ func (helper resourcePropertyHelper) SetStringListItems(key string, items []stri
 	return helper.data.Set(key, rawItems)
 }
 
-func (helper resourcePropertyHelper) SetPartial(key string) {
-	helper.data.SetPartial(key)
-}
-
 func (helper resourcePropertyHelper) GetTags(key string) (tags []compute.Tag) {
 	value, ok := helper.data.GetOk(key)
 	if !ok {
@@ -459,12 +456,8 @@ func (helper resourcePropertyHelper) GetOldServerNetworkAdapters() (networkAdapt
 	return
 }
 
-func (helper resourcePropertyHelper) SetServerNetworkAdapters(networkAdapters models.NetworkAdapters, isPartial bool) {
+func (helper resourcePropertyHelper) SetServerNetworkAdapters(networkAdapters models.NetworkAdapters) {
 	data := helper.data
-	if isPartial {
-		data.SetPartial(resourceKeyServerPrimaryNetworkAdapter)
-		data.SetPartial(resourceKeyServerAdditionalNetworkAdapter)
-	}
 
 	if networkAdapters.IsEmpty() {
 		data.Set(resourceKeyServerPrimaryNetworkAdapter, [0]interface{}{})
@@ -528,15 +521,8 @@ func (helper resourcePropertyHelper) GetNetworkAdapter() models.NetworkAdapter {
 	return *networkAdapter
 }
 
-func (helper resourcePropertyHelper) SetNetworkAdapter(networkAdapter models.NetworkAdapter, isPartial bool) {
+func (helper resourcePropertyHelper) SetNetworkAdapter(networkAdapter models.NetworkAdapter) {
 	data := helper.data
-	if isPartial {
-		data.SetPartial(resourceKeyNetworkAdapterMACAddress)
-		data.SetPartial(resourceKeyNetworkAdapterVLANID)
-		data.SetPartial(resourceKeyNetworkAdapterPrivateIPV4)
-		data.SetPartial(resourceKeyNetworkAdapterPrivateIPV6)
-		data.SetPartial(resourceKeyNetworkAdapterType)
-	}
 
 	data.Set(resourceKeyNetworkAdapterMACAddress, networkAdapter.MACAddress)
 	data.Set(resourceKeyNetworkAdapterVLANID, networkAdapter.VLANID)
@@ -576,35 +562,29 @@ func (helper resourcePropertyHelper) GetVirtualListenerIRuleNames(apiClient *com
 }
 
 func (helper resourcePropertyHelper) GetVirtualListenerIRules(apiClient *compute.Client) (iRules []compute.EntityReference, err error) {
-	value, ok := helper.data.GetOk(resourceKeyVirtualListenerIRuleNames)
-	if !ok {
-		return
-	}
-	iRuleNames := value.(*schema.Set)
-	if iRuleNames.Len() == 0 {
+	iRuleNames := helper.GetStringSetItems(resourceKeyVirtualListenerIRuleNames)
+	if len(iRuleNames) == 0 {
 		return
 	}
 
 	networkDomainID := helper.data.Get(resourceKeyVirtualListenerNetworkDomainID).(string)
 
-	page := compute.DefaultPaging()
-	for {
-		var results *compute.IRules
-		results, err = apiClient.ListDefaultIRules(networkDomainID, page)
-		if err != nil {
-			return
-		}
-		if results.IsEmpty() {
-			break // We're done
-		}
+	availableIRules, err := listIRules(networkDomainID, apiClient)
+	if err != nil {
+		return
+	}
 
-		for _, iRule := range results.Items {
-			if iRuleNames.Contains(iRule.Name) {
-				iRules = append(iRules, iRule.ToEntityReference())
-			}
-		}
+	matchingIRules, missingNames := findIRulesByName(availableIRules, iRuleNames)
+	if len(missingNames) > 0 {
+		err = fmt.Errorf("cannot find iRule(s) named '%s' in network domain '%s'",
+			strings.Join(missingNames, "', '"), networkDomainID,
+		)
 
-		page.Next()
+		return
+	}
+
+	for _, iRule := range matchingIRules {
+		iRules = append(iRules, iRule.ToEntityReference())
 	}
 
 	return
@@ -642,29 +622,21 @@ func (helper resourcePropertyHelper) GetVirtualListenerPersistenceProfile(apiCli
 
 	networkDomainID := helper.data.Get(resourceKeyVirtualListenerNetworkDomainID).(string)
 
-	page := compute.DefaultPaging()
-	for {
-		var persistenceProfiles *compute.PersistenceProfiles
-		persistenceProfiles, err = apiClient.ListDefaultPersistenceProfiles(networkDomainID, page)
-		if err != nil {
-			return
-		}
-		if persistenceProfiles.IsEmpty() {
-			break // We're done
-		}
-
-		for _, profile := range persistenceProfiles.Items {
-			if profile.Name == persistenceProfileName {
-				persistenceProfileReference := profile.ToEntityReference()
-				persistenceProfile = &persistenceProfileReference
+	persistenceProfiles, err := listPersistenceProfiles(networkDomainID, apiClient)
+	if err != nil {
+		return
+	}
 
-				return
-			}
-		}
+	profile := findPersistenceProfileByName(persistenceProfiles, persistenceProfileName)
+	if profile == nil {
+		err = fmt.Errorf("cannot find persistence profile named '%s' in network domain '%s'", persistenceProfileName, networkDomainID)
 
-		page.Next()
+		return
 	}
 
+	persistenceProfileReference := profile.ToEntityReference()
+	persistenceProfile = &persistenceProfileReference
+
 	return
 }
 
@@ -1,13 +1,16 @@
 package ddcloud
 
 import (
+	"context"
+
 	"fmt"
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"log"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/validators"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -21,9 +24,9 @@ const (
 
 func resourceIPAddressReservation() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceIPAddressReservationCreate,
-		Read:   resourceIPAddressReservationRead,
-		Delete: resourceIPAddressReservationDelete,
+		CreateContext: resourceIPAddressReservationCreate,
+		ReadContext:   resourceIPAddressReservationRead,
+		DeleteContext: resourceIPAddressReservationDelete,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyIPAddressReservationVLANID: &schema.Schema{
@@ -100,7 +103,7 @@ func resourceIPAddressReservationExists(data *schema.ResourceData, provider inte
 	return exists, nil
 }
 
-func resourceIPAddressReservationCreate(data *schema.ResourceData, provider interface{}) (err error) {
+func resourceIPAddressReservationCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	vlanID := data.Get(resourceKeyIPAddressReservationVLANID).(string)
 	address := data.Get(resourceKeyIPAddressReservationAddress).(string)
 	addressType := data.Get(resourceKeyIPAddressReservationAddressType).(string)
@@ -117,7 +120,7 @@ func resourceIPAddressReservationCreate(data *schema.ResourceData, provider inte
 	apiClient := providerState.Client()
 
 	operationDescription := fmt.Sprintf("Reserve IP Address '%s'", description)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock("Reserve IP Address '%s'", description)
 		defer asyncLock.Release()
@@ -145,7 +148,7 @@ func resourceIPAddressReservationCreate(data *schema.ResourceData, provider inte
 	})
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(fmt.Sprintf("%s/%s",
@@ -159,10 +162,10 @@ func resourceIPAddressReservationCreate(data *schema.ResourceData, provider inte
 		vlanID,
 	)
 
-	return
+	return nil
 }
 
-func resourceIPAddressReservationRead(data *schema.ResourceData, provider interface{}) (err error) {
+func resourceIPAddressReservationRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 
 	log.Println("resourceIPAddressReservationRead")
 
@@ -180,10 +183,9 @@ func resourceIPAddressReservationRead(data *schema.ResourceData, provider interf
 
 	providerState := provider.(*providerState)
 
-	var reservedIPAddresses map[string]compute.ReservedIPAddress
-	reservedIPAddresses, err = getReservedIPAddresses(vlanID, addressType, providerState)
+	reservedIPAddresses, err := getReservedIPAddresses(vlanID, addressType, providerState)
 	if err != nil {
-		return
+		return diag.FromErr(err)
 	}
 
 	ipAddrReserved, exists := reservedIPAddresses[address]
@@ -200,7 +202,7 @@ func resourceIPAddressReservationRead(data *schema.ResourceData, provider interf
 	return nil
 }
 
-func resourceIPAddressReservationDelete(data *schema.ResourceData, provider interface{}) (err error) {
+func resourceIPAddressReservationDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	vlanID := data.Get(resourceKeyIPAddressReservationVLANID).(string)
 	address := data.Get(resourceKeyIPAddressReservationAddress).(string)
 	addressType := data.Get(resourceKeyIPAddressReservationAddressType).(string)
@@ -209,8 +211,9 @@ func resourceIPAddressReservationDelete(data *schema.ResourceData, provider inte
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
+	var err error
 	operationDescription := fmt.Sprintf("Delete Reserved IP Address '%s'", description)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock("Delete Reserved IP Address '%s'", description)
 		defer asyncLock.Release()
@@ -232,7 +235,7 @@ func resourceIPAddressReservationDelete(data *schema.ResourceData, provider inte
 
 	data.SetId("")
 
-	return
+	return diag.FromErr(err)
 }
 
 func getReservedIPAddresses(vlanID string, addressType string, providerState *providerState) (map[string]compute.ReservedIPAddress, error) {
@@ -0,0 +1,1310 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	resourceKeyLoadBalancerName                    = "name"
+	resourceKeyLoadBalancerDescription             = "description"
+	resourceKeyLoadBalancerNetworkDomainID         = "networkdomain"
+	resourceKeyLoadBalancerBackend                 = "backend"
+	resourceKeyLoadBalancerBackendIPv4Address      = "ipv4"
+	resourceKeyLoadBalancerBackendPort             = "port"
+	resourceKeyLoadBalancerNodeConnectionLimit     = "node_connection_limit"
+	resourceKeyLoadBalancerNodeConnectionRateLimit = "node_connection_rate_limit"
+	resourceKeyLoadBalancerLoadBalanceMethod       = "load_balance_method"
+	resourceKeyLoadBalancerHealthMonitorNames      = "health_monitors"
+	resourceKeyLoadBalancerSlowRampTime            = "slow_ramp_time"
+	resourceKeyLoadBalancerProtocol                = "protocol"
+	resourceKeyLoadBalancerPort                    = "port"
+	resourceKeyLoadBalancerIPv4Address             = "ipv4"
+	resourceKeyLoadBalancerOptimizationProfile     = "optimization_profile"
+	resourceKeyLoadBalancerPersistenceProfile      = "persistence_profile"
+	resourceKeyLoadBalancerConnectionLimit         = "connection_limit"
+	resourceKeyLoadBalancerConnectionRateLimit     = "connection_rate_limit"
+	resourceKeyLoadBalancerCertificate             = "certificate"
+	resourceKeyLoadBalancerPrivateKey              = "private_key"
+	resourceKeyLoadBalancerCertificateChain        = "certificate_chain"
+	resourceKeyLoadBalancerPoolID                  = "pool"
+	resourceKeyLoadBalancerSSLDomainCertificate    = "ssl_domain_certificate"
+	resourceKeyLoadBalancerSSLCertificateChain     = "ssl_certificate_chain"
+	resourceKeyLoadBalancerSSLOffloadProfile       = "ssl_offload_profile"
+	resourceKeyLoadBalancerMember                  = "member"
+	resourceKeyLoadBalancerMemberID                = "id"
+	resourceKeyLoadBalancerMemberNodeID            = "node"
+	resourceKeyLoadBalancerMemberIPv4Address       = "ipv4"
+	resourceKeyLoadBalancerMemberPort              = "port"
+)
+
+func resourceLoadBalancer() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLoadBalancerCreate,
+		ReadContext:   resourceLoadBalancerRead,
+		UpdateContext: resourceLoadBalancerUpdate,
+		DeleteContext: resourceLoadBalancerDelete,
+		CustomizeDiff: resourceLoadBalancerCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			resourceKeyLoadBalancerName: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "A name for the load balancer (also used as the prefix for the names of its underlying resources)",
+			},
+			resourceKeyLoadBalancerDescription: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "A description of the load balancer",
+			},
+			resourceKeyLoadBalancerNetworkDomainID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The Id of the network domain in which the load balancer is created",
+			},
+			resourceKeyLoadBalancerBackend: &schema.Schema{
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "The back-end servers to which the load balancer forwards traffic",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						resourceKeyLoadBalancerBackendIPv4Address: &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The back-end server's IPv4 address",
+						},
+						resourceKeyLoadBalancerBackendPort: &schema.Schema{
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "The port on the back-end server to which traffic is forwarded (0 means the port on which the traffic was received)",
+						},
+					},
+				},
+			},
+			resourceKeyLoadBalancerNodeConnectionLimit: &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      20000,
+				Description:  "The number of active connections that each of the load balancer's VIP nodes supports",
+				ValidateFunc: validateLoadBalancerConnectionLimit,
+			},
+			resourceKeyLoadBalancerNodeConnectionRateLimit: &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2000,
+				Description:  "The number of connections per second that each of the load balancer's VIP nodes supports",
+				ValidateFunc: validateLoadBalancerConnectionLimit,
+			},
+			resourceKeyLoadBalancerLoadBalanceMethod: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     compute.LoadBalanceMethodRoundRobin,
+				Description: "The load-balancing method used by the load balancer's VIP pool",
+			},
+			resourceKeyLoadBalancerHealthMonitorNames: &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				MaxItems:    2,
+				Description: "The names of the health monitors (if any) used by the load balancer's VIP pool",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set: schema.HashString,
+			},
+			resourceKeyLoadBalancerSlowRampTime: &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     10,
+				Description: "The time, in seconds, over which the load balancer ramps new back-end servers up to their full request rate",
+			},
+			resourceKeyLoadBalancerProtocol: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     compute.VirtualListenerStandardProtocolHTTP,
+				Description: "The protocol supported by the load balancer's virtual listener",
+			},
+			resourceKeyLoadBalancerPort: &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The port on which the load balancer's virtual listener accepts traffic",
+			},
+			resourceKeyLoadBalancerIPv4Address: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The IPv4 address on which the load balancer's virtual listener accepts traffic (if not specified, a public IPv4 address is allocated)",
+			},
+			resourceKeyLoadBalancerOptimizationProfile: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The optimisation profile for the load balancer's virtual listener",
+			},
+			resourceKeyLoadBalancerPersistenceProfile: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The name of the persistence profile (if any) used by the load balancer's virtual listener",
+			},
+			resourceKeyLoadBalancerConnectionLimit: &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      20000,
+				Description:  "The number of active connections that the load balancer's virtual listener supports",
+				ValidateFunc: validateLoadBalancerConnectionLimit,
+			},
+			resourceKeyLoadBalancerConnectionRateLimit: &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2000,
+				Description:  "The number of connections per second that the load balancer's virtual listener supports",
+				ValidateFunc: validateLoadBalancerConnectionLimit,
+			},
+			resourceKeyLoadBalancerCertificate: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The SSL certificate (in PEM format) used to offload SSL for the load balancer",
+				RequiredWith: []string{
+					resourceKeyLoadBalancerPrivateKey,
+					resourceKeyLoadBalancerCertificateChain,
+				},
+			},
+			resourceKeyLoadBalancerPrivateKey: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Sensitive:   true,
+				Description: "The SSL certificate's private key (in PEM format)",
+				RequiredWith: []string{
+					resourceKeyLoadBalancerCertificate,
+				},
+			},
+			resourceKeyLoadBalancerCertificateChain: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The SSL certificate chain (in PEM format)",
+				RequiredWith: []string{
+					resourceKeyLoadBalancerCertificate,
+				},
+			},
+			resourceKeyLoadBalancerPoolID: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Id of the load balancer's VIP pool",
+			},
+			resourceKeyLoadBalancerSSLDomainCertificate: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Id of the load balancer's SSL domain certificate (if any)",
+			},
+			resourceKeyLoadBalancerSSLCertificateChain: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Id of the load balancer's SSL certificate chain (if any)",
+			},
+			resourceKeyLoadBalancerSSLOffloadProfile: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Id of the load balancer's SSL-offload profile (if any)",
+			},
+			resourceKeyLoadBalancerMember: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The VIP pool members (and VIP nodes) that represent the load balancer's back-end servers",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						resourceKeyLoadBalancerMemberID: &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						resourceKeyLoadBalancerMemberNodeID: &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						resourceKeyLoadBalancerMemberIPv4Address: &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						resourceKeyLoadBalancerMemberPort: &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// A back-end server for a load balancer.
+type loadBalancerBackend struct {
+	IPv4Address string
+	Port        int
+}
+
+// The connection limits for a load balancer's VIP nodes.
+type loadBalancerConnectionLimits struct {
+	ConnectionLimit     int
+	ConnectionRateLimit int
+}
+
+// A VIP pool member (and its VIP node) representing a load balancer's back-end server.
+type loadBalancerMember struct {
+	loadBalancerBackend
+
+	ID     string
+	NodeID string
+}
+
+// Create a ddcloud_load_balancer resource.
+//
+// If any step fails, the underlying resources created by the previous steps are deleted.
+func resourceLoadBalancerCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	name := data.Get(resourceKeyLoadBalancerName).(string)
+	description := data.Get(resourceKeyLoadBalancerDescription).(string)
+	networkDomainID := data.Get(resourceKeyLoadBalancerNetworkDomainID).(string)
+
+	log.Printf("Create load balancer '%s' ('%s') in network domain '%s'.", name, description, networkDomainID)
+
+	providerState := provider.(*providerState)
+	apiClient := providerState.Client()
+
+	propertyHelper := propertyHelper(data)
+
+	// Resolve names to Ids before creating anything.
+	healthMonitorIDs, err := getLoadBalancerHealthMonitorIDs(data, apiClient)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	persistenceProfileID, err := getLoadBalancerPersistenceProfileID(data, apiClient)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rollback := &rollbackActions{}
+
+	// SSL offload (optional).
+	var sslDomainCertificateID, sslCertificateChainID, sslOffloadProfileID string
+	certificatePEM := data.Get(resourceKeyLoadBalancerCertificate).(string)
+	if certificatePEM != "" {
+		versionSuffix := newSSLResourceVersionSuffix()
+
+		sslDomainCertificateID, err = importLoadBalancerCertificate(ctx, providerState, rollback, name, networkDomainID, versionSuffix,
+			certificatePEM,
+			data.Get(resourceKeyLoadBalancerPrivateKey).(string),
+		)
+		if err != nil {
+			return rollbackLoadBalancer(name, "create", rollback, err)
+		}
+
+		sslCertificateChainID, err = importLoadBalancerCertificateChain(ctx, providerState, rollback, name, networkDomainID, versionSuffix,
+			data.Get(resourceKeyLoadBalancerCertificateChain).(string),
+		)
+		if err != nil {
+			return rollbackLoadBalancer(name, "create", rollback, err)
+		}
+
+		operationDescription := fmt.Sprintf("Create SSL-offload profile for load balancer '%s'", name)
+		err = runLoadBalancerOperation(ctx, providerState, operationDescription, func() (createError error) {
+			sslOffloadProfileID, createError = apiClient.CreateSSLOffloadProfile(networkDomainID, name+"-offload", description, nil, sslDomainCertificateID, sslCertificateChainID)
+
+			return
+		})
+		if err != nil {
+			return rollbackLoadBalancer(name, "create", rollback, err)
+		}
+		rollback.Add(fmt.Sprintf("delete SSL-offload profile '%s'", sslOffloadProfileID), func() error {
+			return deleteLoadBalancerSSLOffloadProfile(context.Background(), providerState, sslOffloadProfileID)
+		})
+	}
+
+	// VIP pool.
+	var poolID string
+	operationDescription := fmt.Sprintf("Create VIP pool for load balancer '%s'", name)
+	err = runLoadBalancerOperation(ctx, providerState, operationDescription, func() (createError error) {
+		poolID, createError = apiClient.CreateVIPPool(compute.NewVIPPoolConfiguration{
+			Name:              name + "-pool",
+			Description:       description,
+			LoadBalanceMethod: data.Get(resourceKeyLoadBalancerLoadBalanceMethod).(string),
+			HealthMonitorIDs:  healthMonitorIDs,
+			ServiceDownAction: compute.ServiceDownActionNone,
+			SlowRampTime:      data.Get(resourceKeyLoadBalancerSlowRampTime).(int),
+			NetworkDomainID:   networkDomainID,
+		})
+
+		return
+	})
+	if err != nil {
+		return rollbackLoadBalancer(name, "create", rollback, err)
+	}
+	rollback.Add(fmt.Sprintf("delete VIP pool '%s'", poolID), func() error {
+		return deleteLoadBalancerPool(context.Background(), providerState, poolID)
+	})
+
+	// VIP nodes and pool members.
+	members, err := addLoadBalancerBackends(ctx, providerState, rollback, name, networkDomainID, poolID, getLoadBalancerNodeConnectionLimits(data), nil, getLoadBalancerBackends(data))
+	if err != nil {
+		return rollbackLoadBalancer(name, "create", rollback, err)
+	}
+
+	// Virtual listener.
+	var virtualListenerID string
+	operationDescription = fmt.Sprintf("Create virtual listener for load balancer '%s'", name)
+	err = runLoadBalancerOperation(ctx, providerState, operationDescription, func() (createError error) {
+		configuration := compute.NewVirtualListenerConfiguration{
+			Name:                   name,
+			Description:            description,
+			Type:                   compute.VirtualListenerTypeStandard,
+			Protocol:               data.Get(resourceKeyLoadBalancerProtocol).(string),
+			Port:                   data.Get(resourceKeyLoadBalancerPort).(int),
+			ListenerIPAddress:      propertyHelper.GetOptionalString(resourceKeyLoadBalancerIPv4Address, false),
+			Enabled:                true,
+			ConnectionLimit:        data.Get(resourceKeyLoadBalancerConnectionLimit).(int),
+			ConnectionRateLimit:    data.Get(resourceKeyLoadBalancerConnectionRateLimit).(int),
+			SourcePortPreservation: compute.SourcePortPreservationEnabled,
+			PoolID:                 &poolID,
+			PersistenceProfileID:   persistenceProfileID,
+			OptimizationProfile:    propertyHelper.GetOptionalString(resourceKeyLoadBalancerOptimizationProfile, false),
+			NetworkDomainID:        networkDomainID,
+		}
+		if sslOffloadProfileID != "" {
+			configuration.SSLOffloadProfileID = &sslOffloadProfileID
+		}
+
+		virtualListenerID, createError = apiClient.CreateVirtualListener(configuration)
+		if compute.IsNoIPAddressAvailableError(createError) {
+			log.Printf("There are no free public IPv4 addresses in network domain '%s'; requesting allocation of a new address block...", networkDomainID)
+
+			_, createError = addPublicIPBlock(networkDomainID, apiClient)
+			if createError != nil {
+				return
+			}
+
+			virtualListenerID, createError = apiClient.CreateVirtualListener(configuration)
+		}
+
+		return
+	})
+	if err != nil {
+		return rollbackLoadBalancer(name, "create", rollback, err)
+	}
+
+	data.SetId(virtualListenerID)
+	data.Set(resourceKeyLoadBalancerPoolID, poolID)
+	data.Set(resourceKeyLoadBalancerSSLDomainCertificate, sslDomainCertificateID)
+	data.Set(resourceKeyLoadBalancerSSLCertificateChain, sslCertificateChainID)
+	data.Set(resourceKeyLoadBalancerSSLOffloadProfile, sslOffloadProfileID)
+	setLoadBalancerMembers(data, members)
+
+	log.Printf("Successfully created load balancer '%s' (virtual listener '%s').", name, virtualListenerID)
+
+	return resourceLoadBalancerRead(ctx, data, provider)
+}
+
+// Read a ddcloud_load_balancer resource.
+func resourceLoadBalancerRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+	name := data.Get(resourceKeyLoadBalancerName).(string)
+	poolID := data.Get(resourceKeyLoadBalancerPoolID).(string)
+
+	log.Printf("Read load balancer '%s' (virtual listener '%s').", name, id)
+
+	apiClient := provider.(*providerState).Client()
+
+	virtualListener, err := apiClient.GetVirtualListener(id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if virtualListener == nil {
+		log.Printf("Virtual listener '%s' for load balancer '%s' has been deleted.", id, name)
+
+		data.SetId("")
+
+		return nil
+	}
+
+	data.Set(resourceKeyLoadBalancerDescription, virtualListener.Description)
+	data.Set(resourceKeyLoadBalancerProtocol, virtualListener.Protocol)
+	data.Set(resourceKeyLoadBalancerPort, virtualListener.Port)
+	data.Set(resourceKeyLoadBalancerIPv4Address, virtualListener.ListenerIPAddress)
+	data.Set(resourceKeyLoadBalancerOptimizationProfile, virtualListener.OptimizationProfile)
+	data.Set(resourceKeyLoadBalancerPersistenceProfile, virtualListener.PersistenceProfile.Name)
+	data.Set(resourceKeyLoadBalancerConnectionLimit, virtualListener.ConnectionLimit)
+	data.Set(resourceKeyLoadBalancerConnectionRateLimit, virtualListener.ConnectionRateLimit)
+
+	pool, err := apiClient.GetVIPPool(poolID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if pool == nil {
+		return diag.Errorf("cannot find VIP pool '%s' for load balancer '%s'", poolID, name)
+	}
+
+	data.Set(resourceKeyLoadBalancerLoadBalanceMethod, pool.LoadBalanceMethod)
+	data.Set(resourceKeyLoadBalancerSlowRampTime, pool.SlowRampTime)
+
+	healthMonitorNames := make([]string, 0, len(pool.HealthMonitors))
+	for _, healthMonitor := range pool.HealthMonitors {
+		healthMonitorNames = append(healthMonitorNames, healthMonitor.Name)
+	}
+	propertyHelper(data).SetStringSetItems(resourceKeyLoadBalancerHealthMonitorNames, healthMonitorNames)
+
+	// Pool members (the configured back-end servers are left as-is, so that any differences are reported by the next plan).
+	members, err := listLoadBalancerMembers(apiClient, poolID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	setLoadBalancerMembers(data, members)
+
+	return nil
+}
+
+// Update a ddcloud_load_balancer resource.
+func resourceLoadBalancerUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+	name := data.Get(resourceKeyLoadBalancerName).(string)
+	description := data.Get(resourceKeyLoadBalancerDescription).(string)
+	networkDomainID := data.Get(resourceKeyLoadBalancerNetworkDomainID).(string)
+	poolID := data.Get(resourceKeyLoadBalancerPoolID).(string)
+
+	log.Printf("Update load balancer '%s' (virtual listener '%s').", name, id)
+
+	providerState := provider.(*providerState)
+	apiClient := providerState.Client()
+
+	propertyHelper := propertyHelper(data)
+
+	// VIP pool.
+	if data.HasChanges(resourceKeyLoadBalancerDescription, resourceKeyLoadBalancerLoadBalanceMethod, resourceKeyLoadBalancerHealthMonitorNames, resourceKeyLoadBalancerSlowRampTime) {
+		configuration := compute.EditVIPPoolConfiguration{
+			Description:       &description,
+			LoadBalanceMethod: propertyHelper.GetOptionalString(resourceKeyLoadBalancerLoadBalanceMethod, false),
+			SlowRampTime:      propertyHelper.GetOptionalInt(resourceKeyLoadBalancerSlowRampTime, false),
+		}
+		if data.HasChange(resourceKeyLoadBalancerHealthMonitorNames) {
+			healthMonitorIDs, err := getLoadBalancerHealthMonitorIDs(data, apiClient)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			configuration.HealthMonitorIDs = &healthMonitorIDs
+		}
+
+		operationDescription := fmt.Sprintf("Edit VIP pool '%s' for load balancer '%s'", poolID, name)
+		err := runLoadBalancerOperation(ctx, providerState, operationDescription, func() error {
+			return apiClient.EditVIPPool(poolID, configuration)
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// VIP nodes (connection limits for existing nodes; new nodes are created with the new limits).
+	nodeConnectionLimits := getLoadBalancerNodeConnectionLimits(data)
+	if data.HasChange(resourceKeyLoadBalancerNodeConnectionLimit) || data.HasChange(resourceKeyLoadBalancerNodeConnectionRateLimit) {
+		for _, nodeID := range getLoadBalancerNodeIDs(getLoadBalancerPriorMembers(data)) {
+			configuration := compute.EditVIPNodeConfiguration{
+				ConnectionLimit:     &nodeConnectionLimits.ConnectionLimit,
+				ConnectionRateLimit: &nodeConnectionLimits.ConnectionRateLimit,
+			}
+
+			operationDescription := fmt.Sprintf("Edit VIP node '%s' for load balancer '%s'", nodeID, name)
+			err := runLoadBalancerOperation(ctx, providerState, operationDescription, func() error {
+				return apiClient.EditVIPNode(nodeID, configuration)
+			})
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	// Back-end servers (new members are added before old ones are removed; if adding fails, the members added so far are removed again).
+	//
+	// Members are compared with the configured back-end servers even if the configuration has not changed, so that members changed outside of Terraform are corrected.
+	currentMembers := getLoadBalancerPriorMembers(data)
+	backendsToAdd, membersToRemove := planLoadBalancerBackends(currentMembers, getLoadBalancerBackends(data))
+	if len(backendsToAdd) > 0 || len(membersToRemove) > 0 {
+		rollback := &rollbackActions{}
+		addedMembers, err := addLoadBalancerBackends(ctx, providerState, rollback, name, networkDomainID, poolID, nodeConnectionLimits, currentMembers, backendsToAdd)
+		if err != nil {
+			return rollbackLoadBalancer(name, "update", rollback, err)
+		}
+		currentMembers = append(currentMembers, addedMembers...)
+		setLoadBalancerMembers(data, currentMembers)
+
+		err = removeLoadBalancerMembers(ctx, providerState, membersToRemove, currentMembers)
+		if err != nil {
+			return refreshLoadBalancerMembersAfterFailedRemoval(data, apiClient, name, poolID, err)
+		}
+	}
+
+	// SSL certificate rotation (the new certificate / chain are uploaded, the SSL-offload profile is switched over to them, and then the old ones are deleted).
+	var diagnostics diag.Diagnostics
+	if data.HasChanges(resourceKeyLoadBalancerCertificate, resourceKeyLoadBalancerPrivateKey, resourceKeyLoadBalancerCertificateChain) {
+		diagnostics = rotateLoadBalancerCertificate(ctx, data, providerState)
+		if diagnostics.HasError() {
+			return diagnostics
+		}
+	}
+
+	// Virtual listener.
+	if data.HasChanges(resourceKeyLoadBalancerDescription, resourceKeyLoadBalancerOptimizationProfile, resourceKeyLoadBalancerPersistenceProfile, resourceKeyLoadBalancerConnectionLimit, resourceKeyLoadBalancerConnectionRateLimit) {
+		configuration := compute.EditVirtualListenerConfiguration{
+			Description: &description,
+		}
+		if data.HasChange(resourceKeyLoadBalancerConnectionLimit) {
+			configuration.ConnectionLimit = propertyHelper.GetOptionalInt(resourceKeyLoadBalancerConnectionLimit, false)
+		}
+		if data.HasChange(resourceKeyLoadBalancerConnectionRateLimit) {
+			configuration.ConnectionRateLimit = propertyHelper.GetOptionalInt(resourceKeyLoadBalancerConnectionRateLimit, false)
+		}
+		if data.HasChange(resourceKeyLoadBalancerOptimizationProfile) {
+			configuration.OptimizationProfile = propertyHelper.GetOptionalString(resourceKeyLoadBalancerOptimizationProfile, false)
+		}
+		if data.HasChange(resourceKeyLoadBalancerPersistenceProfile) {
+			persistenceProfileID, err := getLoadBalancerPersistenceProfileID(data, apiClient)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			configuration.PersistenceProfileID = persistenceProfileID
+		}
+
+		operationDescription := fmt.Sprintf("Edit virtual listener '%s' for load balancer '%s'", id, name)
+		err := runLoadBalancerOperation(ctx, providerState, operationDescription, func() error {
+			return apiClient.EditVirtualListener(id, configuration)
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return append(diagnostics, resourceLoadBalancerRead(ctx, data, provider)...)
+}
+
+// Delete a ddcloud_load_balancer resource.
+func resourceLoadBalancerDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+	name := data.Get(resourceKeyLoadBalancerName).(string)
+	poolID := data.Get(resourceKeyLoadBalancerPoolID).(string)
+	sslOffloadProfileID := data.Get(resourceKeyLoadBalancerSSLOffloadProfile).(string)
+	sslDomainCertificateID := data.Get(resourceKeyLoadBalancerSSLDomainCertificate).(string)
+	sslCertificateChainID := data.Get(resourceKeyLoadBalancerSSLCertificateChain).(string)
+
+	log.Printf("Delete load balancer '%s' (virtual listener '%s').", name, id)
+
+	providerState := provider.(*providerState)
+	apiClient := providerState.Client()
+
+	operationDescription := fmt.Sprintf("Delete virtual listener '%s' for load balancer '%s'", id, name)
+	err := runLoadBalancerOperation(ctx, providerState, operationDescription, func() error {
+		return ignoreResourceNotFound(
+			apiClient.DeleteVirtualListener(id),
+		)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	members := getLoadBalancerMembers(data)
+	err = removeLoadBalancerMembers(ctx, providerState, members, members)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = deleteLoadBalancerPool(ctx, providerState, poolID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if sslOffloadProfileID != "" {
+		err = deleteLoadBalancerSSLOffloadProfile(ctx, providerState, sslOffloadProfileID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if sslDomainCertificateID != "" {
+		err = deleteLoadBalancerCertificate(ctx, providerState, sslDomainCertificateID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if sslCertificateChainID != "" {
+		err = deleteLoadBalancerCertificateChain(ctx, providerState, sslCertificateChainID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Printf("Successfully deleted load balancer '%s'.", name)
+
+	return nil
+}
+
+// Validate the load balancer's SSL certificate (if any) before it is sent to CloudControl.
+//
+// Adding or removing SSL offload requires the load balancer to be replaced (changing the certificate itself does not).
+//
+// If the load balancer's pool members (as last read from CloudControl) do not match its configured back-end servers, they are marked as changing.
+func resourceLoadBalancerCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, provider interface{}) error {
+	if diff.Id() != "" {
+		membersChanged := !diff.NewValueKnown(resourceKeyLoadBalancerBackend)
+		if !membersChanged {
+			backendsToAdd, membersToRemove := planLoadBalancerBackends(
+				parseLoadBalancerMembers(diff.Get(resourceKeyLoadBalancerMember).([]interface{})),
+				parseLoadBalancerBackends(diff.Get(resourceKeyLoadBalancerBackend).(*schema.Set)),
+			)
+			membersChanged = len(backendsToAdd) > 0 || len(membersToRemove) > 0
+		}
+		if membersChanged {
+			err := diff.SetNewComputed(resourceKeyLoadBalancerMember)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	certificateKeys := []string{
+		resourceKeyLoadBalancerCertificate,
+		resourceKeyLoadBalancerPrivateKey,
+		resourceKeyLoadBalancerCertificateChain,
+	}
+
+	hasCertificateChange := false
+	for _, key := range certificateKeys {
+		if !diff.NewValueKnown(key) {
+			return nil // Will be validated once the values are known.
+		}
+
+		hasCertificateChange = hasCertificateChange || diff.HasChange(key)
+	}
+	if diff.Id() != "" && !hasCertificateChange {
+		return nil
+	}
+
+	certificatePEM := diff.Get(resourceKeyLoadBalancerCertificate).(string)
+	if certificatePEM != "" {
+		err := validateSSLCertificateAndChain(
+			certificatePEM,
+			diff.Get(resourceKeyLoadBalancerPrivateKey).(string),
+			diff.Get(resourceKeyLoadBalancerCertificateChain).(string),
+			time.Now(),
+		)
+		if err != nil {
+			return fmt.Errorf("invalid SSL configuration for load balancer '%s': %s", diff.Get(resourceKeyLoadBalancerName).(string), err)
+		}
+	}
+
+	if diff.Id() == "" || !diff.HasChange(resourceKeyLoadBalancerCertificate) {
+		return nil
+	}
+
+	oldCertificate, newCertificate := diff.GetChange(resourceKeyLoadBalancerCertificate)
+	if (oldCertificate.(string) == "") == (newCertificate.(string) == "") {
+		return nil
+	}
+
+	return diff.ForceNew(resourceKeyLoadBalancerCertificate)
+}
+
+// Roll back the steps completed so far when creating or updating a load balancer ("create" or "update").
+//
+// Any underlying resources that could not be cleaned up are reported as warnings.
+func rollbackLoadBalancer(name string, operation string, rollback *rollbackActions, err error) diag.Diagnostics {
+	log.Printf("Failed to %s load balancer '%s' (%s); rolling back...", operation, name, err)
+
+	diagnostics := diag.Errorf("failed to %s load balancer '%s': %s", operation, name, err)
+	for _, rollbackError := range rollback.Run() {
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Failed to clean up after load balancer '%s'", name),
+			Detail:   fmt.Sprintf("Unable to %s; this resource must be removed manually.", rollbackError),
+		})
+	}
+
+	return diagnostics
+}
+
+// Refresh a load balancer's members after some of them could not be removed.
+//
+// Removal is not rolled back (the new back-end servers have already been added, so the load balancer is still serving the configured back-end servers).
+// Instead, the members that remain are recorded in state so that the next plan removes them.
+func refreshLoadBalancerMembersAfterFailedRemoval(data *schema.ResourceData, apiClient *compute.Client, name string, poolID string, err error) diag.Diagnostics {
+	log.Printf("Failed to remove old members from load balancer '%s' (%s); refreshing its members...", name, err)
+
+	diagnostics := diag.Diagnostics{
+		diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("failed to remove old back-end servers from load balancer '%s': %s", name, err),
+			Detail:   "The configured back-end servers have been added; the remaining old back-end servers will be removed by the next apply.",
+		},
+	}
+
+	members, listError := listLoadBalancerMembers(apiClient, poolID)
+	if listError != nil {
+		return append(diagnostics, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Unable to refresh the members of load balancer '%s'", name),
+			Detail:   listError.Error(),
+		})
+	}
+	setLoadBalancerMembers(data, members)
+
+	return diagnostics
+}
+
+// Upload a new SSL certificate and / or chain, switch the load balancer's SSL-offload profile over to them, and then delete the old ones.
+func rotateLoadBalancerCertificate(ctx context.Context, data *schema.ResourceData, providerState *providerState) (diagnostics diag.Diagnostics) {
+	name := data.Get(resourceKeyLoadBalancerName).(string)
+	networkDomainID := data.Get(resourceKeyLoadBalancerNetworkDomainID).(string)
+	sslOffloadProfileID := data.Get(resourceKeyLoadBalancerSSLOffloadProfile).(string)
+	oldCertificateID := data.Get(resourceKeyLoadBalancerSSLDomainCertificate).(string)
+	oldChainID := data.Get(resourceKeyLoadBalancerSSLCertificateChain).(string)
+
+	log.Printf("Rotate SSL certificate for load balancer '%s' (SSL-offload profile '%s').", name, sslOffloadProfileID)
+
+	apiClient := providerState.Client()
+
+	sslOffloadProfile, err := apiClient.GetSSLOffloadProfile(sslOffloadProfileID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if sslOffloadProfile == nil {
+		return diag.Errorf("cannot find SSL-offload profile '%s' for load balancer '%s'", sslOffloadProfileID, name)
+	}
+
+	rollback := &rollbackActions{}
+	versionSuffix := newSSLResourceVersionSuffix()
+
+	newCertificateID := oldCertificateID
+	if data.HasChanges(resourceKeyLoadBalancerCertificate, resourceKeyLoadBalancerPrivateKey) {
+		newCertificateID, err = importLoadBalancerCertificate(ctx, providerState, rollback, name, networkDomainID, versionSuffix,
+			data.Get(resourceKeyLoadBalancerCertificate).(string),
+			data.Get(resourceKeyLoadBalancerPrivateKey).(string),
+		)
+		if err != nil {
+			return rollbackLoadBalancer(name, "update", rollback, err)
+		}
+	}
+
+	newChainID := oldChainID
+	if data.HasChange(resourceKeyLoadBalancerCertificateChain) {
+		newChainID, err = importLoadBalancerCertificateChain(ctx, providerState, rollback, name, networkDomainID, versionSuffix,
+			data.Get(resourceKeyLoadBalancerCertificateChain).(string),
+		)
+		if err != nil {
+			return rollbackLoadBalancer(name, "update", rollback, err)
+		}
+	}
+
+	sslOffloadProfile.SSLDomainCertificate.ID = newCertificateID
+	sslOffloadProfile.SSLCertificateChain.ID = newChainID
+
+	operationDescription := fmt.Sprintf("Switch SSL-offload profile '%s' for load balancer '%s' to new certificate", sslOffloadProfileID, name)
+	err = runLoadBalancerOperation(ctx, providerState, operationDescription, func() error {
+		return apiClient.EditSSLOffloadProfile(*sslOffloadProfile)
+	})
+	if err != nil {
+		return rollbackLoadBalancer(name, "update", rollback, err)
+	}
+
+	data.Set(resourceKeyLoadBalancerSSLDomainCertificate, newCertificateID)
+	data.Set(resourceKeyLoadBalancerSSLCertificateChain, newChainID)
+
+	// The old certificate and chain are no longer in use; failure to delete them does not affect the load balancer.
+	if newCertificateID != oldCertificateID {
+		err = deleteLoadBalancerCertificate(ctx, providerState, oldCertificateID)
+		if err != nil {
+			diagnostics = append(diagnostics, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Failed to delete previous SSL certificate for load balancer '%s'", name),
+				Detail:   fmt.Sprintf("SSL domain certificate '%s' must be removed manually: %s", oldCertificateID, err),
+			})
+		}
+	}
+	if newChainID != oldChainID {
+		err = deleteLoadBalancerCertificateChain(ctx, providerState, oldChainID)
+		if err != nil {
+			diagnostics = append(diagnostics, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Failed to delete previous SSL certificate chain for load balancer '%s'", name),
+				Detail:   fmt.Sprintf("SSL certificate chain '%s' must be removed manually: %s", oldChainID, err),
+			})
+		}
+	}
+
+	return
+}
+
+// Import an SSL domain certificate for a load balancer.
+func importLoadBalancerCertificate(ctx context.Context, providerState *providerState, rollback *rollbackActions, name string, networkDomainID string, versionSuffix string, certificatePEM string, privateKeyPEM string) (certificateID string, err error) {
+	apiClient := providerState.Client()
+
+	operationDescription := fmt.Sprintf("Import SSL domain certificate for load balancer '%s'", name)
+	err = runLoadBalancerOperation(ctx, providerState, operationDescription, func() (importError error) {
+		certificateID, importError = apiClient.ImportSSLDomainCertificate(networkDomainID, name+"-certificate-"+versionSuffix, "", certificatePEM, privateKeyPEM)
+
+		return
+	})
+	if err != nil {
+		return
+	}
+	rollback.Add(fmt.Sprintf("delete SSL domain certificate '%s'", certificateID), func() error {
+		return deleteLoadBalancerCertificate(context.Background(), providerState, certificateID)
+	})
+
+	return
+}
+
+// Import an SSL certificate chain for a load balancer.
+func importLoadBalancerCertificateChain(ctx context.Context, providerState *providerState, rollback *rollbackActions, name string, networkDomainID string, versionSuffix string, chainPEM string) (chainID string, err error) {
+	apiClient := providerState.Client()
+
+	operationDescription := fmt.Sprintf("Import SSL certificate chain for load balancer '%s'", name)
+	err = runLoadBalancerOperation(ctx, providerState, operationDescription, func() (importError error) {
+		chainID, importError = apiClient.ImportSSLCertificateChain(networkDomainID, name+"-chain-"+versionSuffix, "", chainPEM)
+
+		return
+	})
+	if err != nil {
+		return
+	}
+	rollback.Add(fmt.Sprintf("delete SSL certificate chain '%s'", chainID), func() error {
+		return deleteLoadBalancerCertificateChain(context.Background(), providerState, chainID)
+	})
+
+	return
+}
+
+// Create VIP nodes (where required) and pool members for a load balancer's back-end servers.
+//
+// Back-end servers with the same IPv4 address share a VIP node (existing members are used to find existing nodes).
+func addLoadBalancerBackends(ctx context.Context, providerState *providerState, rollback *rollbackActions, name string, networkDomainID string, poolID string, nodeConnectionLimits loadBalancerConnectionLimits, existingMembers []loadBalancerMember, backends []loadBalancerBackend) (members []loadBalancerMember, err error) {
+	apiClient := providerState.Client()
+
+	nodeIDsByIPv4Address := make(map[string]string)
+	for _, member := range existingMembers {
+		nodeIDsByIPv4Address[member.IPv4Address] = member.NodeID
+	}
+
+	for _, backend := range backends {
+		nodeID, ok := nodeIDsByIPv4Address[backend.IPv4Address]
+		if !ok {
+			operationDescription := fmt.Sprintf("Create VIP node for '%s' (load balancer '%s')", backend.IPv4Address, name)
+			err = runLoadBalancerOperation(ctx, providerState, operationDescription, func() (createError error) {
+				nodeID, createError = apiClient.CreateVIPNode(compute.NewVIPNodeConfiguration{
+					Name:                fmt.Sprintf("%s-node-%s", name, strings.Replace(backend.IPv4Address, ".", "-", -1)),
+					IPv4Address:         backend.IPv4Address,
+					Status:              compute.VIPNodeStatusEnabled,
+					ConnectionLimit:     nodeConnectionLimits.ConnectionLimit,
+					ConnectionRateLimit: nodeConnectionLimits.ConnectionRateLimit,
+					NetworkDomainID:     networkDomainID,
+				})
+
+				return
+			})
+			if err != nil {
+				return
+			}
+
+			createdNodeID := nodeID
+			rollback.Add(fmt.Sprintf("delete VIP node '%s'", createdNodeID), func() error {
+				return deleteLoadBalancerNode(context.Background(), providerState, createdNodeID)
+			})
+			nodeIDsByIPv4Address[backend.IPv4Address] = nodeID
+		}
+
+		var port *int
+		if backend.Port != 0 {
+			memberPort := backend.Port
+			port = &memberPort
+		}
+
+		var memberID string
+		operationDescription := fmt.Sprintf("Add '%s' to VIP pool '%s' (load balancer '%s')", backend, poolID, name)
+		err = runLoadBalancerOperation(ctx, providerState, operationDescription, func() (addError error) {
+			memberID, addError = apiClient.AddVIPPoolMember(poolID, nodeID, compute.VIPNodeStatusEnabled, port)
+
+			return
+		})
+		if err != nil {
+			return
+		}
+
+		addedMemberID := memberID
+		rollback.Add(fmt.Sprintf("remove VIP pool member '%s'", addedMemberID), func() error {
+			return removeLoadBalancerMember(context.Background(), providerState, addedMemberID)
+		})
+
+		members = append(members, loadBalancerMember{
+			loadBalancerBackend: backend,
+			ID:                  memberID,
+			NodeID:              nodeID,
+		})
+	}
+
+	return
+}
+
+// Remove pool members from a load balancer, and delete any of their VIP nodes that are no longer used by the remaining members.
+func removeLoadBalancerMembers(ctx context.Context, providerState *providerState, membersToRemove []loadBalancerMember, allMembers []loadBalancerMember) error {
+	for _, member := range membersToRemove {
+		err := removeLoadBalancerMember(ctx, providerState, member.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, nodeID := range getUnusedLoadBalancerNodeIDs(membersToRemove, allMembers) {
+		err := deleteLoadBalancerNode(ctx, providerState, nodeID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Determine which back-end servers must be added to a load balancer, and which of its existing members must be removed.
+func planLoadBalancerBackends(currentMembers []loadBalancerMember, backends []loadBalancerBackend) (backendsToAdd []loadBalancerBackend, membersToRemove []loadBalancerMember) {
+	configuredBackends := make(map[loadBalancerBackend]bool)
+	for _, backend := range backends {
+		configuredBackends[backend] = true
+	}
+
+	existingBackends := make(map[loadBalancerBackend]bool)
+	for _, member := range currentMembers {
+		existingBackends[member.loadBalancerBackend] = true
+
+		if !configuredBackends[member.loadBalancerBackend] {
+			membersToRemove = append(membersToRemove, member)
+		}
+	}
+
+	for _, backend := range backends {
+		if !existingBackends[backend] {
+			backendsToAdd = append(backendsToAdd, backend)
+		}
+	}
+
+	return
+}
+
+// Get the Ids of the VIP nodes used by removed members that are not used by any remaining member.
+func getUnusedLoadBalancerNodeIDs(removedMembers []loadBalancerMember, allMembers []loadBalancerMember) (unusedNodeIDs []string) {
+	removedMemberIDs := make(map[string]bool)
+	for _, member := range removedMembers {
+		removedMemberIDs[member.ID] = true
+	}
+
+	usedNodeIDs := make(map[string]bool)
+	for _, member := range allMembers {
+		if !removedMemberIDs[member.ID] {
+			usedNodeIDs[member.NodeID] = true
+		}
+	}
+
+	seenNodeIDs := make(map[string]bool)
+	for _, member := range removedMembers {
+		if usedNodeIDs[member.NodeID] || seenNodeIDs[member.NodeID] {
+			continue
+		}
+
+		seenNodeIDs[member.NodeID] = true
+		unusedNodeIDs = append(unusedNodeIDs, member.NodeID)
+	}
+
+	return
+}
+
+// List the members of a load balancer's VIP pool.
+func listLoadBalancerMembers(apiClient *compute.Client, poolID string) (members []loadBalancerMember, err error) {
+	page := compute.DefaultPaging()
+	for {
+		var poolMembers *compute.VIPPoolMembers
+		poolMembers, err = apiClient.ListVIPPoolMembers(poolID, page)
+		if err != nil {
+			return
+		}
+		if poolMembers.IsEmpty() {
+			break // We're done
+		}
+
+		for _, poolMember := range poolMembers.Items {
+			member := loadBalancerMember{
+				ID:     poolMember.ID,
+				NodeID: poolMember.Node.ID,
+			}
+			member.IPv4Address = poolMember.Node.IPAddress
+			if poolMember.Port != nil {
+				member.Port = *poolMember.Port
+			}
+
+			members = append(members, member)
+		}
+
+		page.Next()
+	}
+
+	sort.Slice(members, func(index1 int, index2 int) bool {
+		return members[index1].String() < members[index2].String()
+	})
+
+	return
+}
+
+// Resolve the Ids of a load balancer's configured health monitors.
+func getLoadBalancerHealthMonitorIDs(data *schema.ResourceData, apiClient *compute.Client) (healthMonitorIDs []string, err error) {
+	healthMonitorNames := propertyHelper(data).GetStringSetItems(resourceKeyLoadBalancerHealthMonitorNames)
+	if len(healthMonitorNames) == 0 {
+		return
+	}
+
+	networkDomainID := data.Get(resourceKeyLoadBalancerNetworkDomainID).(string)
+	healthMonitorIDsByName, err := getHealthMonitorIDsByName(networkDomainID, apiClient)
+	if err != nil {
+		return
+	}
+
+	for _, healthMonitorName := range healthMonitorNames {
+		healthMonitorID, ok := healthMonitorIDsByName[healthMonitorName]
+		if !ok {
+			return nil, fmt.Errorf("cannot find health monitor named '%s' in network domain '%s'", healthMonitorName, networkDomainID)
+		}
+
+		healthMonitorIDs = append(healthMonitorIDs, healthMonitorID)
+	}
+
+	return
+}
+
+// Resolve the Id of a load balancer's configured persistence profile (nil if no persistence profile is configured).
+func getLoadBalancerPersistenceProfileID(data *schema.ResourceData, apiClient *compute.Client) (*string, error) {
+	persistenceProfileName := data.Get(resourceKeyLoadBalancerPersistenceProfile).(string)
+	if persistenceProfileName == "" {
+		return nil, nil
+	}
+
+	networkDomainID := data.Get(resourceKeyLoadBalancerNetworkDomainID).(string)
+	persistenceProfiles, err := listPersistenceProfiles(networkDomainID, apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	persistenceProfile := findPersistenceProfileByName(persistenceProfiles, persistenceProfileName)
+	if persistenceProfile == nil {
+		return nil, fmt.Errorf("cannot find persistence profile named '%s' in network domain '%s'", persistenceProfileName, networkDomainID)
+	}
+
+	return &persistenceProfile.ID, nil
+}
+
+// Get a load balancer's configured back-end servers.
+func getLoadBalancerBackends(data *schema.ResourceData) []loadBalancerBackend {
+	return parseLoadBalancerBackends(
+		data.Get(resourceKeyLoadBalancerBackend).(*schema.Set),
+	)
+}
+
+// Parse a load balancer's back-end servers from their schema representation.
+func parseLoadBalancerBackends(backendSet *schema.Set) (backends []loadBalancerBackend) {
+	for _, item := range backendSet.List() {
+		backendProperties := item.(map[string]interface{})
+
+		backends = append(backends, loadBalancerBackend{
+			IPv4Address: backendProperties[resourceKeyLoadBalancerBackendIPv4Address].(string),
+			Port:        backendProperties[resourceKeyLoadBalancerBackendPort].(int),
+		})
+	}
+
+	return
+}
+
+// Get a load balancer's pool members (as last persisted to state).
+func getLoadBalancerMembers(data *schema.ResourceData) []loadBalancerMember {
+	return parseLoadBalancerMembers(
+		data.Get(resourceKeyLoadBalancerMember).([]interface{}),
+	)
+}
+
+// Get a load balancer's pool members as they were before the current change.
+//
+// While a load balancer is being updated, its members are not yet known if they differ from its configured back-end servers (see resourceLoadBalancerCustomizeDiff).
+func getLoadBalancerPriorMembers(data *schema.ResourceData) []loadBalancerMember {
+	priorMembers, _ := data.GetChange(resourceKeyLoadBalancerMember)
+
+	return parseLoadBalancerMembers(
+		priorMembers.([]interface{}),
+	)
+}
+
+// Parse a load balancer's pool members from their schema representation.
+func parseLoadBalancerMembers(memberList []interface{}) (members []loadBalancerMember) {
+	for _, item := range memberList {
+		memberProperties := item.(map[string]interface{})
+
+		member := loadBalancerMember{
+			ID:     memberProperties[resourceKeyLoadBalancerMemberID].(string),
+			NodeID: memberProperties[resourceKeyLoadBalancerMemberNodeID].(string),
+		}
+		member.IPv4Address = memberProperties[resourceKeyLoadBalancerMemberIPv4Address].(string)
+		member.Port = memberProperties[resourceKeyLoadBalancerMemberPort].(int)
+
+		members = append(members, member)
+	}
+
+	return
+}
+
+// Set a load balancer's pool members.
+func setLoadBalancerMembers(data *schema.ResourceData, members []loadBalancerMember) {
+	memberProperties := make([]interface{}, len(members))
+	for index, member := range members {
+		memberProperties[index] = map[string]interface{}{
+			resourceKeyLoadBalancerMemberID:          member.ID,
+			resourceKeyLoadBalancerMemberNodeID:      member.NodeID,
+			resourceKeyLoadBalancerMemberIPv4Address: member.IPv4Address,
+			resourceKeyLoadBalancerMemberPort:        member.Port,
+		}
+	}
+
+	data.Set(resourceKeyLoadBalancerMember, memberProperties)
+}
+
+// Get the connection limits for a load balancer's VIP nodes.
+func getLoadBalancerNodeConnectionLimits(data *schema.ResourceData) loadBalancerConnectionLimits {
+	return loadBalancerConnectionLimits{
+		ConnectionLimit:     data.Get(resourceKeyLoadBalancerNodeConnectionLimit).(int),
+		ConnectionRateLimit: data.Get(resourceKeyLoadBalancerNodeConnectionRateLimit).(int),
+	}
+}
+
+// Get the (distinct) Ids of the VIP nodes used by a load balancer's pool members.
+func getLoadBalancerNodeIDs(members []loadBalancerMember) (nodeIDs []string) {
+	seenNodeIDs := make(map[string]bool)
+	for _, member := range members {
+		if seenNodeIDs[member.NodeID] {
+			continue
+		}
+
+		seenNodeIDs[member.NodeID] = true
+		nodeIDs = append(nodeIDs, member.NodeID)
+	}
+
+	return
+}
+
+// Validate a connection limit or connection rate limit for a load balancer's virtual listener or VIP nodes.
+func validateLoadBalancerConnectionLimit(value interface{}, propertyName string) (messages []string, errors []error) {
+	connectionLimit := value.(int)
+	if connectionLimit > 0 {
+		return
+	}
+
+	errors = append(errors,
+		fmt.Errorf("'%s' must be greater than 0", propertyName),
+	)
+
+	return
+}
+
+// String returns a string representation of the back-end server ("ipv4:port", or just "ipv4" if no port is specified).
+func (backend loadBalancerBackend) String() string {
+	if backend.Port == 0 {
+		return backend.IPv4Address
+	}
+
+	return fmt.Sprintf("%s:%d", backend.IPv4Address, backend.Port)
+}
+
+// Run an operation on one of a load balancer's underlying resources (retrying if CloudControl reports that the resource is busy).
+func runLoadBalancerOperation(ctx context.Context, providerState *providerState, operationDescription string, operation func() error) error {
+	return providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release() // Released at the end of the current attempt.
+
+		err := operation()
+		if compute.IsResourceBusyError(err) {
+			context.Retry()
+		} else if err != nil {
+			context.Fail(err)
+		}
+	})
+}
+
+// Treat a "resource not found" error as success (the resource has already been deleted).
+func ignoreResourceNotFound(err error) error {
+	if compute.IsResourceNotFoundError(err) {
+		return nil
+	}
+
+	return err
+}
+
+func deleteLoadBalancerSSLOffloadProfile(ctx context.Context, providerState *providerState, id string) error {
+	return runLoadBalancerOperation(ctx, providerState, fmt.Sprintf("Delete SSL-offload profile '%s'", id), func() error {
+		return ignoreResourceNotFound(
+			providerState.Client().DeleteSSLOffloadProfile(id),
+		)
+	})
+}
+
+func deleteLoadBalancerCertificate(ctx context.Context, providerState *providerState, id string) error {
+	return runLoadBalancerOperation(ctx, providerState, fmt.Sprintf("Delete SSL domain certificate '%s'", id), func() error {
+		return ignoreResourceNotFound(
+			providerState.Client().DeleteSSLDomainCertificate(id),
+		)
+	})
+}
+
+func deleteLoadBalancerCertificateChain(ctx context.Context, providerState *providerState, id string) error {
+	return runLoadBalancerOperation(ctx, providerState, fmt.Sprintf("Delete SSL certificate chain '%s'", id), func() error {
+		return ignoreResourceNotFound(
+			providerState.Client().DeleteSSLCertificateChain(id),
+		)
+	})
+}
+
+func deleteLoadBalancerPool(ctx context.Context, providerState *providerState, id string) error {
+	return runLoadBalancerOperation(ctx, providerState, fmt.Sprintf("Delete VIP pool '%s'", id), func() error {
+		return ignoreResourceNotFound(
+			providerState.Client().DeleteVIPPool(id),
+		)
+	})
+}
+
+func deleteLoadBalancerNode(ctx context.Context, providerState *providerState, id string) error {
+	return runLoadBalancerOperation(ctx, providerState, fmt.Sprintf("Delete VIP node '%s'", id), func() error {
+		return ignoreResourceNotFound(
+			providerState.Client().DeleteVIPNode(id),
+		)
+	})
+}
+
+func removeLoadBalancerMember(ctx context.Context, providerState *providerState, id string) error {
+	return runLoadBalancerOperation(ctx, providerState, fmt.Sprintf("Remove VIP pool member '%s'", id), func() error {
+		return ignoreResourceNotFound(
+			providerState.Client().RemoveVIPPoolMember(id),
+		)
+	})
+}
@@ -0,0 +1,485 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+/*
+ * Acceptance-test configurations.
+ */
+
+// A basic load balancer (and the network domain that contains it).
+func testAccDDCloudLoadBalancerBasic(name string, backendIPv4Addresses ...string) string {
+	backends := ""
+	for _, backendIPv4Address := range backendIPv4Addresses {
+		backends += fmt.Sprintf(`
+			backend {
+				ipv4		= "%s"
+				port		= 8080
+			}
+		`, backendIPv4Address)
+	}
+
+	return fmt.Sprintf(`
+		provider "ddcloud" {
+			region		= "AU"
+		}
+
+		resource "ddcloud_networkdomain" "acc_test_domain" {
+			name		= "acc-test-networkdomain"
+			description	= "Network domain for Terraform acceptance test."
+			datacenter	= "AU9"
+
+			plan		= "ADVANCED"
+		}
+
+		resource "ddcloud_load_balancer" "acc_test_load_balancer" {
+			name				= "%s"
+			port				= 80
+			ipv4				= "192.168.18.10"
+
+			%s
+
+			networkdomain		= "${ddcloud_networkdomain.acc_test_domain.id}"
+		}
+	`, name, backends)
+}
+
+/*
+ * Acceptance tests.
+ */
+
+// Acceptance test for ddcloud_load_balancer (basic):
+//
+// Create a load balancer, then change its back-end servers, and verify that they are updated in-place.
+func TestAccLoadBalancerBasicUpdateBackends(t *testing.T) {
+	testAccResourceUpdateInPlace(t, testAccResourceUpdate{
+		ResourceName: "ddcloud_load_balancer.acc_test_load_balancer",
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testCheckDDCloudVirtualListenerDestroy,
+			testCheckDDCloudNetworkDomainDestroy,
+		),
+
+		// Create
+		InitialConfig: testAccDDCloudLoadBalancerBasic("AccTestLoadBalancer", "192.168.17.10", "192.168.17.11"),
+		InitialCheck: resource.ComposeTestCheckFunc(
+			resource.TestCheckResourceAttr("ddcloud_load_balancer.acc_test_load_balancer", "member.#", "2"),
+		),
+
+		// Update
+		UpdateConfig: testAccDDCloudLoadBalancerBasic("AccTestLoadBalancer", "192.168.17.11", "192.168.17.12"),
+		UpdateCheck: resource.ComposeTestCheckFunc(
+			resource.TestCheckResourceAttr("ddcloud_load_balancer.acc_test_load_balancer", "member.#", "2"),
+			resource.TestCheckResourceAttr("ddcloud_load_balancer.acc_test_load_balancer", "member.0.ipv4", "192.168.17.11"),
+			resource.TestCheckResourceAttr("ddcloud_load_balancer.acc_test_load_balancer", "member.1.ipv4", "192.168.17.12"),
+		),
+	})
+}
+
+/*
+ * Unit tests.
+ */
+
+// Unit test - only back-end servers that have changed are added or removed.
+func TestPlanLoadBalancerBackends(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	currentMembers := []loadBalancerMember{
+		testLoadBalancerMember("member-1", "node-1", "10.0.0.1", 80),
+		testLoadBalancerMember("member-2", "node-2", "10.0.0.2", 80),
+	}
+	backends := []loadBalancerBackend{
+		loadBalancerBackend{IPv4Address: "10.0.0.2", Port: 80},
+		loadBalancerBackend{IPv4Address: "10.0.0.2", Port: 8080},
+		loadBalancerBackend{IPv4Address: "10.0.0.3", Port: 0},
+	}
+
+	backendsToAdd, membersToRemove := planLoadBalancerBackends(currentMembers, backends)
+
+	assert.EqualsInt("len(backendsToAdd)", 2, len(backendsToAdd))
+	assert.EqualsString("backendsToAdd[0]", "10.0.0.2:8080", backendsToAdd[0].String())
+	assert.EqualsString("backendsToAdd[1]", "10.0.0.3", backendsToAdd[1].String())
+
+	assert.EqualsInt("len(membersToRemove)", 1, len(membersToRemove))
+	assert.EqualsString("membersToRemove[0].ID", "member-1", membersToRemove[0].ID)
+}
+
+// Unit test - VIP nodes are only deleted once no remaining members use them.
+func TestGetUnusedLoadBalancerNodeIDs(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	allMembers := []loadBalancerMember{
+		testLoadBalancerMember("member-1", "node-1", "10.0.0.1", 80),
+		testLoadBalancerMember("member-2", "node-1", "10.0.0.1", 8080),
+		testLoadBalancerMember("member-3", "node-2", "10.0.0.2", 80),
+		testLoadBalancerMember("member-4", "node-2", "10.0.0.2", 8080),
+	}
+	removedMembers := []loadBalancerMember{
+		allMembers[0],
+		allMembers[2],
+		allMembers[3],
+	}
+
+	unusedNodeIDs := getUnusedLoadBalancerNodeIDs(removedMembers, allMembers)
+	assert.EqualsInt("len(unusedNodeIDs)", 1, len(unusedNodeIDs))
+	assert.EqualsString("unusedNodeIDs[0]", "node-2", unusedNodeIDs[0])
+}
+
+// Unit test - adding SSL offload to an existing load balancer forces a new resource.
+func TestLoadBalancerCustomizeDiffAddCertificate(test *testing.T) {
+	diff := testLoadBalancerCertificateChangeDiff(test, "")
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("Diff.RequiresNew", diff.RequiresNew())
+}
+
+// Unit test - changing the certificate of an existing load balancer does not force a new resource.
+func TestLoadBalancerCustomizeDiffChangeCertificate(test *testing.T) {
+	diff := testLoadBalancerCertificateChangeDiff(test, "-----BEGIN CERTIFICATE-----\nold\n-----END CERTIFICATE-----")
+
+	assert := assert.ForTest(test)
+	assert.IsFalse("Diff.RequiresNew", diff.RequiresNew())
+	assert.IsTrue("Diff.Attributes[certificate] != nil", diff.Attributes[resourceKeyLoadBalancerCertificate] != nil)
+}
+
+func testLoadBalancerMember(id string, nodeID string, ipv4Address string, port int) loadBalancerMember {
+	return loadBalancerMember{
+		loadBalancerBackend: loadBalancerBackend{
+			IPv4Address: ipv4Address,
+			Port:        port,
+		},
+		ID:     id,
+		NodeID: nodeID,
+	}
+}
+
+// Unit test - an invalid certificate is rejected when planning.
+func TestLoadBalancerCustomizeDiffInvalidCertificate(test *testing.T) {
+	certificates := testGenerateCertificates(test, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	_, err := resourceLoadBalancer().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeyLoadBalancerName:            "lb-1",
+		resourceKeyLoadBalancerNetworkDomainID: "networkdomain-1",
+		resourceKeyLoadBalancerBackend: []interface{}{
+			map[string]interface{}{
+				resourceKeyLoadBalancerBackendIPv4Address: "10.0.0.1",
+			},
+		},
+		resourceKeyLoadBalancerPort:             443,
+		resourceKeyLoadBalancerCertificate:      certificates.LeafPEM,
+		resourceKeyLoadBalancerPrivateKey:       certificates.IntermediateKeyPEM,
+		resourceKeyLoadBalancerCertificateChain: certificates.ChainPEM(),
+	}), &providerState{})
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("err != nil", err != nil)
+}
+
+// Compute the diff for changing an existing load balancer's certificate (to a newly-generated one).
+func testLoadBalancerCertificateChangeDiff(test *testing.T, oldCertificate string) *terraform.InstanceDiff {
+	certificates := testGenerateCertificates(test, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	oldPrivateKey := ""
+	oldChain := ""
+	if oldCertificate != "" {
+		oldPrivateKey = "private-key"
+		oldChain = "chain"
+	}
+
+	state := &terraform.InstanceState{
+		ID: "listener-1",
+		Attributes: map[string]string{
+			"id":                                       "listener-1",
+			resourceKeyLoadBalancerName:                "lb-1",
+			resourceKeyLoadBalancerDescription:         "",
+			resourceKeyLoadBalancerNetworkDomainID:     "networkdomain-1",
+			"backend.#":                                "1",
+			"backend.1234.ipv4":                        "10.0.0.1",
+			"backend.1234.port":                        "80",
+			resourceKeyLoadBalancerLoadBalanceMethod:   "ROUND_ROBIN",
+			resourceKeyLoadBalancerSlowRampTime:        "10",
+			resourceKeyLoadBalancerProtocol:            "HTTP",
+			resourceKeyLoadBalancerPort:                "443",
+			resourceKeyLoadBalancerIPv4Address:         "10.0.1.1",
+			resourceKeyLoadBalancerOptimizationProfile: "",
+			resourceKeyLoadBalancerPersistenceProfile:  "",
+			resourceKeyLoadBalancerCertificate:         oldCertificate,
+			resourceKeyLoadBalancerPrivateKey:          oldPrivateKey,
+			resourceKeyLoadBalancerCertificateChain:    oldChain,
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeyLoadBalancerName:            "lb-1",
+		resourceKeyLoadBalancerNetworkDomainID: "networkdomain-1",
+		resourceKeyLoadBalancerBackend: []interface{}{
+			map[string]interface{}{
+				resourceKeyLoadBalancerBackendIPv4Address: "10.0.0.1",
+				resourceKeyLoadBalancerBackendPort:        80,
+			},
+		},
+		resourceKeyLoadBalancerPort:             443,
+		resourceKeyLoadBalancerIPv4Address:      "10.0.1.1",
+		resourceKeyLoadBalancerCertificate:      certificates.LeafPEM,
+		resourceKeyLoadBalancerPrivateKey:       certificates.LeafKeyPEM,
+		resourceKeyLoadBalancerCertificateChain: certificates.ChainPEM(),
+	})
+
+	diff, err := resourceLoadBalancer().Diff(context.Background(), state, config, &providerState{})
+	if err != nil {
+		test.Fatal(err)
+	}
+	if diff == nil {
+		test.Fatal("Diff is nil.")
+	}
+
+	return diff
+}
+
+// Unit test - pool members that match the configured back-end servers are not marked as changing.
+func TestLoadBalancerCustomizeDiffMembersMatchBackends(test *testing.T) {
+	diff := testLoadBalancerBackendDiff(test, testLoadBalancerMember("member-1", "node-1", "10.0.0.1", 80))
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("diff == nil", diff == nil)
+}
+
+// Unit test - a pool member that has been removed outside of Terraform marks the load balancer's members as changing.
+func TestLoadBalancerCustomizeDiffMemberRemoved(test *testing.T) {
+	diff := testLoadBalancerBackendDiff(test)
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("diff != nil", diff != nil)
+
+	memberCount := diff.Attributes["member.#"]
+	assert.IsTrue("Diff.Attributes[member.#] != nil", memberCount != nil)
+	assert.IsTrue("Diff.Attributes[member.#].NewComputed", memberCount.NewComputed)
+	assert.IsFalse("Diff.RequiresNew", diff.RequiresNew())
+}
+
+// Unit test - if adding a back-end server fails during an update, the VIP node created for it is deleted again.
+func TestLoadBalancerUpdateAddBackendRollback(test *testing.T) {
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	state := testLoadBalancerBackendState(testLoadBalancerMember("member-1", "node-1", "10.0.0.1", 80))
+	config := testLoadBalancerBackendConfig("10.0.0.1", "10.0.0.2")
+
+	loadBalancerResource := resourceLoadBalancer()
+	diff, err := loadBalancerResource.Diff(context.Background(), state, config, &providerState{})
+	if err != nil {
+		test.Fatal(err)
+	}
+	data, err := schema.InternalMap(loadBalancerResource.Schema).Data(state, diff)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	diagnostics := resourceLoadBalancerUpdate(context.Background(), data, standIn.NewProviderState())
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("diagnostics.HasError()", diagnostics.HasError())
+	assert.EqualsInt("len(CreateVIPNodeRequests)", 1, len(standIn.CreateVIPNodeRequests))
+	assert.Equals("CreateVIPNodeRequests[0].ipv4Address", "10.0.0.2", standIn.CreateVIPNodeRequests[0]["ipv4Address"])
+	assert.EqualsInt("len(DeleteVIPNodeRequests)", 1, len(standIn.DeleteVIPNodeRequests))
+	assert.Equals("DeleteVIPNodeRequests[0].id", "test-node-1", standIn.DeleteVIPNodeRequests[0]["id"])
+}
+
+// Unit test - if removing an old pool member fails during an update, the members that remain in CloudControl are kept in state.
+func TestLoadBalancerUpdateRemoveMemberFailure(test *testing.T) {
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.VIPPoolMembers = []compute.VIPPoolMember{
+		testLoadBalancerPoolMember("member-1", "node-1", "10.0.0.1", 80),
+		testLoadBalancerPoolMember("member-2", "node-2", "10.0.0.3", 80),
+	}
+
+	state := testLoadBalancerBackendState(
+		testLoadBalancerMember("member-1", "node-1", "10.0.0.1", 80),
+		testLoadBalancerMember("member-2", "node-2", "10.0.0.3", 80),
+	)
+	config := testLoadBalancerBackendConfig("10.0.0.1")
+
+	loadBalancerResource := resourceLoadBalancer()
+	diff, err := loadBalancerResource.Diff(context.Background(), state, config, &providerState{})
+	if err != nil {
+		test.Fatal(err)
+	}
+	data, err := schema.InternalMap(loadBalancerResource.Schema).Data(state, diff)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	diagnostics := resourceLoadBalancerUpdate(context.Background(), data, standIn.NewProviderState())
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("diagnostics.HasError()", diagnostics.HasError())
+	assert.EqualsInt("len(RemoveVIPPoolMemberRequests)", 1, len(standIn.RemoveVIPPoolMemberRequests))
+	assert.Equals("RemoveVIPPoolMemberRequests[0].id", "member-2", standIn.RemoveVIPPoolMemberRequests[0]["id"])
+	assert.EqualsInt("len(DeleteVIPNodeRequests)", 0, len(standIn.DeleteVIPNodeRequests))
+
+	members := getLoadBalancerMembers(data)
+	assert.EqualsInt("len(members)", 2, len(members))
+	assert.EqualsString("members[1].ID", "member-2", members[1].ID)
+}
+
+// Unit test - Read refreshes the load balancer's virtual listener and VIP pool properties from CloudControl.
+func TestLoadBalancerRead(test *testing.T) {
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.VirtualListener = &compute.VirtualListener{
+		ID:                  "listener-1",
+		Name:                "lb-1",
+		Description:         "Changed outside of Terraform",
+		Protocol:            "TCP",
+		ListenerIPAddress:   "10.0.1.1",
+		Port:                8080,
+		ConnectionLimit:     10000,
+		ConnectionRateLimit: 1000,
+		OptimizationProfile: "TCP",
+		PersistenceProfile: compute.EntityReference{
+			ID:   "persistence-profile-1",
+			Name: "CCDEFAULT.SourceAddress",
+		},
+	}
+	standIn.VIPPool = &compute.VIPPool{
+		ID:                "pool-1",
+		Name:              "lb-1-pool",
+		LoadBalanceMethod: compute.LoadBalanceMethodLeastConnectionsMember,
+		SlowRampTime:      20,
+		HealthMonitors: []compute.EntityReference{
+			{ID: "health-monitor-1", Name: "CCDEFAULT.Http"},
+		},
+	}
+	standIn.VIPPoolMembers = []compute.VIPPoolMember{
+		testLoadBalancerPoolMember("member-1", "node-1", "10.0.0.1", 80),
+	}
+
+	loadBalancerResource := resourceLoadBalancer()
+	data, err := schema.InternalMap(loadBalancerResource.Schema).Data(testLoadBalancerBackendState(), nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	diagnostics := resourceLoadBalancerRead(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	assert := assert.ForTest(test)
+	assert.EqualsString("description", "Changed outside of Terraform", data.Get(resourceKeyLoadBalancerDescription).(string))
+	assert.EqualsString("protocol", "TCP", data.Get(resourceKeyLoadBalancerProtocol).(string))
+	assert.EqualsInt("port", 8080, data.Get(resourceKeyLoadBalancerPort).(int))
+	assert.EqualsString("optimization_profile", "TCP", data.Get(resourceKeyLoadBalancerOptimizationProfile).(string))
+	assert.EqualsString("persistence_profile", "CCDEFAULT.SourceAddress", data.Get(resourceKeyLoadBalancerPersistenceProfile).(string))
+	assert.EqualsInt("connection_limit", 10000, data.Get(resourceKeyLoadBalancerConnectionLimit).(int))
+	assert.EqualsString("load_balance_method", compute.LoadBalanceMethodLeastConnectionsMember, data.Get(resourceKeyLoadBalancerLoadBalanceMethod).(string))
+	assert.EqualsInt("slow_ramp_time", 20, data.Get(resourceKeyLoadBalancerSlowRampTime).(int))
+
+	healthMonitorNames := propertyHelper(data).GetStringSetItems(resourceKeyLoadBalancerHealthMonitorNames)
+	assert.EqualsInt("len(health_monitors)", 1, len(healthMonitorNames))
+	assert.EqualsString("health_monitors[0]", "CCDEFAULT.Http", healthMonitorNames[0])
+
+	members := getLoadBalancerMembers(data)
+	assert.EqualsInt("len(members)", 1, len(members))
+	assert.EqualsString("members[0].ID", "member-1", members[0].ID)
+}
+
+// Create a CloudControl VIP pool member (as returned by the stand-in API).
+func testLoadBalancerPoolMember(id string, nodeID string, ipv4Address string, port int) compute.VIPPoolMember {
+	poolMember := compute.VIPPoolMember{
+		ID:   id,
+		Port: &port,
+	}
+	poolMember.Node.ID = nodeID
+	poolMember.Node.IPAddress = ipv4Address
+
+	return poolMember
+}
+
+// Compute the diff for an existing load balancer (with the specified pool members) whose configured back-end server is 10.0.0.1:80.
+func testLoadBalancerBackendDiff(test *testing.T, members ...loadBalancerMember) *terraform.InstanceDiff {
+	diff, err := resourceLoadBalancer().Diff(context.Background(),
+		testLoadBalancerBackendState(members...),
+		testLoadBalancerBackendConfig("10.0.0.1"),
+		&providerState{},
+	)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	return diff
+}
+
+// Create the state for an existing load balancer (with the specified pool members) whose configured back-end server is 10.0.0.1:80.
+func testLoadBalancerBackendState(members ...loadBalancerMember) *terraform.InstanceState {
+	attributes := map[string]string{
+		"id":                                               "listener-1",
+		resourceKeyLoadBalancerName:                        "lb-1",
+		resourceKeyLoadBalancerDescription:                 "",
+		resourceKeyLoadBalancerNetworkDomainID:             "networkdomain-1",
+		"backend.#":                                        "1",
+		"backend.1234.ipv4":                                "10.0.0.1",
+		"backend.1234.port":                                "80",
+		resourceKeyLoadBalancerNodeConnectionLimit:         "20000",
+		resourceKeyLoadBalancerNodeConnectionRateLimit:     "2000",
+		resourceKeyLoadBalancerLoadBalanceMethod:           "ROUND_ROBIN",
+		resourceKeyLoadBalancerSlowRampTime:                "10",
+		resourceKeyLoadBalancerProtocol:                    "HTTP",
+		resourceKeyLoadBalancerPort:                        "80",
+		resourceKeyLoadBalancerIPv4Address:                 "10.0.1.1",
+		resourceKeyLoadBalancerOptimizationProfile:         "",
+		resourceKeyLoadBalancerPersistenceProfile:          "",
+		resourceKeyLoadBalancerConnectionLimit:             "20000",
+		resourceKeyLoadBalancerConnectionRateLimit:         "2000",
+		resourceKeyLoadBalancerCertificate:                 "",
+		resourceKeyLoadBalancerPrivateKey:                  "",
+		resourceKeyLoadBalancerCertificateChain:            "",
+		resourceKeyLoadBalancerPoolID:                      "pool-1",
+		resourceKeyLoadBalancerSSLDomainCertificate:        "",
+		resourceKeyLoadBalancerSSLCertificateChain:         "",
+		resourceKeyLoadBalancerSSLOffloadProfile:           "",
+		fmt.Sprintf("%s.#", resourceKeyLoadBalancerMember): fmt.Sprintf("%d", len(members)),
+	}
+	for index, member := range members {
+		prefix := fmt.Sprintf("%s.%d.", resourceKeyLoadBalancerMember, index)
+		attributes[prefix+resourceKeyLoadBalancerMemberID] = member.ID
+		attributes[prefix+resourceKeyLoadBalancerMemberNodeID] = member.NodeID
+		attributes[prefix+resourceKeyLoadBalancerMemberIPv4Address] = member.IPv4Address
+		attributes[prefix+resourceKeyLoadBalancerMemberPort] = fmt.Sprintf("%d", member.Port)
+	}
+
+	return &terraform.InstanceState{
+		ID:         "listener-1",
+		Attributes: attributes,
+	}
+}
+
+// Create the configuration for a load balancer with the specified back-end servers (on port 80).
+func testLoadBalancerBackendConfig(backendIPv4Addresses ...string) *terraform.ResourceConfig {
+	backends := make([]interface{}, len(backendIPv4Addresses))
+	for index, backendIPv4Address := range backendIPv4Addresses {
+		backends[index] = map[string]interface{}{
+			resourceKeyLoadBalancerBackendIPv4Address: backendIPv4Address,
+			resourceKeyLoadBalancerBackendPort:        80,
+		}
+	}
+
+	return terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeyLoadBalancerName:            "lb-1",
+		resourceKeyLoadBalancerNetworkDomainID: "networkdomain-1",
+		resourceKeyLoadBalancerBackend:         backends,
+		resourceKeyLoadBalancerPort:            80,
+		resourceKeyLoadBalancerIPv4Address:     "10.0.1.1",
+	})
+}
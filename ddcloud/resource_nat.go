@@ -1,6 +1,7 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strconv"
@@ -9,7 +10,8 @@ import (
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -23,13 +25,12 @@ const (
 
 func resourceNAT() *schema.Resource {
 	return &schema.Resource{
-		Exists: resourceNATExists,
-		Create: resourceNATCreate,
-		Read:   resourceNATRead,
-		Update: resourceNATUpdate,
-		Delete: resourceNATDelete,
+		CreateContext: resourceNATCreate,
+		ReadContext:   resourceNATRead,
+		UpdateContext: resourceNATUpdate,
+		DeleteContext: resourceNATDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceNATImport,
+			StateContext: resourceNATImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -56,28 +57,8 @@ func resourceNAT() *schema.Resource {
 	}
 }
 
-// Check if a NAT resource exists.
-func resourceNATExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	id := data.Id()
-	log.Printf("Check if NAT rule '%s' exists.", id)
-
-	providerState := provider.(*providerState)
-	apiClient := providerState.Client()
-
-	natRule, err := apiClient.GetNATRule(id)
-	if err != nil {
-		return false, err
-	}
-
-	exists := natRule != nil
-
-	log.Printf("NAT rule '%s' exists: %t.", id, exists)
-
-	return exists, nil
-}
-
 // Create a NAT resource.
-func resourceNATCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceNATCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	var err error
 
 	propertyHelper := propertyHelper(data)
@@ -101,7 +82,7 @@ func resourceNATCreate(data *schema.ResourceData, provider interface{}) error {
 	)
 
 	operationDescription := fmt.Sprintf("Create NAT rule (from public IP '%s' to private IP '%s')", publicIPDescription, privateIP)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -129,7 +110,7 @@ func resourceNATCreate(data *schema.ResourceData, provider interface{}) error {
 		}
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(natRuleID)
@@ -137,11 +118,11 @@ func resourceNATCreate(data *schema.ResourceData, provider interface{}) error {
 
 	natRule, err := apiClient.GetNATRule(natRuleID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if natRule == nil {
-		return fmt.Errorf("cannot find newly-added NAT rule '%s'", natRuleID)
+		return diag.Errorf("cannot find newly-added NAT rule '%s'", natRuleID)
 	}
 
 	data.Set(resourceKeyNATPublicAddress, natRule.ExternalIPAddress)
@@ -151,7 +132,7 @@ func resourceNATCreate(data *schema.ResourceData, provider interface{}) error {
 }
 
 // Read a NAT resource.
-func resourceNATRead(data *schema.ResourceData, provider interface{}) error {
+func resourceNATRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeyNATNetworkDomainID).(string)
 	privateIP := data.Get(resourceKeyNATPrivateAddress).(string)
@@ -163,7 +144,7 @@ func resourceNATRead(data *schema.ResourceData, provider interface{}) error {
 
 	natRule, err := apiClient.GetNATRule(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if natRule == nil {
 		data.SetId("") // NAT rule has been deleted
@@ -175,7 +156,7 @@ func resourceNATRead(data *schema.ResourceData, provider interface{}) error {
 }
 
 // Update a NAT resource.
-func resourceNATUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceNATUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeyNATNetworkDomainID).(string)
 	privateIP := data.Get(resourceKeyNATPrivateAddress).(string)
@@ -187,7 +168,7 @@ func resourceNATUpdate(data *schema.ResourceData, provider interface{}) error {
 }
 
 // Delete a NAT resource.
-func resourceNATDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceNATDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeyNATNetworkDomainID).(string)
 	privateIP := data.Get(resourceKeyNATPrivateAddress).(string)
@@ -200,7 +181,7 @@ func resourceNATDelete(data *schema.ResourceData, provider interface{}) error {
 
 	operationDescription := fmt.Sprintf("Delete NAT '%s", id)
 
-	return providerState.RetryAction(operationDescription, func(context retry.Context) {
+	return diag.FromErr(providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
@@ -213,11 +194,11 @@ func resourceNATDelete(data *schema.ResourceData, provider interface{}) error {
 				context.Fail(err)
 			}
 		}
-	})
+	}))
 }
 
 // Import data for an existing network domain.
-func resourceNATImport(data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+func resourceNATImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
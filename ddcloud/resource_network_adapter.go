@@ -1,13 +1,15 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -22,13 +24,12 @@ const (
 
 func resourceNetworkAdapter() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetworkAdapterCreate,
-		Exists: resourceNetworkAdapterExists,
-		Read:   resourceNetworkAdapterRead,
-		Update: resourceNetworkAdapterUpdate,
-		Delete: resourceNetworkAdapterDelete,
+		CreateContext: resourceNetworkAdapterCreate,
+		ReadContext:   resourceNetworkAdapterRead,
+		UpdateContext: resourceNetworkAdapterUpdate,
+		DeleteContext: resourceNetworkAdapterDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceNetworkAdapterImport,
+			StateContext: resourceNetworkAdapterImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -68,7 +69,7 @@ func resourceNetworkAdapter() *schema.Resource {
 
 }
 
-func resourceNetworkAdapterCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceNetworkAdapterCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	propertyHelper := propertyHelper(data)
 	serverID := data.Get(resourceKeyNetworkAdapterServerID).(string)
 	ipv4Address := data.Get(resourceKeyNetworkAdapterPrivateIPV4).(string)
@@ -82,17 +83,17 @@ func resourceNetworkAdapterCreate(data *schema.ResourceData, provider interface{
 
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
-		return fmt.Errorf("cannot find server with '%s'", serverID)
+		return diag.Errorf("cannot find server with '%s'", serverID)
 	}
 
 	isStarted := server.Started
 	if isStarted {
-		err = serverShutdown(providerState, serverID)
+		err = serverShutdown(ctx, providerState, serverID)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -100,7 +101,7 @@ func resourceNetworkAdapterCreate(data *schema.ResourceData, provider interface{
 
 	var networkAdapterID string
 	operationDescription := fmt.Sprintf("Add network adapter to server '%s'", serverID)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -120,7 +121,7 @@ func resourceNetworkAdapterCreate(data *schema.ResourceData, provider interface{
 		asyncLock.Release()
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	data.SetId(networkAdapterID)
 
@@ -136,24 +137,24 @@ func resourceNetworkAdapterCreate(data *schema.ResourceData, provider interface{
 		resourceUpdateTimeoutServer,
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("created the nic with the id %s", networkAdapterID)
 	if isStarted {
-		err = serverStart(providerState, serverID)
+		err = serverStart(ctx, providerState, serverID)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	log.Printf("Refresh properties for network adapter '%s' in server '%s'", networkAdapterID, serverID)
 	server, err = apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
-		return fmt.Errorf("cannot find server '%s'", serverID)
+		return diag.Errorf("cannot find server '%s'", serverID)
 	}
 
 	serverNetworkAdapters := models.NewNetworkAdaptersFromVirtualMachineNetwork(server.Network)
@@ -161,10 +162,10 @@ func resourceNetworkAdapterCreate(data *schema.ResourceData, provider interface{
 	if serverNetworkAdapter == nil {
 		data.SetId("") // NetworkAdapter deleted
 
-		return fmt.Errorf("Newly-created network adapter (Id = '%s') not found", networkAdapterID)
+		return diag.Errorf("Newly-created network adapter (Id = '%s') not found", networkAdapterID)
 	}
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.Set(resourceKeyNetworkAdapterPrivateIPV4, serverNetworkAdapter.PrivateIPv4Address)
@@ -175,38 +176,7 @@ func resourceNetworkAdapterCreate(data *schema.ResourceData, provider interface{
 	return nil
 }
 
-func resourceNetworkAdapterExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	nicID := data.Id()
-	serverID := data.Get(resourceKeyNetworkAdapterServerID).(string)
-
-	apiClient := provider.(*providerState).Client()
-
-	log.Printf("Get the server with Id %s", serverID)
-
-	server, err := apiClient.GetServer(serverID)
-
-	if server == nil {
-		log.Printf("Server '%s' not found; will treat network adapter '%s' as non-existent.", serverID, nicID)
-
-		return false, nil
-	}
-
-	if err != nil {
-		return false, err
-	}
-
-	serverNetworkAdapters := server.Network.AdditionalNetworkAdapters
-	for _, nic := range serverNetworkAdapters {
-
-		if *nic.ID == nicID {
-			return true, nil
-		}
-	}
-
-	return false, nil
-}
-
-func resourceNetworkAdapterRead(data *schema.ResourceData, provider interface{}) error {
+func resourceNetworkAdapterRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	networkAdapterID := data.Id()
 	serverID := data.Get(resourceKeyNetworkAdapterServerID).(string)
 
@@ -216,10 +186,13 @@ func resourceNetworkAdapterRead(data *schema.ResourceData, provider interface{})
 	apiClient := providerState.Client()
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
-		return fmt.Errorf("Server '%s' not found", serverID)
+		log.Printf("Server '%s' was not found (will treat network adapter '%s' as deleted).", serverID, networkAdapterID)
+		data.SetId("") // Deleted.
+
+		return nil
 	}
 
 	serverNetworkAdapter := models.NewNetworkAdaptersFromVirtualMachineNetwork(server.Network).GetByID(networkAdapterID)
@@ -241,7 +214,7 @@ func resourceNetworkAdapterRead(data *schema.ResourceData, provider interface{})
 	return nil
 }
 
-func resourceNetworkAdapterUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceNetworkAdapterUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	networkAdapterID := data.Id()
 	serverID := data.Get(resourceKeyNetworkAdapterServerID).(string)
 
@@ -249,10 +222,13 @@ func resourceNetworkAdapterUpdate(data *schema.ResourceData, provider interface{
 	apiClient := providerState.Client()
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
-		return fmt.Errorf("Server '%s' not found", serverID)
+		log.Printf("Server '%s' was not found (will treat network adapter '%s' as deleted).", serverID, networkAdapterID)
+		data.SetId("") // Deleted.
+
+		return nil
 	}
 
 	serverNetworkAdapter := models.NewNetworkAdaptersFromVirtualMachineNetwork(server.Network).GetByID(networkAdapterID)
@@ -268,22 +244,22 @@ func resourceNetworkAdapterUpdate(data *schema.ResourceData, provider interface{
 
 	configuredNetworkAdapter := propertyHelper(data).GetNetworkAdapter()
 	if data.HasChange(resourceKeyNetworkAdapterPrivateIPV4) {
-		err := modifyServerNetworkAdapterIP(providerState, serverID, configuredNetworkAdapter)
+		err := modifyServerNetworkAdapterIP(ctx, providerState, serverID, configuredNetworkAdapter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	if data.HasChange(resourceKeyNetworkAdapterType) {
-		err := modifyServerNetworkAdapterType(providerState, serverID, configuredNetworkAdapter)
+		err := modifyServerNetworkAdapterType(ctx, providerState, serverID, configuredNetworkAdapter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceNetworkAdapterDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceNetworkAdapterDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	networkAdapterID := data.Id()
 	serverID := data.Get(resourceKeyNetworkAdapterServerID).(string)
 
@@ -294,10 +270,10 @@ func resourceNetworkAdapterDelete(data *schema.ResourceData, provider interface{
 
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
-		return fmt.Errorf("cannot find server '%s'", serverID)
+		return diag.Errorf("cannot find server '%s'", serverID)
 	}
 
 	serverNetworkAdapter := models.NewNetworkAdaptersFromVirtualMachineNetwork(server.Network).GetByID(networkAdapterID)
@@ -312,14 +288,14 @@ func resourceNetworkAdapterDelete(data *schema.ResourceData, provider interface{
 
 	isStarted := server.Started
 	if isStarted {
-		err = serverShutdown(providerState, serverID)
+		err = serverShutdown(ctx, providerState, serverID)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	operationDescription := fmt.Sprintf("Remove network adapter '%s' from server '%s'", networkAdapterID, serverID)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -333,7 +309,7 @@ func resourceNetworkAdapterDelete(data *schema.ResourceData, provider interface{
 		}
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Removing network adapter '%s' from server '%s'...",
@@ -347,7 +323,7 @@ func resourceNetworkAdapterDelete(data *schema.ResourceData, provider interface{
 		resourceUpdateTimeoutServer,
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId("") // Resource deleted.
@@ -358,9 +334,9 @@ func resourceNetworkAdapterDelete(data *schema.ResourceData, provider interface{
 	)
 
 	if isStarted {
-		err = serverStart(providerState, serverID)
+		err = serverStart(ctx, providerState, serverID)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -368,7 +344,7 @@ func resourceNetworkAdapterDelete(data *schema.ResourceData, provider interface{
 }
 
 // Import data for an existing network adapter.
-func resourceNetworkAdapterImport(data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+func resourceNetworkAdapterImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
@@ -1,6 +1,7 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -8,7 +9,8 @@ import (
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -25,12 +27,12 @@ const (
 
 func resourceNetworkDomain() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetworkDomainCreate,
-		Read:   resourceNetworkDomainRead,
-		Update: resourceNetworkDomainUpdate,
-		Delete: resourceNetworkDomainDelete,
+		CreateContext: resourceNetworkDomainCreate,
+		ReadContext:   resourceNetworkDomainRead,
+		UpdateContext: resourceNetworkDomainUpdate,
+		DeleteContext: resourceNetworkDomainDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceNetworkDomainImport,
+			StateContext: resourceNetworkDomainImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -78,7 +80,7 @@ func resourceNetworkDomain() *schema.Resource {
 }
 
 // Create a network domain resource.
-func resourceNetworkDomainCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceNetworkDomainCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	var name, description, plan, dataCenterID string
 
 	name = data.Get(resourceKeyNetworkDomainName).(string)
@@ -93,7 +95,7 @@ func resourceNetworkDomainCreate(data *schema.ResourceData, provider interface{}
 
 	var networkDomainID string
 	operationDescription := fmt.Sprintf("Create network domain '%s'", name)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock("Create network domain '%s'", name)
 		defer asyncLock.Release()
@@ -109,7 +111,7 @@ func resourceNetworkDomainCreate(data *schema.ResourceData, provider interface{}
 		asyncLock.Release()
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(networkDomainID)
@@ -118,35 +120,29 @@ func resourceNetworkDomainCreate(data *schema.ResourceData, provider interface{}
 
 	resource, err := apiClient.WaitForDeploy(compute.ResourceTypeNetworkDomain, networkDomainID, resourceCreateTimeoutVLAN)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	data.Partial(true)
-
 	// Capture additional properties that are only available after deployment.
 	networkDomain := resource.(*compute.NetworkDomain)
 	data.Set(resourceKeyNetworkDomainNatIPv4Address, networkDomain.NatIPv4Address)
-	data.SetPartial(resourceKeyNetworkDomainNatIPv4Address)
 
 	data.Set(resourceKeyNetworkDomainOutsideTransitIPv4Subnet, fmt.Sprintf(
 		"%s/%d",
 		networkDomain.OutsideTransitVLANIPv4Subnet.BaseAddress,
 		networkDomain.OutsideTransitVLANIPv4Subnet.PrefixSize,
 	))
-	data.SetPartial(resourceKeyNetworkDomainOutsideTransitIPv4Subnet)
 
 	err = applyNetworkDomainDefaultFirewallRules(data, apiClient)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	data.Partial(false)
-
 	return nil
 }
 
 // Read a network domain resource.
-func resourceNetworkDomainRead(data *schema.ResourceData, provider interface{}) error {
+func resourceNetworkDomainRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	var name, description, plan, dataCenterID string
 
 	id := data.Id()
@@ -161,44 +157,34 @@ func resourceNetworkDomainRead(data *schema.ResourceData, provider interface{})
 
 	networkDomain, err := apiClient.GetNetworkDomain(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	data.Partial(true)
-
 	if networkDomain != nil {
 		data.Set(resourceKeyNetworkDomainName, networkDomain.Name)
-		data.SetPartial(resourceKeyNetworkDomainName)
 		data.Set(resourceKeyNetworkDomainDescription, networkDomain.Description)
-		data.SetPartial(resourceKeyNetworkDomainDescription)
 		data.Set(resourceKeyNetworkDomainPlan, networkDomain.Type)
-		data.SetPartial(resourceKeyNetworkDomainPlan)
 		data.Set(resourceKeyNetworkDomainDataCenter, networkDomain.DatacenterID)
-		data.SetPartial(resourceKeyNetworkDomainDataCenter)
 		data.Set(resourceKeyNetworkDomainNatIPv4Address, networkDomain.NatIPv4Address)
-		data.SetPartial(resourceKeyNetworkDomainNatIPv4Address)
 		data.Set(resourceKeyNetworkDomainOutsideTransitIPv4Subnet, fmt.Sprintf(
 			"%s/%d",
 			networkDomain.OutsideTransitVLANIPv4Subnet.BaseAddress,
 			networkDomain.OutsideTransitVLANIPv4Subnet.PrefixSize,
 		))
-		data.SetPartial(resourceKeyNetworkDomainOutsideTransitIPv4Subnet)
 	} else {
 		data.SetId("") // Mark resource as deleted.
 	}
 
 	err = readNetworkDomainDefaultFirewallRules(data, apiClient)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	data.Partial(false)
-
 	return nil
 }
 
 // Update a network domain resource.
-func resourceNetworkDomainUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceNetworkDomainUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	var (
 		id, name, description, plan      string
 		newName, newDescription, newPlan *string
@@ -230,20 +216,20 @@ func resourceNetworkDomainUpdate(data *schema.ResourceData, provider interface{}
 	if newName != nil || newPlan != nil || newDescription != nil {
 		err = apiClient.EditNetworkDomain(id, newName, newDescription, newPlan)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	err = applyNetworkDomainDefaultFirewallRules(data, apiClient)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
 // Delete a network domain resource.
-func resourceNetworkDomainDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceNetworkDomainDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	networkDomainID := data.Id()
 	name := data.Get(resourceKeyNetworkDomainName).(string)
 	dataCenterID := data.Get(resourceKeyNetworkDomainDataCenter).(string)
@@ -255,11 +241,11 @@ func resourceNetworkDomainDelete(data *schema.ResourceData, provider interface{}
 
 	err := deleteAllPublicIPBlocks(networkDomainID, providerState)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	operationDescription := fmt.Sprintf("Create network domain '%s'", name)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock("Delete network domain '%s'", networkDomainID)
 		defer asyncLock.Release()
@@ -274,12 +260,12 @@ func resourceNetworkDomainDelete(data *schema.ResourceData, provider interface{}
 		asyncLock.Release()
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Network domain '%s' is being deleted...", networkDomainID)
 
-	return apiClient.WaitForDelete(compute.ResourceTypeNetworkDomain, networkDomainID, resourceDeleteTimeoutServer)
+	return diag.FromErr(apiClient.WaitForDelete(compute.ResourceTypeNetworkDomain, networkDomainID, resourceDeleteTimeoutServer))
 }
 
 // Delete all public IP blocks (if any) in a network domain.
@@ -315,7 +301,7 @@ func deleteAllPublicIPBlocks(networkDomainID string, providerState *providerStat
 }
 
 // Import data for an existing network domain.
-func resourceNetworkDomainImport(data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+func resourceNetworkDomainImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
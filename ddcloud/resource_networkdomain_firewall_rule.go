@@ -6,7 +6,7 @@ import (
 	"strings"
 
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -93,7 +93,6 @@ func readNetworkDomainDefaultFirewallRules(data *schema.ResourceData, apiClient
 	// Persist changes.
 	defaultRuleSet := schema.NewSet(hashNetworkDomainFirewallRule, defaultRules)
 	data.Set(resourceKeyNetworkDomainFirewallRule, defaultRuleSet)
-	data.SetPartial(resourceKeyNetworkDomainFirewallRule)
 
 	return nil
 }
@@ -1,10 +1,12 @@
 package ddcloud
 
 import (
+	"context"
 	"log"
 
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -20,11 +22,10 @@ const (
 
 func resourcePortList() *schema.Resource {
 	return &schema.Resource{
-		Exists: resourcePortListExists,
-		Create: resourcePortListCreate,
-		Read:   resourcePortListRead,
-		Update: resourcePortListUpdate,
-		Delete: resourcePortListDelete,
+		CreateContext: resourcePortListCreate,
+		ReadContext:   resourcePortListRead,
+		UpdateContext: resourcePortListUpdate,
+		DeleteContext: resourcePortListDelete,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyPortListNetworkDomainID: &schema.Schema{
@@ -88,24 +89,8 @@ func resourcePortList() *schema.Resource {
 	}
 }
 
-// Check if a port list resource exists.
-func resourcePortListExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	portListID := data.Id()
-
-	log.Printf("Check if port list '%s' exists.", portListID)
-
-	client := provider.(*providerState).Client()
-
-	portList, err := client.GetPortList(portListID)
-	exists := (portList != nil)
-
-	log.Printf("Port list '%s' exists: %t", portListID, true)
-
-	return exists, err
-}
-
 // Create a port list resource.
-func resourcePortListCreate(data *schema.ResourceData, provider interface{}) error {
+func resourcePortListCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	propertyHelper := propertyHelper(data)
 
 	networkDomainID := data.Get(resourceKeyPortListNetworkDomainID).(string)
@@ -132,7 +117,7 @@ func resourcePortListCreate(data *schema.ResourceData, provider interface{}) err
 	client := provider.(*providerState).Client()
 	portListID, err := client.CreatePortList(name, description, networkDomainID, portListEntries, childListIDs)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(portListID)
@@ -143,7 +128,7 @@ func resourcePortListCreate(data *schema.ResourceData, provider interface{}) err
 }
 
 // Read a port list resource.
-func resourcePortListRead(data *schema.ResourceData, provider interface{}) error {
+func resourcePortListRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	portListID := data.Id()
 	networkDomainID := data.Get(resourceKeyPortListNetworkDomainID).(string)
 
@@ -152,7 +137,7 @@ func resourcePortListRead(data *schema.ResourceData, provider interface{}) error
 	client := provider.(*providerState).Client()
 	portList, err := client.GetPortList(portListID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if portList == nil {
@@ -188,7 +173,7 @@ func resourcePortListRead(data *schema.ResourceData, provider interface{}) error
 }
 
 // Update a port list resource.
-func resourcePortListUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourcePortListUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	portListID := data.Id()
 	networkDomainID := data.Get(resourceKeyPortListNetworkDomainID).(string)
 
@@ -197,7 +182,7 @@ func resourcePortListUpdate(data *schema.ResourceData, provider interface{}) err
 	client := provider.(*providerState).Client()
 	portList, err := client.GetPortList(portListID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if portList == nil {
@@ -237,7 +222,7 @@ func resourcePortListUpdate(data *schema.ResourceData, provider interface{}) err
 
 	err = client.EditPortList(portListID, editRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Updated port list '%s'.", portListID)
@@ -246,7 +231,7 @@ func resourcePortListUpdate(data *schema.ResourceData, provider interface{}) err
 }
 
 // Delete a port list resource.
-func resourcePortListDelete(data *schema.ResourceData, provider interface{}) error {
+func resourcePortListDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	portListID := data.Id()
 	networkDomainID := data.Get(resourceKeyPortListNetworkDomainID).(string)
 
@@ -255,7 +240,7 @@ func resourcePortListDelete(data *schema.ResourceData, provider interface{}) err
 	client := provider.(*providerState).Client()
 	portList, err := client.GetPortList(portListID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if portList == nil {
@@ -266,7 +251,7 @@ func resourcePortListDelete(data *schema.ResourceData, provider interface{}) err
 
 	err = client.DeletePortList(portListID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Successfully deleted port list '%s' in network domain '%s'.", portListID, networkDomainID)
@@ -1,6 +1,7 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -11,7 +12,8 @@ import (
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/validators"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -39,7 +41,7 @@ const (
 	resourceKeyServerBackupEnabled            = "backup_enabled"
 	resourceKeyServerBackupClientDownloadURLs = "backup_client_urls"
 
-	// Obsolete properties
+	// Obsolete properties (only used when upgrading state from previous schema versions)
 	resourceKeyServerOSImageID          = "os_image_id"
 	resourceKeyServerOSImageName        = "os_image_name"
 	resourceKeyServerCustomerImageID    = "customer_image_id"
@@ -54,14 +56,15 @@ const (
 )
 
 func resourceServer() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		SchemaVersion: 5,
-		Create:        resourceServerCreate,
-		Read:          resourceServerRead,
-		Update:        resourceServerUpdate,
-		Delete:        resourceServerDelete,
+		CreateContext: resourceServerCreate,
+		ReadContext:   resourceServerRead,
+		UpdateContext: resourceServerUpdate,
+		DeleteContext: resourceServerDelete,
+		CustomizeDiff: resourceServerCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceServerImport,
+			StateContext: resourceServerImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -211,6 +214,9 @@ func resourceServer() *schema.Resource {
 
 			resourceKeyTag: schemaTag(),
 
+			resourceKeyServerSnapshotService:     schemaServerSnapshotService(),
+			resourceKeyServerRestoreFromSnapshot: schemaServerRestoreFromSnapshot(),
+
 			resourceKeyServerBackupEnabled: &schema.Schema{
 				Type:        schema.TypeBool,
 				Computed:    true,
@@ -221,73 +227,52 @@ func resourceServer() *schema.Resource {
 				Computed:    true,
 				Description: "Download URLs for the server's backup clients (if any)",
 			},
-
-			// Obsolete properties
-			resourceKeyServerPrimaryAdapterType: &schema.Schema{
-				Type:        schema.TypeString,
-				Computed:    true,
-				Default:     nil,
-				Description: "The type of the server's primary network adapter (E1000 or VMXNET3)",
-				Removed:     "This property has been removed because it is not exposed via the CloudControl API and will not be available until the provider uses the new (v2.4) API",
-			},
-			resourceKeyServerOSImageID: &schema.Schema{
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     nil,
-				Description: "The Id of the OS (built-in) image from which the server is created",
-				Removed:     fmt.Sprintf("This property has been removed; use %s instead.", resourceKeyServerImage),
-			},
-			resourceKeyServerOSImageName: &schema.Schema{
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     nil,
-				Description: "The name of the OS (built-in) image from which the server is created",
-				Removed:     fmt.Sprintf("This property has been removed; use %s instead.", resourceKeyServerImage),
-			},
-			resourceKeyServerCustomerImageID: &schema.Schema{
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     nil,
-				Description: "The Id of the customer (custom) image from which the server is created",
-				Removed:     fmt.Sprintf("This property has been removed; use %s instead.", resourceKeyServerImage),
-			},
-			resourceKeyServerCustomerImageName: &schema.Schema{
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     nil,
-				Description: "The name of the customer (custom) image from which the server is created",
-				Removed:     fmt.Sprintf("This property has been removed; use %s instead.", resourceKeyServerImage),
-			},
-			resourceKeyServerAutoStart: &schema.Schema{
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "Should the server be started automatically once it has been deployed",
-				Removed:     fmt.Sprintf("This propery as been removed; set %s to autostart instead", resourceKeyServerPowerState),
-			},
 		},
-		MigrateState: resourceServerMigrateState,
 	}
+	resource.StateUpgraders = resourceServerStateUpgraders(
+		stateTypeOf(resource),
+	)
+
+	return resource
 }
 
 // Create a server resource.
-func resourceServerCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceServerCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	name := data.Get(resourceKeyServerName).(string)
 	description := data.Get(resourceKeyServerDescription).(string)
 	networkDomainID := data.Get(resourceKeyServerNetworkDomainID).(string)
 
 	log.Printf("Create server '%s' in network domain '%s' (description = '%s').", name, networkDomainID, description)
 
+	diagnostics := validateServerDisks(propertyHelper(data).GetDisks())
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
 	networkDomain, err := apiClient.GetNetworkDomain(networkDomainID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if networkDomain == nil {
-		return fmt.Errorf("no network domain was found with Id '%s'", networkDomainID)
+		return diag.Errorf("no network domain was found with Id '%s'", networkDomainID)
+	}
+
+	if data.Get(resourceKeyServerRestoreFromSnapshot).(string) != "" {
+		err = restoreServerFromSnapshot(ctx, data, providerState)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = updateServerSnapshotService(ctx, data, providerState)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		return resourceServerRead(ctx, data, provider)
 	}
 
 	dataCenterID := networkDomain.DatacenterID
@@ -297,21 +282,26 @@ func resourceServerCreate(data *schema.ResourceData, provider interface{}) error
 	configuredImageType := data.Get(resourceKeyServerImageType).(string)
 	image, err := resolveServerImage(configuredImage, configuredImageType, dataCenterID, apiClient)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if image == nil {
-		return fmt.Errorf("an unexpected error occurred while resolving the configured server image")
+		return diag.Errorf("an unexpected error occurred while resolving the configured server image")
 	}
 
 	if image.RequiresCustomization() {
-		return deployCustomizedServer(data, providerState, networkDomain, image)
+		err = deployCustomizedServer(ctx, data, providerState, networkDomain, image)
+	} else {
+		err = deployUncustomizedServer(ctx, data, providerState, networkDomain, image)
+	}
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	return deployUncustomizedServer(data, providerState, networkDomain, image)
+	return diag.FromErr(updateServerSnapshotService(ctx, data, providerState))
 }
 
 // Read a server resource.
-func resourceServerRead(data *schema.ResourceData, provider interface{}) error {
+func resourceServerRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	log.Printf("resource_server > resourceServerRead")
 	propertyHelper := propertyHelper(data)
 
@@ -325,7 +315,7 @@ func resourceServerRead(data *schema.ResourceData, provider interface{}) error {
 	apiClient := provider.(*providerState).Client()
 	server, err := apiClient.GetServer(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if server == nil {
@@ -372,7 +362,7 @@ func resourceServerRead(data *schema.ResourceData, provider interface{}) error {
 		}
 	}
 
-	captureServerNetworkConfiguration(server, data, false)
+	captureServerNetworkConfiguration(server, data)
 
 	var publicIPv4Address string
 	publicIPv4Address, err = findPublicIPv4Address(apiClient,
@@ -380,7 +370,7 @@ func resourceServerRead(data *schema.ResourceData, provider interface{}) error {
 		*server.Network.PrimaryAdapter.PrivateIPv4Address,
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if !isEmpty(publicIPv4Address) {
 		data.Set(resourceKeyServerPublicIPv4, publicIPv4Address)
@@ -390,31 +380,48 @@ func resourceServerRead(data *schema.ResourceData, provider interface{}) error {
 
 	err = readTags(data, apiClient, compute.AssetTypeServer)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+
+	diskResourceSCSIPaths, err := getDiskResourceSCSIPaths(apiClient, id)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	propertyHelper.SetDisks(
-		models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers),
+		models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers).Managed(diskResourceSCSIPaths),
 	)
 
 	networkAdapters := propertyHelper.GetServerNetworkAdapters()
-	propertyHelper.SetServerNetworkAdapters(networkAdapters, false)
+	propertyHelper.SetServerNetworkAdapters(networkAdapters)
 
-	return readServerBackupClientDownloadURLs(server.ID, data, apiClient)
+	err = readServerSnapshotService(ctx, data, provider.(*providerState))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(readServerBackupClientDownloadURLs(server.ID, data, apiClient))
 }
 
 // Update a server resource.
-func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceServerUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	serverID := data.Id()
 
 	log.Printf("Update server '%s'.", serverID)
 
+	if data.HasChange(resourceKeyServerDisk) {
+		diagnostics := validateServerDisks(propertyHelper(data).GetDisks())
+		if diagnostics.HasError() {
+			return diagnostics
+		}
+	}
+
 	providerState := provider.(*providerState)
 
 	apiClient := providerState.Client()
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if server == nil {
@@ -424,8 +431,6 @@ func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error
 		return nil
 	}
 
-	data.Partial(true)
-
 	propertyHelper := propertyHelper(data)
 
 	var name, description *string
@@ -442,14 +447,12 @@ func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error
 
 		err = apiClient.EditServerMetadata(serverID, name, description)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		if name != nil {
-			data.SetPartial(resourceKeyServerName)
 		}
 		if description != nil {
-			data.SetPartial(resourceKeyServerDescription)
 		}
 	}
 
@@ -473,15 +476,13 @@ func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error
 
 		err = updateServerConfiguration(apiClient, server, memoryGB, cpuCount, cpuCoreCount, cpuSpeed)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		if data.HasChange(resourceKeyServerMemoryGB) {
-			data.SetPartial(resourceKeyServerMemoryGB)
 		}
 
 		if data.HasChange(resourceKeyServerCPUCount) {
-			data.SetPartial(resourceKeyServerCPUCount)
 		}
 	}
 
@@ -497,17 +498,17 @@ func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error
 
 		if (configuredPrimaryNetworkAdapter.PrivateIPv4Address != actualPrimaryNetworkAdapter.PrivateIPv4Address) ||
 			(configuredPrimaryNetworkAdapter.PrivateIPv6Address != actualPrimaryNetworkAdapter.PrivateIPv6Address) {
-			err = modifyServerNetworkAdapterIP(providerState, serverID, *configuredPrimaryNetworkAdapter)
+			err = modifyServerNetworkAdapterIP(ctx, providerState, serverID, *configuredPrimaryNetworkAdapter)
 
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 
 		if configuredPrimaryNetworkAdapter.AdapterType != actualPrimaryNetworkAdapter.AdapterType {
-			err = modifyServerNetworkAdapterType(providerState, serverID, *configuredPrimaryNetworkAdapter)
+			err = modifyServerNetworkAdapterType(ctx, providerState, serverID, *configuredPrimaryNetworkAdapter)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 
@@ -518,7 +519,7 @@ func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error
 			*server.Network.PrimaryAdapter.PrivateIPv4Address,
 		)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		if !isEmpty(publicIPv4Address) {
 			data.Set(resourceKeyServerPublicIPv4, publicIPv4Address)
@@ -529,10 +530,10 @@ func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error
 		// Persist final state.
 		server, err = apiClient.GetServer(serverID)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		if server == nil {
-			return fmt.Errorf("cannot find server with Id '%s'", serverID)
+			return diag.Errorf("cannot find server with Id '%s'", serverID)
 		}
 	}
 
@@ -552,19 +553,19 @@ func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error
 		configuredAdditionalAdapters := propertyHelper.GetServerNetworkAdapters().GetAdditional()
 		for _, configured := range configuredAdditionalAdapters {
 
-			err = modifyServerNetworkAdapterIP(providerState, serverID, configured)
+			err = modifyServerNetworkAdapterIP(ctx, providerState, serverID, configured)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 
 		// Persist final state.
 		server, err = apiClient.GetServer(serverID)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		if server == nil {
-			return fmt.Errorf("cannot find server with Id '%s'", serverID)
+			return diag.Errorf("cannot find server with Id '%s'", serverID)
 		}
 
 	}
@@ -572,16 +573,22 @@ func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error
 	if data.HasChange(resourceKeyTag) {
 		err = applyTags(data, apiClient, compute.AssetTypeServer, providerState.Settings())
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
-		data.SetPartial(resourceKeyTag)
 	}
 
 	if data.HasChange(resourceKeyServerDisk) {
-		err = updateDisks(data, providerState)
+		err = updateDisks(ctx, data, providerState)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
+		}
+	}
+
+	if data.HasChange(resourceKeyServerSnapshotService) {
+		err = updateServerSnapshotService(ctx, data, providerState)
+		if err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
@@ -595,14 +602,14 @@ func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error
 			switch strings.ToLower(*powerState) {
 			case "start":
 				if !isServerStartedActual {
-					err = serverStart(providerState, serverID)
+					err = serverStart(ctx, providerState, serverID)
 				}
 			case "shutdown":
 				if isServerStartedActual {
-					err = serverShutdown(providerState, serverID)
+					err = serverShutdown(ctx, providerState, serverID)
 				}
 			case "shutdown-hard":
-				err = serverPowerOff(providerState, serverID)
+				err = serverPowerOff(ctx, providerState, serverID)
 			case "disabled", "autostart":
 				// do nothing
 				break
@@ -611,17 +618,16 @@ func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error
 			}
 
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 
-			data.SetPartial(resourceKeyServerPowerState)
 		}
 
 	}
 	// Refresh Server State after Power State
 	server, err = apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if server.Started {
@@ -629,13 +635,12 @@ func resourceServerUpdate(data *schema.ResourceData, provider interface{}) error
 	} else {
 		data.Set(resourceKeyServerStarted, false)
 	}
-	data.Partial(false)
 
 	return nil
 }
 
 // Delete a server resource.
-func resourceServerDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceServerDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	name := data.Get(resourceKeyServerName).(string)
 	networkDomainID := data.Get(resourceKeyServerNetworkDomainID).(string)
@@ -647,7 +652,7 @@ func resourceServerDelete(data *schema.ResourceData, provider interface{}) error
 
 	server, err := apiClient.GetServer(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
 		log.Printf("Server '%s' not found; will treat the server as having already been deleted.", id)
@@ -657,14 +662,14 @@ func resourceServerDelete(data *schema.ResourceData, provider interface{}) error
 
 	if server.Started {
 		log.Printf("Server '%s' is currently running. The server will be powered off.", id)
-		err = serverPowerOff(providerState, id)
+		err = serverPowerOff(ctx, providerState, id)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	operationDescription := fmt.Sprintf("Delete server '%s'", id)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -676,16 +681,16 @@ func resourceServerDelete(data *schema.ResourceData, provider interface{}) error
 		}
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Server '%s' is being deleted...", id)
 
-	return apiClient.WaitForDelete(compute.ResourceTypeServer, id, resourceDeleteTimeoutServer)
+	return diag.FromErr(apiClient.WaitForDelete(compute.ResourceTypeServer, id, resourceDeleteTimeoutServer))
 }
 
 // Import data for an existing server.
-func resourceServerImport(data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+func resourceServerImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
@@ -710,7 +715,7 @@ func resourceServerImport(data *schema.ResourceData, provider interface{}) (impo
 	data.Set(resourceKeyServerCPUSpeed, server.CPU.Speed)
 	data.Set(resourceKeyServerOSType, server.OperatingSystem)
 
-	captureServerNetworkConfiguration(server, data, false)
+	captureServerNetworkConfiguration(server, data)
 
 	var publicIPv4Address string
 	publicIPv4Address, err = findPublicIPv4Address(apiClient,
@@ -751,7 +756,7 @@ func resourceServerImport(data *schema.ResourceData, provider interface{}) (impo
 // TODO: Refactor deployCustomizedServer / deployUncustomizedServer and move common logic to shared functions.
 
 // Deploy a server with guest OS customisation.
-func deployCustomizedServer(data *schema.ResourceData, providerState *providerState, networkDomain *compute.NetworkDomain, image compute.Image) error {
+func deployCustomizedServer(ctx context.Context, data *schema.ResourceData, providerState *providerState, networkDomain *compute.NetworkDomain, image compute.Image) error {
 	name := data.Get(resourceKeyServerName).(string)
 	description := data.Get(resourceKeyServerDescription).(string)
 	adminPassword := data.Get(resourceKeyServerAdminPassword).(string)
@@ -791,16 +796,9 @@ func deployCustomizedServer(data *schema.ResourceData, providerState *providerSt
 
 	operatingSystem := image.GetOS()
 	data.Set(resourceKeyServerOSType, operatingSystem.DisplayName)
-	data.SetPartial(resourceKeyServerOSType)
 	data.Set(resourceKeyServerOSFamily, operatingSystem.Family)
-	data.SetPartial(resourceKeyServerOSFamily)
 
-	// Validate disk configuration.
 	configuredDisks := propertyHelper.GetDisks()
-	err = validateServerDisks(configuredDisks)
-	if err != nil {
-		return err
-	}
 
 	// Image disk speeds
 	configuredDisksBySCSIPath := configuredDisks.BySCSIPath()
@@ -864,7 +862,7 @@ func deployCustomizedServer(data *schema.ResourceData, providerState *providerSt
 
 	var serverID string
 	operationDescription := fmt.Sprintf("Deploy customised server '%s'", name)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -890,31 +888,26 @@ func deployCustomizedServer(data *schema.ResourceData, providerState *providerSt
 	server := resource.(*compute.Server)
 
 	// Capture additional properties (those only available after deployment) and modify auto-assinged IPs to the one specified in tf file.
-	err = captureCreatedServerProperties(data, providerState, server, networkAdapters)
+	err = captureCreatedServerProperties(ctx, data, providerState, server, networkAdapters)
 	if err != nil {
 		return err
 	}
 
-	data.Partial(true)
-
 	err = applyTags(data, apiClient, compute.AssetTypeServer, providerState.Settings())
 	if err != nil {
 		return err
 	}
-	data.SetPartial(resourceKeyTag)
 
-	err = createDisks(server, data, providerState)
+	err = createDisks(ctx, server, data, providerState)
 	if err != nil {
 		return err
 	}
 
-	data.Partial(false)
-
 	return nil
 }
 
 // Deploy a server without guest OS customisation.
-func deployUncustomizedServer(data *schema.ResourceData, providerState *providerState, networkDomain *compute.NetworkDomain, image compute.Image) error {
+func deployUncustomizedServer(ctx context.Context, data *schema.ResourceData, providerState *providerState, networkDomain *compute.NetworkDomain, image compute.Image) error {
 	name := data.Get(resourceKeyServerName).(string)
 	description := data.Get(resourceKeyServerDescription).(string)
 	powerState := data.Get(resourceKeyServerPowerState).(string)
@@ -943,17 +936,10 @@ func deployUncustomizedServer(data *schema.ResourceData, providerState *provider
 
 	operatingSystem := image.GetOS()
 	data.Set(resourceKeyServerOSType, operatingSystem.DisplayName)
-	data.SetPartial(resourceKeyServerOSType)
 	data.Set(resourceKeyServerOSFamily, operatingSystem.Family)
-	data.SetPartial(resourceKeyServerOSFamily)
 
-	// Validate disk configuration.
 	propertyHelper := propertyHelper(data)
 	configuredDisks := propertyHelper.GetDisks()
-	err := validateServerDisks(configuredDisks)
-	if err != nil {
-		return err
-	}
 
 	// Image disk speeds (for uncustomised servers, only a single SCSI controller is supported for initial deployment).
 	configuredDisksBySCSIPath := configuredDisks.BySCSIPath()
@@ -1009,7 +995,7 @@ func deployUncustomizedServer(data *schema.ResourceData, providerState *provider
 
 	var serverID string
 	operationDescription := fmt.Sprintf("Deploy uncustomised server '%s'", name)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -1035,31 +1021,26 @@ func deployUncustomizedServer(data *schema.ResourceData, providerState *provider
 	server := resource.(*compute.Server)
 
 	// Capture additional properties that may only be available after deployment.
-	err = captureCreatedServerProperties(data, providerState, server, networkAdapters)
+	err = captureCreatedServerProperties(ctx, data, providerState, server, networkAdapters)
 	if err != nil {
 		return err
 	}
 
-	data.Partial(true)
-
 	err = applyTags(data, apiClient, compute.AssetTypeServer, providerState.Settings())
 	if err != nil {
 		return err
 	}
-	data.SetPartial(resourceKeyTag)
 
-	err = createDisks(server, data, providerState)
+	err = createDisks(ctx, server, data, providerState)
 	if err != nil {
 		return err
 	}
 
-	data.Partial(false)
-
 	return nil
 }
 
 // Capture additional properties that may only be available after deployment.
-func captureCreatedServerProperties(data *schema.ResourceData, providerState *providerState, server *compute.Server, networkAdapters models.NetworkAdapters) error {
+func captureCreatedServerProperties(ctx context.Context, data *schema.ResourceData, providerState *providerState, server *compute.Server, networkAdapters models.NetworkAdapters) error {
 	networkDomainID := data.Get(resourceKeyServerNetworkDomainID).(string)
 
 	apiClient := providerState.Client()
@@ -1067,8 +1048,8 @@ func captureCreatedServerProperties(data *schema.ResourceData, providerState *pr
 	propertyHelper := propertyHelper(data)
 
 	networkAdapters.CaptureIDs(server.Network)
-	propertyHelper.SetServerNetworkAdapters(networkAdapters, true)
-	captureServerNetworkConfiguration(server, data, true)
+	propertyHelper.SetServerNetworkAdapters(networkAdapters)
+	captureServerNetworkConfiguration(server, data)
 
 	// Public IPv4
 	publicIPv4Address, err := findPublicIPv4Address(apiClient,
@@ -1083,17 +1064,15 @@ func captureCreatedServerProperties(data *schema.ResourceData, providerState *pr
 	} else {
 		data.Set(resourceKeyServerPublicIPv4, nil)
 	}
-	data.SetPartial(resourceKeyServerPublicIPv4)
 
 	// Started
 	data.Set(resourceKeyServerStarted, server.Started)
-	data.SetPartial(resourceKeyServerStarted)
 
 	// Update network adapter IPs from Auto-Assigned to user-defined
 	for _, nic := range networkAdapters {
 		log.Printf("[DD] resource_server > captureCreatedServerProperties() nic id:%s ipv4:%s ipv6:%s",
 			nic.ID, nic.PrivateIPv4Address, nic.PrivateIPv6Address)
-		err = modifyServerNetworkAdapterIP(providerState, server.ID, nic)
+		err = modifyServerNetworkAdapterIP(ctx, providerState, server.ID, nic)
 		if err != nil {
 			return err
 		}
@@ -1200,7 +1179,7 @@ func validateAdminPassword(adminPassword string, image compute.Image) error {
 // Start a server.
 //
 // Respects providerSettings.AllowServerReboots.
-func serverStart(providerState *providerState, serverID string) error {
+func serverStart(ctx context.Context, providerState *providerState, serverID string) error {
 	providerSettings := providerState.Settings()
 	apiClient := providerState.Client()
 
@@ -1209,7 +1188,7 @@ func serverStart(providerState *providerState, serverID string) error {
 	}
 
 	operationDescription := fmt.Sprintf("Start server '%s'", serverID)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -1237,7 +1216,7 @@ func serverStart(providerState *providerState, serverID string) error {
 // Gracefully stop a server.
 //
 // Respects providerSettings.AllowServerReboots.
-func serverShutdown(providerState *providerState, serverID string) error {
+func serverShutdown(ctx context.Context, providerState *providerState, serverID string) error {
 	providerSettings := providerState.Settings()
 	apiClient := providerState.Client()
 
@@ -1246,7 +1225,7 @@ func serverShutdown(providerState *providerState, serverID string) error {
 	}
 
 	operationDescription := fmt.Sprintf("Shut down server '%s'", serverID)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -1271,14 +1250,97 @@ func serverShutdown(providerState *providerState, serverID string) error {
 	return nil
 }
 
+// Perform an action that requires a server to be powered off.
+//
+// If the server is running, it will be shut down before the action is performed, and started again afterwards (even if the action fails).
+// Respects providerSettings.AllowServerReboots.
+func withServerPoweredOff(ctx context.Context, providerState *providerState, serverID string, action func() error) error {
+	apiClient := providerState.Client()
+
+	server, err := apiClient.GetServer(serverID)
+	if err != nil {
+		return err
+	}
+	if server == nil {
+		return fmt.Errorf("cannot find server with Id '%s'", serverID)
+	}
+
+	serverWasStarted := server.Started
+	if serverWasStarted {
+		log.Printf("Shutting down server '%s' ('%s') before performing an operation that requires it to be powered off...",
+			server.Name,
+			server.ID,
+		)
+
+		err = serverShutdown(ctx, providerState, serverID)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Shutdown complete for server '%s' ('%s').",
+			server.Name,
+			server.ID,
+		)
+	}
+
+	actionError := action()
+
+	if serverWasStarted {
+		log.Printf("Restarting server '%s' ('%s')...",
+			server.Name,
+			server.ID,
+		)
+
+		err = serverStart(ctx, providerState, serverID)
+		if err != nil {
+			if actionError != nil {
+				return fmt.Errorf("%s (additionally, failed to restart server '%s': %s)", actionError, serverID, err)
+			}
+
+			return err
+		}
+
+		log.Printf("Restart complete for server '%s' ('%s').",
+			server.Name,
+			server.ID,
+		)
+	}
+
+	return actionError
+}
+
+// Verify (at plan time) that a change requiring a server to be powered off can be applied.
+//
+// If server reboots have not been enabled (via providerSettings.AllowServerReboots), the server must already be stopped.
+func validateServerPowerCycle(providerState *providerState, serverID string, change string) error {
+	if providerState.Settings().AllowServerReboots {
+		log.Printf("%s requires server '%s' to be powered off; it will be shut down (if running) and then restarted.", change, serverID)
+
+		return nil
+	}
+
+	server, err := providerState.Client().GetServer(serverID)
+	if err != nil {
+		return err
+	}
+	if server == nil || !server.Started {
+		return nil
+	}
+
+	return fmt.Errorf("%s requires server '%s' to be powered off, but server reboots have not been enabled via the 'allow_server_reboot' provider setting or 'MCP_ALLOW_SERVER_REBOOT' environment variable (enable server reboots, or shut down the server first)",
+		change,
+		serverID,
+	)
+}
+
 // Forcefully stop a server.
 //
 // Does not respect providerSettings.AllowServerReboots.
-func serverPowerOff(providerState *providerState, serverID string) error {
+func serverPowerOff(ctx context.Context, providerState *providerState, serverID string) error {
 	apiClient := providerState.Client()
 
 	operationDescription := fmt.Sprintf("Power off server '%s'", serverID)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -1,13 +1,15 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -22,9 +24,9 @@ const (
 
 func resourceAntiAffinityRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAntiAffinityRuleCreate,
-		Read:   resourceAntiAffinityRuleRead,
-		Delete: resourceAntiAffinityRuleDelete,
+		CreateContext: resourceAntiAffinityRuleCreate,
+		ReadContext:   resourceAntiAffinityRuleRead,
+		DeleteContext: resourceAntiAffinityRuleDelete,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyAntiAffinityRuleServer1ID: &schema.Schema{
@@ -59,7 +61,7 @@ func resourceAntiAffinityRule() *schema.Resource {
 }
 
 // Create a server anti-affinity rule resource.
-func resourceAntiAffinityRuleCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceAntiAffinityRuleCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	server1ID := data.Get(resourceKeyAntiAffinityRuleServer1ID).(string)
 	server2ID := data.Get(resourceKeyAntiAffinityRuleServer2ID).(string)
 
@@ -71,61 +73,34 @@ func resourceAntiAffinityRuleCreate(data *schema.ResourceData, provider interfac
 	// Capture server details
 	server1, err := apiClient.GetServer(server1ID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server1 == nil {
-		return fmt.Errorf("cannot create anti-affinity rule (server 1 not found with Id '%s')", server1ID)
+		return diag.Errorf("cannot create anti-affinity rule (server 1 not found with Id '%s')", server1ID)
 	}
 
 	server2, err := apiClient.GetServer(server2ID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server2 == nil {
-		return fmt.Errorf("cannot create anti-affinity rule (server 2 not found with Id '%s')", server2ID)
+		return diag.Errorf("cannot create anti-affinity rule (server 2 not found with Id '%s')", server2ID)
 	}
 
 	// We don't support anti-affinity rules between servers in different network domains.
 	if server1.Network.NetworkDomainID != server2.Network.NetworkDomainID {
-		return fmt.Errorf("cannot create server anti-affinity rule (server '%s' is in network domain '%s', but server '%s' is in network domain '%s'", server1ID, server1.Network.NetworkDomainID, server2ID, server2.Network.NetworkDomainID)
+		return diag.Errorf("cannot create server anti-affinity rule (server '%s' is in network domain '%s', but server '%s' is in network domain '%s'", server1ID, server1.Network.NetworkDomainID, server2ID, server2.Network.NetworkDomainID)
 	}
 
 	networkDomainID := server1.Network.NetworkDomainID
 
-	var (
-		ruleID      string
-		createError error
-	)
-	operationDescription := fmt.Sprintf("Create anti-affinity rule between servers '%s' and '%s'", server1ID, server2ID)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
-		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
-		asyncLock := providerState.AcquireAsyncOperationLock("Create server anti-affinity rule '%s'", networkDomainID)
-		defer asyncLock.Release()
-
-		ruleID, createError = apiClient.CreateServerAntiAffinityRule(server1ID, server2ID)
-		if compute.IsResourceBusyError(createError) {
-			context.Retry()
-		} else if createError != nil {
-			context.Fail(createError)
-		}
-
-		asyncLock.Release()
-	})
-	if err != nil {
-		return err
+	ruleID, antiAffinityRule, err := createServerAntiAffinityRule(ctx, providerState, server1ID, server2ID, networkDomainID)
+	if ruleID != "" {
+		data.SetId(ruleID)
+		data.Set(resourceKeyAntiAffinityRuleNetworkDomainID, networkDomainID)
 	}
-
-	data.SetId(ruleID)
-
-	qualifiedRuleID := networkDomainID + "/" + ruleID
-	resource, err := apiClient.WaitForChange(compute.ResourceTypeServerAntiAffinityRule, qualifiedRuleID, "Create", resourceCreateTimeoutAntiAffinityRule)
 	if err != nil {
-		return err
-	}
-
-	antiAffinityRule := resource.(*compute.ServerAntiAffinityRule)
-	if antiAffinityRule == nil {
-		return fmt.Errorf("cannot find newly-created server anti-affinity rule '%s' in network domain '%s'", ruleID, networkDomainID)
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Created server anti-affinity rule '%s'.", ruleID)
@@ -138,23 +113,22 @@ func resourceAntiAffinityRuleCreate(data *schema.ResourceData, provider interfac
 
 	targetServer1, ok := serversByID[server1ID]
 	if !ok {
-		return fmt.Errorf("anti-affinity rule '%s' targets unexpected server ('%s')", ruleID, server1ID)
+		return diag.Errorf("anti-affinity rule '%s' targets unexpected server ('%s')", ruleID, server1ID)
 	}
 
 	targetServer2, ok := serversByID[server2ID]
 	if !ok {
-		return fmt.Errorf("anti-affinity rule '%s' targets unexpected server ('%s')", ruleID, server2ID)
+		return diag.Errorf("anti-affinity rule '%s' targets unexpected server ('%s')", ruleID, server2ID)
 	}
 
 	data.Set(resourceKeyAntiAffinityRuleServer1Name, targetServer1.Name)
 	data.Set(resourceKeyAntiAffinityRuleServer2Name, targetServer2.Name)
-	data.Set(resourceKeyAntiAffinityRuleNetworkDomainID, server1.Network.NetworkDomainID)
 
 	return nil
 }
 
 // Read a server anti-affinity rule resource.
-func resourceAntiAffinityRuleRead(data *schema.ResourceData, provider interface{}) error {
+func resourceAntiAffinityRuleRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	ruleID := data.Id()
 	server1Name := data.Get(resourceKeyAntiAffinityRuleServer1Name).(string)
 	server2Name := data.Get(resourceKeyAntiAffinityRuleServer2Name).(string)
@@ -166,12 +140,12 @@ func resourceAntiAffinityRuleRead(data *schema.ResourceData, provider interface{
 
 	antiAffinityRule, err := apiClient.GetServerAntiAffinityRule(ruleID, networkDomainID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if antiAffinityRule != nil {
 		if len(antiAffinityRule.Servers) != 2 {
-			return fmt.Errorf("anti-affinity rule relates to unexpected number of servers (%d)",
+			return diag.Errorf("anti-affinity rule relates to unexpected number of servers (%d)",
 				len(antiAffinityRule.Servers),
 			)
 		}
@@ -185,13 +159,13 @@ func resourceAntiAffinityRuleRead(data *schema.ResourceData, provider interface{
 		server1ID := data.Get(resourceKeyAntiAffinityRuleServer1ID).(string)
 		server1, ok := serversByID[server1ID]
 		if !ok {
-			return fmt.Errorf("Anti-affinity rule '%s' relates to unexpected server ('%s')", ruleID, server1ID)
+			return diag.Errorf("Anti-affinity rule '%s' relates to unexpected server ('%s')", ruleID, server1ID)
 		}
 
-		server2ID := data.Get(resourceKeyAntiAffinityRuleServer1ID).(string)
+		server2ID := data.Get(resourceKeyAntiAffinityRuleServer2ID).(string)
 		server2, ok := serversByID[server2ID]
 		if !ok {
-			return fmt.Errorf("Anti-affinity rule '%s' relates to unexpected server ('%s')", ruleID, server2ID)
+			return diag.Errorf("Anti-affinity rule '%s' relates to unexpected server ('%s')", ruleID, server2ID)
 		}
 
 		data.Set(resourceKeyAntiAffinityRuleServer1Name, server1.Name)
@@ -204,17 +178,68 @@ func resourceAntiAffinityRuleRead(data *schema.ResourceData, provider interface{
 }
 
 // Delete a server anti-affinity rule resource.
-func resourceAntiAffinityRuleDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceAntiAffinityRuleDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	ruleID := data.Id()
 	networkDomainID := data.Get(resourceKeyAntiAffinityRuleNetworkDomainID).(string)
 
 	log.Printf("Delete server anti-affinity rule '%s' in network domain '%s'.", ruleID, networkDomainID)
 
-	providerState := provider.(*providerState)
+	err := deleteServerAntiAffinityRule(ctx, provider.(*providerState), ruleID, networkDomainID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Deleted server anti-affinity rule '%s' in network domain '%s'.", ruleID, networkDomainID)
+
+	return nil
+}
+
+// Create an anti-affinity rule for the specified servers, and wait for it to be created.
+//
+// If the rule was created, its Id is returned (even if an error occurred while waiting for it to be created).
+func createServerAntiAffinityRule(ctx context.Context, providerState *providerState, server1ID string, server2ID string, networkDomainID string) (ruleID string, antiAffinityRule *compute.ServerAntiAffinityRule, err error) {
+	apiClient := providerState.Client()
+
+	operationDescription := fmt.Sprintf("Create anti-affinity rule between servers '%s' and '%s'", server1ID, server2ID)
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
+		asyncLock := providerState.AcquireAsyncOperationLock("Create server anti-affinity rule '%s'", networkDomainID)
+		defer asyncLock.Release()
+
+		var createError error
+		ruleID, createError = apiClient.CreateServerAntiAffinityRule(server1ID, server2ID)
+		if compute.IsResourceBusyError(createError) {
+			context.Retry()
+		} else if createError != nil {
+			context.Fail(createError)
+		}
+
+		asyncLock.Release()
+	})
+	if err != nil {
+		return
+	}
+
+	qualifiedRuleID := networkDomainID + "/" + ruleID
+	resource, err := apiClient.WaitForChange(compute.ResourceTypeServerAntiAffinityRule, qualifiedRuleID, "Create", resourceCreateTimeoutAntiAffinityRule)
+	if err != nil {
+		return
+	}
+
+	antiAffinityRule = resource.(*compute.ServerAntiAffinityRule)
+	if antiAffinityRule == nil {
+		err = fmt.Errorf("cannot find newly-created server anti-affinity rule '%s' in network domain '%s'", ruleID, networkDomainID)
+	}
+
+	return
+}
+
+// Delete an anti-affinity rule, and wait for it to be deleted.
+func deleteServerAntiAffinityRule(ctx context.Context, providerState *providerState, ruleID string, networkDomainID string) error {
 	apiClient := providerState.Client()
 
 	operationDescription := fmt.Sprintf("Delete anti-affinity rule '%s'", ruleID)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock("Delete server anti-affinity rule '%s'", networkDomainID)
 		defer asyncLock.Release()
@@ -235,12 +260,6 @@ func resourceAntiAffinityRuleDelete(data *schema.ResourceData, provider interfac
 	log.Printf("Deleting server anti-affinity rule '%s' in network domain '%s'...", ruleID, networkDomainID)
 
 	qualifiedRuleID := networkDomainID + "/" + ruleID
-	err = apiClient.WaitForDelete(compute.ResourceTypeServerAntiAffinityRule, qualifiedRuleID, resourceDeleteTimeoutAntiAffinityRule)
-	if err != nil {
-		return err
-	}
-
-	log.Printf("Deleted server anti-affinity rule '%s' in network domain '%s'.", ruleID, networkDomainID)
 
-	return nil
+	return apiClient.WaitForDelete(compute.ResourceTypeServerAntiAffinityRule, qualifiedRuleID, resourceDeleteTimeoutAntiAffinityRule)
 }
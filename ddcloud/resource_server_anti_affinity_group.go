@@ -0,0 +1,533 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	resourceKeyAntiAffinityGroupServers         = "servers"
+	resourceKeyAntiAffinityGroupNetworkDomainID = "networkdomain"
+	resourceKeyAntiAffinityGroupRule            = "rule"
+	resourceKeyAntiAffinityGroupRuleID          = "id"
+	resourceKeyAntiAffinityGroupRuleServer1ID   = "server1"
+	resourceKeyAntiAffinityGroupRuleServer2ID   = "server2"
+)
+
+func resourceServerAntiAffinityGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerAntiAffinityGroupCreate,
+		ReadContext:   resourceServerAntiAffinityGroupRead,
+		UpdateContext: resourceServerAntiAffinityGroupUpdate,
+		DeleteContext: resourceServerAntiAffinityGroupDelete,
+		CustomizeDiff: resourceServerAntiAffinityGroupCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			resourceKeyAntiAffinityGroupServers: &schema.Schema{
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    2,
+				Description: "The Ids of the servers that must not run on the same physical hardware.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set: schema.HashString,
+			},
+			resourceKeyAntiAffinityGroupNetworkDomainID: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Id of the network domain in which the anti-affinity rules apply.",
+			},
+			resourceKeyAntiAffinityGroupRule: &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The pairwise anti-affinity rules managed by the group.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						resourceKeyAntiAffinityGroupRuleID: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The Id of the anti-affinity rule.",
+						},
+						resourceKeyAntiAffinityGroupRuleServer1ID: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The Id of the first server that the anti-affinity rule relates to.",
+						},
+						resourceKeyAntiAffinityGroupRuleServer2ID: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The Id of the second server that the anti-affinity rule relates to.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Mark the group's rules as changing when its servers change.
+func resourceServerAntiAffinityGroupCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, provider interface{}) error {
+	if diff.Id() != "" && diff.HasChange(resourceKeyAntiAffinityGroupServers) {
+		// The group's rules (and, potentially, its network domain) will change.
+		diff.SetNewComputed(resourceKeyAntiAffinityGroupRule)
+		diff.SetNewComputed(resourceKeyAntiAffinityGroupNetworkDomainID)
+	}
+
+	return nil
+}
+
+// Create a server anti-affinity group resource.
+func resourceServerAntiAffinityGroupCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	serverIDs := propertyHelper(data).GetStringSetItems(resourceKeyAntiAffinityGroupServers)
+
+	log.Printf("Create server anti-affinity group for %d servers (%s).", len(serverIDs), strings.Join(serverIDs, ", "))
+
+	// The group itself does not exist in CloudControl (only its rules do).
+	data.SetId(resource.UniqueId())
+
+	err := reconcileServerAntiAffinityGroup(ctx, data, provider.(*providerState))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// Read a server anti-affinity group resource.
+func resourceServerAntiAffinityGroupRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	groupID := data.Id()
+	networkDomainID := data.Get(resourceKeyAntiAffinityGroupNetworkDomainID).(string)
+
+	log.Printf("Read server anti-affinity group '%s' in network domain '%s'.", groupID, networkDomainID)
+
+	if networkDomainID == "" {
+		return nil // Group has no rules yet.
+	}
+
+	apiClient := provider.(*providerState).Client()
+	existingRules, err := listServerAntiAffinityRules(apiClient, networkDomainID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	managedRules := filterExistingAntiAffinityRules(getAntiAffinityGroupRules(data), existingRules)
+	err = setAntiAffinityGroupRules(data, managedRules)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Any server that is no longer kept apart from every other server in the group is removed from state (so the next plan will restore its rules).
+	serverIDs := propertyHelper(data).GetStringSetItems(resourceKeyAntiAffinityGroupServers)
+	coveredServerIDs := coveredAntiAffinityGroupServers(serverIDs, existingRules)
+	if len(coveredServerIDs) != len(serverIDs) {
+		log.Printf("Server anti-affinity group '%s' is missing one or more rules (only %d of %d servers are fully covered).",
+			groupID, len(coveredServerIDs), len(serverIDs),
+		)
+
+		err = propertyHelper(data).SetStringSetItems(resourceKeyAntiAffinityGroupServers, coveredServerIDs)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+// Update a server anti-affinity group resource.
+func resourceServerAntiAffinityGroupUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	groupID := data.Id()
+
+	log.Printf("Update server anti-affinity group '%s'.", groupID)
+
+	err := reconcileServerAntiAffinityGroup(ctx, data, provider.(*providerState))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// Delete a server anti-affinity group resource.
+func resourceServerAntiAffinityGroupDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	groupID := data.Id()
+	networkDomainID := data.Get(resourceKeyAntiAffinityGroupNetworkDomainID).(string)
+
+	log.Printf("Delete server anti-affinity group '%s' in network domain '%s'.", groupID, networkDomainID)
+
+	providerState := provider.(*providerState)
+
+	managedRules := getAntiAffinityGroupRules(data)
+	for index, managedRule := range managedRules {
+		err := deleteServerAntiAffinityRule(ctx, providerState, managedRule.ID, networkDomainID)
+		if err != nil {
+			// Remember which rules have not been deleted yet.
+			setAntiAffinityGroupRules(data, managedRules[index:])
+
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Printf("Deleted server anti-affinity group '%s' (%d rules).", groupID, len(managedRules))
+
+	return nil
+}
+
+// Create and delete anti-affinity rules so that every server in the group is kept apart from every other server in the group.
+//
+// Only rules that need to change are created or deleted.
+func reconcileServerAntiAffinityGroup(ctx context.Context, data *schema.ResourceData, providerState *providerState) error {
+	groupID := data.Id()
+	serverIDs := propertyHelper(data).GetStringSetItems(resourceKeyAntiAffinityGroupServers)
+	sort.Strings(serverIDs)
+
+	apiClient := providerState.Client()
+
+	// All servers must be in the same network domain.
+	networkDomainID := ""
+	for _, serverID := range serverIDs {
+		server, err := apiClient.GetServer(serverID)
+		if err != nil {
+			return err
+		}
+		if server == nil {
+			return fmt.Errorf("cannot find server '%s' for anti-affinity group '%s'", serverID, groupID)
+		}
+
+		if networkDomainID == "" {
+			networkDomainID = server.Network.NetworkDomainID
+		} else if server.Network.NetworkDomainID != networkDomainID {
+			return fmt.Errorf("cannot create server anti-affinity rules for group '%s' (server '%s' is in network domain '%s', but server '%s' is in network domain '%s')",
+				groupID, serverIDs[0], networkDomainID, serverID, server.Network.NetworkDomainID,
+			)
+		}
+	}
+
+	managedRules := getAntiAffinityGroupRules(data)
+
+	// If the group has moved to a different network domain, its existing rules are simply removed.
+	previousValue, _ := data.GetChange(resourceKeyAntiAffinityGroupNetworkDomainID)
+	previousNetworkDomainID, _ := previousValue.(string)
+	if previousNetworkDomainID != "" && previousNetworkDomainID != networkDomainID {
+		for index, managedRule := range managedRules {
+			err := deleteServerAntiAffinityRule(ctx, providerState, managedRule.ID, previousNetworkDomainID)
+			if err != nil {
+				setAntiAffinityGroupRules(data, managedRules[index:])
+
+				return err
+			}
+		}
+		managedRules = nil
+	}
+	data.Set(resourceKeyAntiAffinityGroupNetworkDomainID, networkDomainID)
+
+	existingRules, err := listServerAntiAffinityRules(apiClient, networkDomainID)
+	if err != nil {
+		return err
+	}
+	managedRules = filterExistingAntiAffinityRules(managedRules, existingRules)
+
+	plan := planServerAntiAffinityGroupRules(serverIDs, managedRules, existingRules)
+	log.Printf("Server anti-affinity group '%s' requires %d new rule(s), %d rule(s) to be removed, and %d rule(s) to be retained.",
+		groupID, len(plan.Create), len(plan.Delete), len(plan.Keep),
+	)
+
+	ruleCounts := plan.CountRulesByServer(existingRules)
+
+	// Rules are removed first, since that may free up capacity for new rules.
+	remainingRules := append(antiAffinityGroupRules{}, plan.Keep...)
+	for index, deleteRule := range plan.Delete {
+		err = deleteServerAntiAffinityRule(ctx, providerState, deleteRule.ID, networkDomainID)
+		if err != nil {
+			setAntiAffinityGroupRules(data, append(remainingRules, plan.Delete[index:]...))
+
+			return err
+		}
+	}
+	setAntiAffinityGroupRules(data, remainingRules)
+
+	for _, createPair := range plan.Create {
+		var ruleID string
+		ruleID, _, err = createServerAntiAffinityRule(ctx, providerState, createPair.Server1ID, createPair.Server2ID, networkDomainID)
+		if ruleID == "" && err != nil {
+			// CloudControl limits the number of anti-affinity rules that a server can belong to (and the limit is not exposed via the API).
+			return fmt.Errorf("failed to create anti-affinity rule between servers '%s' and '%s' for group '%s' (a group of %d servers requires each server to belong to %d rules; once the group's rules have been created, these servers would belong to %d and %d rules respectively, which may exceed CloudControl's per-server limit): %s",
+				createPair.Server1ID, createPair.Server2ID, groupID,
+				len(serverIDs), len(serverIDs)-1,
+				ruleCounts[createPair.Server1ID], ruleCounts[createPair.Server2ID],
+				err,
+			)
+		}
+		if ruleID != "" {
+			remainingRules = append(remainingRules, antiAffinityGroupRule{
+				ID:   ruleID,
+				Pair: createPair,
+			})
+			setAntiAffinityGroupRules(data, remainingRules)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Server anti-affinity group '%s' now manages %d rule(s).", groupID, len(remainingRules))
+
+	return nil
+}
+
+// serverPair represents 2 servers that are kept apart by a single anti-affinity rule.
+//
+// Server Ids are always ordered, so that each pair of servers has exactly one representation.
+type serverPair struct {
+	Server1ID string
+	Server2ID string
+}
+
+func newServerPair(serverID1 string, serverID2 string) serverPair {
+	if serverID2 < serverID1 {
+		serverID1, serverID2 = serverID2, serverID1
+	}
+
+	return serverPair{
+		Server1ID: serverID1,
+		Server2ID: serverID2,
+	}
+}
+
+// Contains determines whether the pair includes the specified server.
+func (pair serverPair) Contains(serverID string) bool {
+	return pair.Server1ID == serverID || pair.Server2ID == serverID
+}
+
+// Expand a group of servers to the minimal set of pairwise anti-affinity rules (exactly one rule for each pair of servers).
+func expandServerPairs(serverIDs []string) (pairs []serverPair) {
+	uniqueServerIDs := make([]string, 0, len(serverIDs))
+	seenServerIDs := make(map[string]bool)
+	for _, serverID := range serverIDs {
+		if !seenServerIDs[serverID] {
+			seenServerIDs[serverID] = true
+			uniqueServerIDs = append(uniqueServerIDs, serverID)
+		}
+	}
+	sort.Strings(uniqueServerIDs)
+
+	for index1 := range uniqueServerIDs {
+		for index2 := index1 + 1; index2 < len(uniqueServerIDs); index2++ {
+			pairs = append(pairs, newServerPair(uniqueServerIDs[index1], uniqueServerIDs[index2]))
+		}
+	}
+
+	return
+}
+
+// antiAffinityGroupRule represents an anti-affinity rule (either one managed by an anti-affinity group, or one that already exists in CloudControl).
+type antiAffinityGroupRule struct {
+	ID   string
+	Pair serverPair
+}
+
+type antiAffinityGroupRules []antiAffinityGroupRule
+
+// Convert CloudControl anti-affinity rules to antiAffinityGroupRules.
+func newAntiAffinityGroupRules(rules []compute.ServerAntiAffinityRule) (groupRules antiAffinityGroupRules) {
+	for _, rule := range rules {
+		if len(rule.Servers) != 2 {
+			continue // Should never happen.
+		}
+
+		groupRules = append(groupRules, antiAffinityGroupRule{
+			ID:   rule.ID,
+			Pair: newServerPair(rule.Servers[0].ID, rule.Servers[1].ID),
+		})
+	}
+
+	return
+}
+
+// CountByServer counts the number of rules that each server belongs to.
+func (rules antiAffinityGroupRules) CountByServer() map[string]int {
+	counts := make(map[string]int)
+	for _, rule := range rules {
+		counts[rule.Pair.Server1ID]++
+		counts[rule.Pair.Server2ID]++
+	}
+
+	return counts
+}
+
+// antiAffinityGroupPlan represents the changes required to an anti-affinity group's rules.
+type antiAffinityGroupPlan struct {
+	// Pairs of servers that need a new rule.
+	Create []serverPair
+
+	// Rules (managed by the group) that are no longer required.
+	Delete antiAffinityGroupRules
+
+	// Rules (managed by the group) that are still required.
+	Keep antiAffinityGroupRules
+}
+
+// Determine which rules must be created or deleted so that every server in the group is kept apart from every other server in the group.
+//
+// Pairs of servers already covered by a rule that is not managed by the group (e.g. a ddcloud_server_anti_affinity) are not duplicated.
+func planServerAntiAffinityGroupRules(serverIDs []string, managedRules antiAffinityGroupRules, existingRules antiAffinityGroupRules) (plan antiAffinityGroupPlan) {
+	requiredPairs := make(map[serverPair]bool)
+	for _, pair := range expandServerPairs(serverIDs) {
+		requiredPairs[pair] = true
+	}
+
+	managedRuleIDs := make(map[string]bool)
+	coveredPairs := make(map[serverPair]bool)
+	for _, managedRule := range managedRules {
+		managedRuleIDs[managedRule.ID] = true
+
+		// Only one rule is required for each pair.
+		if requiredPairs[managedRule.Pair] && !coveredPairs[managedRule.Pair] {
+			coveredPairs[managedRule.Pair] = true
+			plan.Keep = append(plan.Keep, managedRule)
+		} else {
+			plan.Delete = append(plan.Delete, managedRule)
+		}
+	}
+	for _, existingRule := range existingRules {
+		if !managedRuleIDs[existingRule.ID] {
+			coveredPairs[existingRule.Pair] = true
+		}
+	}
+
+	for _, pair := range expandServerPairs(serverIDs) {
+		if !coveredPairs[pair] {
+			plan.Create = append(plan.Create, pair)
+		}
+	}
+
+	return
+}
+
+// CountRulesByServer counts the number of anti-affinity rules that each server will belong to once the plan has been applied.
+func (plan antiAffinityGroupPlan) CountRulesByServer(existingRules antiAffinityGroupRules) map[string]int {
+	ruleCounts := existingRules.CountByServer()
+	for _, deleteRule := range plan.Delete {
+		ruleCounts[deleteRule.Pair.Server1ID]--
+		ruleCounts[deleteRule.Pair.Server2ID]--
+	}
+	for _, createPair := range plan.Create {
+		ruleCounts[createPair.Server1ID]++
+		ruleCounts[createPair.Server2ID]++
+	}
+
+	return ruleCounts
+}
+
+// Filter out any rules that no longer exist in CloudControl.
+func filterExistingAntiAffinityRules(rules antiAffinityGroupRules, existingRules antiAffinityGroupRules) (filteredRules antiAffinityGroupRules) {
+	existingRuleIDs := make(map[string]bool)
+	for _, existingRule := range existingRules {
+		existingRuleIDs[existingRule.ID] = true
+	}
+
+	for _, rule := range rules {
+		if existingRuleIDs[rule.ID] {
+			filteredRules = append(filteredRules, rule)
+		} else {
+			log.Printf("Anti-affinity rule '%s' (servers '%s' and '%s') no longer exists.", rule.ID, rule.Pair.Server1ID, rule.Pair.Server2ID)
+		}
+	}
+
+	return
+}
+
+// Determine which servers are kept apart from every other server in the group.
+func coveredAntiAffinityGroupServers(serverIDs []string, existingRules antiAffinityGroupRules) (coveredServerIDs []string) {
+	coveredPairs := make(map[serverPair]bool)
+	for _, existingRule := range existingRules {
+		coveredPairs[existingRule.Pair] = true
+	}
+
+	uncoveredServerIDs := make(map[string]bool)
+	for _, pair := range expandServerPairs(serverIDs) {
+		if !coveredPairs[pair] {
+			uncoveredServerIDs[pair.Server1ID] = true
+			uncoveredServerIDs[pair.Server2ID] = true
+		}
+	}
+
+	for _, serverID := range serverIDs {
+		if !uncoveredServerIDs[serverID] {
+			coveredServerIDs = append(coveredServerIDs, serverID)
+		}
+	}
+
+	return
+}
+
+// List all anti-affinity rules in the specified network domain.
+func listServerAntiAffinityRules(apiClient *compute.Client, networkDomainID string) (rules antiAffinityGroupRules, err error) {
+	page := compute.DefaultPaging()
+	for {
+		var antiAffinityRules *compute.ServerAntiAffinityRules
+		antiAffinityRules, err = apiClient.ListServerAntiAffinityRules(networkDomainID, page)
+		if err != nil {
+			return
+		}
+		if antiAffinityRules.IsEmpty() {
+			break // We're done
+		}
+
+		rules = append(rules, newAntiAffinityGroupRules(antiAffinityRules.Items)...)
+
+		page.Next()
+	}
+
+	return
+}
+
+// Get the rules managed by an anti-affinity group (as recorded in its current state).
+//
+// The prior value is used because, when the group's servers change, the planned value of its rules is unknown.
+func getAntiAffinityGroupRules(data *schema.ResourceData) (rules antiAffinityGroupRules) {
+	value, _ := data.GetChange(resourceKeyAntiAffinityGroupRule)
+	items, ok := value.([]interface{})
+	if !ok {
+		return
+	}
+
+	for _, item := range items {
+		ruleProperties, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		rules = append(rules, antiAffinityGroupRule{
+			ID: ruleProperties[resourceKeyAntiAffinityGroupRuleID].(string),
+			Pair: newServerPair(
+				ruleProperties[resourceKeyAntiAffinityGroupRuleServer1ID].(string),
+				ruleProperties[resourceKeyAntiAffinityGroupRuleServer2ID].(string),
+			),
+		})
+	}
+
+	return
+}
+
+// Set the rules managed by an anti-affinity group.
+func setAntiAffinityGroupRules(data *schema.ResourceData, rules antiAffinityGroupRules) error {
+	ruleProperties := make([]interface{}, len(rules))
+	for index, rule := range rules {
+		ruleProperties[index] = map[string]interface{}{
+			resourceKeyAntiAffinityGroupRuleID:        rule.ID,
+			resourceKeyAntiAffinityGroupRuleServer1ID: rule.Pair.Server1ID,
+			resourceKeyAntiAffinityGroupRuleServer2ID: rule.Pair.Server2ID,
+		}
+	}
+
+	return data.Set(resourceKeyAntiAffinityGroupRule, ruleProperties)
+}
@@ -0,0 +1,288 @@
+package ddcloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+/*
+ * Unit tests.
+ */
+
+// Unit test - a group of servers expands to exactly one rule for each pair of servers.
+func TestExpandServerPairs(test *testing.T) {
+	pairs := expandServerPairs([]string{"c", "a", "b", "a"})
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Pairs.Length", 3, len(pairs))
+	assert.IsTrue("Pairs[0] == a/b", pairs[0] == serverPair{Server1ID: "a", Server2ID: "b"})
+	assert.IsTrue("Pairs[1] == a/c", pairs[1] == serverPair{Server1ID: "a", Server2ID: "c"})
+	assert.IsTrue("Pairs[2] == b/c", pairs[2] == serverPair{Server1ID: "b", Server2ID: "c"})
+}
+
+// Unit test - server pairs are ordered.
+func TestNewServerPair(test *testing.T) {
+	assert.ForTest(test).IsTrue("b/a == a/b", newServerPair("b", "a") == newServerPair("a", "b"))
+}
+
+// Unit test - plan rules for a new group.
+func TestPlanServerAntiAffinityGroupRulesCreate(test *testing.T) {
+	plan := planServerAntiAffinityGroupRules([]string{"a", "b", "c"}, nil, nil)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Create.Length", 3, len(plan.Create))
+	assert.EqualsInt("Delete.Length", 0, len(plan.Delete))
+	assert.EqualsInt("Keep.Length", 0, len(plan.Keep))
+}
+
+// Unit test - adding and removing servers only changes the affected rules.
+func TestPlanServerAntiAffinityGroupRulesIncremental(test *testing.T) {
+	managedRules := antiAffinityGroupRules{
+		antiAffinityGroupRule{ID: "rule-ab", Pair: newServerPair("a", "b")},
+		antiAffinityGroupRule{ID: "rule-ac", Pair: newServerPair("a", "c")},
+		antiAffinityGroupRule{ID: "rule-bc", Pair: newServerPair("b", "c")},
+	}
+
+	// Remove "c", add "d".
+	plan := planServerAntiAffinityGroupRules([]string{"a", "b", "d"}, managedRules, managedRules)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Keep.Length", 1, len(plan.Keep))
+	assert.EqualsString("Keep[0].ID", "rule-ab", plan.Keep[0].ID)
+	assert.EqualsInt("Delete.Length", 2, len(plan.Delete))
+	assert.EqualsString("Delete[0].ID", "rule-ac", plan.Delete[0].ID)
+	assert.EqualsString("Delete[1].ID", "rule-bc", plan.Delete[1].ID)
+	assert.EqualsInt("Create.Length", 2, len(plan.Create))
+	assert.IsTrue("Create[0] == a/d", plan.Create[0] == newServerPair("a", "d"))
+	assert.IsTrue("Create[1] == b/d", plan.Create[1] == newServerPair("b", "d"))
+}
+
+// Unit test - pairs already covered by rules that are not managed by the group are not duplicated.
+func TestPlanServerAntiAffinityGroupRulesExternalRule(test *testing.T) {
+	existingRules := antiAffinityGroupRules{
+		antiAffinityGroupRule{ID: "external-ab", Pair: newServerPair("a", "b")},
+	}
+
+	plan := planServerAntiAffinityGroupRules([]string{"a", "b", "c"}, nil, existingRules)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Create.Length", 2, len(plan.Create))
+	assert.IsTrue("Create[0] == a/c", plan.Create[0] == newServerPair("a", "c"))
+	assert.IsTrue("Create[1] == b/c", plan.Create[1] == newServerPair("b", "c"))
+}
+
+// Unit test - duplicate managed rules for the same pair are removed.
+func TestPlanServerAntiAffinityGroupRulesDuplicate(test *testing.T) {
+	managedRules := antiAffinityGroupRules{
+		antiAffinityGroupRule{ID: "rule-1", Pair: newServerPair("a", "b")},
+		antiAffinityGroupRule{ID: "rule-2", Pair: newServerPair("b", "a")},
+	}
+
+	plan := planServerAntiAffinityGroupRules([]string{"a", "b"}, managedRules, managedRules)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Keep.Length", 1, len(plan.Keep))
+	assert.EqualsInt("Delete.Length", 1, len(plan.Delete))
+	assert.EqualsString("Delete[0].ID", "rule-2", plan.Delete[0].ID)
+	assert.EqualsInt("Create.Length", 0, len(plan.Create))
+}
+
+// Unit test - rules that already exist (outside the group) are included in per-server rule counts.
+func TestAntiAffinityGroupPlanCountRulesByServer(test *testing.T) {
+	var existingRules antiAffinityGroupRules
+	for index := 0; index < 4; index++ {
+		existingRules = append(existingRules, antiAffinityGroupRule{
+			ID:   fmt.Sprintf("external-%d", index),
+			Pair: newServerPair("a", fmt.Sprintf("other-%d", index)),
+		})
+	}
+	managedRules := antiAffinityGroupRules{
+		antiAffinityGroupRule{ID: "rule-ac", Pair: newServerPair("a", "c")},
+	}
+	existingRules = append(existingRules, managedRules...)
+
+	plan := planServerAntiAffinityGroupRules([]string{"a", "b"}, managedRules, existingRules)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Create.Length", 1, len(plan.Create))
+	assert.EqualsInt("Delete.Length", 1, len(plan.Delete))
+
+	ruleCounts := plan.CountRulesByServer(existingRules)
+	assert.EqualsInt("RuleCounts[a]", 5, ruleCounts["a"])
+	assert.EqualsInt("RuleCounts[b]", 1, ruleCounts["b"])
+	assert.EqualsInt("RuleCounts[c]", 0, ruleCounts["c"])
+}
+
+// Unit test - servers missing one or more rules are not considered covered.
+func TestCoveredAntiAffinityGroupServers(test *testing.T) {
+	existingRules := antiAffinityGroupRules{
+		antiAffinityGroupRule{ID: "rule-ab", Pair: newServerPair("a", "b")},
+		antiAffinityGroupRule{ID: "rule-ac", Pair: newServerPair("a", "c")},
+	}
+
+	coveredServerIDs := coveredAntiAffinityGroupServers([]string{"a", "b", "c"}, existingRules)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("CoveredServerIDs.Length", 1, len(coveredServerIDs))
+	assert.EqualsString("CoveredServerIDs[0]", "a", coveredServerIDs[0])
+}
+
+/*
+ * Acceptance-test configurations.
+ */
+
+func testAccDDCloudServerAntiAffinityGroupBasic(serverCount int, groupSize int) string {
+	return fmt.Sprintf(`
+		provider "ddcloud" {
+			region		= "AU"
+		}
+
+		resource "ddcloud_networkdomain" "acc_test_domain" {
+			name		= "acc-test-networkdomain"
+			description	= "Network domain for Terraform acceptance test."
+			datacenter	= "AU9"
+
+			plan		= "ADVANCED"
+		}
+
+		resource "ddcloud_vlan" "acc_test_vlan" {
+			name				= "acc-test-vlan"
+			description 		= "VLAN for Terraform acceptance test."
+
+			networkdomain 		= "${ddcloud_networkdomain.acc_test_domain.id}"
+			attached_vlan_gateway_addressing = "HIGH"
+			ipv4_base_address	= "192.168.17.0"
+			ipv4_prefix_size	= 24
+		}
+
+		resource "ddcloud_server" "acc_test_server" {
+			count					= %d
+			name					= "acc_test_server-${format("%%d", count.index + 1)}"
+			description 			= "Server ${format("%%d", count.index + 1)} for Terraform anti-affinity group acceptance test."
+			admin_password			= "Snausages!1234"
+
+			memory_gb				= 8
+
+			networkdomain 			= "${ddcloud_networkdomain.acc_test_domain.id}"
+
+			primary_network_adapter {
+				vlan				= "${ddcloud_vlan.acc_test_vlan.id}"
+				ipv4				= "192.168.17.${count.index + 6}"
+			}
+
+			dns_primary				= "8.8.8.8"
+			dns_secondary			= "8.8.4.4"
+
+			image					= "CentOS 7 64-bit 2 CPU"
+		}
+
+		resource "ddcloud_server_anti_affinity_group" "acc_test_anti_affinity_group" {
+			servers = "${slice(ddcloud_server.acc_test_server.*.id, 0, %d)}"
+		}
+	`, serverCount, groupSize)
+}
+
+/*
+ * Acceptance tests.
+ */
+
+// Acceptance test for ddcloud_server_anti_affinity_group (basic):
+//
+// Create a group of 3 servers, then add a fourth server and verify that only the required rules are created.
+func TestAccServerAntiAffinityGroupBasicCreateAndUpdate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckDDCloudServerAntiAffinityGroupDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDDCloudServerAntiAffinityGroupBasic(4, 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ddcloud_server_anti_affinity_group.acc_test_anti_affinity_group", "rule.#", "3"),
+					testCheckDDCloudServerAntiAffinityGroupRulesExist("acc_test_anti_affinity_group"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccDDCloudServerAntiAffinityGroupBasic(4, 4),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ddcloud_server_anti_affinity_group.acc_test_anti_affinity_group", "rule.#", "6"),
+					testCheckDDCloudServerAntiAffinityGroupRulesExist("acc_test_anti_affinity_group"),
+				),
+			},
+		},
+	})
+}
+
+/*
+ * Acceptance-test checks.
+ */
+
+// Acceptance test check for ddcloud_server_anti_affinity_group:
+//
+// Check that all rules managed by the group exist.
+func testCheckDDCloudServerAntiAffinityGroupRulesExist(name string) resource.TestCheckFunc {
+	name = ensureResourceTypePrefix(name, "ddcloud_server_anti_affinity_group")
+
+	return func(state *terraform.State) error {
+		res, ok := state.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		networkDomainID := res.Primary.Attributes[resourceKeyAntiAffinityGroupNetworkDomainID]
+
+		client := testAccProvider.Meta().(*providerState).Client()
+		existingRules, err := listServerAntiAffinityRules(client, networkDomainID)
+		if err != nil {
+			return fmt.Errorf("bad: List server anti-affinity rules: %s", err.Error())
+		}
+		existingRuleIDs := make(map[string]bool)
+		for _, existingRule := range existingRules {
+			existingRuleIDs[existingRule.ID] = true
+		}
+
+		var ruleCount int
+		fmt.Sscanf(res.Primary.Attributes[resourceKeyAntiAffinityGroupRule+".#"], "%d", &ruleCount)
+		for index := 0; index < ruleCount; index++ {
+			ruleID := res.Primary.Attributes[fmt.Sprintf("%s.%d.%s", resourceKeyAntiAffinityGroupRule, index, resourceKeyAntiAffinityGroupRuleID)]
+			if !existingRuleIDs[ruleID] {
+				return fmt.Errorf("bad: Server anti-affinity rule not found with Id '%s' in network domain '%s'", ruleID, networkDomainID)
+			}
+		}
+
+		return nil
+	}
+}
+
+// Acceptance test resource-destruction check for ddcloud_server_anti_affinity_group:
+//
+// Check all server anti-affinity rules managed by groups in the configuration have been destroyed.
+func testCheckDDCloudServerAntiAffinityGroupDestroy(state *terraform.State) error {
+	for _, res := range state.RootModule().Resources {
+		if res.Type != "ddcloud_server_anti_affinity_group" {
+			continue
+		}
+
+		networkDomainID := res.Primary.Attributes[resourceKeyAntiAffinityGroupNetworkDomainID]
+
+		var ruleCount int
+		fmt.Sscanf(res.Primary.Attributes[resourceKeyAntiAffinityGroupRule+".#"], "%d", &ruleCount)
+		for index := 0; index < ruleCount; index++ {
+			ruleID := res.Primary.Attributes[fmt.Sprintf("%s.%d.%s", resourceKeyAntiAffinityGroupRule, index, resourceKeyAntiAffinityGroupRuleID)]
+
+			client := testAccProvider.Meta().(*providerState).Client()
+			rule, err := client.GetServerAntiAffinityRule(ruleID, networkDomainID)
+			if err != nil {
+				return nil
+			}
+			if rule != nil {
+				return fmt.Errorf("Server anti-affinity rule '%s' still exists in network domain '%s'", ruleID, networkDomainID)
+			}
+		}
+	}
+
+	return nil
+}
@@ -1,8 +1,11 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
@@ -11,7 +14,8 @@ import (
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
 )
 
@@ -30,17 +34,21 @@ const (
 	resourceKeyServerBackupClientAlert              = "alert"
 	resourceKeyServerBackupClientAlertTrigger       = "trigger"
 	resourceKeyServerBackupClientAlertEmails        = "emails"
+	resourceKeyServerBackupClientLastJobTime        = "last_job_time"
+	resourceKeyServerBackupClientLastJobStatus      = "last_job_status"
+	resourceKeyServerBackupClientNextJobTime        = "next_job_time"
 
 	resourceCreateTimeoutServerBackup = 10 * time.Minute
 )
 
 func resourceServerBackup() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		SchemaVersion: 1,
-		Create:        resourceServerBackupCreate,
-		Read:          resourceServerBackupRead,
-		Update:        resourceServerBackupUpdate,
-		Delete:        resourceServerBackupDelete,
+		CreateContext: resourceServerBackupCreate,
+		ReadContext:   resourceServerBackupRead,
+		UpdateContext: resourceServerBackupUpdate,
+		DeleteContext: resourceServerBackupDelete,
+		CustomizeDiff: resourceServerBackupCustomizeDiff,
 		// Importer: &schema.ResourceImporter{
 		// 	State: resourceServerBackupImport,
 		// },
@@ -92,6 +100,21 @@ func resourceServerBackup() *schema.Resource {
 							Computed:    true,
 							Description: "The backup client's current status",
 						},
+						resourceKeyServerBackupClientLastJobTime: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The date / time when the backup client's last backup job finished",
+						},
+						resourceKeyServerBackupClientLastJobStatus: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status of the backup client's last backup job",
+						},
+						resourceKeyServerBackupClientNextJobTime: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The date / time when the backup client's next scheduled backup job will run",
+						},
 						resourceKeyServerBackupClientStoragePolicyName: &schema.Schema{
 							Type:        schema.TypeString,
 							Required:    true,
@@ -109,19 +132,23 @@ func resourceServerBackup() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									resourceKeyServerBackupClientAlertTrigger: &schema.Schema{
-										Type:         schema.TypeString,
-										Optional:     true,
-										Default:      "",
-										Description:  "If alerts are enabled, one of 'ON_FAILURE', 'ON_SUCCESS', 'ON_SUCCESS_OR_FAILURE'",
-										ValidateFunc: validators.StringIsOneOf("ON_FAILURE", "ON_SUCCESS", "ON_SUCCESS_OR_FAILURE"),
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "When alerts are sent (one of 'ON_FAILURE', 'ON_SUCCESS', 'ON_SUCCESS_OR_FAILURE')",
+										ValidateFunc: validators.StringIsOneOf("backup alert trigger",
+											"ON_FAILURE",
+											"ON_SUCCESS",
+											"ON_SUCCESS_OR_FAILURE",
+										),
 									},
 									resourceKeyServerBackupClientAlertEmails: &schema.Schema{
 										Type:        schema.TypeList,
-										Optional:    true,
+										Required:    true,
 										MinItems:    1,
-										Description: "If alerts are enabled, the email address to which alerts will be sent",
+										Description: "The email addresses to which alerts will be sent",
 										Elem: &schema.Schema{
-											Type: schema.TypeString,
+											Type:         schema.TypeString,
+											ValidateFunc: validateBackupAlertEmailAddress,
 										},
 									},
 								},
@@ -134,10 +161,17 @@ func resourceServerBackup() *schema.Resource {
 			},
 		},
 	}
+
+	// Schema version 1 did not change the shape of the resource's state data.
+	resource.StateUpgraders = []schema.StateUpgrader{
+		stateUpgraderNoChange("ddcloud_server_backup", 0, stateTypeOf(resource)),
+	}
+
+	return resource
 }
 
 // Create a server backup resource.
-func resourceServerBackupCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceServerBackupCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	serverID := data.Get(resourceKeyServerBackupServerID).(string)
 	servicePlan := data.Get(resourceKeyServerBackupServicePlan).(string)
 
@@ -146,16 +180,17 @@ func resourceServerBackupCreate(data *schema.ResourceData, provider interface{})
 
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
-		return fmt.Errorf("cannot find server '%s'", serverID)
+		return diag.Errorf("cannot find server '%s'", serverID)
 	}
 
 	log.Printf("Enabling backup for server '%s'...", serverID)
 
+	backupWasEnabled := false
 	operationDescription := fmt.Sprintf("Enable backup for server '%s'.", server.Name)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -165,45 +200,63 @@ func resourceServerBackupCreate(data *schema.ResourceData, provider interface{})
 				context.Retry()
 			} else if compute.IsAPIErrorCode(enableError, compute.ResultCodeBackupEnabledForServer) {
 				// Backup is already enabled; proceed (if there's service plan mismatch, it will be resolved in the next apply-cycle).
+				backupWasEnabled = true
 			} else {
 				context.Fail(enableError)
 			}
 		}
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	_, err = apiClient.WaitForServerBackupStatus(serverID, "enable backup", compute.ResourceStatusNormal, resourceCreateTimeoutServerBackup)
 	if err != nil {
-		return errors.Wrapf(err, "timed out waiting to enable backup for server '%s'", serverID)
+		return diag.FromErr(errors.Wrapf(err, "timed out waiting to enable backup for server '%s'", serverID))
 	}
 
 	backupDetails, err := apiClient.GetServerBackupDetails(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if backupDetails == nil {
-		return fmt.Errorf("cannot find backup details for server '%s'", serverID)
+		return diag.Errorf("cannot find backup details for server '%s'", serverID)
 	}
 
-	data.SetId(serverID)
-	data.Set(resourceKeyServerBackupAssetID, backupDetails.AssetID)
-
+	// The policies available to backup clients depend on the service plan, so they can only be checked once backup has been enabled.
 	propertyHelper := propertyHelper(data)
 	backupClients := propertyHelper.GetServerBackupClients()
 
+	err = checkBackupClientPolicies(server, backupClients, apiClient)
+	if err != nil {
+		if backupWasEnabled {
+			return diag.FromErr(err)
+		}
+
+		log.Printf("Backup client configuration for server '%s' is invalid; disabling backup again...", serverID)
+
+		disableError := disableServerBackup(ctx, server, providerState)
+		if disableError != nil {
+			return diag.Errorf("%s (additionally, failed to disable backup for server '%s': %s)", err, serverID, disableError)
+		}
+
+		return diag.FromErr(err)
+	}
+
+	data.SetId(serverID)
+	data.Set(resourceKeyServerBackupAssetID, backupDetails.AssetID)
+
 	if backupClients.IsEmpty() {
 		return nil
 	}
 
 	log.Printf("Adding backup clients to server '%s'...", serverID)
 
-	return createBackupClients(server, backupClients, data, providerState)
+	return diag.FromErr(createBackupClients(ctx, server, backupClients, data, providerState))
 }
 
 // Read a server backup resource.
-func resourceServerBackupRead(data *schema.ResourceData, provider interface{}) error {
+func resourceServerBackupRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	serverID := data.Get(resourceKeyServerBackupServerID).(string)
 
 	providerState := provider.(*providerState)
@@ -211,7 +264,7 @@ func resourceServerBackupRead(data *schema.ResourceData, provider interface{}) e
 
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
 		log.Printf("cannot find server '%s' (will treat as deleted)", serverID)
@@ -225,7 +278,7 @@ func resourceServerBackupRead(data *schema.ResourceData, provider interface{}) e
 
 	backupDetails, err := apiClient.GetServerBackupDetails(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if backupDetails == nil {
 		log.Printf("backup is not enabled for server '%s' (will treat as deleted)", serverID)
@@ -238,16 +291,25 @@ func resourceServerBackupRead(data *schema.ResourceData, provider interface{}) e
 	data.Set(resourceKeyServerBackupAssetID, backupDetails.AssetID)
 	data.Set(resourceKeyServerBackupServicePlan, backupDetails.ServicePlan)
 
+	backupClients := models.NewServerBackupClientsFromBackupClientDetails(backupDetails.Clients)
+
+	jobDetails, err := getServerBackupJobDetails(ctx, providerState, serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if jobDetails != nil {
+		applyBackupClientJobDetails(backupClients, jobDetails)
+	}
+
 	propertyHelper := propertyHelper(data)
-	propertyHelper.SetServerBackupClients(
-		models.NewServerBackupClientsFromBackupClientDetails(backupDetails.Clients),
-	)
+	backupClients.CaptureAlertingEmailOrder(propertyHelper.GetServerBackupClients())
+	propertyHelper.SetServerBackupClients(backupClients)
 
 	return nil
 }
 
 // Update a server backup resource.
-func resourceServerBackupUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceServerBackupUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	propertyHelper := propertyHelper(data)
 	serverID := data.Get(resourceKeyServerBackupServerID).(string)
 
@@ -256,15 +318,15 @@ func resourceServerBackupUpdate(data *schema.ResourceData, provider interface{})
 
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
-		return fmt.Errorf("cannot find server '%s'", serverID)
+		return diag.Errorf("cannot find server '%s'", serverID)
 	}
 
 	backupDetails, err := apiClient.GetServerBackupDetails(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if backupDetails == nil {
 		log.Printf("Backup is not enabled for server '%s' (will treat ddcloud_server_backup resource as deleted).", serverID)
@@ -283,7 +345,7 @@ func resourceServerBackupUpdate(data *schema.ResourceData, provider interface{})
 		)
 
 		operationDescription := fmt.Sprintf("Change backup service plan for server '%s'.", server.Name)
-		err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+		err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 			changeServicePlanError := apiClient.ChangeServerBackupServicePlan(serverID, servicePlan)
 			if changeServicePlanError != nil {
 				if compute.IsResourceBusyError(changeServicePlanError) {
@@ -294,7 +356,7 @@ func resourceServerBackupUpdate(data *schema.ResourceData, provider interface{})
 			}
 		})
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -302,26 +364,31 @@ func resourceServerBackupUpdate(data *schema.ResourceData, provider interface{})
 	actualBackupClients := models.NewServerBackupClientsFromBackupClientDetails(backupDetails.Clients)
 	addedBackupClients, changedBackupClients, removedBackupClients := configuredBackupClients.SplitByAction(actualBackupClients)
 
-	err = deleteBackupClients(server, removedBackupClients, data, providerState)
+	err = checkBackupClientPolicies(server, append(addedBackupClients, changedBackupClients...), apiClient)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	err = createBackupClients(server, addedBackupClients, data, providerState)
+	err = deleteBackupClients(ctx, server, removedBackupClients, data, providerState)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	err = updateBackupClients(server, changedBackupClients, data, providerState)
+	err = createBackupClients(ctx, server, addedBackupClients, data, providerState)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+
+	err = updateBackupClients(ctx, server, changedBackupClients, data, providerState)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
 // Delete a server backup resource.
-func resourceServerBackupDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceServerBackupDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	serverID := data.Get(resourceKeyServerBackupServerID).(string)
 
 	providerState := provider.(*providerState)
@@ -329,15 +396,15 @@ func resourceServerBackupDelete(data *schema.ResourceData, provider interface{})
 
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
-		return fmt.Errorf("cannot find server '%s'", serverID)
+		return diag.Errorf("cannot find server '%s'", serverID)
 	}
 
 	backupDetails, err := apiClient.GetServerBackupDetails(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if backupDetails == nil {
 		log.Printf("Backup is not enabled for server '%s' (will treat ddcloud_server_backup resource as deleted).", serverID)
@@ -350,16 +417,83 @@ func resourceServerBackupDelete(data *schema.ResourceData, provider interface{})
 	log.Printf("Remove backup clients (if any) for server '%s'.", serverID)
 
 	backupClientsToRemove := models.NewServerBackupClientsFromBackupClientDetails(backupDetails.Clients)
-	err = deleteBackupClients(server, backupClientsToRemove, data, providerState)
+	err = deleteBackupClients(ctx, server, backupClientsToRemove, data, providerState)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = disableServerBackup(ctx, server, providerState)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// Check a server backup resource's planned changes.
+//
+// The storage and schedule policies available to backup clients depend on the server's backup service plan, so they are only checked when the plan is created if backup is already enabled and the service plan is not changing (otherwise, they are checked when the plan is applied).
+func resourceServerBackupCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, provider interface{}) error {
+	if diff.Id() == "" || !diff.HasChange(resourceKeyServerBackupClients) || diff.HasChange(resourceKeyServerBackupServicePlan) {
+		return nil
+	}
+	if !diff.NewValueKnown(resourceKeyServerBackupClients) {
+		return nil
+	}
+
+	oldValue, newValue := diff.GetChange(resourceKeyServerBackupClients)
+	oldBackupClients, ok := oldValue.([]interface{})
+	if !ok {
+		return nil
+	}
+	newBackupClients, ok := newValue.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var configuredBackupClients models.ServerBackupClients
+	for index, backupClient := range models.NewServerBackupClientsFromStateData(newBackupClients) {
+		storagePolicyKey := fmt.Sprintf("%s.%d.%s", resourceKeyServerBackupClients, index, resourceKeyServerBackupClientStoragePolicyName)
+		schedulePolicyKey := fmt.Sprintf("%s.%d.%s", resourceKeyServerBackupClients, index, resourceKeyServerBackupClientSchedulePolicyName)
+		if !diff.NewValueKnown(storagePolicyKey) || !diff.NewValueKnown(schedulePolicyKey) {
+			continue
+		}
+
+		configuredBackupClients = append(configuredBackupClients, backupClient)
+	}
+
+	addedBackupClients, changedBackupClients, _ := configuredBackupClients.SplitByAction(
+		models.NewServerBackupClientsFromStateData(oldBackupClients),
+	)
+	backupClients := append(addedBackupClients, changedBackupClients...)
+	if backupClients.IsEmpty() {
+		return nil
+	}
+
+	serverID := diff.Get(resourceKeyServerBackupServerID).(string)
+	apiClient := provider.(*providerState).Client()
+
+	server, err := apiClient.GetServer(serverID)
 	if err != nil {
 		return err
 	}
+	if server == nil {
+		return nil
+	}
 
-	log.Printf("Disable backup for server '%s'.", serverID)
+	return checkBackupClientPolicies(server, backupClients, apiClient)
+}
+
+// Disable backup for a server.
+func disableServerBackup(ctx context.Context, server *compute.Server, providerState *providerState) error {
+	apiClient := providerState.Client()
+
+	log.Printf("Disable backup for server '%s'.", server.ID)
 
 	operationDescription := fmt.Sprintf("Disable backup for server '%s'.", server.Name)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
-		disableError := apiClient.DisableServerBackup(serverID)
+
+	return providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		disableError := apiClient.DisableServerBackup(server.ID)
 		if disableError != nil {
 			if compute.IsResourceBusyError(disableError) {
 				context.Retry()
@@ -368,14 +502,70 @@ func resourceServerBackupDelete(data *schema.ResourceData, provider interface{})
 			}
 		}
 	})
+}
+
+// Check that the storage and schedule policies used by the specified backup clients are available for the server.
+func checkBackupClientPolicies(server *compute.Server, backupClients models.ServerBackupClients, apiClient *compute.Client) error {
+	if backupClients.IsEmpty() {
+		return nil
+	}
+
+	storagePolicies, err := apiClient.GetServerBackupStoragePolicies(server.ID)
+	if err != nil {
+		return err
+	}
+	schedulePolicies, err := apiClient.GetServerBackupSchedulePolicies(server.ID)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	policyErrors := validateBackupClientPolicies(backupClients, storagePolicies.Items, schedulePolicies.Items)
+	if len(policyErrors) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(policyErrors))
+	for index, policyError := range policyErrors {
+		messages[index] = policyError.Error()
+	}
+
+	return fmt.Errorf("invalid backup client configuration for server '%s':\n%s", server.ID, strings.Join(messages, "\n"))
+}
+
+// Validate that the storage and schedule policies used by the specified backup clients are available.
+func validateBackupClientPolicies(backupClients models.ServerBackupClients, storagePolicies []compute.BackupStoragePolicy, schedulePolicies []compute.BackupSchedulePolicy) (errors []error) {
+	storagePolicyNames := make([]string, len(storagePolicies))
+	isStoragePolicyAvailable := make(map[string]bool)
+	for index, storagePolicy := range storagePolicies {
+		storagePolicyNames[index] = storagePolicy.Name
+		isStoragePolicyAvailable[storagePolicy.Name] = true
+	}
+	schedulePolicyNames := make([]string, len(schedulePolicies))
+	isSchedulePolicyAvailable := make(map[string]bool)
+	for index, schedulePolicy := range schedulePolicies {
+		schedulePolicyNames[index] = schedulePolicy.Name
+		isSchedulePolicyAvailable[schedulePolicy.Name] = true
+	}
+
+	for _, backupClient := range backupClients {
+		if !isStoragePolicyAvailable[backupClient.StoragePolicyName] {
+			errors = append(errors, fmt.Errorf("%s: '%s' backup client uses storage policy '%s', which is not available for the server's service plan (available storage policies are: %s)",
+				resourceKeyServerBackupClientStoragePolicyName, backupClient.Type, backupClient.StoragePolicyName,
+				strings.Join(storagePolicyNames, ", "),
+			))
+		}
+		if !isSchedulePolicyAvailable[backupClient.SchedulePolicyName] {
+			errors = append(errors, fmt.Errorf("%s: '%s' backup client uses schedule policy '%s', which is not available for the server's service plan (available schedule policies are: %s)",
+				resourceKeyServerBackupClientSchedulePolicyName, backupClient.Type, backupClient.SchedulePolicyName,
+				strings.Join(schedulePolicyNames, ", "),
+			))
+		}
+	}
+
+	return
 }
 
-func createBackupClients(server *compute.Server, backupClients models.ServerBackupClients, data *schema.ResourceData, providerState *providerState) error {
+func createBackupClients(ctx context.Context, server *compute.Server, backupClients models.ServerBackupClients, data *schema.ResourceData, providerState *providerState) error {
 	if len(backupClients) == 0 {
 		return nil
 	}
@@ -397,7 +587,7 @@ func createBackupClients(server *compute.Server, backupClients models.ServerBack
 
 		var backupClientID string
 		operationDescription := fmt.Sprintf("Add '%s' backup client to server '%s'.", backupClient.Type, server.Name)
-		err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+		err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 			asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 			defer asyncLock.Release()
 
@@ -438,7 +628,7 @@ func createBackupClients(server *compute.Server, backupClients models.ServerBack
 	return nil
 }
 
-func updateBackupClients(server *compute.Server, backupClients models.ServerBackupClients, data *schema.ResourceData, providerState *providerState) error {
+func updateBackupClients(ctx context.Context, server *compute.Server, backupClients models.ServerBackupClients, data *schema.ResourceData, providerState *providerState) error {
 	if len(backupClients) == 0 {
 		return nil
 	}
@@ -453,13 +643,18 @@ func updateBackupClients(server *compute.Server, backupClients models.ServerBack
 
 		log.Printf("Modifying '%s' backup client of server '%s'...", backupClient.Type, server.ID)
 
+		// If alerting has been removed from the configuration, modifying the client without alerting will disable alerts for it.
 		var backupClientAlerting *compute.BackupClientAlerting
 		if backupClient.Alerting != nil {
 			backupClientAlerting = backupClient.ToBackupClientDetail().Alerting
+
+			log.Printf("Backup client '%s' of server '%s' will send alerts (%s) to %d email addresses.", backupClient.ID, server.ID, backupClient.Alerting.Trigger, len(backupClient.Alerting.Emails))
+		} else {
+			log.Printf("Backup client '%s' of server '%s' will not send alerts.", backupClient.ID, server.ID)
 		}
 
 		operationDescription := fmt.Sprintf("Modify backup client '%s' of server '%s'.", backupClient.ID, server.Name)
-		err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+		err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 			asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 			defer asyncLock.Release()
 
@@ -503,7 +698,7 @@ func updateBackupClients(server *compute.Server, backupClients models.ServerBack
 	return nil
 }
 
-func deleteBackupClients(server *compute.Server, backupClients models.ServerBackupClients, data *schema.ResourceData, providerState *providerState) error {
+func deleteBackupClients(ctx context.Context, server *compute.Server, backupClients models.ServerBackupClients, data *schema.ResourceData, providerState *providerState) error {
 	if len(backupClients) == 0 {
 		return nil
 	}
@@ -519,7 +714,7 @@ func deleteBackupClients(server *compute.Server, backupClients models.ServerBack
 		log.Printf("Removing '%s' backup client '%s' from server '%s'...", backupClient.Type, backupClient.ID, server.ID)
 
 		operationDescription := fmt.Sprintf("Remove '%s' backup client '%s' from server '%s'.", backupClient.Type, backupClient.ID, server.Name)
-		err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+		err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 			asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 			defer asyncLock.Release()
 
@@ -578,3 +773,16 @@ func deleteBackupClients(server *compute.Server, backupClients models.ServerBack
 
 	return nil
 }
+
+func validateBackupAlertEmailAddress(value interface{}, propertyName string) (messages []string, errors []error) {
+	emailAddress := value.(string)
+
+	address, err := mail.ParseAddress(emailAddress)
+	if err != nil || address.Address != emailAddress {
+		errors = append(errors,
+			fmt.Errorf("invalid email address '%s' for '%s' (must be a plain email address such as 'alerts@example.com')", emailAddress, propertyName),
+		)
+	}
+
+	return
+}
@@ -0,0 +1,142 @@
+package ddcloud
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Unit test - backup client storage and schedule policies must be available for the server.
+func TestValidateBackupClientPolicies(test *testing.T) {
+	storagePolicies := []compute.BackupStoragePolicy{
+		compute.BackupStoragePolicy{Name: "14 Day Storage Policy", RetentionPeriodInDays: 14},
+		compute.BackupStoragePolicy{Name: "30 Day Storage Policy", RetentionPeriodInDays: 30},
+	}
+	schedulePolicies := []compute.BackupSchedulePolicy{
+		compute.BackupSchedulePolicy{Name: "12AM - 6AM"},
+		compute.BackupSchedulePolicy{Name: "6AM - 12PM"},
+	}
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Valid policies", 0, len(
+		validateBackupClientPolicies(models.ServerBackupClients{
+			models.ServerBackupClient{Type: "FA.Linux", StoragePolicyName: "14 Day Storage Policy", SchedulePolicyName: "6AM - 12PM"},
+		}, storagePolicies, schedulePolicies),
+	))
+
+	errors := validateBackupClientPolicies(models.ServerBackupClients{
+		models.ServerBackupClient{Type: "FA.Linux", StoragePolicyName: "14 Day Storage Polcy", SchedulePolicyName: "6AM - 12PM"},
+		models.ServerBackupClient{Type: "MySQL", StoragePolicyName: "30 Day Storage Policy", SchedulePolicyName: "6PM - 12AM"},
+	}, storagePolicies, schedulePolicies)
+	assert.EqualsInt("Errors.Length", 2, len(errors))
+	assert.IsTrue("Errors[0] is for missing storage policy", strings.Contains(errors[0].Error(), "storage policy '14 Day Storage Polcy'"))
+	assert.IsTrue("Errors[0] lists available storage policies", strings.Contains(errors[0].Error(), "14 Day Storage Policy, 30 Day Storage Policy"))
+	assert.IsTrue("Errors[1] is for missing schedule policy", strings.Contains(errors[1].Error(), "schedule policy '6PM - 12AM'"))
+}
+
+// Compute the diff for changing the storage policy of an existing server backup's client.
+func testServerBackupChangeDiff(standIn *testCloudControlStandIn, servicePlan string, storagePolicy string) (*terraform.InstanceDiff, error) {
+	state := &terraform.InstanceState{
+		ID: "server-1",
+		Attributes: map[string]string{
+			"id":                               "server-1",
+			resourceKeyServerBackupServerID:    "server-1",
+			resourceKeyServerBackupServicePlan: "Enterprise",
+			"client.#":                         "1",
+			"client.0.id":                      "client-1",
+			"client.0.type":                    "FA.Linux",
+			"client.0.storage_policy":          "14 Day Storage Policy",
+			"client.0.schedule_policy":         "12AM - 6AM",
+			"client.0.alert.#":                 "0",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeyServerBackupServerID:    "server-1",
+		resourceKeyServerBackupServicePlan: servicePlan,
+		resourceKeyServerBackupClients: []interface{}{
+			map[string]interface{}{
+				resourceKeyServerBackupClientType:               "FA.Linux",
+				resourceKeyServerBackupClientStoragePolicyName:  storagePolicy,
+				resourceKeyServerBackupClientSchedulePolicyName: "12AM - 6AM",
+			},
+		},
+	})
+
+	return resourceServerBackup().Diff(context.Background(), state, config, standIn.NewProviderState())
+}
+
+// Unit test - backup client policies are checked when the plan is created (if backup is already enabled).
+func TestServerBackupCustomizeDiffPolicies(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.ComputeServer = &compute.Server{
+		ID:   "server-1",
+		Name: "server1",
+	}
+
+	_, err := testServerBackupChangeDiff(standIn, "Enterprise", "30 Day Storage Policy")
+	assert.IsTrue("err != nil (unavailable storage policy)", err != nil)
+	assert.IsTrue("error mentions storage policy", strings.Contains(err.Error(), "storage policy '30 Day Storage Policy'"))
+
+	// Policies depend on the service plan, so they are not checked if it is changing.
+	_, err = testServerBackupChangeDiff(standIn, "Advanced", "30 Day Storage Policy")
+	assert.IsTrue("err == nil (service plan changed)", err == nil)
+}
+
+// Unit test - backup alert triggers and email addresses are validated at plan time.
+func TestServerBackupAlertValidation(test *testing.T) {
+	testConfig := func(trigger string, emails ...interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			resourceKeyServerBackupServerID:    "server-1",
+			resourceKeyServerBackupServicePlan: "Essentials",
+			resourceKeyServerBackupClients: []interface{}{
+				map[string]interface{}{
+					resourceKeyServerBackupClientType:               "FA.Linux",
+					resourceKeyServerBackupClientStoragePolicyName:  "14 Day Storage Policy",
+					resourceKeyServerBackupClientSchedulePolicyName: "6AM - 12PM",
+					resourceKeyServerBackupClientAlert: []interface{}{
+						map[string]interface{}{
+							resourceKeyServerBackupClientAlertTrigger: trigger,
+							resourceKeyServerBackupClientAlertEmails:  emails,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	assert := assert.ForTest(test)
+	resource := resourceServerBackup()
+
+	for _, trigger := range []string{"ON_FAILURE", "ON_SUCCESS", "ON_SUCCESS_OR_FAILURE"} {
+		diagnostics := resource.Validate(testConfig(trigger, "a@example.com", "b@example.com"))
+		assert.IsFalse("Valid trigger "+trigger, diagnostics.HasError())
+	}
+
+	assert.IsTrue("Invalid trigger", resource.Validate(testConfig("ALWAYS", "a@example.com")).HasError())
+	assert.IsTrue("Invalid email", resource.Validate(testConfig("ON_FAILURE", "a@example.com", "not-an-email")).HasError())
+	assert.IsTrue("No emails", resource.Validate(testConfig("ON_FAILURE")).HasError())
+}
+
+// Unit test - backup alert email addresses must be plain email addresses.
+func TestValidateBackupAlertEmailAddress(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	for _, emailAddress := range []string{"alerts@example.com", "first.last+backup@sub.example.com"} {
+		_, errors := validateBackupAlertEmailAddress(emailAddress, "emails.0")
+		assert.EqualsInt("Errors for "+emailAddress, 0, len(errors))
+	}
+
+	for _, emailAddress := range []string{"", "alerts", "alerts@", "Alerts <alerts@example.com>", "a@example.com, b@example.com"} {
+		_, errors := validateBackupAlertEmailAddress(emailAddress, "emails.0")
+		assert.EqualsInt("Errors for "+emailAddress, 1, len(errors))
+	}
+}
@@ -6,7 +6,7 @@ import (
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // updateServerConfiguration reconfigures a server, changing the allocated RAM and / or CPU count.
@@ -45,19 +45,11 @@ func updateServerConfiguration(apiClient *compute.Client, server *compute.Server
 	return err
 }
 
-func captureServerNetworkConfiguration(server *compute.Server, data *schema.ResourceData, isPartial bool) {
+func captureServerNetworkConfiguration(server *compute.Server, data *schema.ResourceData) {
 	propertyHelper := propertyHelper(data)
 
 	networkAdapters := models.NewNetworkAdaptersFromVirtualMachineNetwork(server.Network)
-	propertyHelper.SetServerNetworkAdapters(networkAdapters, isPartial)
-
-	if isPartial {
-		data.SetPartial(resourceKeyServerPrimaryAdapterVLAN)
-		data.SetPartial(resourceKeyServerPrimaryAdapterIPv4)
-		data.SetPartial(resourceKeyServerPrimaryAdapterIPv6)
-		data.SetPartial(resourceKeyServerPrimaryAdapterType)
-		data.SetPartial(resourceKeyServerNetworkDomainID)
-	}
+	propertyHelper.SetServerNetworkAdapters(networkAdapters)
 
 	// Publish primary network adapter type.
 	primaryNetworkAdapter := networkAdapters.GetPrimary()
@@ -65,7 +57,6 @@ func captureServerNetworkConfiguration(server *compute.Server, data *schema.Reso
 		data.Set(resourceKeyServerPrimaryAdapterVLAN, primaryNetworkAdapter.VLANID)
 		data.Set(resourceKeyServerPrimaryAdapterIPv4, primaryNetworkAdapter.PrivateIPv4Address)
 		data.Set(resourceKeyServerPrimaryAdapterIPv6, primaryNetworkAdapter.PrivateIPv6Address)
-		data.Set(resourceKeyServerPrimaryAdapterType, primaryNetworkAdapter.AdapterType)
 	} else {
 		data.Set(resourceKeyServerPrimaryAdapterVLAN, nil)
 		data.Set(resourceKeyServerPrimaryAdapterIPv4, nil)
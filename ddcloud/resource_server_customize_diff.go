@@ -0,0 +1,296 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Validate a planned change to a server resource.
+//
+// These checks would otherwise only fail at apply time (by which point the server may already have been deployed or partially reconfigured).
+// Values that are not yet known (e.g. because they depend on resources that have not been created yet) are not validated.
+func resourceServerCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, provider interface{}) error {
+	name := diff.Get(resourceKeyServerName).(string)
+	isNew := diff.Id() == ""
+
+	var problems []string
+
+	// Disk configuration.
+	var configuredDisks models.Disks
+	if diff.NewValueKnown(resourceKeyServerDisk) && (isNew || diff.HasChange(resourceKeyServerDisk)) {
+		configuredDisks = getServerDisksFromDiff(diff)
+
+		for _, diagnostic := range validateServerDisks(configuredDisks) {
+			problems = append(problems, formatDiagnostic(diagnostic))
+		}
+
+		if !isNew {
+			err := validateDisksPowerCycle(diff, provider.(*providerState), diff.Id())
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s", resourceKeyServerDisk, err))
+			}
+		}
+	}
+
+	// The image only matters when the server is being deployed (rather than restored from a snapshot).
+	var imageConfiguration *compute.ServerDeploymentConfiguration
+	if isNew && diff.Get(resourceKeyServerRestoreFromSnapshot).(string) == "" {
+		image, err := resolveServerImageForDiff(diff, provider.(*providerState))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", resourceKeyServerImage, err))
+		} else if image != nil {
+			imageConfiguration = &compute.ServerDeploymentConfiguration{}
+			image.ApplyTo(imageConfiguration)
+
+			if image.RequiresCustomization() && diff.NewValueKnown(resourceKeyServerAdminPassword) {
+				adminPassword := diff.Get(resourceKeyServerAdminPassword).(string)
+				err = validateAdminPassword(adminPassword, image)
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %s", resourceKeyServerAdminPassword, err))
+				}
+			}
+
+			for _, err = range validateServerDisksAgainstImage(configuredDisks, imageConfiguration) {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+
+	// CPU and memory (if not configured, new servers use the image defaults).
+	//
+	// Existing servers are only validated when their CPU or memory configuration changes (a server that was resized outside of Terraform can still be planned).
+	// Upper limits for CPU and memory vary between data centres, so they are left to CloudControl.
+	if isNew || diff.HasChange(resourceKeyServerCPUCount) || diff.HasChange(resourceKeyServerCPUCoreCount) {
+		cpuCount := getKnownServerInt(diff, resourceKeyServerCPUCount)
+		cpuCoreCount := getKnownServerInt(diff, resourceKeyServerCPUCoreCount)
+		if imageConfiguration != nil {
+			if cpuCount == 0 {
+				cpuCount = imageConfiguration.CPU.Count
+			}
+			if cpuCoreCount == 0 {
+				cpuCoreCount = imageConfiguration.CPU.CoresPerSocket
+			}
+		}
+
+		err := validateServerCPU(cpuCount, cpuCoreCount)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if isNew || diff.HasChange(resourceKeyServerMemoryGB) {
+		memoryGB := getKnownServerInt(diff, resourceKeyServerMemoryGB)
+		if imageConfiguration != nil && memoryGB == 0 {
+			memoryGB = imageConfiguration.MemoryGB
+		}
+
+		err := validateServerMemory(memoryGB)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", resourceKeyServerMemoryGB, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		log.Printf("Planned configuration for server '%s' is invalid (%d problem(s) found).", name, len(problems))
+
+		return fmt.Errorf("invalid configuration for server '%s':\n  - %s",
+			name,
+			strings.Join(problems, "\n  - "),
+		)
+	}
+
+	return nil
+}
+
+// Resolve the image for a server that is about to be deployed.
+//
+// Returns nil (with no error) if the image or network domain are not yet known.
+func resolveServerImageForDiff(diff *schema.ResourceDiff, providerState *providerState) (compute.Image, error) {
+	if !diff.NewValueKnown(resourceKeyServerImage) || !diff.NewValueKnown(resourceKeyServerImageType) || !diff.NewValueKnown(resourceKeyServerNetworkDomainID) {
+		return nil, nil
+	}
+
+	apiClient := providerState.Client()
+
+	networkDomainID := diff.Get(resourceKeyServerNetworkDomainID).(string)
+	networkDomain, err := apiClient.GetNetworkDomain(networkDomainID)
+	if err != nil {
+		return nil, err
+	}
+	if networkDomain == nil {
+		return nil, fmt.Errorf("no network domain was found with Id '%s'", networkDomainID)
+	}
+
+	configuredImage := diff.Get(resourceKeyServerImage).(string)
+	configuredImageType := diff.Get(resourceKeyServerImageType).(string)
+
+	return resolveServerImage(configuredImage, configuredImageType, networkDomain.DatacenterID, apiClient)
+}
+
+// Get the disks configured for a server (or storage controller) from a planned change.
+//
+// The size of any disk whose size, speed, or IOPS is not yet known is treated as 0 (and is not validated).
+func getServerDisksFromDiff(diff *schema.ResourceDiff) models.Disks {
+	serverDisks, ok := diff.Get(resourceKeyServerDisk).([]interface{})
+	if !ok {
+		return nil
+	}
+
+	disks := models.NewDisksFromStateData(serverDisks)
+	for index := range disks {
+		for _, key := range []string{resourceKeyServerDiskSizeGB, resourceKeyServerDiskSpeed, resourceKeyServerDiskIops} {
+			if !diff.NewValueKnown(fmt.Sprintf("%s.%d.%s", resourceKeyServerDisk, index, key)) {
+				disks[index].SizeGB = 0
+			}
+		}
+	}
+
+	return disks
+}
+
+// Verify that planned disk changes for a server (or storage controller) can be applied, if they require the server to be powered off.
+func validateDisksPowerCycle(diff *schema.ResourceDiff, providerState *providerState, serverID string) error {
+	oldValue, newValue := diff.GetChange(resourceKeyServerDisk)
+	oldDisks, ok := oldValue.([]interface{})
+	if !ok {
+		return nil
+	}
+	newDisks, ok := newValue.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	configuredDisks := models.NewDisksFromStateData(newDisks)
+	actualDisks := models.NewDisksFromStateData(oldDisks)
+	if configuredDisks.RequirePowerCycle(actualDisks) {
+		return validateServerPowerCycle(providerState, serverID, "changing disk speed to or from "+compute.ServerDiskSpeedProvisionedIops)
+	}
+
+	return nil
+}
+
+// Get the value of an integer server property from a planned change (0 if the value is not set or not yet known).
+func getKnownServerInt(diff *schema.ResourceDiff, key string) int {
+	if !diff.NewValueKnown(key) {
+		return 0
+	}
+
+	value, _ := diff.Get(key).(int)
+
+	return value
+}
+
+// Validate configured disks against the disks defined by a server's image.
+//
+// Image disks can be resized (but not shrunk) and cannot be moved to a different SCSI unit.
+func validateServerDisksAgainstImage(disks models.Disks, imageConfiguration *compute.ServerDeploymentConfiguration) (errors []error) {
+	if disks.IsEmpty() {
+		return
+	}
+
+	imageController := imageConfiguration.SCSIControllers.GetByBusNumber(0)
+	if imageController == nil {
+		return
+	}
+
+	for diskIndex, disk := range disks {
+		for _, imageDisk := range imageController.Disks {
+			if imageDisk.SCSIUnitID != disk.SCSIUnitID {
+				continue
+			}
+
+			if disk.SizeGB != 0 && disk.SizeGB < imageDisk.SizeGB {
+				errors = append(errors, fmt.Errorf("%s.%d.%s: disk with SCSI unit ID %d is %d GB in the image, and cannot be made smaller (%d GB requested)",
+					resourceKeyServerDisk, diskIndex, resourceKeyServerDiskSizeGB,
+					disk.SCSIUnitID, imageDisk.SizeGB, disk.SizeGB,
+				))
+			}
+		}
+	}
+
+	return
+}
+
+// Validate a server's CPU configuration (0 means "not specified").
+func validateServerCPU(cpuCount int, cpuCoreCount int) error {
+	if cpuCount == 0 {
+		return nil
+	}
+
+	if cpuCount < 1 {
+		return fmt.Errorf("%s: CPU count must be at least 1 (%d requested)",
+			resourceKeyServerCPUCount, cpuCount,
+		)
+	}
+
+	if cpuCoreCount == 0 {
+		return nil
+	}
+
+	if cpuCoreCount < 1 || cpuCoreCount > cpuCount || cpuCount%cpuCoreCount != 0 {
+		return fmt.Errorf("%s: CPU count (%d) must be a multiple of the number of cores per CPU (%d)",
+			resourceKeyServerCPUCoreCount, cpuCount, cpuCoreCount,
+		)
+	}
+
+	return nil
+}
+
+// Validate a server's memory configuration (0 means "not specified").
+func validateServerMemory(memoryGB int) error {
+	if memoryGB == 0 {
+		return nil
+	}
+
+	if memoryGB < 1 {
+		return fmt.Errorf("memory must be at least 1 GB (%d GB requested)",
+			memoryGB,
+		)
+	}
+
+	return nil
+}
+
+// Format a diagnostic as a single line (prefixed with its attribute path, if any).
+func formatDiagnostic(diagnostic diag.Diagnostic) string {
+	message := diagnostic.Summary
+	if diagnostic.Detail != "" {
+		message += " (" + diagnostic.Detail + ")"
+	}
+
+	attributePath := formatAttributePath(diagnostic.AttributePath)
+	if attributePath == "" {
+		return message
+	}
+
+	return attributePath + ": " + message
+}
+
+// Format an attribute path using Terraform's flatmap notation (e.g. "disk.1.scsi_unit_id").
+func formatAttributePath(path cty.Path) string {
+	var segments []string
+	for _, step := range path {
+		switch typedStep := step.(type) {
+		case cty.GetAttrStep:
+			segments = append(segments, typedStep.Name)
+		case cty.IndexStep:
+			key := typedStep.Key
+			if key.Type() == cty.Number {
+				index, _ := key.AsBigFloat().Int64()
+				segments = append(segments, fmt.Sprintf("%d", index))
+			} else if key.Type() == cty.String {
+				segments = append(segments, key.AsString())
+			}
+		}
+	}
+
+	return strings.Join(segments, ".")
+}
@@ -0,0 +1,160 @@
+package ddcloud
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Unknown value placeholder used by Terraform in raw configuration.
+const testUnknownValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// Unit test - CPU count / cores-per-CPU combinations.
+func TestValidateServerCPU(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	assert.IsTrue("2 CPUs, unspecified cores is valid", validateServerCPU(2, 0) == nil)
+	assert.IsTrue("4 CPUs, 2 cores per CPU is valid", validateServerCPU(4, 2) == nil)
+	assert.IsTrue("Unspecified CPUs is valid", validateServerCPU(0, 3) == nil)
+	assert.IsTrue("64 CPUs is valid (upper limit is enforced by CloudControl)", validateServerCPU(64, 0) == nil)
+	assert.IsTrue("-1 CPUs is invalid", validateServerCPU(-1, 0) != nil)
+	assert.IsTrue("3 CPUs, 2 cores per CPU is invalid", validateServerCPU(3, 2) != nil)
+	assert.IsTrue("2 CPUs, 4 cores per CPU is invalid", validateServerCPU(2, 4) != nil)
+}
+
+// Unit test - memory limits.
+func TestValidateServerMemory(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	assert.IsTrue("Unspecified memory is valid", validateServerMemory(0) == nil)
+	assert.IsTrue("8 GB is valid", validateServerMemory(8) == nil)
+	assert.IsTrue("2048 GB is valid (upper limit is enforced by CloudControl)", validateServerMemory(2048) == nil)
+	assert.IsTrue("-1 GB is invalid", validateServerMemory(-1) != nil)
+}
+
+// Unit test - configured disks cannot be smaller than the corresponding image disks.
+func TestValidateServerDisksAgainstImage(test *testing.T) {
+	imageConfiguration := &compute.ServerDeploymentConfiguration{
+		SCSIControllers: compute.VirtualMachineSCSIControllers{
+			compute.VirtualMachineSCSIController{
+				BusNumber: 0,
+				Disks: compute.VirtualMachineDisks{
+					compute.VirtualMachineDisk{SCSIUnitID: 0, SizeGB: 10},
+					compute.VirtualMachineDisk{SCSIUnitID: 1, SizeGB: 40},
+				},
+			},
+		},
+	}
+
+	errors := validateServerDisksAgainstImage(models.Disks{
+		models.Disk{SCSIUnitID: 0, SizeGB: 20},
+		models.Disk{SCSIUnitID: 1, SizeGB: 30},
+		models.Disk{SCSIUnitID: 2, SizeGB: 5},
+	}, imageConfiguration)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Errors.Length", 1, len(errors))
+	assert.IsTrue("Error refers to disk.1.size_gb", strings.HasPrefix(errors[0].Error(), "disk.1.size_gb:"))
+}
+
+// Unit test - attribute paths are formatted using flatmap notation.
+func TestFormatAttributePath(test *testing.T) {
+	path := cty.GetAttrPath("disk").IndexInt(2).GetAttr("scsi_unit_id")
+
+	assert.ForTest(test).EqualsString("Path", "disk.2.scsi_unit_id", formatAttributePath(path))
+}
+
+// Unit test - an invalid server plan is rejected (even when the image cannot yet be resolved).
+func TestServerCustomizeDiffInvalidPlan(test *testing.T) {
+	resource := resourceServer()
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeyServerName:            "server-1",
+		resourceKeyServerImage:           "CentOS 7 64-bit 2 CPU",
+		resourceKeyServerNetworkDomainID: testUnknownValue,
+		resourceKeyServerCPUCount:        3,
+		resourceKeyServerCPUCoreCount:    2,
+		resourceKeyServerDisk: []interface{}{
+			map[string]interface{}{
+				resourceKeyServerDiskUnitID: 0,
+				resourceKeyServerDiskSizeGB: 10,
+			},
+			map[string]interface{}{
+				resourceKeyServerDiskUnitID: 0,
+				resourceKeyServerDiskSizeGB: 20,
+			},
+		},
+	})
+
+	_, err := resource.Diff(context.Background(), nil, config, &providerState{})
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("Diff fails", err != nil)
+	assert.IsTrue("Error mentions cores_per_cpu", strings.Contains(err.Error(), "cores_per_cpu:"))
+	assert.IsTrue("Error mentions disk.1.scsi_unit_id", strings.Contains(err.Error(), "disk.1.scsi_unit_id:"))
+}
+
+// Unit test - a valid server plan is accepted.
+func TestServerCustomizeDiffValidPlan(test *testing.T) {
+	resource := resourceServer()
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeyServerName:            "server-1",
+		resourceKeyServerImage:           "CentOS 7 64-bit 2 CPU",
+		resourceKeyServerNetworkDomainID: testUnknownValue,
+		resourceKeyServerCPUCount:        4,
+		resourceKeyServerCPUCoreCount:    2,
+		resourceKeyServerMemoryGB:        8,
+	})
+
+	_, err := resource.Diff(context.Background(), nil, config, &providerState{})
+	if err != nil {
+		test.Fatal(err)
+	}
+}
+
+// Unit test - the CPU configuration of an existing server is only validated when it changes.
+func TestServerCustomizeDiffExistingServerUnchangedCPU(test *testing.T) {
+	resource := resourceServer()
+
+	state := &terraform.InstanceState{
+		ID: "server-1",
+		Attributes: map[string]string{
+			"id":                             "server-1",
+			resourceKeyServerName:            "server-1",
+			resourceKeyServerImage:           "CentOS 7 64-bit 2 CPU",
+			resourceKeyServerNetworkDomainID: "network-domain-1",
+			resourceKeyServerCPUCount:        "3",
+			resourceKeyServerCPUCoreCount:    "2",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeyServerName:            "server-1",
+		resourceKeyServerImage:           "CentOS 7 64-bit 2 CPU",
+		resourceKeyServerNetworkDomainID: "network-domain-1",
+		resourceKeyServerCPUCount:        3,
+		resourceKeyServerCPUCoreCount:    2,
+	})
+
+	_, err := resource.Diff(context.Background(), state, config, &providerState{})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeyServerName:            "server-1",
+		resourceKeyServerImage:           "CentOS 7 64-bit 2 CPU",
+		resourceKeyServerNetworkDomainID: "network-domain-1",
+		resourceKeyServerCPUCount:        5,
+		resourceKeyServerCPUCoreCount:    2,
+	})
+
+	_, err = resource.Diff(context.Background(), state, config, &providerState{})
+	assert.ForTest(test).IsTrue("Diff fails when CPU count changes", err != nil)
+}
@@ -1,13 +1,16 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -70,7 +73,7 @@ func schemaDisk() *schema.Schema {
 // When creating a server resource, synchronise the server's disks with its resource data.
 //
 // If the server is running, then it will be stopped before creating / updating disks, and then restarted.
-func createDisks(server *compute.Server, data *schema.ResourceData, providerState *providerState) error {
+func createDisks(ctx context.Context, server *compute.Server, data *schema.ResourceData, providerState *providerState) error {
 	log.Printf("Resource server disks. createDisks ...")
 	propertyHelper := propertyHelper(data)
 	serverID := data.Id()
@@ -83,16 +86,10 @@ func createDisks(server *compute.Server, data *schema.ResourceData, providerStat
 	actualDisks := models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers)
 	configuredDisks.CaptureIDs(actualDisks)
 
-	err := validateServerDisks(configuredDisks)
-	if err != nil {
-		return err
-	}
-
 	if len(configuredDisks) == 0 {
 		log.Printf("Configuration for server '%s' does not specify any disks; the provider will assume all disks are either image-only, or configured via ddcloud_storage_controller.", serverID)
 
 		propertyHelper.SetDisks(actualDisks)
-		propertyHelper.SetPartial(resourceKeyServerDisk)
 
 		log.Printf("Server '%s' now has %d disks: %#v.", serverID, len(actualDisks), actualDisks)
 
@@ -102,11 +99,10 @@ func createDisks(server *compute.Server, data *schema.ResourceData, providerStat
 	log.Printf("Configuration for server '%s' specifies %d disks: %#v.", serverID, len(configuredDisks), configuredDisks)
 
 	propertyHelper.SetDisks(configuredDisks)
-	propertyHelper.SetPartial(resourceKeyServerDisk)
 
 	apiClient := providerState.Client()
 
-	server, err = apiClient.GetServer(serverID)
+	server, err := apiClient.GetServer(serverID)
 	if err != nil {
 		return err
 	}
@@ -132,7 +128,7 @@ func createDisks(server *compute.Server, data *schema.ResourceData, providerStat
 			server.ID,
 		)
 
-		err = serverShutdown(providerState, serverID)
+		err = serverShutdown(ctx, providerState, serverID)
 		if err != nil {
 			return err
 		}
@@ -143,13 +139,13 @@ func createDisks(server *compute.Server, data *schema.ResourceData, providerStat
 		)
 	}
 
-	err = processModifyDisks(modifyDisks, data, providerState)
+	err = processModifyDisks(ctx, modifyDisks, data, providerState)
 	if err != nil {
 		return err
 	}
 
 	log.Printf("Configure additional disks for server '%s'...", serverID)
-	err = processAddDisks(addDisks, data, providerState)
+	err = processAddDisks(ctx, addDisks, data, providerState)
 	if err != nil {
 		return err
 	}
@@ -160,7 +156,7 @@ func createDisks(server *compute.Server, data *schema.ResourceData, providerStat
 			server.ID,
 		)
 
-		err = serverStart(providerState, serverID)
+		err = serverStart(ctx, providerState, serverID)
 		if err != nil {
 			return err
 		}
@@ -177,8 +173,8 @@ func createDisks(server *compute.Server, data *schema.ResourceData, providerStat
 // When updating a server resource, synchronise the server's image disk attributes with its resource data
 // Removes image disks from existingDisksByUnitID as they are processed, leaving only additional disks.
 //
-// If the server is running, then it will be stopped before creating / updating disks, and then restarted.
-func updateDisks(data *schema.ResourceData, providerState *providerState) error {
+// If the server is running and the changes require it to be powered off (see models.Disks.RequirePowerCycle), then it will be stopped before updating disks, and then restarted.
+func updateDisks(ctx context.Context, data *schema.ResourceData, providerState *providerState) error {
 	log.Printf("Resource server disks. updateDisks ...")
 	propertyHelper := propertyHelper(data)
 	serverID := data.Id()
@@ -186,6 +182,12 @@ func updateDisks(data *schema.ResourceData, providerState *providerState) error
 	log.Printf("Configure image disks for server '%s'...", serverID)
 
 	apiClient := providerState.Client()
+
+	diskResourceSCSIPaths, err := getDiskResourceSCSIPaths(apiClient, serverID)
+	if err != nil {
+		return err
+	}
+
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
 		return err
@@ -195,7 +197,7 @@ func updateDisks(data *schema.ResourceData, providerState *providerState) error
 
 		return fmt.Errorf("server '%s' has been deleted", serverID)
 	}
-	actualDisks := models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers)
+	actualDisks := models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers).Managed(diskResourceSCSIPaths)
 
 	configuredDisks := propertyHelper.GetDisks()
 	log.Printf("Configuration for server '%s' specifies %d disks: %#v.", serverID, len(configuredDisks), configuredDisks)
@@ -222,15 +224,9 @@ func updateDisks(data *schema.ResourceData, providerState *providerState) error
 		)
 	}
 
-	err = validateServerDisks(configuredDisks)
-	if err != nil {
-		return err
-	}
-
 	if configuredDisks.IsEmpty() {
 		// No explicitly-configured disks.
 		propertyHelper.SetDisks(actualDisks)
-		propertyHelper.SetPartial(resourceKeyServerDisk)
 
 		log.Printf("Server '%s' now has %d disks: %#v.", serverID, server.SCSIControllers.GetDiskCount(), server.SCSIControllers)
 
@@ -245,69 +241,47 @@ func updateDisks(data *schema.ResourceData, providerState *providerState) error
 		return nil
 	}
 
-	serverWasStarted := server.Started
-	if serverWasStarted {
-		log.Printf("Shutting down server '%s' ('%s') before modifying disk configuration...",
-			server.Name,
-			server.ID,
-		)
-
-		err = serverShutdown(providerState, serverID)
+	applyDiskChanges := func() error {
+		// First remove any disks that are no longer required.
+		err := processRemoveDisks(ctx, removeDisks, data, providerState)
 		if err != nil {
 			return err
 		}
 
-		log.Printf("Shutdown complete for server '%s' ('%s').",
-			server.Name,
-			server.ID,
-		)
-	}
-
-	// First remove any disks that are no longer required.
-	err = processRemoveDisks(removeDisks, data, providerState)
-	if err != nil {
-		return err
-	}
-
-	// Then modify existing disks
-	err = processModifyDisks(modifyDisks, data, providerState)
-	if err != nil {
-		return err
-	}
-
-	// Finally, add new disks
-	err = processAddDisks(addDisks, data, providerState)
-	if err != nil {
-		return err
-	}
-
-	if serverWasStarted {
-		log.Printf("Restarting server '%s' ('%s') after modifying disk configuration...",
-			server.Name,
-			server.ID,
-		)
-
-		err = serverStart(providerState, serverID)
+		// Then modify existing disks
+		err = processModifyDisks(ctx, modifyDisks, data, providerState)
 		if err != nil {
 			return err
 		}
 
-		log.Printf("Restart complete for server '%s' ('%s').",
+		// Finally, add new disks
+		return processAddDisks(ctx, addDisks, data, providerState)
+	}
+
+	if modifyDisks.RequirePowerCycle(actualDisks) {
+		log.Printf("Disk changes for server '%s' ('%s') require the server to be powered off.",
 			server.Name,
 			server.ID,
 		)
+
+		return withServerPoweredOff(ctx, providerState, serverID, applyDiskChanges)
 	}
 
-	return nil
+	return applyDiskChanges()
 }
 
 // Process the collection of disks that need to be added to the server.
-func processAddDisks(addDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
+func processAddDisks(ctx context.Context, addDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
 	propertyHelper := propertyHelper(data)
 	serverID := data.Id()
 
 	apiClient := providerState.Client()
 
+	diskResourceSCSIPaths, err := getDiskResourceSCSIPaths(apiClient, serverID)
+	if err != nil {
+		return err
+	}
+
 	for index := range addDisks {
 		addDisk := &addDisks[index]
 
@@ -315,12 +289,13 @@ func processAddDisks(addDisks models.Disks, data *schema.ResourceData, providerS
 			addDisk.SCSIUnitID,
 			serverID,
 		)
-		err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+		err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 			asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 			defer asyncLock.Release()
 
 			var addDiskError error
 
+			addDisk.Speed = normalizeSpeed(addDisk.Speed)
 			if addDisk.Speed != compute.ServerDiskSpeedProvisionedIops {
 				addDisk.Iops = 0
 			}
@@ -367,9 +342,8 @@ func processAddDisks(addDisks models.Disks, data *schema.ResourceData, providerS
 
 		server := resource.(*compute.Server)
 		propertyHelper.SetDisks(
-			models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers),
+			models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers).Managed(diskResourceSCSIPaths),
 		)
-		propertyHelper.SetPartial(resourceKeyServerDisk)
 
 		log.Printf("Server '%s' now has %d disks: %#v.", serverID, server.SCSIControllers.GetDiskCount(), server.SCSIControllers)
 
@@ -386,7 +360,7 @@ func processAddDisks(addDisks models.Disks, data *schema.ResourceData, providerS
 // Process the collection of disks whose configuration needs to be modified.
 //
 // Disk Ids must already be populated.
-func processModifyDisks(modifyDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
+func processModifyDisks(ctx context.Context, modifyDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
 	log.Printf("modifyDisks = %#v", modifyDisks)
 
 	propertyHelper := propertyHelper(data)
@@ -394,6 +368,11 @@ func processModifyDisks(modifyDisks models.Disks, data *schema.ResourceData, pro
 
 	apiClient := providerState.Client()
 
+	diskResourceSCSIPaths, err := getDiskResourceSCSIPaths(apiClient, serverID)
+	if err != nil {
+		return err
+	}
+
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
 		return err
@@ -433,7 +412,7 @@ func processModifyDisks(modifyDisks models.Disks, data *schema.ResourceData, pro
 			)
 
 			operationDescription := fmt.Sprintf("Expand disk '%s' in server '%s'", modifyDisk.ID, serverID)
-			err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+			err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 				asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 				defer asyncLock.Release()
 
@@ -477,9 +456,8 @@ func processModifyDisks(modifyDisks models.Disks, data *schema.ResourceData, pro
 
 			server := resource.(*compute.Server)
 			propertyHelper.SetDisks(
-				models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers),
+				models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers).Managed(diskResourceSCSIPaths),
 			)
-			propertyHelper.SetPartial(resourceKeyServerDisk)
 
 			log.Printf("Server '%s' now has %d disks: %#v.", serverID, server.SCSIControllers.GetDiskCount(), server.SCSIControllers)
 
@@ -506,7 +484,7 @@ func processModifyDisks(modifyDisks models.Disks, data *schema.ResourceData, pro
 			var speedErr error
 
 			operationDescription := fmt.Sprintf("Change speed of disk '%s' in server '%s'", modifyDisk.ID, serverID)
-			err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+			err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 				asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 				defer asyncLock.Release()
 
@@ -546,9 +524,8 @@ func processModifyDisks(modifyDisks models.Disks, data *schema.ResourceData, pro
 
 			server = resource.(*compute.Server)
 			propertyHelper.SetDisks(
-				models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers),
+				models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers).Managed(diskResourceSCSIPaths),
 			)
-			propertyHelper.SetPartial(resourceKeyServerDisk)
 
 			log.Printf(
 				"Change disk speed for diskID:'%s' for server:'%s' (from '%s' to '%s').",
@@ -587,9 +564,8 @@ func processModifyDisks(modifyDisks models.Disks, data *schema.ResourceData, pro
 
 			server = resource.(*compute.Server)
 			propertyHelper.SetDisks(
-				models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers),
+				models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers).Managed(diskResourceSCSIPaths),
 			)
-			propertyHelper.SetPartial(resourceKeyServerDisk)
 		}
 	}
 
@@ -599,12 +575,17 @@ func processModifyDisks(modifyDisks models.Disks, data *schema.ResourceData, pro
 // Process the collection of disks that need to be removed.
 //
 // Disk Ids must already be populated.
-func processRemoveDisks(removeDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
+func processRemoveDisks(ctx context.Context, removeDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
 	propertyHelper := propertyHelper(data)
 	serverID := data.Id()
 
 	apiClient := providerState.Client()
 
+	diskResourceSCSIPaths, err := getDiskResourceSCSIPaths(apiClient, serverID)
+	if err != nil {
+		return err
+	}
+
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
 		return err
@@ -623,7 +604,7 @@ func processRemoveDisks(removeDisks models.Disks, data *schema.ResourceData, pro
 		)
 
 		operationDescription := fmt.Sprintf("Remove disk '%s' from server '%s'", removeDisk.ID, serverID)
-		err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+		err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 			asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 			defer asyncLock.Release()
 
@@ -650,9 +631,8 @@ func processRemoveDisks(removeDisks models.Disks, data *schema.ResourceData, pro
 
 		server := resource.(*compute.Server)
 		propertyHelper.SetDisks(
-			models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers),
+			models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers).Managed(diskResourceSCSIPaths),
 		)
-		propertyHelper.SetPartial(resourceKeyServerDisk)
 
 		log.Printf(
 			"Removed disk '%s' from server '%s'.",
@@ -680,38 +660,78 @@ func hashDiskUnitID(item interface{}) int {
 	return diskData[resourceKeyServerDiskUnitID].(int)
 }
 
-func validateDisks(disks models.Disks) error {
+// Validate the disks configured for a server or storage controller.
+//
+// diskKey is the name of the attribute containing the disk configuration (used to identify the disk that failed validation).
+func validateDisks(disks models.Disks, diskKey string) (diagnostics diag.Diagnostics) {
 	if disks.IsEmpty() {
-		return nil
+		return
 	}
 
-	disksByUnitID := make(map[int]models.Disk)
-	for _, disk := range disks {
-		_, duplicate := disksByUnitID[disk.SCSIUnitID]
+	diskIndexesByUnitID := make(map[int]int)
+	for diskIndex, disk := range disks {
+		firstDiskIndex, duplicate := diskIndexesByUnitID[disk.SCSIUnitID]
 		if duplicate {
-			return fmt.Errorf("multiple disks with SCSI unit ID %d", disk.SCSIUnitID)
+			diagnostics = append(diagnostics, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("multiple disks with SCSI unit ID %d", disk.SCSIUnitID),
+				Detail:        fmt.Sprintf("%s.%d has the same SCSI unit ID as %s.%d.", diskKey, diskIndex, diskKey, firstDiskIndex),
+				AttributePath: cty.GetAttrPath(diskKey).IndexInt(diskIndex).GetAttr(resourceKeyServerDiskUnitID),
+			})
+
+			continue
 		}
 
-		disksByUnitID[disk.SCSIUnitID] = disk
+		diskIndexesByUnitID[disk.SCSIUnitID] = diskIndex
 	}
 
-	return nil
+	for diskIndex, disk := range disks {
+		err := validateDiskIops(disk)
+		if err != nil {
+			diagnostics = append(diagnostics, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       err.Error(),
+				AttributePath: cty.GetAttrPath(diskKey).IndexInt(diskIndex).GetAttr(resourceKeyServerDiskIops),
+			})
+		}
+	}
+
+	return
 }
 
-func validateServerDisks(disks models.Disks) error {
-	err := validateDisks(disks)
-	if err != nil {
-		return err
+// Validate a disk's IOPS (only applies to provisioned-IOPS disks, which must specify their IOPS).
+//
+// CloudControl enforces the range of IOPS permitted for a disk of a given size when the disk is created or modified.
+// A size of 0 means the size is not yet known (and the disk is not validated).
+func validateDiskIops(disk models.Disk) error {
+	if disk.SizeGB == 0 || normalizeSpeed(disk.Speed) != compute.ServerDiskSpeedProvisionedIops {
+		return nil
 	}
 
-	for _, disk := range disks {
+	if disk.Iops <= 0 {
+		return fmt.Errorf("a disk with speed %s must specify its IOPS", compute.ServerDiskSpeedProvisionedIops)
+	}
+
+	return nil
+}
+
+// Validate the disks configured for a server.
+func validateServerDisks(disks models.Disks) diag.Diagnostics {
+	diagnostics := validateDisks(disks, resourceKeyServerDisk)
+
+	for diskIndex, disk := range disks {
 		// Cannot target non-default SCSI controllers if disks are declared directly on the server.
 		if disk.SCSIBusNumber != 0 {
-			return fmt.Errorf("unsupported configuration: disk configured to use non-default SCSI bus %d (declare the disk on a ddcloud_storage controller if targeting a SCSI bus other than 0)", disk.SCSIBusNumber)
+			diagnostics = append(diagnostics, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("unsupported configuration: disk configured to use non-default SCSI bus %d", disk.SCSIBusNumber),
+				Detail:        "Declare the disk on a ddcloud_storage_controller if targeting a SCSI bus other than 0.",
+				AttributePath: cty.GetAttrPath(resourceKeyServerDisk).IndexInt(diskIndex).GetAttr(resourceKeyServerDiskBusNumber),
+			})
 		}
 	}
 
-	return nil
+	return diagnostics
 }
 
 func validateDiskSpeed(value interface{}, propertyName string) (messages []string, errors []error) {
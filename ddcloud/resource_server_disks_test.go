@@ -0,0 +1,74 @@
+package ddcloud
+
+import (
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
+	"github.com/hashicorp/go-cty/cty"
+)
+
+// Unit test - valid server disk configuration produces no diagnostics.
+func TestValidateServerDisksValid(test *testing.T) {
+	diagnostics := validateServerDisks(models.Disks{
+		models.Disk{SCSIBusNumber: 0, SCSIUnitID: 0, SizeGB: 10},
+		models.Disk{SCSIBusNumber: 0, SCSIUnitID: 1, SizeGB: 20},
+	})
+
+	assert.ForTest(test).EqualsInt("Diagnostics.Length", 0, len(diagnostics))
+}
+
+// Unit test - duplicate SCSI unit Id is reported against the disk that duplicates it.
+func TestValidateServerDisksDuplicateUnitID(test *testing.T) {
+	diagnostics := validateServerDisks(models.Disks{
+		models.Disk{SCSIBusNumber: 0, SCSIUnitID: 0, SizeGB: 10},
+		models.Disk{SCSIBusNumber: 0, SCSIUnitID: 1, SizeGB: 20},
+		models.Disk{SCSIBusNumber: 0, SCSIUnitID: 1, SizeGB: 30},
+	})
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Diagnostics.Length", 1, len(diagnostics))
+	assert.IsTrue("Diagnostics.HasError", diagnostics.HasError())
+
+	expectedPath := cty.GetAttrPath("disk").IndexInt(2).GetAttr("scsi_unit_id")
+	assert.IsTrue("Diagnostic.AttributePath == disk.2.scsi_unit_id", diagnostics[0].AttributePath.Equals(expectedPath))
+}
+
+// Unit test - disk declared on a non-default SCSI bus is reported against that disk's bus number.
+func TestValidateServerDisksNonDefaultBus(test *testing.T) {
+	diagnostics := validateServerDisks(models.Disks{
+		models.Disk{SCSIBusNumber: 0, SCSIUnitID: 0, SizeGB: 10},
+		models.Disk{SCSIBusNumber: 1, SCSIUnitID: 1, SizeGB: 20},
+	})
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Diagnostics.Length", 1, len(diagnostics))
+
+	expectedPath := cty.GetAttrPath("disk").IndexInt(1).GetAttr("scsi_bus_number")
+	assert.IsTrue("Diagnostic.AttributePath == disk.1.scsi_bus_number", diagnostics[0].AttributePath.Equals(expectedPath))
+}
+
+// Unit test - provisioned-IOPS disks must specify their IOPS.
+func TestValidateDiskIops(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	assert.IsTrue("Standard disk without IOPS is valid", validateDiskIops(models.Disk{SizeGB: 10, Speed: "STANDARD"}) == nil)
+	assert.IsTrue("Unknown size is not validated", validateDiskIops(models.Disk{SizeGB: 0, Speed: "PROVISIONEDIOPS"}) == nil)
+	assert.IsTrue("10 GB with 30 IOPS is valid", validateDiskIops(models.Disk{SizeGB: 10, Speed: "PROVISIONEDIOPS", Iops: 30}) == nil)
+	assert.IsTrue("10 GB with 150 IOPS is valid", validateDiskIops(models.Disk{SizeGB: 10, Speed: "provisionediops", Iops: 150}) == nil)
+	assert.IsTrue("10 GB without IOPS is invalid", validateDiskIops(models.Disk{SizeGB: 10, Speed: "PROVISIONEDIOPS"}) != nil)
+}
+
+// Unit test - missing provisioned IOPS is reported against the disk's IOPS.
+func TestValidateServerDisksProvisionedIops(test *testing.T) {
+	diagnostics := validateServerDisks(models.Disks{
+		models.Disk{SCSIBusNumber: 0, SCSIUnitID: 0, SizeGB: 10, Speed: "STANDARD"},
+		models.Disk{SCSIBusNumber: 0, SCSIUnitID: 1, SizeGB: 20, Speed: "PROVISIONEDIOPS"},
+	})
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Diagnostics.Length", 1, len(diagnostics))
+
+	expectedPath := cty.GetAttrPath("disk").IndexInt(1).GetAttr("iops")
+	assert.IsTrue("Diagnostic.AttributePath == disk.1.iops", diagnostics[0].AttributePath.Equals(expectedPath))
+}
@@ -1,109 +1,113 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sort"
-	"strings"
 
-	"strconv"
-
-	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-// Migrate state for ddcloud_server.
-func resourceServerMigrateState(schemaVersion int, instanceState *terraform.InstanceState, provider interface{}) (migratedState *terraform.InstanceState, err error) {
-	if instanceState.Empty() {
-		log.Println("Empty Server state; nothing to migrate.")
-		migratedState = instanceState
-
-		return
-	}
-
-	const currentSchemaVersion = 5
-	for schemaVersion < currentSchemaVersion {
-		switch schemaVersion {
-		case 0:
-			log.Println("Found Server state v0; migrating to v1")
-			migratedState, err = migrateServerStateV0toV1(instanceState)
-		case 1:
-			log.Println("Found Server state v1; migrating to v2")
-			migratedState, err = migrateServerStateV1toV2(instanceState)
-		case 2:
-			log.Println("Found Server state v2; migrating to v3")
-			migratedState, err = migrateServerStateV2toV3(instanceState)
-		case 3:
-			log.Println("Found Server state v3; migrating to v4")
-			migratedState, err = migrateServerStateV3toV4(instanceState)
-		case 4:
-			log.Println("[Migration]Found server state v4; migrating to v5")
-			migratedState, err = migrateServerStateV4toV5(instanceState)
-		default:
-			err = fmt.Errorf("Unexpected schema version: %d", schemaVersion)
-		}
-		if err != nil {
-			return
-		}
-
-		schemaVersion++
+// State upgraders for ddcloud_server.
+//
+// stateType is the type of the state data for the current (v5) schema.
+func resourceServerStateUpgraders(stateType cty.Type) []schema.StateUpgrader {
+	diskType := stateType.AttributeType(resourceKeyServerDisk).ElementType()
+
+	// Only the attributes whose type differs from the current schema (or that have since been removed from it) need to be specified.
+	stateTypeV4 := stateTypeWithAttributes(stateType, map[string]cty.Type{
+		resourceKeyServerPrimaryAdapterType: cty.String,
+		resourceKeyServerAutoStart:          cty.Bool,
+	})
+	stateTypeV3 := stateTypeV4
+	stateTypeV2 := stateTypeWithAttributes(stateTypeV3, map[string]cty.Type{
+		resourceKeyServerImage: cty.List(cty.Object(map[string]cty.Type{
+			"id":   cty.String,
+			"name": cty.String,
+			"type": cty.String,
+		})),
+	})
+	stateTypeV1 := stateTypeWithAttributes(stateTypeV3, map[string]cty.Type{
+		resourceKeyServerOSImageID:         cty.String,
+		resourceKeyServerOSImageName:       cty.String,
+		resourceKeyServerCustomerImageID:   cty.String,
+		resourceKeyServerCustomerImageName: cty.String,
+	})
+	stateTypeV0 := stateTypeWithAttributes(stateTypeV1, map[string]cty.Type{
+		resourceKeyServerDisk: cty.Set(diskType),
+	})
+
+	return []schema.StateUpgrader{
+		schema.StateUpgrader{
+			Version: 0,
+			Type:    stateTypeV0,
+			Upgrade: migrateServerStateV0toV1,
+		},
+		schema.StateUpgrader{
+			Version: 1,
+			Type:    stateTypeV1,
+			Upgrade: migrateServerStateV1toV2,
+		},
+		schema.StateUpgrader{
+			Version: 2,
+			Type:    stateTypeV2,
+			Upgrade: migrateServerStateV2toV3,
+		},
+		schema.StateUpgrader{
+			Version: 3,
+			Type:    stateTypeV3,
+			Upgrade: migrateServerStateV3toV4,
+		},
+		schema.StateUpgrader{
+			Version: 4,
+			Type:    stateTypeV4,
+			Upgrade: migrateServerStateV4toV5,
+		},
 	}
-
-	return
 }
 
 // Migrate state for ddcloud_server (v0 to v1).
 //
-// disk.HASH.xxx -> disk.INDEX.xxx (where INDEX is the 0-based index of the disk in the set).
-// Note that we should really be sorting disks by SCSI unit Id, but that's a little complicated for now.
-//
-// Also, os_image_id / os_image_name / customer_image_id / customer_image_name -> image, image_type
-func migrateServerStateV0toV1(instanceState *terraform.InstanceState) (migratedState *terraform.InstanceState, err error) {
-	migratedState = instanceState
-
-	// Convert disks from Set ("disk.HASH.property") to List ("disk.INDEX.property")
-	//
-	// Where INDEX is the 0-based index of the disk in the set.
-	var keys []string
-	for key := range migratedState.Attributes {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
+// disk was a Set; it is now a List (disks are ordered by SCSI bus number and SCSI unit Id).
+func migrateServerStateV0toV1(ctx context.Context, rawState map[string]interface{}, provider interface{}) (map[string]interface{}, error) {
+	log.Println("Found Server state v0; migrating to v1")
 
-	nextIndex := 0
-	diskIndexesByHash := make(map[string]int)
-	for _, key := range keys {
-		if !strings.HasPrefix(key, "disk.") {
-			continue
-		}
+	disks, _ := rawState[resourceKeyServerDisk].([]interface{})
 
-		// Should be "disk.HASH.property".
-		keyParts := strings.Split(key, ".")
-		if len(keyParts) != 3 {
-			continue
-		}
-		hash := keyParts[1]
+	var sortErr error
+	diskSortKey := func(disk interface{}) (busNumber int, unitID int) {
+		diskProperties, _ := disk.(map[string]interface{})
 
-		diskIndex, ok := diskIndexesByHash[hash]
-		if !ok {
-			nextIndex++
-			diskIndex = nextIndex
-			diskIndexesByHash[hash] = diskIndex
+		var err error
+		busNumber, err = stateValueAsInt(diskProperties[resourceKeyServerDiskBusNumber])
+		if err != nil && sortErr == nil {
+			sortErr = err
+		}
+		unitID, err = stateValueAsInt(diskProperties[resourceKeyServerDiskUnitID])
+		if err != nil && sortErr == nil {
+			sortErr = err
 		}
 
-		value := migratedState.Attributes[key]
-		delete(migratedState.Attributes, key)
+		return
+	}
+	sort.SliceStable(disks, func(index1 int, index2 int) bool {
+		busNumber1, unitID1 := diskSortKey(disks[index1])
+		busNumber2, unitID2 := diskSortKey(disks[index2])
+		if busNumber1 != busNumber2 {
+			return busNumber1 < busNumber2
+		}
 
-		// Convert to "disk.N.property"
-		keyParts[1] = strconv.Itoa(diskIndex)
-		key = strings.Join(keyParts, ".")
-		migratedState.Attributes[key] = value
+		return unitID1 < unitID2
+	})
+	if sortErr != nil {
+		return nil, fmt.Errorf("invalid disk in server state: %s", sortErr)
 	}
 
-	log.Printf("Server attributes after migration from v0 to v1: %#v",
-		migratedState.Attributes,
-	)
+	log.Printf("Server attributes after migration from v0 to v1: %#v", rawState)
 
-	return
+	return rawState, nil
 }
 
 // Migrate state for ddcloud_server (v1 to v2).
@@ -112,54 +116,39 @@ func migrateServerStateV0toV1(instanceState *terraform.InstanceState) (migratedS
 // os_image_name       = "xxx" -> image { name = "xxx", type = "os" }
 // customer_image_id   = "xxx" -> image { id   = "xxx", type = "customer" }
 // customer_image_name = "xxx" -> image { name = "xxx", type = "customer" }
-func migrateServerStateV1toV2(instanceState *terraform.InstanceState) (migratedState *terraform.InstanceState, err error) {
-	migratedState = instanceState
-
-	var (
-		osImageID         string
-		osImageName       string
-		customerImageID   string
-		customerImageName string
-	)
-	osImageID, _ = migratedState.Attributes[resourceKeyServerOSImageID]
-	delete(migratedState.Attributes, resourceKeyServerOSImageID)
-	osImageName, _ = migratedState.Attributes[resourceKeyServerOSImageName]
-	delete(migratedState.Attributes, resourceKeyServerOSImageName)
-	customerImageID, _ = migratedState.Attributes[resourceKeyServerCustomerImageID]
-	delete(migratedState.Attributes, resourceKeyServerCustomerImageID)
-	customerImageName, _ = migratedState.Attributes[resourceKeyServerCustomerImageName]
-	delete(migratedState.Attributes, resourceKeyServerCustomerImageName)
-
-	// Single-item list.
-	migratedState.Attributes[resourceKeyServerImage+".#"] = "1"
-	setImageProperty := func(propertyName string, propertyValue string) {
-		key := fmt.Sprintf("%s.0.%s",
-			resourceKeyServerImage,
-			propertyName,
-		)
-
-		migratedState.Attributes[key] = propertyValue
-	}
-
+func migrateServerStateV1toV2(ctx context.Context, rawState map[string]interface{}, provider interface{}) (map[string]interface{}, error) {
+	log.Println("Found Server state v1; migrating to v2")
+
+	osImageID := stateValueAsString(rawState[resourceKeyServerOSImageID])
+	delete(rawState, resourceKeyServerOSImageID)
+	osImageName := stateValueAsString(rawState[resourceKeyServerOSImageName])
+	delete(rawState, resourceKeyServerOSImageName)
+	customerImageID := stateValueAsString(rawState[resourceKeyServerCustomerImageID])
+	delete(rawState, resourceKeyServerCustomerImageID)
+	customerImageName := stateValueAsString(rawState[resourceKeyServerCustomerImageName])
+	delete(rawState, resourceKeyServerCustomerImageName)
+
+	image := make(map[string]interface{})
 	if osImageID != "" {
-		setImageProperty("id", osImageID)
-		setImageProperty(resourceKeyServerImageType, "os")
+		image["id"] = osImageID
+		image["type"] = "os"
 	} else if osImageName != "" {
-		setImageProperty("name", osImageName)
-		setImageProperty(resourceKeyServerImageType, "os")
+		image["name"] = osImageName
+		image["type"] = "os"
 	} else if customerImageID != "" {
-		setImageProperty("id", customerImageID)
-		setImageProperty(resourceKeyServerImageType, "customer")
+		image["id"] = customerImageID
+		image["type"] = "customer"
 	} else if customerImageName != "" {
-		setImageProperty("name", customerImageName)
-		setImageProperty(resourceKeyServerImageType, "customer")
+		image["name"] = customerImageName
+		image["type"] = "customer"
 	}
 
-	log.Printf("Server attributes after migration from v1 to v2: %#v",
-		migratedState.Attributes,
-	)
+	// Single-item list.
+	rawState[resourceKeyServerImage] = []interface{}{image}
 
-	return
+	log.Printf("Server attributes after migration from v1 to v2: %#v", rawState)
+
+	return rawState, nil
 }
 
 // Migrate state for ddcloud_server (v2 to v3).
@@ -168,44 +157,32 @@ func migrateServerStateV1toV2(instanceState *terraform.InstanceState) (migratedS
 // image { name = "xxx", type = "os" }       -> image = "xxx"
 // image { id   = "xxx", type = "customer" } -> image = "xxx", image_type = "customer"
 // image { name = "xxx", type = "customer" } -> image = "xxx", image_type = "customer"
-func migrateServerStateV2toV3(instanceState *terraform.InstanceState) (migratedState *terraform.InstanceState, err error) {
-	migratedState = instanceState
+func migrateServerStateV2toV3(ctx context.Context, rawState map[string]interface{}, provider interface{}) (map[string]interface{}, error) {
+	log.Println("Found Server state v2; migrating to v3")
 
-	const keyPrefix = "image."
 	var image, imageType string
-	for key := range migratedState.Attributes {
-		if !strings.HasPrefix(key, keyPrefix) {
-			continue
-		}
+	images, _ := rawState[resourceKeyServerImage].([]interface{})
+	if len(images) > 0 {
+		imageProperties, _ := images[0].(map[string]interface{})
 
-		value := migratedState.Attributes[key]
-		delete(migratedState.Attributes, key)
-
-		if key == keyPrefix+"#" { // "image.#"
-			continue // Count - nothing else to do.
-		}
-
-		switch key[len(keyPrefix+"0")+1:] { // "image.0."
-		case "id":
-		case "name":
-			image = value
-		case resourceKeyServerImageType:
-			imageType = value
+		image = stateValueAsString(imageProperties["id"])
+		if image == "" {
+			image = stateValueAsString(imageProperties["name"])
 		}
+		imageType = stateValueAsString(imageProperties["type"])
 	}
+	delete(rawState, resourceKeyServerImage)
 
 	if image != "" {
-		migratedState.Attributes[resourceKeyServerImage] = image
-		if imageType == "customer" {
-			migratedState.Attributes[resourceKeyServerImageType] = "customer"
+		rawState[resourceKeyServerImage] = image
+		if imageType == serverImageTypeCustomer {
+			rawState[resourceKeyServerImageType] = serverImageTypeCustomer
 		}
 	}
 
-	log.Printf("Server attributes after migration from v2 to v3: %#v",
-		migratedState.Attributes,
-	)
+	log.Printf("Server attributes after migration from v2 to v3: %#v", rawState)
 
-	return
+	return rawState, nil
 }
 
 // Migrate state for ddcloud_server (v3 to v4).
@@ -216,70 +193,56 @@ func migrateServerStateV2toV3(instanceState *terraform.InstanceState) (migratedS
 // primary_adapter_type = "zzz"
 //
 // To:
-// primary_network_adapter {
-//     ipv4 = "xxx"
-//     vlan = "yyy"
-//     type = "zzz"
-// }
-func migrateServerStateV3toV4(instanceState *terraform.InstanceState) (migratedState *terraform.InstanceState, err error) {
-	setPrimaryAdapterProperty := func(key string, value string) {
-		if key != "#" { // Count
-			key = "0." + key // First element of list
-		}
-		migratedState.Attributes[resourceKeyServerPrimaryNetworkAdapter+"."+key] = value
-	}
+//
+//	primary_network_adapter {
+//	    ipv4 = "xxx"
+//	    vlan = "yyy"
+//	    type = "zzz"
+//	}
+func migrateServerStateV3toV4(ctx context.Context, rawState map[string]interface{}, provider interface{}) (map[string]interface{}, error) {
+	log.Println("Found Server state v3; migrating to v4")
+
+	primaryAdapterIPv4 := stateValueAsString(rawState[resourceKeyServerPrimaryAdapterIPv4])
+	primaryAdapterVLAN := stateValueAsString(rawState[resourceKeyServerPrimaryAdapterVLAN])
+	primaryAdapterType := stateValueAsString(rawState[resourceKeyServerPrimaryAdapterType])
 
-	migratedState = instanceState
-
-	var primaryAdapterIPv4, primaryAdapterVLAN, primaryAdapterType string
-	for key := range migratedState.Attributes {
-		switch key {
-		case resourceKeyServerPrimaryAdapterIPv4:
-			primaryAdapterIPv4 = migratedState.Attributes[key]
-		case resourceKeyServerPrimaryAdapterVLAN:
-			primaryAdapterVLAN = migratedState.Attributes[key]
-		case resourceKeyServerPrimaryAdapterType:
-			primaryAdapterType = migratedState.Attributes[key]
+	if primaryAdapterIPv4 != "" || primaryAdapterVLAN != "" || primaryAdapterType != "" {
+		primaryNetworkAdapter := make(map[string]interface{})
+		if primaryAdapterIPv4 != "" {
+			primaryNetworkAdapter[resourceKeyServerNetworkAdapterIPV4] = primaryAdapterIPv4
+		}
+		if primaryAdapterVLAN != "" {
+			primaryNetworkAdapter[resourceKeyServerNetworkAdapterVLANID] = primaryAdapterVLAN
+		}
+		if primaryAdapterType != "" {
+			primaryNetworkAdapter[resourceKeyServerNetworkAdapterType] = primaryAdapterType
 		}
-	}
 
-	if primaryAdapterIPv4 != "" || primaryAdapterVLAN != "" || primaryAdapterType != "" {
-		setPrimaryAdapterProperty("#", "1")
-	}
-	if primaryAdapterIPv4 != "" {
-		setPrimaryAdapterProperty(resourceKeyServerPrimaryAdapterIPv4, primaryAdapterIPv4)
-	}
-	if primaryAdapterVLAN != "" {
-		setPrimaryAdapterProperty(resourceKeyServerPrimaryAdapterVLAN, primaryAdapterVLAN)
-	}
-	if primaryAdapterType != "" {
-		setPrimaryAdapterProperty(resourceKeyServerPrimaryAdapterType, primaryAdapterType)
+		// Single-item list.
+		rawState[resourceKeyServerPrimaryNetworkAdapter] = []interface{}{primaryNetworkAdapter}
 	}
 
-	log.Printf("Server attributes after migration from v3 to v4: %#v",
-		migratedState.Attributes,
-	)
+	log.Printf("Server attributes after migration from v3 to v4: %#v", rawState)
 
-	return
+	return rawState, nil
 }
 
-// Migrate state for ddcloud_server (v3 to v4).
+// Migrate state for ddcloud_server (v4 to v5).
 //
 // From:
 // auto_start: true/false
 //
 // To:
 // power_state: disabled/autostart/start/shutdown/shutdown-hard
-func migrateServerStateV4toV5(instanceState *terraform.InstanceState) (migratedState *terraform.InstanceState, err error) {
-	migratedState = instanceState
+func migrateServerStateV4toV5(ctx context.Context, rawState map[string]interface{}, provider interface{}) (map[string]interface{}, error) {
+	log.Println("Found Server state v4; migrating to v5")
 
-	autoStart := migratedState.Attributes[resourceKeyServerAutoStart]
-	if autoStart == "true" {
-		migratedState.Attributes[resourceKeyServerPowerState] = "autostart"
+	if stateValueAsBool(rawState[resourceKeyServerAutoStart]) {
+		rawState[resourceKeyServerPowerState] = "autostart"
 	}
-	delete(migratedState.Attributes, resourceKeyServerAutoStart)
+	delete(rawState, resourceKeyServerAutoStart)
 
-	log.Printf("[Migration] Server attributes after migratation from v4 to v5: %#v", migratedState.Attributes)
+	log.Printf("Server attributes after migration from v4 to v5: %#v", rawState)
 
-	return
+	return rawState, nil
 }
@@ -0,0 +1,237 @@
+package ddcloud
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Unit test - every resource with a schema version greater than 0 has a state upgrader for each previous schema version.
+func TestResourceStateUpgraders(test *testing.T) {
+	provider := Provider()
+
+	for resourceType, resource := range provider.ResourcesMap {
+		if resource.MigrateState != nil {
+			test.Errorf("Resource type '%s' uses MigrateState (use StateUpgraders instead).", resourceType)
+		}
+
+		if len(resource.StateUpgraders) != resource.SchemaVersion {
+			test.Errorf("Resource type '%s' has schema version %d but %d state upgrader(s).",
+				resourceType, resource.SchemaVersion, len(resource.StateUpgraders),
+			)
+
+			continue
+		}
+		for index, upgrader := range resource.StateUpgraders {
+			if upgrader.Version != index {
+				test.Errorf("Resource type '%s' has state upgrader for version %d at index %d.",
+					resourceType, upgrader.Version, index,
+				)
+			}
+		}
+	}
+}
+
+// Unit test - migrate ddcloud_server state from v0 to v1 (disks are ordered by SCSI bus and unit).
+func TestServerMigrateStateV0toV1(test *testing.T) {
+	rawState := testReadRawState(test, `{
+		"id": "server-1",
+		"disk": [
+			{ "scsi_bus_number": 0, "scsi_unit_id": 2, "size_gb": 20 },
+			{ "scsi_bus_number": 1, "scsi_unit_id": 0, "size_gb": 30 },
+			{ "scsi_bus_number": 0, "scsi_unit_id": 0, "size_gb": 10 }
+		]
+	}`)
+
+	migratedState, err := migrateServerStateV0toV1(context.Background(), rawState, nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	disks := migratedState[resourceKeyServerDisk].([]interface{})
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Disks.Length", 3, len(disks))
+	for index, expectedSizeGB := range []int{10, 20, 30} {
+		sizeGB, err := stateValueAsInt(disks[index].(map[string]interface{})[resourceKeyServerDiskSizeGB])
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		assert.EqualsInt("Disk.SizeGB", expectedSizeGB, sizeGB)
+	}
+}
+
+// Unit test - migrate ddcloud_server state from v1 to v2 (customer image name).
+func TestServerMigrateStateV1toV2CustomerImage(test *testing.T) {
+	rawState := testReadRawState(test, `{
+		"id": "server-1",
+		"customer_image_name": "my-image"
+	}`)
+
+	migratedState, err := migrateServerStateV1toV2(context.Background(), rawState, nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	images := migratedState[resourceKeyServerImage].([]interface{})
+	image := images[0].(map[string]interface{})
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Images.Length", 1, len(images))
+	assert.EqualsString("Image.Name", "my-image", image["name"].(string))
+	assert.EqualsString("Image.Type", "customer", image["type"].(string))
+	assert.IsTrue("CustomerImageName == nil", migratedState[resourceKeyServerCustomerImageName] == nil)
+}
+
+// Unit test - migrate ddcloud_server state from v1 to v2 (OS image Id takes precedence).
+func TestServerMigrateStateV1toV2OSImage(test *testing.T) {
+	rawState := testReadRawState(test, `{
+		"id": "server-1",
+		"os_image_id": "image-1",
+		"os_image_name": "CentOS 7 64-bit 2 CPU"
+	}`)
+
+	migratedState, err := migrateServerStateV1toV2(context.Background(), rawState, nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	image := migratedState[resourceKeyServerImage].([]interface{})[0].(map[string]interface{})
+
+	assert := assert.ForTest(test)
+	assert.EqualsString("Image.ID", "image-1", image["id"].(string))
+	assert.EqualsString("Image.Type", "os", image["type"].(string))
+	assert.IsTrue("Image.Name == nil", image["name"] == nil)
+	assert.IsTrue("OSImageID == nil", migratedState[resourceKeyServerOSImageID] == nil)
+	assert.IsTrue("OSImageName == nil", migratedState[resourceKeyServerOSImageName] == nil)
+}
+
+// Unit test - migrate ddcloud_server state from v2 to v3.
+func TestServerMigrateStateV2toV3(test *testing.T) {
+	rawState := testReadRawState(test, `{
+		"id": "server-1",
+		"image": [
+			{ "id": null, "name": "my-image", "type": "customer" }
+		]
+	}`)
+
+	migratedState, err := migrateServerStateV2toV3(context.Background(), rawState, nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	assert := assert.ForTest(test)
+	assert.EqualsString("Image", "my-image", migratedState[resourceKeyServerImage].(string))
+	assert.EqualsString("ImageType", "customer", migratedState[resourceKeyServerImageType].(string))
+}
+
+// Unit test - migrate ddcloud_server state from v2 to v3 (no image).
+func TestServerMigrateStateV2toV3NoImage(test *testing.T) {
+	rawState := testReadRawState(test, `{
+		"id": "server-1",
+		"image": [ {} ]
+	}`)
+
+	migratedState, err := migrateServerStateV2toV3(context.Background(), rawState, nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("Image == nil", migratedState[resourceKeyServerImage] == nil)
+	assert.IsTrue("ImageType == nil", migratedState[resourceKeyServerImageType] == nil)
+}
+
+// Unit test - migrate ddcloud_server state from v3 to v4.
+func TestServerMigrateStateV3toV4(test *testing.T) {
+	rawState := testReadRawState(test, `{
+		"id": "server-1",
+		"primary_adapter_ipv4": "10.0.1.20",
+		"primary_adapter_vlan": "vlan-1"
+	}`)
+
+	migratedState, err := migrateServerStateV3toV4(context.Background(), rawState, nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	primaryNetworkAdapters := migratedState[resourceKeyServerPrimaryNetworkAdapter].([]interface{})
+	primaryNetworkAdapter := primaryNetworkAdapters[0].(map[string]interface{})
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("PrimaryNetworkAdapters.Length", 1, len(primaryNetworkAdapters))
+	assert.EqualsString("PrimaryNetworkAdapter.IPv4", "10.0.1.20", primaryNetworkAdapter[resourceKeyServerNetworkAdapterIPV4].(string))
+	assert.EqualsString("PrimaryNetworkAdapter.VLAN", "vlan-1", primaryNetworkAdapter[resourceKeyServerNetworkAdapterVLANID].(string))
+	assert.IsTrue("PrimaryNetworkAdapter.Type == nil", primaryNetworkAdapter[resourceKeyServerNetworkAdapterType] == nil)
+}
+
+// Unit test - migrate ddcloud_server state from v4 to v5.
+func TestServerMigrateStateV4toV5(test *testing.T) {
+	rawState := testReadRawState(test, `{
+		"id": "server-1",
+		"auto_start": true
+	}`)
+
+	migratedState, err := migrateServerStateV4toV5(context.Background(), rawState, nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	assert := assert.ForTest(test)
+	assert.EqualsString("PowerState", "autostart", migratedState[resourceKeyServerPowerState].(string))
+	assert.IsTrue("AutoStart == nil", migratedState[resourceKeyServerAutoStart] == nil)
+}
+
+// Unit test - migrate ddcloud_server state from v4 to v5 (auto-start disabled).
+func TestServerMigrateStateV4toV5AutoStartDisabled(test *testing.T) {
+	rawState := testReadRawState(test, `{
+		"id": "server-1",
+		"auto_start": false
+	}`)
+
+	migratedState, err := migrateServerStateV4toV5(context.Background(), rawState, nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("PowerState == nil", migratedState[resourceKeyServerPowerState] == nil)
+	assert.IsTrue("AutoStart == nil", migratedState[resourceKeyServerAutoStart] == nil)
+}
+
+// Unit test - migrate ddcloud_server_backup and ddcloud_storage_controller state from v0 to v1.
+func TestSchemaVersion1MigrateStateV0toV1(test *testing.T) {
+	resources := map[string]*schema.Resource{
+		"ddcloud_server_backup":      resourceServerBackup(),
+		"ddcloud_storage_controller": resourceStorageController(),
+	}
+	for resourceType, resource := range resources {
+		rawState := testReadRawState(test, `{
+			"id": "resource-1",
+			"server": "server-1"
+		}`)
+
+		migratedState, err := resource.StateUpgraders[0].Upgrade(context.Background(), rawState, nil)
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		assert := assert.ForTest(test)
+		assert.EqualsString(resourceType+".ID", "resource-1", migratedState["id"].(string))
+		assert.EqualsString(resourceType+".Server", "server-1", migratedState["server"].(string))
+	}
+}
+
+func testReadRawState(test *testing.T, rawStateJSON string) map[string]interface{} {
+	var rawState map[string]interface{}
+	err := json.Unmarshal([]byte(rawStateJSON), &rawState)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	return rawState
+}
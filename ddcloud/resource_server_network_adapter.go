@@ -1,13 +1,14 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -94,13 +95,13 @@ func schemaServerNetworkAdapter(isPrimary bool) *schema.Schema {
 	}
 }
 
-func addServerNetworkAdapter(providerState *providerState, serverID string, networkAdapter *models.NetworkAdapter) error {
+func addServerNetworkAdapter(ctx context.Context, providerState *providerState, serverID string, networkAdapter *models.NetworkAdapter) error {
 	log.Printf("Add network adapter to server '%s'", serverID)
 
 	apiClient := providerState.Client()
 
 	operationDescription := fmt.Sprintf("Add network adapter to server '%s'", serverID)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -147,13 +148,13 @@ func addServerNetworkAdapter(providerState *providerState, serverID string, netw
 	return nil
 }
 
-func modifyServerNetworkAdapterIP(providerState *providerState, serverID string, networkAdapter models.NetworkAdapter) error {
+func modifyServerNetworkAdapterIP(ctx context.Context, providerState *providerState, serverID string, networkAdapter models.NetworkAdapter) error {
 	log.Printf("Update IP address(es) for network adapter '%s'.", networkAdapter.ID)
 
 	apiClient := providerState.Client()
 
 	operationDescription := fmt.Sprintf("Update IP address info for network adapter '%s'", networkAdapter.ID)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 		log.Printf("[DD] resource_server_network > modifyServerNetworkAdapterIP - Updating NIC id:%s ipv4:%s ipv6:%s",
@@ -186,7 +187,7 @@ func modifyServerNetworkAdapterIP(providerState *providerState, serverID string,
 	return err
 }
 
-func modifyServerNetworkAdapterType(providerState *providerState, serverID string, networkAdapter models.NetworkAdapter) error {
+func modifyServerNetworkAdapterType(ctx context.Context, providerState *providerState, serverID string, networkAdapter models.NetworkAdapter) error {
 	log.Printf("[DD] Change type of network adapter '%s' to '%s'.",
 		networkAdapter.ID,
 		networkAdapter.AdapterType,
@@ -195,7 +196,7 @@ func modifyServerNetworkAdapterType(providerState *providerState, serverID strin
 	apiClient := providerState.Client()
 
 	operationDescription := fmt.Sprintf("Change type of network adapter '%s'", networkAdapter.ID)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -222,14 +223,14 @@ func modifyServerNetworkAdapterType(providerState *providerState, serverID strin
 	return err
 }
 
-func removeServerNetworkAdapter(providerState *providerState, serverID string, networkAdapter *models.NetworkAdapter) error {
+func removeServerNetworkAdapter(ctx context.Context, providerState *providerState, serverID string, networkAdapter *models.NetworkAdapter) error {
 	log.Printf("Remove network adapter '%s'.", networkAdapter.ID)
 
 	apiClient := providerState.Client()
 
 	removingAdapter := true
 	operationDescription := fmt.Sprintf("Remove network adapter '%s'", networkAdapter.ID)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -0,0 +1,321 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	resourceKeyServerSnapshotServerID         = "server"
+	resourceKeyServerSnapshotType             = "type"
+	resourceKeyServerSnapshotState            = "state"
+	resourceKeyServerSnapshotStartTime        = "start_time"
+	resourceKeyServerSnapshotExpiryTime       = "expiry_time"
+	resourceKeyServerSnapshotConsistencyLevel = "consistency_level"
+	resourceCreateTimeoutServerSnapshot       = 30 * time.Minute
+	resourceDeleteTimeoutServerSnapshot       = 10 * time.Minute
+	serverSnapshotPollInterval                = 5 * time.Second
+)
+
+/*
+ * Snapshots are managed through CloudControl's snapshot/* operations (via invokeCloudControlAPI); their JSON representation is modelled below.
+ */
+
+// A server snapshot.
+type serverSnapshot struct {
+	ID               string `json:"id"`
+	ServerID         string `json:"serverId"`
+	Type             string `json:"type"`
+	State            string `json:"state"`
+	StartTime        string `json:"startTime"`
+	ExpiryTime       string `json:"expiryTime"`
+	ConsistencyLevel string `json:"consistencyLevel"`
+}
+
+// A page of server snapshots.
+type serverSnapshotPage struct {
+	Snapshots  []serverSnapshot `json:"snapshot"`
+	PageNumber int              `json:"pageNumber"`
+	PageCount  int              `json:"pageCount"`
+	TotalCount int              `json:"totalCount"`
+	PageSize   int              `json:"pageSize"`
+}
+
+func resourceServerSnapshot() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerSnapshotCreate,
+		ReadContext:   resourceServerSnapshotRead,
+		DeleteContext: resourceServerSnapshotDelete,
+
+		Schema: map[string]*schema.Schema{
+			resourceKeyServerSnapshotServerID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The Id of the server to snapshot (the server's snapshot service must be enabled)",
+			},
+			resourceKeyServerSnapshotType: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The snapshot type (MANUAL or SYSTEM)",
+			},
+			resourceKeyServerSnapshotState: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The snapshot's current state",
+			},
+			resourceKeyServerSnapshotStartTime: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date / time when the snapshot was started",
+			},
+			resourceKeyServerSnapshotExpiryTime: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date / time when the snapshot will expire (as determined by the server's snapshot service plan)",
+			},
+			resourceKeyServerSnapshotConsistencyLevel: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The snapshot's consistency level (e.g. CRASH_CONSISTENT)",
+			},
+		},
+	}
+}
+
+// Create a ddcloud_server_snapshot resource (take a manual snapshot of the server).
+func resourceServerSnapshotCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	serverID := data.Get(resourceKeyServerSnapshotServerID).(string)
+
+	log.Printf("Create manual snapshot of server '%s'.", serverID)
+
+	providerState := provider.(*providerState)
+
+	apiResponse, err := invokeServerSnapshotOperation(ctx, providerState,
+		fmt.Sprintf("Initiate manual snapshot of server '%s'", serverID),
+		"snapshot/initiateManualSnapshot",
+		map[string]interface{}{
+			"serverId": serverID,
+		},
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	snapshotID := apiResponse.GetFieldMessage("snapshotId")
+	if snapshotID == nil {
+		return diag.FromErr(
+			apiResponse.ToError("Received an unexpected response (missing 'snapshotId') with status code '%s': %s", apiResponse.ResponseCode, apiResponse.Message),
+		)
+	}
+	data.SetId(*snapshotID)
+
+	log.Printf("Manual snapshot '%s' of server '%s' is being created...", *snapshotID, serverID)
+
+	snapshot, err := waitForServerSnapshot(ctx, providerState, *snapshotID, false, resourceCreateTimeoutServerSnapshot)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Created manual snapshot '%s' of server '%s'.", snapshot.ID, serverID)
+
+	setServerSnapshotProperties(data, snapshot)
+
+	return nil
+}
+
+// Read a ddcloud_server_snapshot resource.
+func resourceServerSnapshotRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+
+	log.Printf("Read server snapshot '%s'.", id)
+
+	snapshot, err := getServerSnapshot(ctx, provider.(*providerState), id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if snapshot == nil {
+		log.Printf("Server snapshot '%s' has been deleted (or has expired).", id)
+
+		// Mark as deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	setServerSnapshotProperties(data, snapshot)
+
+	return nil
+}
+
+// Delete a ddcloud_server_snapshot resource.
+func resourceServerSnapshotDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
+	id := data.Id()
+	serverID := data.Get(resourceKeyServerSnapshotServerID).(string)
+
+	log.Printf("Delete manual snapshot '%s' of server '%s'.", id, serverID)
+
+	providerState := provider.(*providerState)
+
+	snapshot, err := getServerSnapshot(ctx, providerState, id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if snapshot == nil {
+		log.Printf("Server snapshot '%s' has already been deleted (or has expired).", id)
+
+		return nil
+	}
+
+	_, err = invokeServerSnapshotOperation(ctx, providerState,
+		fmt.Sprintf("Delete manual snapshot '%s' of server '%s'", id, serverID),
+		"snapshot/deleteManualSnapshot",
+		map[string]interface{}{
+			"id": id,
+		},
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_, err = waitForServerSnapshot(ctx, providerState, id, true, resourceDeleteTimeoutServerSnapshot)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("Deleted manual snapshot '%s' of server '%s'.", id, serverID)
+
+	return nil
+}
+
+func setServerSnapshotProperties(data *schema.ResourceData, snapshot *serverSnapshot) {
+	data.Set(resourceKeyServerSnapshotServerID, snapshot.ServerID)
+	data.Set(resourceKeyServerSnapshotType, snapshot.Type)
+	data.Set(resourceKeyServerSnapshotState, snapshot.State)
+	data.Set(resourceKeyServerSnapshotStartTime, snapshot.StartTime)
+	data.Set(resourceKeyServerSnapshotExpiryTime, snapshot.ExpiryTime)
+	data.Set(resourceKeyServerSnapshotConsistencyLevel, snapshot.ConsistencyLevel)
+}
+
+// Retrieve a server snapshot by Id.
+//
+// Returns nil if the snapshot was not found.
+func getServerSnapshot(ctx context.Context, providerState *providerState, id string) (*serverSnapshot, error) {
+	snapshot := &serverSnapshot{}
+	found, err := readCloudControlAPI(ctx, providerState, "snapshot/snapshot/"+url.PathEscape(id), snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	return snapshot, nil
+}
+
+// List the snapshots of a server (most recent first).
+func listServerSnapshots(ctx context.Context, providerState *providerState, serverID string) ([]serverSnapshot, error) {
+	var snapshots []serverSnapshot
+
+	page := compute.DefaultPaging()
+	for {
+		query := url.Values{}
+		query.Set("serverId", serverID)
+		query.Set("pageNumber", fmt.Sprintf("%d", page.PageNumber))
+		query.Set("pageSize", fmt.Sprintf("%d", page.PageSize))
+
+		snapshotPage := &serverSnapshotPage{}
+		_, err := readCloudControlAPI(ctx, providerState, "snapshot/snapshot?"+query.Encode(), snapshotPage)
+		if err != nil {
+			return nil, err
+		}
+		if snapshotPage.PageCount == 0 {
+			break
+		}
+
+		snapshots = append(snapshots, snapshotPage.Snapshots...)
+
+		page.Next()
+	}
+
+	// Start times are ISO 8601 (UTC), so they sort lexically.
+	sort.SliceStable(snapshots, func(index1 int, index2 int) bool {
+		return snapshots[index1].StartTime > snapshots[index2].StartTime
+	})
+
+	return snapshots, nil
+}
+
+// Wait for a server snapshot to become ready (or, if isDelete is true, to be deleted).
+func waitForServerSnapshot(ctx context.Context, providerState *providerState, id string, isDelete bool, timeout time.Duration) (*serverSnapshot, error) {
+	waitTimeout := time.NewTimer(timeout)
+	defer waitTimeout.Stop()
+
+	for {
+		snapshot, err := getServerSnapshot(ctx, providerState, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if snapshot == nil {
+			if isDelete {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("no snapshot was found with Id '%s'", id)
+		}
+
+		switch snapshot.State {
+		case compute.ResourceStatusNormal:
+			if !isDelete {
+				return snapshot, nil
+			}
+		case compute.ResourceStatusPendingAdd, compute.ResourceStatusPendingChange, compute.ResourceStatusPendingDelete:
+			log.Printf("Snapshot '%s' is still in state '%s'...", id, snapshot.State)
+		default:
+			return nil, fmt.Errorf("snapshot '%s' of server '%s' encountered unexpected state '%s'", id, snapshot.ServerID, snapshot.State)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-waitTimeout.C:
+			return nil, fmt.Errorf("timed out after waiting %d seconds for snapshot '%s'", timeout/time.Second, id)
+		case <-time.After(serverSnapshotPollInterval):
+		}
+	}
+}
+
+// Invoke a snapshot-related CloudControl API operation, retrying if CloudControl reports that the resource is busy.
+//
+// The operation is expected to return either OK or IN_PROGRESS.
+func invokeServerSnapshotOperation(ctx context.Context, providerState *providerState, operationDescription string, relativeURI string, requestBody interface{}) (apiResponse *compute.APIResponseV2, err error) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release()
+
+		var invokeError error
+		apiResponse, invokeError = invokeCloudControlAPI(ctx, providerState, http.MethodPost, relativeURI, requestBody)
+		if invokeError != nil {
+			context.Fail(invokeError)
+		} else if apiResponse.ResponseCode == compute.ResponseCodeResourceBusy {
+			context.Retry()
+		} else if apiResponse.ResponseCode != compute.ResponseCodeOK && apiResponse.ResponseCode != compute.ResponseCodeInProgress {
+			context.Fail(
+				apiResponse.ToError("%s failed (response code '%s'): %s", operationDescription, apiResponse.ResponseCode, apiResponse.Message),
+			)
+		}
+	})
+
+	return
+}
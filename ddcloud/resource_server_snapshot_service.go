@@ -0,0 +1,235 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	resourceKeyServerSnapshotService         = "snapshot_service"
+	resourceKeyServerSnapshotServicePlan     = "service_plan"
+	resourceKeyServerSnapshotServiceWindowID = "window_id"
+	resourceKeyServerRestoreFromSnapshot     = "restore_from_snapshot"
+)
+
+// The snapshot service configuration for a server (as represented in the server's API representation).
+type serverSnapshotService struct {
+	ServicePlan string `json:"servicePlan"`
+	State       string `json:"state"`
+}
+
+func schemaServerSnapshotService() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "The server's snapshot service configuration (if not specified, the snapshot service is disabled)",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				resourceKeyServerSnapshotServicePlan: &schema.Schema{
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The snapshot service plan (e.g. ONE_MONTH)",
+				},
+				resourceKeyServerSnapshotServiceWindowID: &schema.Schema{
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The Id of the snapshot window (for the service plan, in the server's datacenter) during which system snapshots are taken",
+				},
+			},
+		},
+	}
+}
+
+func schemaServerRestoreFromSnapshot() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "The Id of a snapshot from which the server will be restored when it is created (instead of deploying it from its image)",
+	}
+}
+
+// Read a server's snapshot service configuration into its resource data.
+func readServerSnapshotService(ctx context.Context, data *schema.ResourceData, providerState *providerState) error {
+	serverID := data.Id()
+
+	snapshotService, err := getServerSnapshotService(ctx, providerState, serverID)
+	if err != nil {
+		return err
+	}
+	if snapshotService == nil {
+		return data.Set(resourceKeyServerSnapshotService, nil)
+	}
+
+	// CloudControl reports the window's day and hour rather than its Id, so the configured window Id is retained.
+	_, windowID := getConfiguredServerSnapshotService(data)
+
+	return data.Set(resourceKeyServerSnapshotService, []interface{}{
+		map[string]interface{}{
+			resourceKeyServerSnapshotServicePlan:     snapshotService.ServicePlan,
+			resourceKeyServerSnapshotServiceWindowID: windowID,
+		},
+	})
+}
+
+// Enable, change, or disable a server's snapshot service to match its resource configuration.
+func updateServerSnapshotService(ctx context.Context, data *schema.ResourceData, providerState *providerState) error {
+	serverID := data.Id()
+
+	actualSnapshotService, err := getServerSnapshotService(ctx, providerState, serverID)
+	if err != nil {
+		return err
+	}
+
+	servicePlan, windowID := getConfiguredServerSnapshotService(data)
+
+	var operationDescription, relativeURI string
+	var requestBody map[string]interface{}
+	switch {
+	case servicePlan == "" && actualSnapshotService == nil:
+		return nil
+
+	case servicePlan == "":
+		operationDescription = fmt.Sprintf("Disable snapshot service for server '%s'", serverID)
+		relativeURI = "snapshot/disableSnapshotService"
+		requestBody = map[string]interface{}{
+			"serverId": serverID,
+		}
+
+	case actualSnapshotService == nil:
+		operationDescription = fmt.Sprintf("Enable snapshot service (plan '%s') for server '%s'", servicePlan, serverID)
+		relativeURI = "snapshot/enableSnapshotService"
+		requestBody = map[string]interface{}{
+			"serverId":    serverID,
+			"servicePlan": servicePlan,
+			"windowId":    windowID,
+		}
+
+	case actualSnapshotService.ServicePlan != servicePlan || data.HasChange(resourceKeyServerSnapshotService):
+		operationDescription = fmt.Sprintf("Change snapshot service plan to '%s' for server '%s'", servicePlan, serverID)
+		relativeURI = "snapshot/changeSnapshotServicePlan"
+		requestBody = map[string]interface{}{
+			"serverId":    serverID,
+			"servicePlan": servicePlan,
+			"windowId":    windowID,
+		}
+
+	default:
+		return nil
+	}
+
+	apiResponse, err := invokeServerSnapshotOperation(ctx, providerState, operationDescription, relativeURI, requestBody)
+	if err != nil {
+		return err
+	}
+	if apiResponse.ResponseCode == compute.ResponseCodeInProgress {
+		_, err = providerState.Client().WaitForChange(compute.ResourceTypeServer, serverID, "Update snapshot service", resourceUpdateTimeoutServer)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("%s: done.", operationDescription)
+
+	return nil
+}
+
+// Create a server by restoring a snapshot.
+//
+// CloudControl restores a snapshot by creating a preview server from it; the preview server is then migrated to become a regular server.
+func restoreServerFromSnapshot(ctx context.Context, data *schema.ResourceData, providerState *providerState) error {
+	snapshotID := data.Get(resourceKeyServerRestoreFromSnapshot).(string)
+	name := data.Get(resourceKeyServerName).(string)
+	description := data.Get(resourceKeyServerDescription).(string)
+	powerState := strings.ToLower(data.Get(resourceKeyServerPowerState).(string))
+
+	log.Printf("Create server '%s' by restoring snapshot '%s'.", name, snapshotID)
+
+	apiClient := providerState.Client()
+
+	apiResponse, err := invokeServerSnapshotOperation(ctx, providerState,
+		fmt.Sprintf("Restore snapshot '%s' to server '%s'", snapshotID, name),
+		"snapshot/createSnapshotPreviewServer",
+		map[string]interface{}{
+			"snapshotId":           snapshotID,
+			"serverName":           name,
+			"serverDescription":    description,
+			"serverStarted":        powerState == "start" || powerState == "autostart",
+			"nicsConnected":        true,
+			"preserveMacAddresses": false,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	serverID := apiResponse.GetFieldMessage("serverId")
+	if serverID == nil {
+		return apiResponse.ToError("Received an unexpected response (missing 'serverId') with status code '%s': %s", apiResponse.ResponseCode, apiResponse.Message)
+	}
+	data.SetId(*serverID)
+
+	log.Printf("Server '%s' is being restored from snapshot '%s'...", *serverID, snapshotID)
+
+	_, err = apiClient.WaitForDeploy(compute.ResourceTypeServer, *serverID, resourceCreateTimeoutServer)
+	if err != nil {
+		return err
+	}
+
+	apiResponse, err = invokeServerSnapshotOperation(ctx, providerState,
+		fmt.Sprintf("Migrate snapshot preview server '%s'", *serverID),
+		"snapshot/migrateSnapshotPreviewServer",
+		map[string]interface{}{
+			"serverId": *serverID,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if apiResponse.ResponseCode == compute.ResponseCodeInProgress {
+		_, err = apiClient.WaitForChange(compute.ResourceTypeServer, *serverID, "Migrate snapshot preview server", resourceCreateTimeoutServer)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Restored server '%s' from snapshot '%s'.", *serverID, snapshotID)
+
+	return applyTags(data, apiClient, compute.AssetTypeServer, providerState.Settings())
+}
+
+// Get the snapshot service plan and window Id configured for a server (empty if the snapshot service is not configured).
+func getConfiguredServerSnapshotService(data *schema.ResourceData) (servicePlan string, windowID string) {
+	snapshotServices := data.Get(resourceKeyServerSnapshotService).([]interface{})
+	if len(snapshotServices) == 0 || snapshotServices[0] == nil {
+		return
+	}
+
+	snapshotService := snapshotServices[0].(map[string]interface{})
+	servicePlan = snapshotService[resourceKeyServerSnapshotServicePlan].(string)
+	windowID = snapshotService[resourceKeyServerSnapshotServiceWindowID].(string)
+
+	return
+}
+
+// Get a server's snapshot service configuration.
+//
+// Returns nil if the snapshot service is not enabled for the server (or the server was not found).
+func getServerSnapshotService(ctx context.Context, providerState *providerState, serverID string) (*serverSnapshotService, error) {
+	var server struct {
+		SnapshotService *serverSnapshotService `json:"snapshotService"`
+	}
+	_, err := readCloudControlAPI(ctx, providerState, "server/server/"+url.PathEscape(serverID), &server)
+	if err != nil {
+		return nil, err
+	}
+
+	return server.SnapshotService, nil
+}
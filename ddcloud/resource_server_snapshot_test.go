@@ -0,0 +1,176 @@
+package ddcloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+/*
+ * Unit tests.
+ */
+
+func testServerDataWithSnapshotService(test *testing.T, servicePlan string) *schema.ResourceData {
+	properties := map[string]interface{}{
+		resourceKeyServerName:            "restored",
+		resourceKeyServerDescription:     "Restored from snapshot",
+		resourceKeyServerNetworkDomainID: "network-domain-1",
+	}
+	if servicePlan != "" {
+		properties[resourceKeyServerSnapshotService] = []interface{}{
+			map[string]interface{}{
+				resourceKeyServerSnapshotServicePlan:     servicePlan,
+				resourceKeyServerSnapshotServiceWindowID: "window-1",
+			},
+		}
+	}
+
+	data := schema.TestResourceDataRaw(test, resourceServer().Schema, properties)
+	data.SetId("server-1")
+
+	return data
+}
+
+// Unit test - creating the resource takes a manual snapshot, and deleting it deletes the snapshot.
+func TestServerSnapshotCreateAndDelete(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	data := schema.TestResourceDataRaw(test, resourceServerSnapshot().Schema, map[string]interface{}{
+		resourceKeyServerSnapshotServerID: "server-1",
+	})
+
+	diagnostics := resourceServerSnapshotCreate(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	testAssert.EqualsString("Id", "test-snapshot-1", data.Id())
+	testAssert.EqualsInt("len(initiateManualSnapshot)", 1, len(standIn.SnapshotRequests["initiateManualSnapshot"]))
+	testAssert.Equals("serverId", "server-1", standIn.SnapshotRequests["initiateManualSnapshot"][0]["serverId"])
+	testAssert.EqualsString(resourceKeyServerSnapshotType, "MANUAL", data.Get(resourceKeyServerSnapshotType).(string))
+	testAssert.EqualsString(resourceKeyServerSnapshotConsistencyLevel, "CRASH_CONSISTENT", data.Get(resourceKeyServerSnapshotConsistencyLevel).(string))
+
+	diagnostics = resourceServerSnapshotDelete(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	testAssert.EqualsInt("len(deleteManualSnapshot)", 1, len(standIn.SnapshotRequests["deleteManualSnapshot"]))
+	testAssert.Equals("id", "test-snapshot-1", standIn.SnapshotRequests["deleteManualSnapshot"][0]["id"])
+	testAssert.EqualsInt("len(Snapshots)", 0, len(standIn.Snapshots))
+
+	// Reading a snapshot that has expired (or been deleted) removes it from state.
+	diagnostics = resourceServerSnapshotRead(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	testAssert.EqualsString("Id", "", data.Id())
+}
+
+// Unit test - the data source lists only the specified server's snapshots, most recent first.
+func TestServerSnapshotsDataSourceRead(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.Snapshots = []serverSnapshot{
+		{ID: "snapshot-1", ServerID: "server-1", Type: "SYSTEM", State: compute.ResourceStatusNormal, StartTime: "2026-10-16T02:00:00.000Z"},
+		{ID: "snapshot-2", ServerID: "server-2", Type: "SYSTEM", State: compute.ResourceStatusNormal, StartTime: "2026-10-17T02:00:00.000Z"},
+		{ID: "snapshot-3", ServerID: "server-1", Type: "MANUAL", State: compute.ResourceStatusNormal, StartTime: "2026-10-18T09:30:00.000Z"},
+	}
+
+	data := schema.TestResourceDataRaw(test, dataSourceServerSnapshots().Schema, map[string]interface{}{
+		dataSourceKeyServerSnapshotsServerID: "server-1",
+	})
+
+	diagnostics := dataSourceServerSnapshotsRead(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	snapshots := data.Get(dataSourceKeyServerSnapshotsSnapshots).([]interface{})
+	testAssert.EqualsInt("len(snapshots)", 2, len(snapshots))
+	testAssert.Equals("snapshots[0].id", "snapshot-3", snapshots[0].(map[string]interface{})[dataSourceKeyServerSnapshotsSnapshotID])
+	testAssert.Equals("snapshots[0].type", "MANUAL", snapshots[0].(map[string]interface{})[dataSourceKeyServerSnapshotsSnapshotType])
+	testAssert.Equals("snapshots[1].id", "snapshot-1", snapshots[1].(map[string]interface{})[dataSourceKeyServerSnapshotsSnapshotID])
+}
+
+// Unit test - configuring the snapshot_service block enables the snapshot service, and removing it disables the service.
+func TestServerSnapshotServiceEnableAndDisable(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.ComputeServer = &compute.Server{
+		ID:    "server-1",
+		Name:  "restored",
+		State: compute.ResourceStatusNormal,
+	}
+
+	data := testServerDataWithSnapshotService(test, "ONE_MONTH")
+	err := updateServerSnapshotService(context.Background(), data, standIn.NewProviderState())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	testAssert.EqualsInt("len(enableSnapshotService)", 1, len(standIn.SnapshotRequests["enableSnapshotService"]))
+	enableRequest := standIn.SnapshotRequests["enableSnapshotService"][0]
+	testAssert.Equals("serverId", "server-1", enableRequest["serverId"])
+	testAssert.Equals("servicePlan", "ONE_MONTH", enableRequest["servicePlan"])
+	testAssert.Equals("windowId", "window-1", enableRequest["windowId"])
+
+	err = readServerSnapshotService(context.Background(), data, standIn.NewProviderState())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	servicePlan, windowID := getConfiguredServerSnapshotService(data)
+	testAssert.EqualsString("servicePlan", "ONE_MONTH", servicePlan)
+	testAssert.EqualsString("windowId", "window-1", windowID)
+
+	data = testServerDataWithSnapshotService(test, "")
+	err = updateServerSnapshotService(context.Background(), data, standIn.NewProviderState())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	testAssert.EqualsInt("len(disableSnapshotService)", 1, len(standIn.SnapshotRequests["disableSnapshotService"]))
+	testAssert.IsTrue("SnapshotService == nil", standIn.SnapshotService == nil)
+}
+
+// Unit test - restoring a snapshot creates a preview server and then migrates it.
+func TestServerRestoreFromSnapshot(test *testing.T) {
+	testAssert := assert.ForTest(test)
+
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	data := testServerDataWithSnapshotService(test, "")
+	data.SetId("")
+	data.Set(resourceKeyServerRestoreFromSnapshot, "snapshot-1")
+
+	err := restoreServerFromSnapshot(context.Background(), data, standIn.NewProviderState())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	testAssert.EqualsString("Id", "test-restored-server", data.Id())
+
+	testAssert.EqualsInt("len(createSnapshotPreviewServer)", 1, len(standIn.SnapshotRequests["createSnapshotPreviewServer"]))
+	previewRequest := standIn.SnapshotRequests["createSnapshotPreviewServer"][0]
+	testAssert.Equals("snapshotId", "snapshot-1", previewRequest["snapshotId"])
+	testAssert.Equals("serverName", "restored", previewRequest["serverName"])
+	testAssert.Equals("serverStarted", false, previewRequest["serverStarted"])
+
+	testAssert.EqualsInt("len(migrateSnapshotPreviewServer)", 1, len(standIn.SnapshotRequests["migrateSnapshotPreviewServer"]))
+	testAssert.Equals("serverId", "test-restored-server", standIn.SnapshotRequests["migrateSnapshotPreviewServer"][0]["serverId"])
+}
@@ -1,12 +1,15 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -14,20 +17,22 @@ const (
 	resourceKeySSLCertificateChainName            = "name"
 	resourceKeySSLCertificateChainDescription     = "description"
 	resourceKeySSLCertificateChainChain           = "chain"
+	resourceKeySSLCertificateChainVersioned       = "versioned"
+	resourceKeySSLCertificateChainVersionedName   = "versioned_name"
 )
 
 func resourceSSLCertificateChain() *schema.Resource {
 	return &schema.Resource{
-		Exists: resourceSSLCertificateChainExists,
-		Create: resourceSSLCertificateChainCreate,
-		Read:   resourceSSLCertificateChainRead,
-		Update: resourceSSLCertificateChainUpdate,
-		Delete: resourceSSLCertificateChainDelete,
+		CreateContext: resourceSSLCertificateChainCreate,
+		ReadContext:   resourceSSLCertificateChainRead,
+		UpdateContext: resourceSSLCertificateChainUpdate,
+		DeleteContext: resourceSSLCertificateChainDelete,
+		CustomizeDiff: resourceSSLCertificateChainCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceSSLCertificateChainImport,
+			StateContext: resourceSSLCertificateChainImport,
 		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: withCertificateAttributes("the certificate chain", map[string]*schema.Schema{
 			resourceKeySSLCertificateChainNetworkDomainID: &schema.Schema{
 				Type:        schema.TypeString,
 				ForceNew:    true,
@@ -52,40 +57,33 @@ func resourceSSLCertificateChain() *schema.Resource {
 				Required:    true,
 				Description: "The certificate chain (in PEM format).",
 			},
-		},
-	}
-}
-
-// Check if a ddcloud_ssl_domain_certificate resource exists.
-func resourceSSLCertificateChainExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	id := data.Id()
-	log.Printf("Check if SSL certificate chain '%s' exists.", id)
-
-	providerState := provider.(*providerState)
-	apiClient := providerState.Client()
-
-	SSLCertificateChain, err := apiClient.GetSSLCertificateChain(id)
-	if err != nil {
-		return false, err
+			resourceKeySSLCertificateChainVersioned: &schema.Schema{
+				Type:        schema.TypeBool,
+				ForceNew:    true,
+				Optional:    true,
+				Default:     false,
+				Description: "Append a version suffix to the name of the SSL certificate chain in CloudControl (enables zero-downtime certificate rotation with create_before_destroy).",
+			},
+			resourceKeySSLCertificateChainVersionedName: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the SSL certificate chain in CloudControl (including the version suffix, if any).",
+			},
+		}),
 	}
-
-	exists := SSLCertificateChain != nil
-
-	log.Printf("SSL certificate chain '%s' exists: %t.", id, exists)
-
-	return exists, nil
 }
 
 // Create a ddcloud_ssl_domain_certificate resource.
-func resourceSSLCertificateChainCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLCertificateChainCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	var err error
 
 	networkDomainID := data.Get(resourceKeySSLCertificateChainNetworkDomainID).(string)
 	name := data.Get(resourceKeySSLCertificateChainName).(string)
 	description := data.Get(resourceKeySSLCertificateChainDescription).(string)
 	chainPEM := data.Get(resourceKeySSLCertificateChainChain).(string)
+	versionedName := getSSLResourceVersionedName(name, data.Get(resourceKeySSLCertificateChainVersioned).(bool))
 
-	log.Printf("Create SSL certificate chain '%s' in network domain '%s'.", name, networkDomainID)
+	log.Printf("Create SSL certificate chain '%s' in network domain '%s'.", versionedName, networkDomainID)
 
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
@@ -96,12 +94,12 @@ func resourceSSLCertificateChainCreate(data *schema.ResourceData, provider inter
 	)
 
 	operationDescription := fmt.Sprintf("Create SSL certificate chain '%s' in network domain '%s'.", name, networkDomainID)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
 
-		certificateChainID, createError = apiClient.ImportSSLCertificateChain(networkDomainID, name, description, chainPEM)
+		certificateChainID, createError = apiClient.ImportSSLCertificateChain(networkDomainID, versionedName, description, chainPEM)
 		if createError != nil {
 			if compute.IsResourceBusyError(createError) {
 				context.Retry()
@@ -111,26 +109,28 @@ func resourceSSLCertificateChainCreate(data *schema.ResourceData, provider inter
 		}
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(certificateChainID)
+	data.Set(resourceKeySSLCertificateChainVersionedName, versionedName)
+	setSSLCertificateChainAttributes(data)
 	log.Printf("Successfully created SSL certificate chain '%s'.", certificateChainID)
 
 	certificateChain, err := apiClient.GetSSLCertificateChain(certificateChainID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if certificateChain == nil {
-		return fmt.Errorf("cannot find newly-added SSL certificate chain '%s'", certificateChainID)
+		return diag.Errorf("cannot find newly-added SSL certificate chain '%s'", certificateChainID)
 	}
 
 	return nil
 }
 
 // Read a ddcloud_ssl_domain_certificate resource.
-func resourceSSLCertificateChainRead(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLCertificateChainRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeySSLCertificateChainNetworkDomainID).(string)
 	name := data.Get(resourceKeySSLCertificateChainName).(string)
@@ -141,7 +141,7 @@ func resourceSSLCertificateChainRead(data *schema.ResourceData, provider interfa
 
 	certificateChain, err := apiClient.GetSSLCertificateChain(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if certificateChain == nil {
 		data.SetId("") // SSL certificate chain has been deleted
@@ -149,11 +149,14 @@ func resourceSSLCertificateChainRead(data *schema.ResourceData, provider interfa
 		return nil
 	}
 
+	data.Set(resourceKeySSLCertificateChainVersionedName, certificateChain.Name)
+	setSSLCertificateChainAttributes(data)
+
 	return nil
 }
 
 // Update a ddcloud_ssl_domain_certificate resource.
-func resourceSSLCertificateChainUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLCertificateChainUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeySSLCertificateChainNetworkDomainID).(string)
 	name := data.Get(resourceKeySSLCertificateChainName).(string)
@@ -164,7 +167,7 @@ func resourceSSLCertificateChainUpdate(data *schema.ResourceData, provider inter
 }
 
 // Delete a ddcloud_ssl_domain_certificate resource.
-func resourceSSLCertificateChainDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLCertificateChainDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeySSLCertificateChainNetworkDomainID).(string)
 	name := data.Get(resourceKeySSLCertificateChainName).(string)
@@ -176,7 +179,7 @@ func resourceSSLCertificateChainDelete(data *schema.ResourceData, provider inter
 
 	operationDescription := fmt.Sprintf("Delete SSL certificate chain '%s", id)
 
-	return providerState.RetryAction(operationDescription, func(context retry.Context) {
+	return diag.FromErr(providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
@@ -189,11 +192,11 @@ func resourceSSLCertificateChainDelete(data *schema.ResourceData, provider inter
 				context.Fail(err)
 			}
 		}
-	})
+	}))
 }
 
 // Import data for an existing SSL certificate chain.
-func resourceSSLCertificateChainImport(data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+func resourceSSLCertificateChainImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
@@ -214,8 +217,56 @@ func resourceSSLCertificateChainImport(data *schema.ResourceData, provider inter
 	data.Set(resourceKeySSLCertificateChainNetworkDomainID, certificateChain.NetworkDomainID)
 	data.Set(resourceKeySSLCertificateChainName, certificateChain.Name)
 	data.Set(resourceKeySSLCertificateChainDescription, certificateChain.Description)
+	data.Set(resourceKeySSLCertificateChainVersioned, false)
+	data.Set(resourceKeySSLCertificateChainVersionedName, certificateChain.Name)
 
 	importedData = []*schema.ResourceData{data}
 
 	return
 }
+
+// Validate the certificates for a ddcloud_ssl_certificate_chain resource before they are sent to CloudControl.
+func resourceSSLCertificateChainCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, provider interface{}) error {
+	if !diff.NewValueKnown(resourceKeySSLCertificateChainChain) {
+		return nil // Will be validated once the value is known.
+	}
+	if diff.Id() != "" && !diff.HasChange(resourceKeySSLCertificateChainChain) {
+		return nil // An existing chain is not re-validated (e.g. it is still usable by CloudControl after it expires).
+	}
+
+	attributes, err := validateSSLCertificateChain(
+		diff.Get(resourceKeySSLCertificateChainChain).(string),
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("invalid SSL certificate chain '%s': %s", diff.Get(resourceKeySSLCertificateChainName).(string), err)
+	}
+
+	for key, value := range attributes.ToMap() {
+		err = diff.SetNew(key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Update the computed attributes for a ddcloud_ssl_certificate_chain resource from its certificates (if available).
+func setSSLCertificateChainAttributes(data *schema.ResourceData) {
+	chainPEM := data.Get(resourceKeySSLCertificateChainChain).(string)
+	if chainPEM == "" {
+		return // e.g. Imported resource.
+	}
+
+	chain, err := parsePEMCertificates(chainPEM)
+	if err != nil {
+		log.Printf("Unable to parse certificates for SSL certificate chain '%s': %s", data.Id(), err)
+
+		return
+	}
+
+	for key, value := range getCertificateChainAttributes(chain).ToMap() {
+		data.Set(key, value)
+	}
+}
@@ -1,12 +1,15 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -15,20 +18,33 @@ const (
 	resourceKeySSLDomainCertificateDescription     = "description"
 	resourceKeySSLDomainCertificateCertificate     = "certificate"
 	resourceKeySSLDomainCertificatePrivateKey      = "private_key"
+	resourceKeySSLDomainCertificateVersioned       = "versioned"
+	resourceKeySSLDomainCertificateVersionedName   = "versioned_name"
+	resourceKeySSLDomainCertificateGenerate        = "generate"
+
+	resourceKeySSLDomainCertificateGenerateCommonName    = "common_name"
+	resourceKeySSLDomainCertificateGenerateOrganization  = "organization"
+	resourceKeySSLDomainCertificateGenerateDNSNames      = "dns_names"
+	resourceKeySSLDomainCertificateGenerateIPAddresses   = "ip_addresses"
+	resourceKeySSLDomainCertificateGenerateValidity      = "validity_hours"
+	resourceKeySSLDomainCertificateGenerateRenewalWindow = "renewal_window_hours"
+	resourceKeySSLDomainCertificateGenerateRSAKeyBits    = "rsa_bits"
+	resourceKeySSLDomainCertificateGenerateCACertificate = "ca_certificate"
+	resourceKeySSLDomainCertificateGenerateCAPrivateKey  = "ca_private_key"
 )
 
 func resourceSSLDomainCertificate() *schema.Resource {
 	return &schema.Resource{
-		Exists: resourceSSLDomainCertificateExists,
-		Create: resourceSSLDomainCertificateCreate,
-		Read:   resourceSSLDomainCertificateRead,
-		Update: resourceSSLDomainCertificateUpdate,
-		Delete: resourceSSLDomainCertificateDelete,
+		CreateContext: resourceSSLDomainCertificateCreate,
+		ReadContext:   resourceSSLDomainCertificateRead,
+		UpdateContext: resourceSSLDomainCertificateUpdate,
+		DeleteContext: resourceSSLDomainCertificateDelete,
+		CustomizeDiff: resourceSSLDomainCertificateCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceSSLDomainCertificateImport,
+			StateContext: resourceSSLDomainCertificateImport,
 		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: withCertificateAttributes("the certificate", map[string]*schema.Schema{
 			resourceKeySSLDomainCertificateNetworkDomainID: &schema.Schema{
 				Type:        schema.TypeString,
 				ForceNew:    true,
@@ -48,44 +64,120 @@ func resourceSSLDomainCertificate() *schema.Resource {
 				Description: "A description of the SSL domain certificate.",
 			},
 			resourceKeySSLDomainCertificateCertificate: &schema.Schema{
-				Type:        schema.TypeString,
-				ForceNew:    true,
-				Required:    true,
-				Description: "The certificate (in PEM format).",
+				Type:         schema.TypeString,
+				ForceNew:     true,
+				Optional:     true,
+				Computed:     true,
+				Description:  "The certificate (in PEM format).",
+				ExactlyOneOf: []string{resourceKeySSLDomainCertificateCertificate, resourceKeySSLDomainCertificateGenerate},
+				RequiredWith: []string{resourceKeySSLDomainCertificatePrivateKey},
 			},
 			resourceKeySSLDomainCertificatePrivateKey: &schema.Schema{
-				Type:        schema.TypeString,
+				Type:          schema.TypeString,
+				ForceNew:      true,
+				Optional:      true,
+				Computed:      true,
+				Sensitive:     true,
+				Description:   "The certificate's private key (in PEM format).",
+				ConflictsWith: []string{resourceKeySSLDomainCertificateGenerate},
+				RequiredWith:  []string{resourceKeySSLDomainCertificateCertificate},
+			},
+			resourceKeySSLDomainCertificateGenerate: &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Generate the certificate and private key (instead of specifying them).",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						resourceKeySSLDomainCertificateGenerateCommonName: &schema.Schema{
+							Type:        schema.TypeString,
+							ForceNew:    true,
+							Required:    true,
+							Description: "The certificate's common name (CN).",
+						},
+						resourceKeySSLDomainCertificateGenerateOrganization: &schema.Schema{
+							Type:        schema.TypeString,
+							ForceNew:    true,
+							Optional:    true,
+							Default:     "",
+							Description: "The certificate's organisation (O).",
+						},
+						resourceKeySSLDomainCertificateGenerateDNSNames: &schema.Schema{
+							Type:        schema.TypeList,
+							ForceNew:    true,
+							Optional:    true,
+							Description: "DNS names to include in the certificate's subject alternative names.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						resourceKeySSLDomainCertificateGenerateIPAddresses: &schema.Schema{
+							Type:        schema.TypeList,
+							ForceNew:    true,
+							Optional:    true,
+							Description: "IP addresses to include in the certificate's subject alternative names.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						resourceKeySSLDomainCertificateGenerateValidity: &schema.Schema{
+							Type:        schema.TypeInt,
+							ForceNew:    true,
+							Optional:    true,
+							Default:     8760,
+							Description: "The number of hours for which the certificate is valid.",
+						},
+						resourceKeySSLDomainCertificateGenerateRenewalWindow: &schema.Schema{
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     720,
+							Description: "The certificate is re-issued if it expires within this number of hours.",
+						},
+						resourceKeySSLDomainCertificateGenerateRSAKeyBits: &schema.Schema{
+							Type:        schema.TypeInt,
+							ForceNew:    true,
+							Optional:    true,
+							Default:     2048,
+							Description: "The size (in bits) of the generated RSA private key.",
+						},
+						resourceKeySSLDomainCertificateGenerateCACertificate: &schema.Schema{
+							Type:         schema.TypeString,
+							ForceNew:     true,
+							Optional:     true,
+							Default:      "",
+							Description:  "The certificate (in PEM format) of the CA that signs the certificate (if not specified, the certificate is self-signed).",
+							RequiredWith: []string{resourceKeySSLDomainCertificateGenerate + ".0." + resourceKeySSLDomainCertificateGenerateCAPrivateKey},
+						},
+						resourceKeySSLDomainCertificateGenerateCAPrivateKey: &schema.Schema{
+							Type:         schema.TypeString,
+							ForceNew:     true,
+							Optional:     true,
+							Default:      "",
+							Sensitive:    true,
+							Description:  "The private key (in PEM format) of the CA that signs the certificate.",
+							RequiredWith: []string{resourceKeySSLDomainCertificateGenerate + ".0." + resourceKeySSLDomainCertificateGenerateCACertificate},
+						},
+					},
+				},
+			},
+			resourceKeySSLDomainCertificateVersioned: &schema.Schema{
+				Type:        schema.TypeBool,
 				ForceNew:    true,
-				Required:    true,
-				Sensitive:   true,
-				Description: "The certificate's private key (in PEM format).",
+				Optional:    true,
+				Default:     false,
+				Description: "Append a version suffix to the name of the SSL domain certificate in CloudControl (enables zero-downtime certificate rotation with create_before_destroy).",
 			},
-		},
-	}
-}
-
-// Check if a ddcloud_ssl_domain_certificate resource exists.
-func resourceSSLDomainCertificateExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	id := data.Id()
-	log.Printf("Check if SSL domain certificate '%s' exists.", id)
-
-	providerState := provider.(*providerState)
-	apiClient := providerState.Client()
-
-	SSLDomainCertificate, err := apiClient.GetSSLDomainCertificate(id)
-	if err != nil {
-		return false, err
+			resourceKeySSLDomainCertificateVersionedName: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the SSL domain certificate in CloudControl (including the version suffix, if any).",
+			},
+		}),
 	}
-
-	exists := SSLDomainCertificate != nil
-
-	log.Printf("SSL domain certificate '%s' exists: %t.", id, exists)
-
-	return exists, nil
 }
 
 // Create a ddcloud_ssl_domain_certificate resource.
-func resourceSSLDomainCertificateCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLDomainCertificateCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	var err error
 
 	networkDomainID := data.Get(resourceKeySSLDomainCertificateNetworkDomainID).(string)
@@ -94,7 +186,21 @@ func resourceSSLDomainCertificateCreate(data *schema.ResourceData, provider inte
 	certificatePEM := data.Get(resourceKeySSLDomainCertificateCertificate).(string)
 	privateKeyPEM := data.Get(resourceKeySSLDomainCertificatePrivateKey).(string)
 
-	log.Printf("Create SSL domain certificate '%s' in network domain '%s'.", name, networkDomainID)
+	generationOptions := getSSLDomainCertificateGenerationOptions(data.Get(resourceKeySSLDomainCertificateGenerate).([]interface{}))
+	if generationOptions != nil {
+		log.Printf("Generate certificate '%s' for SSL domain certificate '%s'.", generationOptions.CommonName, name)
+
+		certificatePEM, privateKeyPEM, err = generateCertificate(*generationOptions, time.Now())
+		if err != nil {
+			return diag.Errorf("failed to generate certificate for SSL domain certificate '%s': %s", name, err)
+		}
+		data.Set(resourceKeySSLDomainCertificateCertificate, certificatePEM)
+		data.Set(resourceKeySSLDomainCertificatePrivateKey, privateKeyPEM)
+	}
+
+	versionedName := getSSLResourceVersionedName(name, data.Get(resourceKeySSLDomainCertificateVersioned).(bool))
+
+	log.Printf("Create SSL domain certificate '%s' in network domain '%s'.", versionedName, networkDomainID)
 
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
@@ -105,12 +211,12 @@ func resourceSSLDomainCertificateCreate(data *schema.ResourceData, provider inte
 	)
 
 	operationDescription := fmt.Sprintf("Create SSL domain certificate '%s' in network domain '%s'.", name, networkDomainID)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
 
-		domainCertificateID, createError = apiClient.ImportSSLDomainCertificate(networkDomainID, name, description, certificatePEM, privateKeyPEM)
+		domainCertificateID, createError = apiClient.ImportSSLDomainCertificate(networkDomainID, versionedName, description, certificatePEM, privateKeyPEM)
 		if createError != nil {
 			if compute.IsResourceBusyError(createError) {
 				context.Retry()
@@ -120,26 +226,28 @@ func resourceSSLDomainCertificateCreate(data *schema.ResourceData, provider inte
 		}
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(domainCertificateID)
+	data.Set(resourceKeySSLDomainCertificateVersionedName, versionedName)
+	setSSLDomainCertificateAttributes(data)
 	log.Printf("Successfully created SSL domain certificate '%s'.", domainCertificateID)
 
 	domainCertificate, err := apiClient.GetSSLDomainCertificate(domainCertificateID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if domainCertificate == nil {
-		return fmt.Errorf("cannot find newly-added SSL domain certificate '%s'", domainCertificateID)
+		return diag.Errorf("cannot find newly-added SSL domain certificate '%s'", domainCertificateID)
 	}
 
 	return nil
 }
 
 // Read a ddcloud_ssl_domain_certificate resource.
-func resourceSSLDomainCertificateRead(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLDomainCertificateRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeySSLDomainCertificateNetworkDomainID).(string)
 	name := data.Get(resourceKeySSLDomainCertificateName).(string)
@@ -150,7 +258,7 @@ func resourceSSLDomainCertificateRead(data *schema.ResourceData, provider interf
 
 	SSLDomainCertificate, err := apiClient.GetSSLDomainCertificate(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if SSLDomainCertificate == nil {
 		data.SetId("") // SSL domain certificate has been deleted
@@ -158,11 +266,14 @@ func resourceSSLDomainCertificateRead(data *schema.ResourceData, provider interf
 		return nil
 	}
 
+	data.Set(resourceKeySSLDomainCertificateVersionedName, SSLDomainCertificate.Name)
+	setSSLDomainCertificateAttributes(data)
+
 	return nil
 }
 
 // Update a ddcloud_ssl_domain_certificate resource.
-func resourceSSLDomainCertificateUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLDomainCertificateUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeySSLDomainCertificateNetworkDomainID).(string)
 	name := data.Get(resourceKeySSLDomainCertificateName).(string)
@@ -173,7 +284,7 @@ func resourceSSLDomainCertificateUpdate(data *schema.ResourceData, provider inte
 }
 
 // Delete a ddcloud_ssl_domain_certificate resource.
-func resourceSSLDomainCertificateDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLDomainCertificateDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeySSLDomainCertificateNetworkDomainID).(string)
 	name := data.Get(resourceKeySSLDomainCertificateName).(string)
@@ -185,7 +296,7 @@ func resourceSSLDomainCertificateDelete(data *schema.ResourceData, provider inte
 
 	operationDescription := fmt.Sprintf("Delete SSL domain certificate '%s", id)
 
-	return providerState.RetryAction(operationDescription, func(context retry.Context) {
+	return diag.FromErr(providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
@@ -198,11 +309,11 @@ func resourceSSLDomainCertificateDelete(data *schema.ResourceData, provider inte
 				context.Fail(err)
 			}
 		}
-	})
+	}))
 }
 
 // Import data for an existing SSL domain certificate.
-func resourceSSLDomainCertificateImport(data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+func resourceSSLDomainCertificateImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
@@ -223,8 +334,180 @@ func resourceSSLDomainCertificateImport(data *schema.ResourceData, provider inte
 	data.Set(resourceKeySSLDomainCertificateNetworkDomainID, domainCertificate.NetworkDomainID)
 	data.Set(resourceKeySSLDomainCertificateName, domainCertificate.Name)
 	data.Set(resourceKeySSLDomainCertificateDescription, domainCertificate.Description)
+	data.Set(resourceKeySSLDomainCertificateVersioned, false)
+	data.Set(resourceKeySSLDomainCertificateVersionedName, domainCertificate.Name)
 
 	importedData = []*schema.ResourceData{data}
 
 	return
 }
+
+// Validate the certificate and private key for a ddcloud_ssl_domain_certificate resource before they are sent to CloudControl.
+//
+// If the certificate is generated, validate the generation options and re-issue the certificate if it is due for renewal.
+func resourceSSLDomainCertificateCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, provider interface{}) error {
+	if len(diff.Get(resourceKeySSLDomainCertificateGenerate).([]interface{})) != 0 {
+		return customizeGeneratedSSLDomainCertificateDiff(diff, time.Now())
+	}
+
+	if !diff.NewValueKnown(resourceKeySSLDomainCertificateCertificate) || !diff.NewValueKnown(resourceKeySSLDomainCertificatePrivateKey) {
+		return nil // Will be validated once the values are known.
+	}
+	if diff.Id() != "" && !diff.HasChange(resourceKeySSLDomainCertificateCertificate) && !diff.HasChange(resourceKeySSLDomainCertificatePrivateKey) {
+		return nil // An existing certificate is not re-validated (e.g. it is still usable by CloudControl after it expires).
+	}
+
+	attributes, err := validateSSLDomainCertificate(
+		diff.Get(resourceKeySSLDomainCertificateCertificate).(string),
+		diff.Get(resourceKeySSLDomainCertificatePrivateKey).(string),
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("invalid SSL domain certificate '%s': %s", diff.Get(resourceKeySSLDomainCertificateName).(string), err)
+	}
+
+	for key, value := range attributes.ToMap() {
+		err = diff.SetNew(key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate the options for a generated SSL domain certificate, and re-issue the certificate if it is due for renewal.
+//
+// If a certificate will be generated (i.e. the resource is being created or the certificate re-issued), the CA (if any) must not expire before the generated certificate.
+func customizeGeneratedSSLDomainCertificateDiff(diff *schema.ResourceDiff, now time.Time) error {
+	name := diff.Get(resourceKeySSLDomainCertificateName).(string)
+
+	generateProperties := diff.Get(resourceKeySSLDomainCertificateGenerate).([]interface{})
+	generationOptions := getSSLDomainCertificateGenerationOptions(generateProperties)
+
+	isCertificateGenerated := diff.Id() == ""
+	if !isCertificateGenerated {
+		isRenewalDue, err := customizeSSLDomainCertificateRenewalDiff(diff, name, generateProperties, now)
+		if err != nil {
+			return err
+		}
+		isCertificateGenerated = isRenewalDue
+	}
+
+	caKeys := []string{
+		resourceKeySSLDomainCertificateGenerate + ".0." + resourceKeySSLDomainCertificateGenerateCACertificate,
+		resourceKeySSLDomainCertificateGenerate + ".0." + resourceKeySSLDomainCertificateGenerateCAPrivateKey,
+	}
+	if generationOptions.CACertificate == "" || !diff.NewValueKnown(caKeys[0]) || !diff.NewValueKnown(caKeys[1]) {
+		return nil
+	}
+
+	caCertificate, _, err := parseCertificateAuthority(generationOptions.CACertificate, generationOptions.CAPrivateKey)
+	if err != nil {
+		return fmt.Errorf("invalid generation options for SSL domain certificate '%s': %s", name, err)
+	}
+
+	err = validateCertificateValidity(caCertificate, now)
+	if err != nil {
+		return fmt.Errorf("invalid generation options for SSL domain certificate '%s': CA %s", name, err)
+	}
+
+	if isCertificateGenerated {
+		err = validateCertificateAuthorityExpiry(caCertificate, now.Add(generationOptions.Validity))
+		if err != nil {
+			return fmt.Errorf("invalid generation options for SSL domain certificate '%s': %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Re-issue an existing generated SSL domain certificate if it is due for renewal.
+func customizeSSLDomainCertificateRenewalDiff(diff *schema.ResourceDiff, name string, generateProperties []interface{}, now time.Time) (isRenewalDue bool, err error) {
+	notAfter, err := time.Parse(time.RFC3339, diff.Get(resourceKeyCertificateNotAfter).(string))
+	if err != nil {
+		return false, nil // Expiry is not yet known (e.g. state from an earlier version of the provider).
+	}
+
+	renewalWindow := time.Duration(generateProperties[0].(map[string]interface{})[resourceKeySSLDomainCertificateGenerateRenewalWindow].(int)) * time.Hour
+	if !isCertificateRenewalDue(notAfter, renewalWindow, now) {
+		return false, nil
+	}
+
+	log.Printf("Generated certificate for SSL domain certificate '%s' expires at %s; it will be re-issued.", name, notAfter.Format(time.RFC3339))
+
+	err = diff.SetNewComputed(resourceKeySSLDomainCertificateCertificate)
+	if err != nil {
+		return
+	}
+	err = diff.SetNewComputed(resourceKeySSLDomainCertificatePrivateKey)
+	if err != nil {
+		return
+	}
+	err = diff.ForceNew(resourceKeySSLDomainCertificateCertificate)
+	if err != nil {
+		return
+	}
+
+	return true, nil
+}
+
+// Get the options for generating an SSL domain certificate (nil if the certificate is not generated).
+func getSSLDomainCertificateGenerationOptions(generateProperties []interface{}) *certificateGenerationOptions {
+	if len(generateProperties) == 0 || generateProperties[0] == nil {
+		return nil
+	}
+	properties := generateProperties[0].(map[string]interface{})
+
+	options := &certificateGenerationOptions{
+		CommonName:    properties[resourceKeySSLDomainCertificateGenerateCommonName].(string),
+		Organization:  properties[resourceKeySSLDomainCertificateGenerateOrganization].(string),
+		Validity:      time.Duration(properties[resourceKeySSLDomainCertificateGenerateValidity].(int)) * time.Hour,
+		RSAKeyBits:    properties[resourceKeySSLDomainCertificateGenerateRSAKeyBits].(int),
+		CACertificate: properties[resourceKeySSLDomainCertificateGenerateCACertificate].(string),
+		CAPrivateKey:  properties[resourceKeySSLDomainCertificateGenerateCAPrivateKey].(string),
+	}
+	for _, dnsName := range properties[resourceKeySSLDomainCertificateGenerateDNSNames].([]interface{}) {
+		options.DNSNames = append(options.DNSNames, dnsName.(string))
+	}
+	for _, ipAddress := range properties[resourceKeySSLDomainCertificateGenerateIPAddresses].([]interface{}) {
+		options.IPAddresses = append(options.IPAddresses, ipAddress.(string))
+	}
+
+	return options
+}
+
+// Update the computed attributes for a ddcloud_ssl_domain_certificate resource from its certificate (if available).
+func setSSLDomainCertificateAttributes(data *schema.ResourceData) {
+	certificatePEM := data.Get(resourceKeySSLDomainCertificateCertificate).(string)
+	if certificatePEM == "" {
+		return // e.g. Imported resource.
+	}
+
+	certificates, err := parsePEMCertificates(certificatePEM)
+	if err != nil {
+		log.Printf("Unable to parse certificate for SSL domain certificate '%s': %s", data.Id(), err)
+
+		return
+	}
+
+	for key, value := range getCertificateAttributes(certificates[0]).ToMap() {
+		data.Set(key, value)
+	}
+}
+
+// Get the name used in CloudControl for an SSL domain certificate or certificate chain.
+//
+// CloudControl requires these names to be unique within a network domain so, if versioned, a suffix is appended to allow a replacement to be created before the original is destroyed.
+func getSSLResourceVersionedName(name string, versioned bool) string {
+	if !versioned {
+		return name
+	}
+
+	return name + "-" + newSSLResourceVersionSuffix()
+}
+
+// Create a suffix used to give each version of an SSL domain certificate or certificate chain a unique name.
+func newSSLResourceVersionSuffix() string {
+	return time.Now().UTC().Format("20060102150405")
+}
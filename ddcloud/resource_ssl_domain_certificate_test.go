@@ -0,0 +1,183 @@
+package ddcloud
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+/*
+ * Unit tests.
+ */
+
+// Unit test - versioned SSL resource names have a unique suffix.
+func TestGetSSLResourceVersionedName(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	assert.EqualsString("Unversioned", "my-certificate", getSSLResourceVersionedName("my-certificate", false))
+
+	versionedName := getSSLResourceVersionedName("my-certificate", true)
+	assert.IsTrue("Versioned name has prefix", strings.HasPrefix(versionedName, "my-certificate-"))
+	assert.EqualsInt("Versioned name length", len("my-certificate-")+len("20060102150405"), len(versionedName))
+}
+
+// Unit test - generate a self-signed certificate.
+func TestGenerateCertificateSelfSigned(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	certificatePEM, privateKeyPEM, err := generateCertificate(certificateGenerationOptions{
+		CommonName:  "internal.example.com",
+		DNSNames:    []string{"internal.example.com"},
+		IPAddresses: []string{"10.0.0.1"},
+		Validity:    24 * time.Hour,
+		RSAKeyBits:  1024,
+	}, time.Now())
+	assert.IsTrue("err == nil", err == nil)
+
+	attributes, err := validateSSLDomainCertificate(certificatePEM, privateKeyPEM, time.Now())
+	assert.IsTrue("err == nil", err == nil)
+	assert.EqualsString("Subject", "CN=internal.example.com", attributes.Subject)
+	assert.EqualsInt("len(SANs)", 2, len(attributes.SANs))
+	assert.EqualsString("SANs[1]", "10.0.0.1", attributes.SANs[1])
+}
+
+// Unit test - generate a certificate signed by a CA.
+func TestGenerateCertificateSignedByCA(test *testing.T) {
+	certificates := testGenerateCertificates(test, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	assert := assert.ForTest(test)
+
+	certificatePEM, privateKeyPEM, err := generateCertificate(certificateGenerationOptions{
+		CommonName:    "internal.example.com",
+		Validity:      time.Hour,
+		RSAKeyBits:    1024,
+		CACertificate: certificates.IntermediatePEM,
+		CAPrivateKey:  certificates.IntermediateKeyPEM,
+	}, time.Now())
+	assert.IsTrue("err == nil", err == nil)
+
+	err = validateSSLCertificateAndChain(certificatePEM, privateKeyPEM, certificates.ChainPEM(), time.Now())
+	assert.IsTrue("err == nil", err == nil)
+
+	// The intermediate CA expires in 24 hours.
+	_, _, err = generateCertificate(certificateGenerationOptions{
+		CommonName:    "internal.example.com",
+		Validity:      48 * time.Hour,
+		RSAKeyBits:    1024,
+		CACertificate: certificates.IntermediatePEM,
+		CAPrivateKey:  certificates.IntermediateKeyPEM,
+	}, time.Now())
+	assert.IsTrue("err != nil (validity exceeds CA)", err != nil)
+
+	// The leaf certificate is not a CA.
+	_, _, err = generateCertificate(certificateGenerationOptions{
+		CommonName:    "internal.example.com",
+		Validity:      time.Hour,
+		RSAKeyBits:    1024,
+		CACertificate: certificates.LeafPEM,
+		CAPrivateKey:  certificates.LeafKeyPEM,
+	}, time.Now())
+	assert.IsTrue("err != nil (not a CA)", err != nil)
+}
+
+// Unit test - a generated certificate is re-issued once it is within its renewal window.
+func TestSSLDomainCertificateCustomizeDiffRenewal(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	diff := testSSLDomainCertificateGeneratedDiff(test, time.Now().Add(10*24*time.Hour))
+	assert.IsTrue("Diff.RequiresNew (expires within renewal window)", diff != nil && diff.RequiresNew())
+
+	diff = testSSLDomainCertificateGeneratedDiff(test, time.Now().Add(60*24*time.Hour))
+	assert.IsTrue("!Diff.RequiresNew (expires after renewal window)", diff == nil || !diff.RequiresNew())
+}
+
+// Unit test - a new generated certificate that would outlive its CA is rejected when planning.
+func TestSSLDomainCertificateCustomizeDiffCAExpiry(test *testing.T) {
+	certificates := testGenerateCertificates(test, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	assert := assert.ForTest(test)
+
+	// The intermediate CA expires in 24 hours.
+	_, err := testSSLDomainCertificateGeneratedCADiff(certificates, 48)
+	assert.IsTrue("err != nil (validity exceeds CA)", err != nil)
+	assert.IsTrue("error mentions CA expiry", err != nil && strings.Contains(err.Error(), "CA certificate"))
+
+	_, err = testSSLDomainCertificateGeneratedCADiff(certificates, 1)
+	assert.IsTrue("err == nil (validity within CA)", err == nil)
+}
+
+// Unit test - renewal window.
+func TestIsCertificateRenewalDue(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	now := time.Now()
+	assert.IsTrue("Expired", isCertificateRenewalDue(now.Add(-time.Hour), 24*time.Hour, now))
+	assert.IsTrue("Within window", isCertificateRenewalDue(now.Add(time.Hour), 24*time.Hour, now))
+	assert.IsFalse("Outside window", isCertificateRenewalDue(now.Add(48*time.Hour), 24*time.Hour, now))
+}
+
+// Compute the diff for an existing generated certificate (with a 30-day renewal window) that expires at the specified time.
+func testSSLDomainCertificateGeneratedDiff(test *testing.T, notAfter time.Time) *terraform.InstanceDiff {
+	state := &terraform.InstanceState{
+		ID: "certificate-1",
+		Attributes: map[string]string{
+			"id": "certificate-1",
+			resourceKeySSLDomainCertificateNetworkDomainID: "networkdomain-1",
+			resourceKeySSLDomainCertificateName:            "certificate-1",
+			resourceKeySSLDomainCertificateDescription:     "",
+			resourceKeySSLDomainCertificateCertificate:     "certificate-pem",
+			resourceKeySSLDomainCertificatePrivateKey:      "private-key-pem",
+			resourceKeySSLDomainCertificateVersioned:       "false",
+			resourceKeySSLDomainCertificateVersionedName:   "certificate-1",
+			resourceKeyCertificateNotAfter:                 notAfter.UTC().Format(time.RFC3339),
+			"generate.#":                                   "1",
+			"generate.0.common_name":                       "internal.example.com",
+			"generate.0.organization":                      "",
+			"generate.0.dns_names.#":                       "0",
+			"generate.0.ip_addresses.#":                    "0",
+			"generate.0.validity_hours":                    "8760",
+			"generate.0.renewal_window_hours":              "720",
+			"generate.0.rsa_bits":                          "2048",
+			"generate.0.ca_certificate":                    "",
+			"generate.0.ca_private_key":                    "",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeySSLDomainCertificateNetworkDomainID: "networkdomain-1",
+		resourceKeySSLDomainCertificateName:            "certificate-1",
+		resourceKeySSLDomainCertificateGenerate: []interface{}{
+			map[string]interface{}{
+				resourceKeySSLDomainCertificateGenerateCommonName: "internal.example.com",
+			},
+		},
+	})
+
+	diff, err := resourceSSLDomainCertificate().Diff(context.Background(), state, config, &providerState{})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	return diff
+}
+
+// Compute the diff for a new certificate, valid for the specified number of hours, generated by the test intermediate CA.
+func testSSLDomainCertificateGeneratedCADiff(certificates testCertificates, validityHours int) (*terraform.InstanceDiff, error) {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeySSLDomainCertificateNetworkDomainID: "networkdomain-1",
+		resourceKeySSLDomainCertificateName:            "certificate-1",
+		resourceKeySSLDomainCertificateGenerate: []interface{}{
+			map[string]interface{}{
+				resourceKeySSLDomainCertificateGenerateCommonName:    "internal.example.com",
+				resourceKeySSLDomainCertificateGenerateValidity:      validityHours,
+				resourceKeySSLDomainCertificateGenerateCACertificate: certificates.IntermediatePEM,
+				resourceKeySSLDomainCertificateGenerateCAPrivateKey:  certificates.IntermediateKeyPEM,
+			},
+		},
+	})
+
+	return resourceSSLDomainCertificate().Diff(context.Background(), nil, config, &providerState{})
+}
@@ -1,12 +1,14 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -20,13 +22,12 @@ const (
 
 func resourceSSLOffloadProfile() *schema.Resource {
 	return &schema.Resource{
-		Exists: resourceSSLOffloadProfileExists,
-		Create: resourceSSLOffloadProfileCreate,
-		Read:   resourceSSLOffloadProfileRead,
-		Update: resourceSSLOffloadProfileUpdate,
-		Delete: resourceSSLOffloadProfileDelete,
+		CreateContext: resourceSSLOffloadProfileCreate,
+		ReadContext:   resourceSSLOffloadProfileRead,
+		UpdateContext: resourceSSLOffloadProfileUpdate,
+		DeleteContext: resourceSSLOffloadProfileDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSSLOffloadProfileImport,
+			StateContext: resourceSSLOffloadProfileImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -67,28 +68,8 @@ func resourceSSLOffloadProfile() *schema.Resource {
 	}
 }
 
-// Check if a ddcloud_ssl_domain_certificate resource exists.
-func resourceSSLOffloadProfileExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	id := data.Id()
-	log.Printf("Check if SSL-offload profile '%s' exists.", id)
-
-	providerState := provider.(*providerState)
-	apiClient := providerState.Client()
-
-	sslOffloadProfile, err := apiClient.GetSSLOffloadProfile(id)
-	if err != nil {
-		return false, err
-	}
-
-	exists := sslOffloadProfile != nil
-
-	log.Printf("SSL-offload profile '%s' exists: %t.", id, exists)
-
-	return exists, nil
-}
-
 // Create a ddcloud_ssl_domain_certificate resource.
-func resourceSSLOffloadProfileCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLOffloadProfileCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	var err error
 
 	propertyHelper := propertyHelper(data)
@@ -111,7 +92,7 @@ func resourceSSLOffloadProfileCreate(data *schema.ResourceData, provider interfa
 	)
 
 	operationDescription := fmt.Sprintf("Create SSL-offload profile '%s' in network domain '%s'.", name, networkDomainID)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
@@ -126,7 +107,7 @@ func resourceSSLOffloadProfileCreate(data *schema.ResourceData, provider interfa
 		}
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(sslOffloadProfileID)
@@ -134,11 +115,11 @@ func resourceSSLOffloadProfileCreate(data *schema.ResourceData, provider interfa
 
 	sslOffloadProfile, err := apiClient.GetSSLOffloadProfile(sslOffloadProfileID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if sslOffloadProfile == nil {
-		return fmt.Errorf("cannot find newly-added SSL-offload profile '%s'", sslOffloadProfileID)
+		return diag.Errorf("cannot find newly-added SSL-offload profile '%s'", sslOffloadProfileID)
 	}
 
 	data.Set(resourceKeySSLOffloadProfileCiphers, sslOffloadProfile.Ciphers)
@@ -147,7 +128,7 @@ func resourceSSLOffloadProfileCreate(data *schema.ResourceData, provider interfa
 }
 
 // Read a ddcloud_ssl_domain_certificate resource.
-func resourceSSLOffloadProfileRead(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLOffloadProfileRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeySSLOffloadProfileNetworkDomainID).(string)
 	name := data.Get(resourceKeySSLOffloadProfileName).(string)
@@ -158,7 +139,7 @@ func resourceSSLOffloadProfileRead(data *schema.ResourceData, provider interface
 
 	sslOffloadProfile, err := apiClient.GetSSLOffloadProfile(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if sslOffloadProfile == nil {
 		data.SetId("") // SSL-offload profile has been deleted
@@ -172,7 +153,7 @@ func resourceSSLOffloadProfileRead(data *schema.ResourceData, provider interface
 }
 
 // Update a ddcloud_ssl_domain_certificate resource.
-func resourceSSLOffloadProfileUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLOffloadProfileUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeySSLOffloadProfileNetworkDomainID).(string)
 	name := data.Get(resourceKeySSLOffloadProfileName).(string)
@@ -186,7 +167,7 @@ func resourceSSLOffloadProfileUpdate(data *schema.ResourceData, provider interfa
 
 	sslOffloadProfile, err := apiClient.GetSSLOffloadProfile(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if sslOffloadProfile == nil {
 		data.SetId("") // SSL-offload profile has been deleted
@@ -231,7 +212,7 @@ func resourceSSLOffloadProfileUpdate(data *schema.ResourceData, provider interfa
 	var editError error
 
 	operationDescription := fmt.Sprintf("Create SSL-offload profile '%s' in network domain '%s'.", name, networkDomainID)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
@@ -246,14 +227,14 @@ func resourceSSLOffloadProfileUpdate(data *schema.ResourceData, provider interfa
 		}
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
 // Delete a ddcloud_ssl_domain_certificate resource.
-func resourceSSLOffloadProfileDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceSSLOffloadProfileDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeySSLOffloadProfileNetworkDomainID).(string)
 	name := data.Get(resourceKeySSLOffloadProfileName).(string)
@@ -265,7 +246,7 @@ func resourceSSLOffloadProfileDelete(data *schema.ResourceData, provider interfa
 
 	operationDescription := fmt.Sprintf("Delete SSL-offload profile '%s", id)
 
-	return providerState.RetryAction(operationDescription, func(context retry.Context) {
+	return diag.FromErr(providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
@@ -278,11 +259,11 @@ func resourceSSLOffloadProfileDelete(data *schema.ResourceData, provider interfa
 				context.Fail(err)
 			}
 		}
-	})
+	}))
 }
 
 // Import data for an existing SSL-offload profile.
-func resourceSSLOffloadProfileImport(data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+func resourceSSLOffloadProfileImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
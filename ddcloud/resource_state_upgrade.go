@@ -0,0 +1,110 @@
+package ddcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+/*
+ * State upgrades
+ *
+ * Each resource whose SchemaVersion is greater than 0 must supply a StateUpgrader for every previous schema version.
+ *
+ * An upgrader's Type describes the shape of the state data for that schema version (Terraform uses it to decode state that was stored in the legacy flatmap format);
+ * since older versions of a resource's schema are usually only slightly different from the current one, these types are derived from the resource's current schema.
+ */
+
+// Get the type of the state data for the current version of a resource's schema.
+func stateTypeOf(resource *schema.Resource) cty.Type {
+	return resource.CoreConfigSchema().ImpliedType()
+}
+
+// Derive the type of a previous version of a resource's state data from the type of the current version.
+//
+// Each entry in attributeTypes replaces (or adds) an attribute; attributes whose type is cty.NilType are removed.
+func stateTypeWithAttributes(stateType cty.Type, attributeTypes map[string]cty.Type) cty.Type {
+	mergedAttributeTypes := make(map[string]cty.Type)
+	for attributeName, attributeType := range stateType.AttributeTypes() {
+		mergedAttributeTypes[attributeName] = attributeType
+	}
+	for attributeName, attributeType := range attributeTypes {
+		if attributeType == cty.NilType {
+			delete(mergedAttributeTypes, attributeName)
+
+			continue
+		}
+
+		mergedAttributeTypes[attributeName] = attributeType
+	}
+
+	return cty.Object(mergedAttributeTypes)
+}
+
+// Create a StateUpgrader for a schema version that did not change the shape of the resource's state data.
+func stateUpgraderNoChange(resourceType string, version int, stateType cty.Type) schema.StateUpgrader {
+	return schema.StateUpgrader{
+		Version: version,
+		Type:    stateType,
+		Upgrade: func(ctx context.Context, rawState map[string]interface{}, provider interface{}) (map[string]interface{}, error) {
+			log.Printf("Found %s state v%d; no changes are required to migrate to v%d.", resourceType, version, version+1)
+
+			return rawState, nil
+		},
+	}
+}
+
+// Read a raw state value as an integer.
+//
+// Depending on how state data was decoded, numbers may be represented as float64 or json.Number (or as strings, for state that has not yet been converted from flatmap format).
+func stateValueAsInt(value interface{}) (int, error) {
+	switch typedValue := value.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return typedValue, nil
+	case float64:
+		return int(typedValue), nil
+	case json.Number:
+		intValue, err := typedValue.Int64()
+
+		return int(intValue), err
+	case string:
+		return strconv.Atoi(typedValue)
+	default:
+		return 0, fmt.Errorf("unexpected value '%#v' (expected a number)", value)
+	}
+}
+
+// Read a raw state value as a boolean.
+func stateValueAsBool(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case bool:
+		return typedValue
+	case string:
+		boolValue, _ := strconv.ParseBool(typedValue)
+
+		return boolValue
+	default:
+		return false
+	}
+}
+
+// Read a raw state value as a string.
+func stateValueAsString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	stringValue, ok := value.(string)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+
+	return stringValue
+}
@@ -1,10 +1,13 @@
 package ddcloud
 
 import (
+	"context"
+
 	"fmt"
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"log"
 )
 
@@ -24,13 +27,12 @@ const (
 func resourceStaticRoute() *schema.Resource {
 
 	return &schema.Resource{
-		Exists: resourceStaticRouteExists,
-		Create: resourceStaticRouteCreate,
-		Read:   resourceStaticRouteRead,
-		Update: resourceStaticRouteUpdate,
-		Delete: resourceStaticRouteDelete,
+		CreateContext: resourceStaticRouteCreate,
+		ReadContext:   resourceStaticRouteRead,
+		UpdateContext: resourceStaticRouteUpdate,
+		DeleteContext: resourceStaticRouteDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceStaticRouteImport,
+			StateContext: resourceStaticRouteImport,
 		},
 		Schema: map[string]*schema.Schema{
 			resourceKeyStaticRouteName: &schema.Schema{
@@ -89,18 +91,7 @@ func resourceStaticRoute() *schema.Resource {
 	}
 }
 
-func resourceStaticRouteExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	log.Println("resourceStaticRouteExists")
-
-	client := provider.(*providerState).Client()
-	id := data.Id()
-	staticRoute, _ := client.GetStaticRoute(id)
-	exists := staticRoute != nil
-
-	return exists, nil
-}
-
-func resourceStaticRouteCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceStaticRouteCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	log.Println("resourceStaticRouteCreate")
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
@@ -129,7 +120,7 @@ func resourceStaticRouteCreate(data *schema.ResourceData, provider interface{})
 
 			err := apiClient.DeleteStaticRoute(route.ID)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			log.Printf("Default system route deleted successfully. Name:%s", name)
 
@@ -145,7 +136,7 @@ func resourceStaticRouteCreate(data *schema.ResourceData, provider interface{})
 
 	var staticRouteID string
 	operationDescription := fmt.Sprintf("Create static route '%s'", name)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock("Create static route '%s'", name)
 		defer asyncLock.Release()
@@ -164,7 +155,7 @@ func resourceStaticRouteCreate(data *schema.ResourceData, provider interface{})
 	})
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(staticRouteID)
@@ -173,31 +164,25 @@ func resourceStaticRouteCreate(data *schema.ResourceData, provider interface{})
 
 	resource, err := apiClient.WaitForDeploy(compute.ResourceTypeStaticRoutes, staticRouteID, resourceCreateTimeoutVLAN)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	data.Partial(true)
-
 	// Capture additional properties that are only available after deployment.
 	staticRoute := resource.(*compute.StaticRoute)
 
 	data.Set(resourceKeyStaticRouteState, staticRoute.State)
-	data.SetPartial(resourceKeyStaticRouteState)
 
 	data.Set(resourceKeyStaticRouteDataCenter, staticRoute.DataCenter)
-	data.SetPartial(resourceKeyStaticRouteDataCenter)
 
 	err = applyNetworkDomainDefaultFirewallRules(data, apiClient)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	data.Partial(false)
-
 	return nil
 }
 
-func resourceStaticRouteRead(data *schema.ResourceData, provider interface{}) error {
+func resourceStaticRouteRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	log.Println("resourceStaticRouteRead")
 
 	var networkDomainId, name, description, ipVersion, destinationNetworkAddress, nextHopAddress string
@@ -221,41 +206,37 @@ func resourceStaticRouteRead(data *schema.ResourceData, provider interface{}) er
 	staticRoute, err := apiClient.GetStaticRoute(id)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	data.Partial(true)
+	if staticRoute == nil {
+		data.SetId("") // Static route has been deleted
+
+		return nil
+	}
 
 	if staticRoute != nil {
 
 		data.Set(resourceKeyStaticRouteNetworkdomain, staticRoute.NetworkDomainId)
-		data.SetPartial(resourceKeyStaticRouteNetworkdomain)
 
 		data.Set(resourceKeyStaticRouteName, staticRoute.Name)
-		data.SetPartial(resourceKeyStaticRouteName)
 
 		data.Set(resourceKeyStaticRouteDescription, staticRoute.Description)
-		data.SetPartial(resourceKeyStaticRouteDescription)
 
 		data.Set(resourceKeyStaticRouteIpVersion, staticRoute.IpVersion)
-		data.SetPartial(resourceKeyStaticRouteIpVersion)
 
 		data.Set(resourceKeyStaticRouteDestinationNetworkAddress, staticRoute.DestinationNetworkAddress)
-		data.SetPartial(resourceKeyStaticRouteDestinationNetworkAddress)
 
 		data.Set(resourceKeyStaticRouteDestinationPrefixSize, staticRoute.DestinationPrefixSize)
-		data.SetPartial(resourceKeyStaticRouteDestinationPrefixSize)
 
 		data.Set(resourceKeyStaticRouteNextHopAddress, staticRoute.NextHopAddress)
-		data.SetPartial(resourceKeyStaticRouteNextHopAddress)
 
 	}
 
-	data.Partial(false)
 	return nil
 }
 
-func resourceStaticRouteUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceStaticRouteUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	log.Println("resourceStaticRouteUpdate")
 
 	providerState := provider.(*providerState)
@@ -284,7 +265,7 @@ func resourceStaticRouteUpdate(data *schema.ResourceData, provider interface{})
 	operationDescriptionDel := fmt.Sprintf("Deleting static route '%s'", name)
 
 	var errDel error
-	errDel = providerState.RetryAction(operationDescriptionDel, func(context retry.Context) {
+	errDel = providerState.RetryAction(ctx, operationDescriptionDel, func(context retry.Context) {
 
 		asyncLock := providerState.AcquireAsyncOperationLock("Delete static route: '%s'", id)
 		defer asyncLock.Release()
@@ -301,7 +282,7 @@ func resourceStaticRouteUpdate(data *schema.ResourceData, provider interface{})
 	})
 
 	if errDel != nil {
-		return errDel
+		return diag.FromErr(errDel)
 	}
 
 	// Update step 2: re-create
@@ -317,7 +298,7 @@ func resourceStaticRouteUpdate(data *schema.ResourceData, provider interface{})
 
 			err := apiClient.DeleteStaticRoute(route.ID)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			log.Printf("Default system route deleted successfully. Name:%s", name)
 
@@ -332,7 +313,7 @@ func resourceStaticRouteUpdate(data *schema.ResourceData, provider interface{})
 
 	var staticRouteID string
 	operationDescription := fmt.Sprintf("Create static route '%s'", name)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock("Create static route '%s'", name)
 		defer asyncLock.Release()
@@ -351,7 +332,7 @@ func resourceStaticRouteUpdate(data *schema.ResourceData, provider interface{})
 	})
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(staticRouteID)
@@ -360,31 +341,25 @@ func resourceStaticRouteUpdate(data *schema.ResourceData, provider interface{})
 
 	resource, err := apiClient.WaitForDeploy(compute.ResourceTypeStaticRoutes, staticRouteID, resourceCreateTimeoutVLAN)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	data.Partial(true)
-
 	// Capture additional properties that are only available after deployment.
 	staticRoute := resource.(*compute.StaticRoute)
 
 	data.Set(resourceKeyStaticRouteState, staticRoute.State)
-	data.SetPartial(resourceKeyStaticRouteState)
 
 	data.Set(resourceKeyStaticRouteDataCenter, staticRoute.DataCenter)
-	data.SetPartial(resourceKeyStaticRouteDataCenter)
 
 	err = applyNetworkDomainDefaultFirewallRules(data, apiClient)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	data.Partial(false)
-
 	return nil
 }
 
-func resourceStaticRouteDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceStaticRouteDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	log.Println("resourceStaticRouteDelete")
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
@@ -395,7 +370,7 @@ func resourceStaticRouteDelete(data *schema.ResourceData, provider interface{})
 	operationDescription := fmt.Sprintf("Deleting static route '%s'", name)
 
 	var err error
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 
 		asyncLock := providerState.AcquireAsyncOperationLock("Delete static route: '%s'", id)
 		defer asyncLock.Release()
@@ -412,13 +387,13 @@ func resourceStaticRouteDelete(data *schema.ResourceData, provider interface{})
 	})
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceStaticRouteImport(data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+func resourceStaticRouteImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
 	log.Println("resourceStaticRouteImport")
 
 	providerState := provider.(*providerState)
@@ -1,17 +1,22 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/validators"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
 	resourceKeyStorageControllerServerID    = "server"
+	resourceKeyStorageControllerType        = "controller_type"
 	resourceKeyStorageControllerBusNumber   = "scsi_bus_number"
 	resourceKeyStorageControllerAdapterType = "adapter_type"
 	resourceKeyStorageControllerDisk        = "disk"
@@ -31,14 +36,15 @@ const (
  */
 
 func resourceStorageController() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		SchemaVersion: 1,
-		Create:        resourceStorageControllerCreate,
-		Read:          resourceStorageControllerRead,
-		Update:        resourceStorageControllerUpdate,
-		Delete:        resourceStorageControllerDelete,
+		CreateContext: resourceStorageControllerCreate,
+		ReadContext:   resourceStorageControllerRead,
+		UpdateContext: resourceStorageControllerUpdate,
+		DeleteContext: resourceStorageControllerDelete,
+		CustomizeDiff: resourceStorageControllerCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceStorageControllerImport,
+			StateContext: resourceStorageControllerImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -48,41 +54,103 @@ func resourceStorageController() *schema.Resource {
 				ForceNew:    true,
 				Description: "The Id of the server that the controller is attached to",
 			},
+			resourceKeyStorageControllerType: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The type of controller (SCSI, SATA, or IDE); defaults to SCSI",
+				ValidateFunc: validators.StringIsOneOf("controller type",
+					models.DiskControllerTypeSCSI,
+					models.DiskControllerTypeSATA,
+					models.DiskControllerTypeIDE,
+				),
+			},
 			resourceKeyStorageControllerBusNumber: &schema.Schema{
 				Type:        schema.TypeInt,
 				Required:    true,
 				ForceNew:    true,
-				Description: "The controller's SCSI bus number",
+				Description: "The controller's SCSI bus number (for SATA controllers, the bus number; for IDE controllers, the channel)",
 			},
 			resourceKeyStorageControllerAdapterType: &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     compute.StorageControllerAdapterTypeLSILogicParallel,
 				ForceNew:    true,
-				Description: "The type of storage adapter used to represent the controller",
+				Description: "The type of storage adapter used to represent the controller (SCSI controllers only)",
 			},
 			resourceKeyStorageControllerDisk: schemaDisk(),
 		},
 	}
+
+	// Schema version 1 did not change the shape of the resource's state data.
+	resource.StateUpgraders = []schema.StateUpgrader{
+		stateUpgraderNoChange("ddcloud_storage_controller", 0, stateTypeOf(resource)),
+	}
+
+	return resource
+}
+
+// Validate a planned change to a storage controller resource.
+//
+// Values that are not yet known are not validated.
+func resourceStorageControllerCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, provider interface{}) error {
+	isNew := diff.Id() == ""
+	if !diff.NewValueKnown(resourceKeyStorageControllerDisk) || !(isNew || diff.HasChange(resourceKeyStorageControllerDisk)) {
+		return nil
+	}
+
+	var problems []string
+	for _, diagnostic := range validateStorageControllerDisks(getServerDisksFromDiff(diff)) {
+		problems = append(problems, formatDiagnostic(diagnostic))
+	}
+
+	if !isNew {
+		serverID := diff.Get(resourceKeyStorageControllerServerID).(string)
+		err := validateDisksPowerCycle(diff, provider.(*providerState), serverID)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", resourceKeyStorageControllerDisk, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration for storage controller:\n  - %s",
+			strings.Join(problems, "\n  - "),
+		)
+	}
+
+	return nil
 }
 
 // Create a storage controller resource.
-func resourceStorageControllerCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceStorageControllerCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	propertyHelper := propertyHelper(data)
 
 	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
 	adapterType := data.Get(resourceKeyStorageControllerAdapterType).(string)
 	busNumber := data.Get(resourceKeyStorageControllerBusNumber).(int)
 
+	diagnostics := validateStorageControllerDisks(propertyHelper.GetDisks())
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
 	providerState := provider.(*providerState)
+
+	controllerType := getStorageControllerType(data)
+	if controllerType != models.DiskControllerTypeSCSI {
+		return resourceSATAOrIDEStorageControllerCreate(ctx, data, providerState, controllerType)
+	}
+	data.Set(resourceKeyStorageControllerType, controllerType)
+
 	apiClient := providerState.Client()
 
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
-		return fmt.Errorf("cannot find server '%s'", serverID)
+		return diag.Errorf("cannot find server '%s'", serverID)
 	}
 
 	log.Printf("Create storage controller for SCSI bus %d in server '%s'.",
@@ -95,7 +163,7 @@ func resourceStorageControllerCreate(data *schema.ResourceData, provider interfa
 		// Default controller (always present)
 		targetController = server.SCSIControllers.GetByBusNumber(busNumber)
 		if targetController == nil {
-			return fmt.Errorf("cannot find controller for bus %d in server '%s'", busNumber, serverID)
+			return diag.Errorf("cannot find controller for bus %d in server '%s'", busNumber, serverID)
 		}
 
 		log.Printf("This controller is the default controller; will treat as already-created.")
@@ -104,7 +172,7 @@ func resourceStorageControllerCreate(data *schema.ResourceData, provider interfa
 			busNumber,
 			serverID,
 		)
-		err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+		err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 			asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 			defer asyncLock.Release()
 
@@ -118,10 +186,10 @@ func resourceStorageControllerCreate(data *schema.ResourceData, provider interfa
 
 		server, err = apiClient.GetServer(serverID)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		if server == nil {
-			return fmt.Errorf("cannot find server '%s'", serverID)
+			return diag.Errorf("cannot find server '%s'", serverID)
 		}
 
 		resource, err := apiClient.WaitForChange(
@@ -131,13 +199,13 @@ func resourceStorageControllerCreate(data *schema.ResourceData, provider interfa
 			resourceUpdateTimeoutServer,
 		)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		server = resource.(*compute.Server)
 		targetController = server.SCSIControllers.GetByBusNumber(busNumber)
 		if targetController == nil {
-			return fmt.Errorf("cannot find controller for bus %d in server '%s'", busNumber, serverID)
+			return diag.Errorf("cannot find controller for bus %d in server '%s'", busNumber, serverID)
 		}
 	}
 
@@ -154,29 +222,37 @@ func resourceStorageControllerCreate(data *schema.ResourceData, provider interfa
 	if configuredDisks.IsEmpty() {
 		// No explicitly-configured disks so just populate from current controller state.
 		propertyHelper.SetDisks(actualDisks)
-		propertyHelper.SetPartial(resourceKeyServerDisk)
 
 		log.Printf("No disks configured; storage controller '%s' now has %d disks: %#v.", serverID, server.SCSIControllers.GetDiskCount(), server.SCSIControllers)
 
 		return nil
 	}
 
-	return updateStorageControllerDisks(data, providerState)
+	return diag.FromErr(updateStorageControllerDisks(ctx, data, providerState))
 }
 
 // Read a storage controller resource.
-func resourceStorageControllerRead(data *schema.ResourceData, provider interface{}) error {
+func resourceStorageControllerRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	propertyHelper := propertyHelper(data)
 
 	controllerID := data.Id()
 	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
 
 	providerState := provider.(*providerState)
+	if getStorageControllerType(data) != models.DiskControllerTypeSCSI {
+		return resourceSATAOrIDEStorageControllerRead(ctx, data, providerState)
+	}
+
 	apiClient := providerState.Client()
 
+	diskResourceSCSIPaths, err := getDiskResourceSCSIPaths(apiClient, serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
 		log.Printf("Cannot find server '%s' for controller '%s'.", serverID, controllerID)
@@ -209,26 +285,43 @@ func resourceStorageControllerRead(data *schema.ResourceData, provider interface
 	log.Printf("Configuration for storage controller '%s' (bus %d) previously specified: %#v.", controllerID, targetController.BusNumber, oldConfDisks)
 	log.Printf("Configuration for storage controller '%s' (bus %d) now specifies: %#v.", controllerID, targetController.BusNumber, newConfDisks)
 
-	actualDisks := models.NewDisksFromVirtualMachineSCSIController(*targetController)
+	actualDisks := models.NewDisksFromVirtualMachineSCSIController(*targetController).Managed(diskResourceSCSIPaths)
 	log.Printf("Storage controller '%s' currently has %d disks: %#v.", controllerID, len(actualDisks), actualDisks)
 
+	data.Set(resourceKeyStorageControllerType, models.DiskControllerTypeSCSI)
 	propertyHelper.SetDisks(actualDisks)
 
 	return nil
 }
 
 // Update a storage controller resource.
-func resourceStorageControllerUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceStorageControllerUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	propertyHelper := propertyHelper(data)
 	controllerID := data.Id()
 	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
 
+	diagnostics := validateStorageControllerDisks(propertyHelper.GetDisks())
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
 	providerState := provider.(*providerState)
+
+	controllerType := getStorageControllerType(data)
+	if controllerType != models.DiskControllerTypeSCSI {
+		return resourceSATAOrIDEStorageControllerUpdate(ctx, data, providerState, controllerType)
+	}
+
 	apiClient := providerState.Client()
 
+	diskResourceSCSIPaths, err := getDiskResourceSCSIPaths(apiClient, serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if server == nil {
 		log.Printf("Cannot find server '%s' for controller '%s'; will treat the controller as deleted.", serverID, controllerID)
@@ -255,32 +348,35 @@ func resourceStorageControllerUpdate(data *schema.ResourceData, provider interfa
 	)
 
 	configuredDisks := propertyHelper.GetDisks()
-	actualDisks := models.NewDisksFromVirtualMachineSCSIController(*targetController)
+	actualDisks := models.NewDisksFromVirtualMachineSCSIController(*targetController).Managed(diskResourceSCSIPaths)
 
 	if configuredDisks.IsEmpty() {
 		// No explicitly-configured disks.
 		propertyHelper.SetDisks(actualDisks)
-		propertyHelper.SetPartial(resourceKeyServerDisk)
 
 		log.Printf("No disks configured; storage controller '%s' now has %d disks: %#v.", serverID, server.SCSIControllers.GetDiskCount(), server.SCSIControllers)
 
 		return nil
 	}
 
-	return updateStorageControllerDisks(data, providerState)
+	return diag.FromErr(updateStorageControllerDisks(ctx, data, providerState))
 }
 
 // Delete a storage controller resource.
-func resourceStorageControllerDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceStorageControllerDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	controllerID := data.Id()
 	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
 
 	providerState := provider.(*providerState)
+	if getStorageControllerType(data) != models.DiskControllerTypeSCSI {
+		return resourceSATAOrIDEStorageControllerDelete(ctx, data, providerState)
+	}
+
 	apiClient := providerState.Client()
 
 	targetController, err := getStorageController(apiClient, data)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Delete storage controller '%s' in server '%s'.",
@@ -289,15 +385,15 @@ func resourceStorageControllerDelete(data *schema.ResourceData, provider interfa
 	)
 
 	removeDisks := models.NewDisksFromVirtualMachineSCSIController(*targetController)
-	err = processRemoveStorageControllerDisks(removeDisks, data, providerState)
+	err = processRemoveStorageControllerDisks(ctx, removeDisks, data, providerState)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// We can't remove the SCSI adapter if it still has a disk (i.e. the last disk in the server).
 	targetController, err = getStorageController(apiClient, data)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if len(targetController.Disks) > 0 {
 		log.Printf("Treating storage controller '%s' in server '%s' as deleted because it still has one or more disks after disk-removal processing; this indicates that the minimum-disk-count-per-server limit has been exceeded.",
@@ -313,7 +409,7 @@ func resourceStorageControllerDelete(data *schema.ResourceData, provider interfa
 		targetController.BusNumber,
 		serverID,
 	)
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release()
 
@@ -326,7 +422,7 @@ func resourceStorageControllerDelete(data *schema.ResourceData, provider interfa
 		}
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Removing storage controller '%s' (SCSI bus %d) from server '%s'...",
@@ -342,7 +438,7 @@ func resourceStorageControllerDelete(data *schema.ResourceData, provider interfa
 		resourceUpdateTimeoutServer,
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Removed storage controller '%s' (SCSI bus %d) from server '%s'.",
@@ -355,7 +451,7 @@ func resourceStorageControllerDelete(data *schema.ResourceData, provider interfa
 }
 
 // Import data for an existing storage controller.
-func resourceStorageControllerImport(data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+func resourceStorageControllerImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
@@ -374,11 +470,32 @@ func resourceStorageControllerImport(data *schema.ResourceData, provider interfa
 	}
 	storageController := server.SCSIControllers.GetByID(storageControllerID)
 	if storageController == nil {
+		// May be a SATA or IDE controller.
+		var controllers *serverStorageControllers
+		controllers, err = getServerSATAAndIDEControllers(ctx, providerState, serverID)
+		if err != nil {
+			return
+		}
+		if controllers != nil {
+			if otherController, controllerType := controllers.GetByID(storageControllerID); otherController != nil {
+				data.Set(resourceKeyStorageControllerType, controllerType)
+				data.Set(resourceKeyStorageControllerBusNumber, otherController.GetBusNumber(controllerType))
+				propertyHelper(data).SetDisks(
+					otherController.GetDisks(controllerType),
+				)
+
+				importedData = []*schema.ResourceData{data}
+
+				return
+			}
+		}
+
 		err = fmt.Errorf("Storage controller '%s' not found in server '%s'", storageControllerID, serverID)
 
 		return
 	}
 
+	data.Set(resourceKeyStorageControllerType, models.DiskControllerTypeSCSI)
 	data.Set(resourceKeyStorageControllerBusNumber, storageController.BusNumber)
 	data.Set(resourceKeyStorageControllerAdapterType, storageController.AdapterType)
 	propertyHelper(data).SetDisks(
@@ -391,7 +508,7 @@ func resourceStorageControllerImport(data *schema.ResourceData, provider interfa
 }
 
 // When updating a storage controller resource, synchronise the controller's disk attributes with its resource data
-func updateStorageControllerDisks(data *schema.ResourceData, providerState *providerState) error {
+func updateStorageControllerDisks(ctx context.Context, data *schema.ResourceData, providerState *providerState) error {
 	propertyHelper := propertyHelper(data)
 	controllerID := data.Id()
 	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
@@ -399,6 +516,12 @@ func updateStorageControllerDisks(data *schema.ResourceData, providerState *prov
 	log.Printf("Configure disks for storage controller '%s' in server '%s'...", controllerID, serverID)
 
 	apiClient := providerState.Client()
+
+	diskResourceSCSIPaths, err := getDiskResourceSCSIPaths(apiClient, serverID)
+	if err != nil {
+		return err
+	}
+
 	server, err := apiClient.GetServer(serverID)
 	if err != nil {
 		return err
@@ -419,17 +542,12 @@ func updateStorageControllerDisks(data *schema.ResourceData, providerState *prov
 	)
 
 	// Filter disks so we're only looking at ones from this controller.
-	actualDisks := models.NewDisksFromVirtualMachineSCSIController(*targetController)
+	actualDisks := models.NewDisksFromVirtualMachineSCSIController(*targetController).Managed(diskResourceSCSIPaths)
 	log.Printf("Storage controller '%s' currently has %d disks: %#v.", controllerID, len(actualDisks), actualDisks)
 
 	configuredDisks := propertyHelper.GetDisks()
 	log.Printf("Configuration for storage controller '%s' (bus %d) specifies %d disks: %#v.", controllerID, targetController.BusNumber, len(configuredDisks), configuredDisks)
 
-	err = validateStorageControllerDisks(configuredDisks)
-	if err != nil {
-		return err
-	}
-
 	addDisks, modifyDisks, removeDisks := configuredDisks.SplitByAction(actualDisks)
 	if addDisks.IsEmpty() && modifyDisks.IsEmpty() && removeDisks.IsEmpty() {
 		log.Printf("No changes required for disks of storage controller '%s' in server '%s'.", controllerID, serverID)
@@ -437,35 +555,46 @@ func updateStorageControllerDisks(data *schema.ResourceData, providerState *prov
 		return nil
 	}
 
-	// First remove any disks that are no longer required.
-	err = processRemoveStorageControllerDisks(removeDisks, data, providerState)
-	if err != nil {
-		return err
-	}
+	applyDiskChanges := func() error {
+		// First remove any disks that are no longer required.
+		err := processRemoveStorageControllerDisks(ctx, removeDisks, data, providerState)
+		if err != nil {
+			return err
+		}
 
-	// Then modify existing disks
-	err = processModifyStorageControllerDisks(modifyDisks, data, providerState)
-	if err != nil {
-		return err
+		// Then modify existing disks
+		err = processModifyStorageControllerDisks(ctx, modifyDisks, data, providerState)
+		if err != nil {
+			return err
+		}
+
+		// Finally, add new disks
+		return processAddStorageControllerDisks(ctx, addDisks, data, providerState)
 	}
 
-	// Finally, add new disks
-	err = processAddStorageControllerDisks(addDisks, data, providerState)
-	if err != nil {
-		return err
+	if modifyDisks.RequirePowerCycle(actualDisks) {
+		log.Printf("Disk changes for storage controller '%s' require server '%s' to be powered off.", controllerID, serverID)
+
+		return withServerPoweredOff(ctx, providerState, serverID, applyDiskChanges)
 	}
 
-	return nil
+	return applyDiskChanges()
 }
 
 // Process the collection of disks that need to be added to the server.
-func processAddStorageControllerDisks(addDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
+func processAddStorageControllerDisks(ctx context.Context, addDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
 	propertyHelper := propertyHelper(data)
 	controllerID := data.Id()
 	busNumber := data.Get(resourceKeyStorageControllerBusNumber).(int)
 	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
 
 	apiClient := providerState.Client()
+
+	diskResourceSCSIPaths, err := getDiskResourceSCSIPaths(apiClient, serverID)
+	if err != nil {
+		return err
+	}
+
 	targetController, err := getStorageController(apiClient, data)
 	if err != nil {
 		return err
@@ -481,7 +610,7 @@ func processAddStorageControllerDisks(addDisks models.Disks, data *schema.Resour
 			busNumber,
 			serverID,
 		)
-		err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+		err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 			asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 			defer asyncLock.Release()
 
@@ -530,9 +659,8 @@ func processAddStorageControllerDisks(addDisks models.Disks, data *schema.Resour
 			return fmt.Errorf("cannot find controller '%s' in server '%s'", controllerID, serverID)
 		}
 		propertyHelper.SetDisks(
-			models.NewDisksFromVirtualMachineSCSIController(*targetController),
+			models.NewDisksFromVirtualMachineSCSIController(*targetController).Managed(diskResourceSCSIPaths),
 		)
-		propertyHelper.SetPartial(resourceKeyStorageControllerDisk)
 
 		log.Printf("Server '%s' now has %d disks: %#v.", serverID, server.SCSIControllers.GetDiskCount(), server.SCSIControllers)
 
@@ -551,11 +679,17 @@ func processAddStorageControllerDisks(addDisks models.Disks, data *schema.Resour
 // Process the collection of disks whose configuration needs to be modified.
 //
 // Disk Ids must already be populated.
-func processModifyStorageControllerDisks(modifyDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
+func processModifyStorageControllerDisks(ctx context.Context, modifyDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
 	propertyHelper := propertyHelper(data)
 	controllerID := data.Id()
 	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
 	apiClient := providerState.Client()
+
+	diskResourceSCSIPaths, err := getDiskResourceSCSIPaths(apiClient, serverID)
+	if err != nil {
+		return err
+	}
+
 	targetController, err := getStorageController(apiClient, data)
 	if err != nil {
 		return err
@@ -599,7 +733,7 @@ func processModifyStorageControllerDisks(modifyDisks models.Disks, data *schema.
 				targetController.BusNumber,
 				serverID,
 			)
-			err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+			err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 				asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 				defer asyncLock.Release()
 
@@ -649,9 +783,8 @@ func processModifyStorageControllerDisks(modifyDisks models.Disks, data *schema.
 				return fmt.Errorf("cannot find controller '%s' in server '%s'", controllerID, serverID)
 			}
 			propertyHelper.SetDisks(
-				models.NewDisksFromVirtualMachineSCSIController(*targetController),
+				models.NewDisksFromVirtualMachineSCSIController(*targetController).Managed(diskResourceSCSIPaths),
 			)
-			propertyHelper.SetPartial(resourceKeyServerDisk)
 
 			log.Printf("storage controller '%s' now has %d disks: %#v.", targetController.ID, len(targetController.Disks), targetController)
 
@@ -690,7 +823,7 @@ func processModifyStorageControllerDisks(modifyDisks models.Disks, data *schema.
 			var iopErr error
 			// var response *compute.APIResponseV2
 
-			err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+			err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 
 				asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 				defer asyncLock.Release()
@@ -730,9 +863,8 @@ func processModifyStorageControllerDisks(modifyDisks models.Disks, data *schema.
 				return fmt.Errorf("cannot find controller '%s' in server '%s'", controllerID, serverID)
 			}
 			propertyHelper.SetDisks(
-				models.NewDisksFromVirtualMachineSCSIController(*targetController),
+				models.NewDisksFromVirtualMachineSCSIController(*targetController).Managed(diskResourceSCSIPaths),
 			)
-			propertyHelper.SetPartial(resourceKeyServerDisk)
 
 			log.Printf("Changed speed of disk '%s' on storage controller '%s' (SCSI bus %d) in server '%s' (from '%s' to GB to '%s').",
 				modifyDisk.ID,
@@ -770,9 +902,8 @@ func processModifyStorageControllerDisks(modifyDisks models.Disks, data *schema.
 
 			server := resource.(*compute.Server)
 			propertyHelper.SetDisks(
-				models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers),
+				models.NewDisksFromVirtualMachineSCSIControllers(server.SCSIControllers).Managed(diskResourceSCSIPaths),
 			)
-			propertyHelper.SetPartial(resourceKeyServerDisk)
 		}
 	}
 
@@ -782,12 +913,18 @@ func processModifyStorageControllerDisks(modifyDisks models.Disks, data *schema.
 // Process the collection of disks that need to be removed.
 //
 // Disk Ids must already be populated.
-func processRemoveStorageControllerDisks(removeDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
+func processRemoveStorageControllerDisks(ctx context.Context, removeDisks models.Disks, data *schema.ResourceData, providerState *providerState) error {
 	propertyHelper := propertyHelper(data)
 	controllerID := data.Id()
 	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
 
 	apiClient := providerState.Client()
+
+	diskResourceSCSIPaths, err := getDiskResourceSCSIPaths(apiClient, serverID)
+	if err != nil {
+		return err
+	}
+
 	targetController, err := getStorageController(apiClient, data)
 	if err != nil {
 		return err
@@ -810,7 +947,7 @@ func processRemoveStorageControllerDisks(removeDisks models.Disks, data *schema.
 			targetController.BusNumber,
 			serverID,
 		)
-		err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+		err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 			asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 			defer asyncLock.Release()
 
@@ -865,9 +1002,8 @@ func processRemoveStorageControllerDisks(removeDisks models.Disks, data *schema.
 			return fmt.Errorf("cannot find controller '%s' in server '%s'", controllerID, serverID)
 		}
 		propertyHelper.SetDisks(
-			models.NewDisksFromVirtualMachineSCSIController(*targetController),
+			models.NewDisksFromVirtualMachineSCSIController(*targetController).Managed(diskResourceSCSIPaths),
 		)
-		propertyHelper.SetPartial(resourceKeyServerDisk)
 
 		log.Printf(
 			"Removed disk '%s' (SCSI unit Id %d) from storage controller '%s' (SCSI bus %d) in server '%s'.",
@@ -882,8 +1018,8 @@ func processRemoveStorageControllerDisks(removeDisks models.Disks, data *schema.
 	return nil
 }
 
-func validateStorageControllerDisks(disks models.Disks) error {
-	return validateDisks(disks)
+func validateStorageControllerDisks(disks models.Disks) diag.Diagnostics {
+	return validateDisks(disks, resourceKeyStorageControllerDisk)
 }
 
 func getStorageController(apiClient *compute.Client, data *schema.ResourceData) (*compute.VirtualMachineSCSIController, error) {
@@ -0,0 +1,512 @@
+package ddcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+/*
+ * SATA and IDE controllers.
+ *
+ * The CloudControl client library only models a server's SCSI controllers, so SATA and IDE controllers (and their disks) are read from
+ * the "sataController" and "ideController" elements of the server's API representation, and disks are added to them using "server/addDisk".
+ * Other disk operations (expand, change speed or IOPS, remove) identify the disk by Id, so they work the same way for all controller types.
+ *
+ * Servers get their SATA and IDE controllers from their image; ddcloud_storage_controller manages the disks attached to them, but does not add or remove the controllers themselves.
+ */
+
+// The SATA and IDE controllers in a server.
+type serverStorageControllers struct {
+	SATAControllers []serverStorageController `json:"sataController"`
+	IDEControllers  []serverStorageController `json:"ideController"`
+}
+
+// A SATA or IDE controller in a server.
+type serverStorageController struct {
+	ID          string                        `json:"id"`
+	AdapterType string                        `json:"adapterType"`
+	Key         int                           `json:"key"`
+	State       string                        `json:"state"`
+	BusNumber   int                           `json:"busNumber"` // SATA controllers only.
+	Channel     int                           `json:"channel"`   // IDE controllers only.
+	Disks       []serverStorageControllerDisk `json:"disk"`
+}
+
+// A disk attached to a SATA or IDE controller.
+type serverStorageControllerDisk struct {
+	ID          string `json:"id"`
+	SizeGB      int    `json:"sizeGb"`
+	Speed       string `json:"speed"`
+	Iops        int    `json:"iops"`
+	State       string `json:"state"`
+	SATAID      int    `json:"sataId"`      // SATA controllers only.
+	DriveNumber int    `json:"driveNumber"` // IDE controllers only.
+}
+
+// Request body when adding a disk to a SATA controller.
+type addDiskToSATAController struct {
+	SATAController struct {
+		ControllerID string `json:"controllerId"`
+		SATAID       int    `json:"sataId"`
+	} `json:"sataController"`
+	SizeGB int    `json:"sizeGb"`
+	Speed  string `json:"speed"`
+	Iops   int    `json:"iops,omitempty"`
+}
+
+// Request body when adding a disk to an IDE controller.
+type addDiskToIDEController struct {
+	IDEController struct {
+		ControllerID string `json:"controllerId"`
+		DriveNumber  int    `json:"driveNumber"`
+	} `json:"ideController"`
+	SizeGB int    `json:"sizeGb"`
+	Speed  string `json:"speed"`
+	Iops   int    `json:"iops,omitempty"`
+}
+
+// Get the controllers of the specified type.
+func (controllers *serverStorageControllers) ByType(controllerType string) []serverStorageController {
+	switch controllerType {
+	case models.DiskControllerTypeSATA:
+		return controllers.SATAControllers
+	case models.DiskControllerTypeIDE:
+		return controllers.IDEControllers
+	default:
+		return nil
+	}
+}
+
+// Get the controller of the specified type with the specified bus number (or IDE channel).
+//
+// Returns nil if no matching controller was found.
+func (controllers *serverStorageControllers) GetByBusNumber(controllerType string, busNumber int) *serverStorageController {
+	typedControllers := controllers.ByType(controllerType)
+	for index := range typedControllers {
+		if typedControllers[index].GetBusNumber(controllerType) == busNumber {
+			return &typedControllers[index]
+		}
+	}
+
+	return nil
+}
+
+// Get the controller with the specified Id.
+//
+// Returns the controller (nil if not found) and its type.
+func (controllers *serverStorageControllers) GetByID(controllerID string) (*serverStorageController, string) {
+	for _, controllerType := range []string{models.DiskControllerTypeSATA, models.DiskControllerTypeIDE} {
+		typedControllers := controllers.ByType(controllerType)
+		for index := range typedControllers {
+			if typedControllers[index].ID == controllerID {
+				return &typedControllers[index], controllerType
+			}
+		}
+	}
+
+	return nil, ""
+}
+
+// Get the controller's bus number (or, for IDE controllers, its channel).
+func (controller *serverStorageController) GetBusNumber(controllerType string) int {
+	if controllerType == models.DiskControllerTypeIDE {
+		return controller.Channel
+	}
+
+	return controller.BusNumber
+}
+
+// Get the disks attached to the controller.
+func (controller *serverStorageController) GetDisks(controllerType string) models.Disks {
+	disks := make(models.Disks, len(controller.Disks))
+	for index, controllerDisk := range controller.Disks {
+		disk := models.Disk{
+			ID:             controllerDisk.ID,
+			ControllerType: controllerType,
+			SCSIBusNumber:  controller.GetBusNumber(controllerType),
+			SCSIUnitID:     controllerDisk.SATAID,
+			SizeGB:         controllerDisk.SizeGB,
+			Speed:          controllerDisk.Speed,
+			Iops:           controllerDisk.Iops,
+		}
+		if controllerType == models.DiskControllerTypeIDE {
+			disk.SCSIUnitID = controllerDisk.DriveNumber
+		}
+
+		disks[index] = disk
+	}
+	disks.SortBySCSIPath()
+
+	return disks
+}
+
+// Get the SATA and IDE controllers in a server.
+//
+// Returns nil if the server was not found.
+func getServerSATAAndIDEControllers(ctx context.Context, providerState *providerState, serverID string) (*serverStorageControllers, error) {
+	controllers := &serverStorageControllers{}
+	found, err := readCloudControlAPI(ctx, providerState, "server/server/"+url.PathEscape(serverID), controllers)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	return controllers, nil
+}
+
+// Get the type of a storage controller resource's controller.
+func getStorageControllerType(data *schema.ResourceData) string {
+	controllerType := data.Get(resourceKeyStorageControllerType).(string)
+	if controllerType == "" {
+		return models.DiskControllerTypeSCSI
+	}
+
+	return controllerType
+}
+
+// Create a storage controller resource for an existing SATA or IDE controller.
+func resourceSATAOrIDEStorageControllerCreate(ctx context.Context, data *schema.ResourceData, providerState *providerState, controllerType string) diag.Diagnostics {
+	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
+	busNumber := data.Get(resourceKeyStorageControllerBusNumber).(int)
+
+	controllers, err := getServerSATAAndIDEControllers(ctx, providerState, serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if controllers == nil {
+		return diag.Errorf("cannot find server '%s'", serverID)
+	}
+
+	targetController := controllers.GetByBusNumber(controllerType, busNumber)
+	if targetController == nil {
+		return diag.Errorf("server '%s' does not have a %s controller for bus %d (SATA and IDE controllers must already be present in the server)", serverID, controllerType, busNumber)
+	}
+
+	log.Printf("Using existing %s controller '%s' (bus %d) in server '%s'.", controllerType, targetController.ID, busNumber, serverID)
+
+	data.SetId(targetController.ID)
+
+	if propertyHelper(data).GetDisks().IsEmpty() {
+		// No explicitly-configured disks so just populate from current controller state.
+		return resourceSATAOrIDEStorageControllerRead(ctx, data, providerState)
+	}
+
+	err = updateSATAOrIDEStorageControllerDisks(ctx, data, providerState, controllerType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceSATAOrIDEStorageControllerRead(ctx, data, providerState)
+}
+
+// Read a storage controller resource for a SATA or IDE controller.
+func resourceSATAOrIDEStorageControllerRead(ctx context.Context, data *schema.ResourceData, providerState *providerState) diag.Diagnostics {
+	controllerID := data.Id()
+	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
+
+	controllers, err := getServerSATAAndIDEControllers(ctx, providerState, serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if controllers == nil {
+		log.Printf("Cannot find server '%s' for controller '%s'.", serverID, controllerID)
+
+		// If the server is deleted, then so is the controller.
+		data.SetId("")
+
+		return nil
+	}
+
+	targetController, controllerType := controllers.GetByID(controllerID)
+	if targetController == nil {
+		log.Printf("Cannot find controller '%s' in server '%s'.", controllerID, serverID)
+
+		// Mark as deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	actualDisks := targetController.GetDisks(controllerType)
+	log.Printf("%s controller '%s' in server '%s' currently has %d disks: %#v.", controllerType, controllerID, serverID, len(actualDisks), actualDisks)
+
+	data.Set(resourceKeyStorageControllerType, controllerType)
+	data.Set(resourceKeyStorageControllerBusNumber, targetController.GetBusNumber(controllerType))
+	propertyHelper(data).SetDisks(actualDisks)
+
+	return nil
+}
+
+// Update a storage controller resource for a SATA or IDE controller.
+func resourceSATAOrIDEStorageControllerUpdate(ctx context.Context, data *schema.ResourceData, providerState *providerState, controllerType string) diag.Diagnostics {
+	if !propertyHelper(data).GetDisks().IsEmpty() {
+		err := updateSATAOrIDEStorageControllerDisks(ctx, data, providerState, controllerType)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceSATAOrIDEStorageControllerRead(ctx, data, providerState)
+}
+
+// Delete a storage controller resource for a SATA or IDE controller.
+//
+// The controller's disks are removed, but the controller itself remains in the server.
+func resourceSATAOrIDEStorageControllerDelete(ctx context.Context, data *schema.ResourceData, providerState *providerState) diag.Diagnostics {
+	controllerID := data.Id()
+	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
+
+	controllers, err := getServerSATAAndIDEControllers(ctx, providerState, serverID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if controllers == nil {
+		return nil
+	}
+	targetController, controllerType := controllers.GetByID(controllerID)
+	if targetController == nil {
+		return nil
+	}
+
+	log.Printf("Delete storage controller '%s' in server '%s' (the %s controller will not be removed).", controllerID, serverID, controllerType)
+
+	for _, removeDisk := range targetController.GetDisks(controllerType) {
+		err = removeServerDiskByID(ctx, providerState, serverID, removeDisk.ID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+// Update the disks attached to a SATA or IDE controller to match the resource configuration.
+//
+// If the server is running and the changes require it to be powered off (see models.Disks.RequirePowerCycle), then it will be stopped before updating disks, and then restarted.
+func updateSATAOrIDEStorageControllerDisks(ctx context.Context, data *schema.ResourceData, providerState *providerState, controllerType string) error {
+	propertyHelper := propertyHelper(data)
+	controllerID := data.Id()
+	serverID := data.Get(resourceKeyStorageControllerServerID).(string)
+
+	controllers, err := getServerSATAAndIDEControllers(ctx, providerState, serverID)
+	if err != nil {
+		return err
+	}
+	if controllers == nil {
+		return fmt.Errorf("cannot find server '%s'", serverID)
+	}
+	targetController, _ := controllers.GetByID(controllerID)
+	if targetController == nil {
+		return fmt.Errorf("cannot find controller '%s' in server '%s'", controllerID, serverID)
+	}
+
+	busNumber := targetController.GetBusNumber(controllerType)
+	actualDisks := targetController.GetDisks(controllerType)
+
+	configuredDisks := propertyHelper.GetDisks()
+	for index := range configuredDisks {
+		configuredDisks[index].ControllerType = controllerType
+		configuredDisks[index].SCSIBusNumber = busNumber
+	}
+	configuredDisks.CaptureIDs(actualDisks)
+
+	addDisks, modifyDisks, removeDisks := configuredDisks.SplitByAction(actualDisks)
+	log.Printf("%s controller '%s' in server '%s': %d disks to add, %d to modify, %d to remove.", controllerType, controllerID, serverID, len(addDisks), len(modifyDisks), len(removeDisks))
+
+	applyDiskChanges := func() error {
+		for _, removeDisk := range removeDisks {
+			err := removeServerDiskByID(ctx, providerState, serverID, removeDisk.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		actualDisksBySCSIPath := actualDisks.BySCSIPath()
+		for _, modifyDisk := range modifyDisks {
+			err := modifyServerDiskByID(ctx, providerState, serverID, actualDisksBySCSIPath[modifyDisk.SCSIPath()], modifyDisk)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, addDisk := range addDisks {
+			err := addDiskToSATAOrIDEController(ctx, providerState, serverID, targetController.ID, addDisk)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if modifyDisks.RequirePowerCycle(actualDisks) {
+		log.Printf("Disk changes for %s controller '%s' require server '%s' to be powered off.", controllerType, controllerID, serverID)
+
+		return withServerPoweredOff(ctx, providerState, serverID, applyDiskChanges)
+	}
+
+	return applyDiskChanges()
+}
+
+// Add a disk to a SATA or IDE controller.
+func addDiskToSATAOrIDEController(ctx context.Context, providerState *providerState, serverID string, controllerID string, disk models.Disk) error {
+	speed := normalizeSpeed(disk.Speed)
+	iops := 0
+	if speed == compute.ServerDiskSpeedProvisionedIops {
+		iops = disk.Iops
+	}
+
+	var requestBody interface{}
+	if disk.GetControllerType() == models.DiskControllerTypeIDE {
+		request := &addDiskToIDEController{SizeGB: disk.SizeGB, Speed: speed, Iops: iops}
+		request.IDEController.ControllerID = controllerID
+		request.IDEController.DriveNumber = disk.SCSIUnitID
+		requestBody = request
+	} else {
+		request := &addDiskToSATAController{SizeGB: disk.SizeGB, Speed: speed, Iops: iops}
+		request.SATAController.ControllerID = controllerID
+		request.SATAController.SATAID = disk.SCSIUnitID
+		requestBody = request
+	}
+
+	operationDescription := fmt.Sprintf("Add disk with unit ID %d to %s controller '%s' in server '%s'",
+		disk.SCSIUnitID,
+		disk.GetControllerType(),
+		controllerID,
+		serverID,
+	)
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release()
+
+		response, addDiskError := invokeCloudControlAPI(ctx, providerState, http.MethodPost, "server/addDisk", requestBody)
+		if addDiskError != nil {
+			context.Fail(addDiskError)
+		} else if response.ResponseCode == compute.ResponseCodeResourceBusy {
+			context.Retry()
+		} else if response.ResponseCode != compute.ResponseCodeInProgress {
+			context.Fail(response.ToError("%s failed (response code '%s'): %s", operationDescription, response.ResponseCode, response.Message))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = providerState.Client().WaitForChange(
+		compute.ResourceTypeServer,
+		serverID,
+		"Add disk",
+		resourceUpdateTimeoutServer,
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Added disk with unit ID %d to %s controller '%s' in server '%s'.", disk.SCSIUnitID, disk.GetControllerType(), controllerID, serverID)
+
+	return nil
+}
+
+// Expand a disk, or change its speed or IOPS, to match the configured disk.
+func modifyServerDiskByID(ctx context.Context, providerState *providerState, serverID string, actualDisk models.Disk, configuredDisk models.Disk) error {
+	apiClient := providerState.Client()
+	diskID := actualDisk.ID
+
+	if configuredDisk.SizeGB < actualDisk.SizeGB {
+		return fmt.Errorf("cannot resize disk '%s' in server '%s' from %d GB to %d GB (disks can only be expanded)", diskID, serverID, actualDisk.SizeGB, configuredDisk.SizeGB)
+	}
+
+	var operations []func() (*compute.APIResponseV2, error)
+	if configuredDisk.SizeGB > actualDisk.SizeGB {
+		operations = append(operations, func() (*compute.APIResponseV2, error) {
+			return apiClient.ExpandDisk(diskID, configuredDisk.SizeGB)
+		})
+	}
+
+	speed := normalizeSpeed(configuredDisk.Speed)
+	iops := configuredDisk.Iops
+	if speed != normalizeSpeed(actualDisk.Speed) {
+		operations = append(operations, func() (*compute.APIResponseV2, error) {
+			if speed == compute.ServerDiskSpeedProvisionedIops {
+				return apiClient.ChangeServerDiskSpeed(serverID, diskID, speed, &iops)
+			}
+
+			return apiClient.ChangeServerDiskSpeed(serverID, diskID, speed, nil)
+		})
+	} else if speed == compute.ServerDiskSpeedProvisionedIops && iops != actualDisk.Iops {
+		operations = append(operations, func() (*compute.APIResponseV2, error) {
+			return apiClient.ChangeServerDiskIops(diskID, iops)
+		})
+	}
+
+	for _, operation := range operations {
+		operationDescription := fmt.Sprintf("Modify disk '%s' in server '%s'", diskID, serverID)
+		err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+			asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+			defer asyncLock.Release()
+
+			_, modifyError := operation()
+			if compute.IsResourceBusyError(modifyError) {
+				context.Retry()
+			} else if modifyError != nil {
+				context.Fail(modifyError)
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = apiClient.WaitForChange(
+			compute.ResourceTypeServer,
+			serverID,
+			fmt.Sprintf("Modify disk '%s'", diskID),
+			resourceUpdateTimeoutServer,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove a disk from a server.
+func removeServerDiskByID(ctx context.Context, providerState *providerState, serverID string, diskID string) error {
+	apiClient := providerState.Client()
+
+	operationDescription := fmt.Sprintf("Remove disk '%s' from server '%s'", diskID, serverID)
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release()
+
+		removeError := apiClient.RemoveDiskFromServer(diskID)
+		if compute.IsResourceBusyError(removeError) {
+			context.Retry()
+		} else if removeError != nil {
+			context.Fail(removeError)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = apiClient.WaitForChange(
+		compute.ResourceTypeServer,
+		serverID,
+		fmt.Sprintf("Remove disk '%s'", diskID),
+		resourceUpdateTimeoutServer,
+	)
+
+	return err
+}
@@ -1,14 +1,16 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/models"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 /*
@@ -450,6 +452,131 @@ func TestAccStorageController2With1DiskEachRemoveSecondController(t *testing.T)
 	})
 }
 
+/*
+ * Unit tests.
+ */
+
+// Unit test - reading a storage controller ignores disks managed by ddcloud_disk resources, but reports other disks (e.g. those added outside of Terraform).
+func TestStorageControllerReadIgnoresDiskResources(test *testing.T) {
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.ComputeServer = &compute.Server{
+		ID: "server-1",
+		SCSIControllers: compute.VirtualMachineSCSIControllers{
+			compute.VirtualMachineSCSIController{
+				ID:        "controller-0",
+				BusNumber: 0,
+				Disks: compute.VirtualMachineDisks{
+					compute.VirtualMachineDisk{ID: "image-disk", SCSIUnitID: 0, SizeGB: 10, Speed: compute.ServerDiskSpeedStandard},
+					compute.VirtualMachineDisk{ID: "disk-resource", SCSIUnitID: 1, SizeGB: 20, Speed: compute.ServerDiskSpeedStandard},
+					compute.VirtualMachineDisk{ID: "portal-disk", SCSIUnitID: 2, SizeGB: 30, Speed: compute.ServerDiskSpeedStandard},
+				},
+			},
+		},
+	}
+	standIn.ServerTags = []compute.Tag{
+		{Name: diskResourcesTagName, Value: "0:1"},
+	}
+
+	data := resourceStorageController().Data(nil)
+	data.SetId("controller-0")
+	data.Set(resourceKeyStorageControllerServerID, "server-1")
+
+	diagnostics := resourceStorageControllerRead(context.Background(), data, standIn.NewProviderState())
+
+	assert := assert.ForTest(test)
+	assert.IsFalse("diagnostics.HasError", diagnostics.HasError())
+
+	disks := propertyHelper(data).GetDisks()
+	assert.EqualsInt("len(disks)", 2, len(disks))
+	assert.EqualsString("disks[0].ID", "image-disk", disks[0].ID)
+	assert.EqualsString("disks[1].ID", "portal-disk", disks[1].ID)
+}
+
+// Unit test - reading a SATA controller populates its disks from the server's "sataController" elements.
+func TestStorageControllerReadSATA(test *testing.T) {
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.ComputeServer = &compute.Server{
+		ID:    "server-1",
+		State: compute.ResourceStatusNormal,
+	}
+	standIn.SATAControllers = []serverStorageController{
+		{
+			ID:        "sata-controller-0",
+			BusNumber: 0,
+			Disks: []serverStorageControllerDisk{
+				{ID: "sata-disk-1", SATAID: 1, SizeGB: 20, Speed: compute.ServerDiskSpeedStandard},
+				{ID: "sata-disk-0", SATAID: 0, SizeGB: 10, Speed: compute.ServerDiskSpeedHighPerformance},
+			},
+		},
+	}
+
+	data := resourceStorageController().Data(nil)
+	data.SetId("sata-controller-0")
+	data.Set(resourceKeyStorageControllerServerID, "server-1")
+	data.Set(resourceKeyStorageControllerType, models.DiskControllerTypeSATA)
+
+	diagnostics := resourceStorageControllerRead(context.Background(), data, standIn.NewProviderState())
+
+	assert := assert.ForTest(test)
+	assert.IsFalse("diagnostics.HasError", diagnostics.HasError())
+	assert.EqualsString("data.Id", "sata-controller-0", data.Id())
+	assert.EqualsString("controller_type", models.DiskControllerTypeSATA, data.Get(resourceKeyStorageControllerType).(string))
+
+	disks := propertyHelper(data).GetDisks()
+	assert.EqualsInt("len(disks)", 2, len(disks))
+	assert.EqualsString("disks[0].ID", "sata-disk-0", disks[0].ID)
+	assert.EqualsInt("disks[0].SCSIUnitID", 0, disks[0].SCSIUnitID)
+	assert.EqualsString("disks[1].ID", "sata-disk-1", disks[1].ID)
+	assert.EqualsInt("disks[1].SizeGB", 20, disks[1].SizeGB)
+}
+
+// Unit test - adding a disk to an IDE controller identifies the controller and drive number.
+func TestStorageControllerAddIDEDisk(test *testing.T) {
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	standIn.ComputeServer = &compute.Server{
+		ID:    "server-1",
+		State: compute.ResourceStatusNormal,
+	}
+	standIn.IDEControllers = []serverStorageController{
+		{ID: "ide-controller-1", Channel: 1},
+	}
+
+	disk := models.Disk{
+		ControllerType: models.DiskControllerTypeIDE,
+		SCSIBusNumber:  1,
+		SCSIUnitID:     1,
+		SizeGB:         15,
+		Speed:          "standard",
+	}
+	err := addDiskToSATAOrIDEController(context.Background(), standIn.NewProviderState(), "server-1", "ide-controller-1", disk)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("len(AddDiskRequests)", 1, len(standIn.AddDiskRequests))
+
+	addDiskRequest := standIn.AddDiskRequests[0]
+	assert.Equals("request.ideController", map[string]interface{}{
+		"controllerId": "ide-controller-1",
+		"driveNumber":  float64(1),
+	}, addDiskRequest["ideController"])
+	assert.Equals("request.sizeGb", float64(15), addDiskRequest["sizeGb"])
+	assert.Equals("request.speed", compute.ServerDiskSpeedStandard, addDiskRequest["speed"])
+	_, haveIops := addDiskRequest["iops"]
+	assert.IsFalse("request has iops", haveIops)
+
+	disks := standIn.IDEControllers[0].Disks
+	assert.EqualsInt("len(disks)", 1, len(disks))
+	assert.EqualsString("disks[0].ID", "test-disk-1", disks[0].ID)
+}
+
 /*
  * Acceptance-test checks.
  */
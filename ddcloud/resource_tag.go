@@ -5,7 +5,7 @@ import (
 	"log"
 
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -62,6 +62,10 @@ func applyTags(data *schema.ResourceData, apiClient *compute.Client, assetType s
 		F: schema.HashString,
 	}
 	for _, tag := range tags {
+		if tag.Name == diskResourcesTagName {
+			continue // Managed by ddcloud_disk resources.
+		}
+
 		unusedTags.Add(tag.Name)
 	}
 	for _, tag := range configuredTags {
@@ -120,7 +124,15 @@ func readTags(data *schema.ResourceData, apiClient *compute.Client, assetType st
 
 	log.Printf("Read %d tags for resource '%s'.", len(tags), resourceID)
 
-	propertyHelper.SetTags(resourceID, tags)
+	// The tag that records the disks managed by ddcloud_disk resources is not part of the resource's configuration.
+	configurableTags := make([]compute.Tag, 0, len(tags))
+	for _, tag := range tags {
+		if tag.Name != diskResourcesTagName {
+			configurableTags = append(configurableTags, tag)
+		}
+	}
+
+	propertyHelper.SetTags(resourceID, configurableTags)
 
 	return nil
 }
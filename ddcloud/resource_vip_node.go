@@ -1,11 +1,13 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -23,11 +25,10 @@ const (
 
 func resourceVIPNode() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVIPNodeCreate,
-		Read:   resourceVIPNodeRead,
-		Exists: resourceVIPNodeExists,
-		Update: resourceVIPNodeUpdate,
-		Delete: resourceVIPNodeDelete,
+		CreateContext: resourceVIPNodeCreate,
+		ReadContext:   resourceVIPNodeRead,
+		UpdateContext: resourceVIPNodeUpdate,
+		DeleteContext: resourceVIPNodeDelete,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyVIPNodeName: &schema.Schema{
@@ -125,7 +126,7 @@ func resourceVIPNode() *schema.Resource {
 	}
 }
 
-func resourceVIPNodeCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPNodeCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	networkDomainID := data.Get(resourceKeyVIPNodeNetworkDomainID).(string)
 	name := data.Get(resourceKeyVIPNodeName).(string)
 	description := data.Get(resourceKeyVIPNodeDescription).(string)
@@ -140,7 +141,7 @@ func resourceVIPNodeCreate(data *schema.ResourceData, provider interface{}) erro
 		page.PageSize = 50
 		healthMonitors, err := apiClient.ListDefaultHealthMonitors(networkDomainID, page)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		for _, healthMonitor := range healthMonitors.Items {
 			if healthMonitor.Name == healthMonitorName {
@@ -160,7 +161,7 @@ func resourceVIPNodeCreate(data *schema.ResourceData, provider interface{}) erro
 		NetworkDomainID:     networkDomainID,
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(vipNodeID)
@@ -169,11 +170,11 @@ func resourceVIPNodeCreate(data *schema.ResourceData, provider interface{}) erro
 
 	vipNode, err := apiClient.GetVIPNode(vipNodeID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if vipNode == nil {
-		return fmt.Errorf("cannot find newly-added VIP node '%s'", vipNodeID)
+		return diag.Errorf("cannot find newly-added VIP node '%s'", vipNodeID)
 	}
 
 	data.Set(resourceKeyVIPNodeDescription, vipNode.Description)
@@ -185,27 +186,7 @@ func resourceVIPNodeCreate(data *schema.ResourceData, provider interface{}) erro
 	return nil
 }
 
-func resourceVIPNodeExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	id := data.Id()
-
-	log.Printf("Check if VIP node '%s' exists...", id)
-
-	providerState := provider.(*providerState)
-	apiClient := providerState.Client()
-
-	vipNode, err := apiClient.GetVIPNode(id)
-	if err != nil {
-		return false, err
-	}
-
-	exists := vipNode != nil
-
-	log.Printf("VIP node '%s' exists: %t.", id, exists)
-
-	return exists, nil
-}
-
-func resourceVIPNodeRead(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPNodeRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 
 	log.Printf("Read VIP node '%s'...", id)
@@ -215,7 +196,7 @@ func resourceVIPNodeRead(data *schema.ResourceData, provider interface{}) error
 
 	vipNode, err := apiClient.GetVIPNode(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if vipNode == nil {
 		data.SetId("") // VIP node has been deleted
@@ -228,7 +209,7 @@ func resourceVIPNodeRead(data *schema.ResourceData, provider interface{}) error
 	return nil
 }
 
-func resourceVIPNodeUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPNodeUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	log.Printf("Update VIP node '%s'...", id)
 	networkDomainID := data.Get(resourceKeyVIPNodeNetworkDomainID).(string)
@@ -252,7 +233,7 @@ func resourceVIPNodeUpdate(data *schema.ResourceData, provider interface{}) erro
 			page.PageSize = 50
 			healthMonitors, err := apiClient.ListDefaultHealthMonitors(networkDomainID, page)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			for _, healthMonitor := range healthMonitors.Items {
 				if healthMonitor.Name == *healthMonitorName {
@@ -271,10 +252,10 @@ func resourceVIPNodeUpdate(data *schema.ResourceData, provider interface{}) erro
 		configuration.ConnectionRateLimit = propertyHelper.GetOptionalInt(resourceKeyVIPNodeConnectionRateLimit, false)
 	}
 
-	return apiClient.EditVIPNode(id, *configuration)
+	return diag.FromErr(apiClient.EditVIPNode(id, *configuration))
 }
 
-func resourceVIPNodeDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPNodeDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	name := data.Get(resourceKeyVIPNodeName).(string)
 	networkDomainID := data.Get(resourceKeyVIPNodeNetworkDomainID).(string)
@@ -286,18 +267,18 @@ func resourceVIPNodeDelete(data *schema.ResourceData, provider interface{}) erro
 
 	poolMemberships, err := getVIPNodePoolMemberships(apiClient, id, networkDomainID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	for _, membership := range poolMemberships {
 		log.Printf("Removing membership '%s' (pool '%s') for node '%s'...", membership.ID, membership.Pool.ID, id)
 		err = apiClient.RemoveVIPPoolMember(membership.ID)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		log.Printf("Removed membership '%s' (pool '%s') for node '%s'.", membership.ID, membership.Pool.ID, id)
 	}
 
-	return apiClient.DeleteVIPNode(id)
+	return diag.FromErr(apiClient.DeleteVIPNode(id))
 }
 
 func getVIPNodePoolMemberships(apiClient *compute.Client, nodeID string, networkDomainID string) (memberships []compute.VIPPoolMember, err error) {
@@ -1,12 +1,14 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -22,11 +24,10 @@ const (
 
 func resourceVIPPool() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVIPPoolCreate,
-		Read:   resourceVIPPoolRead,
-		Exists: resourceVIPPoolExists,
-		Update: resourceVIPPoolUpdate,
-		Delete: resourceVIPPoolDelete,
+		CreateContext: resourceVIPPoolCreate,
+		ReadContext:   resourceVIPPoolRead,
+		UpdateContext: resourceVIPPoolUpdate,
+		DeleteContext: resourceVIPPoolDelete,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyVIPPoolName: &schema.Schema{
@@ -97,7 +98,7 @@ func resourceVIPPool() *schema.Resource {
 	}
 }
 
-func resourceVIPPoolCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPPoolCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	networkDomainID := data.Get(resourceKeyVIPPoolNetworkDomainID).(string)
 	name := data.Get(resourceKeyVIPPoolName).(string)
 	description := data.Get(resourceKeyVIPPoolDescription).(string)
@@ -114,7 +115,7 @@ func resourceVIPPoolCreate(data *schema.ResourceData, provider interface{}) erro
 		log.Printf("Count of health monitor names '%d'", len(healthMonitorNames))
 		healthMonitorIDsByName, err := getHealthMonitorIDsByName(networkDomainID, apiClient)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		for index, healthMonitorName := range healthMonitorNames {
 			log.Printf("health monitor name '%s'", healthMonitorName)
@@ -133,7 +134,7 @@ func resourceVIPPoolCreate(data *schema.ResourceData, provider interface{}) erro
 		NetworkDomainID:   networkDomainID,
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(vipPoolID)
@@ -143,27 +144,7 @@ func resourceVIPPoolCreate(data *schema.ResourceData, provider interface{}) erro
 	return nil
 }
 
-func resourceVIPPoolExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	id := data.Id()
-
-	log.Printf("Check if VIP pool '%s' exists...", id)
-
-	providerState := provider.(*providerState)
-	apiClient := providerState.Client()
-
-	vipPool, err := apiClient.GetVIPPool(id)
-	if err != nil {
-		return false, err
-	}
-
-	exists := vipPool != nil
-
-	log.Printf("VIP pool '%s' exists: %t.", id, exists)
-
-	return exists, nil
-}
-
-func resourceVIPPoolRead(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPPoolRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 
 	log.Printf("Read VIP pool '%s'...", id)
@@ -173,7 +154,7 @@ func resourceVIPPoolRead(data *schema.ResourceData, provider interface{}) error
 
 	vipPool, err := apiClient.GetVIPPool(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if vipPool == nil {
 		data.SetId("") // VIP pool has been deleted
@@ -196,7 +177,7 @@ func resourceVIPPoolRead(data *schema.ResourceData, provider interface{}) error
 	return nil
 }
 
-func resourceVIPPoolUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPPoolUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	log.Printf("Update VIP pool '%s'...", id)
 
@@ -221,7 +202,7 @@ func resourceVIPPoolUpdate(data *schema.ResourceData, provider interface{}) erro
 		if healthMonitorNames != nil {
 			healthMonitorIDsByName, err := getHealthMonitorIDsByName(networkDomainID, apiClient)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			for index, healthMonitorName := range healthMonitorNames {
 				healthMonitorIDs[index] = healthMonitorIDsByName[healthMonitorName]
@@ -239,7 +220,7 @@ func resourceVIPPoolUpdate(data *schema.ResourceData, provider interface{}) erro
 	}
 
 	operationDescription := fmt.Sprintf("Edit VIP pool '%s'", id)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		editError := apiClient.EditVIPPool(id, *configuration)
 		if compute.IsResourceBusyError(editError) {
 			context.Retry()
@@ -248,13 +229,13 @@ func resourceVIPPoolUpdate(data *schema.ResourceData, provider interface{}) erro
 		}
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceVIPPoolDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPPoolDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	name := data.Get(resourceKeyVIPPoolName).(string)
 	networkDomainID := data.Get(resourceKeyVIPPoolNetworkDomainID)
@@ -265,7 +246,7 @@ func resourceVIPPoolDelete(data *schema.ResourceData, provider interface{}) erro
 	apiClient := providerState.Client()
 
 	operationDescription := fmt.Sprintf("Delete VIP pool '%s'", id)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		deleteError := apiClient.DeleteVIPPool(id)
 		if compute.IsResourceBusyError(deleteError) {
 			context.Retry()
@@ -274,30 +255,21 @@ func resourceVIPPoolDelete(data *schema.ResourceData, provider interface{}) erro
 		}
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
 func getHealthMonitorIDsByName(networkDomainID string, apiClient *compute.Client) (map[string]string, error) {
-	healthMonitorIdsByName := make(map[string]string)
-
-	page := compute.DefaultPaging()
-	for {
-		healthMonitors, err := apiClient.ListDefaultHealthMonitors(networkDomainID, page)
-		if err != nil {
-			return nil, err
-		}
-		if healthMonitors.IsEmpty() {
-			break
-		}
-
-		for _, healthMonitor := range healthMonitors.Items {
-			healthMonitorIdsByName[healthMonitor.Name] = healthMonitor.ID
-		}
+	healthMonitors, err := listHealthMonitors(networkDomainID, apiClient)
+	if err != nil {
+		return nil, err
+	}
 
-		page.Next()
+	healthMonitorIdsByName := make(map[string]string)
+	for _, healthMonitor := range healthMonitors {
+		healthMonitorIdsByName[healthMonitor.Name] = healthMonitor.ID
 	}
 
 	return healthMonitorIdsByName, nil
@@ -1,31 +1,34 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
-	resourceKeyVIPPoolMemberPoolID   = "pool"
-	resourceKeyVIPPoolMemberPoolName = "pool_name"
-	resourceKeyVIPPoolMemberNodeID   = "node"
-	resourceKeyVIPPoolMemberNodeName = "node_name"
-	resourceKeyVIPPoolMemberPort     = "port"
-	resourceKeyVIPPoolMemberStatus   = "status"
+	resourceKeyVIPPoolMemberPoolID       = "pool"
+	resourceKeyVIPPoolMemberPoolName     = "pool_name"
+	resourceKeyVIPPoolMemberNodeID       = "node"
+	resourceKeyVIPPoolMemberNodeName     = "node_name"
+	resourceKeyVIPPoolMemberPort         = "port"
+	resourceKeyVIPPoolMemberStatus       = "status"
+	resourceKeyVIPPoolMemberDisableDelay = "disable_delay"
 )
 
 func resourceVIPPoolMember() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVIPPoolMemberCreate,
-		Read:   resourceVIPPoolMemberRead,
-		Exists: resourceVIPPoolMemberExists,
-		Update: resourceVIPPoolMemberUpdate,
-		Delete: resourceVIPPoolMemberDelete,
+		CreateContext: resourceVIPPoolMemberCreate,
+		ReadContext:   resourceVIPPoolMemberRead,
+		UpdateContext: resourceVIPPoolMemberUpdate,
+		DeleteContext: resourceVIPPoolMemberDelete,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyVIPPoolMemberPoolID: &schema.Schema{
@@ -57,11 +60,24 @@ func resourceVIPPoolMember() *schema.Resource {
 				Default:      compute.VIPNodeStatusEnabled,
 				ValidateFunc: vipStatusValidator("VIP pool member"),
 			},
+			resourceKeyVIPPoolMemberDisableDelay: &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+				ValidateFunc: func(data interface{}, fieldName string) (messages []string, errors []error) {
+					if data.(int) < 0 {
+						errors = append(errors, fmt.Errorf("invalid disable delay %d for field '%s' (must not be negative)", data.(int), fieldName))
+					}
+
+					return
+				},
+				Description: "If greater than 0, the time (in seconds) to wait after disabling an enabled pool member before it is forced offline or removed from the pool",
+			},
 		},
 	}
 }
 
-func resourceVIPPoolMemberCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPPoolMemberCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	propertyHelper := propertyHelper(data)
 
 	nodeID := data.Get(resourceKeyVIPPoolMemberNodeID).(string)
@@ -81,7 +97,7 @@ func resourceVIPPoolMemberCreate(data *schema.ResourceData, provider interface{}
 
 	operationDescription := fmt.Sprintf("Add node '%s' to VIP pool '%s'", nodeID, poolID)
 
-	err = providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
 
@@ -95,7 +111,7 @@ func resourceVIPPoolMemberCreate(data *schema.ResourceData, provider interface{}
 		asyncLock.Release()
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(memberID)
@@ -104,10 +120,10 @@ func resourceVIPPoolMemberCreate(data *schema.ResourceData, provider interface{}
 
 	member, err := apiClient.GetVIPPoolMember(memberID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if member == nil {
-		return fmt.Errorf("unable to find newly-created pool member '%s'", memberID)
+		return diag.Errorf("unable to find newly-created pool member '%s'", memberID)
 	}
 
 	data.Set(resourceKeyVIPPoolMemberNodeName, member.Node.Name)
@@ -117,27 +133,7 @@ func resourceVIPPoolMemberCreate(data *schema.ResourceData, provider interface{}
 	return nil
 }
 
-func resourceVIPPoolMemberExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	id := data.Id()
-
-	log.Printf("Check if VIP pool member '%s' exists...", id)
-
-	providerState := provider.(*providerState)
-	apiClient := providerState.Client()
-
-	member, err := apiClient.GetVIPPoolMember(id)
-	if err != nil {
-		return false, err
-	}
-
-	exists := member != nil
-
-	log.Printf("VIP pool member '%s' exists: %t.", id, exists)
-
-	return exists, nil
-}
-
-func resourceVIPPoolMemberRead(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPPoolMemberRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 
 	log.Printf("Read VIP pool '%s'...", id)
@@ -147,7 +143,7 @@ func resourceVIPPoolMemberRead(data *schema.ResourceData, provider interface{})
 
 	member, err := apiClient.GetVIPPoolMember(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if member == nil {
 		data.SetId("") // VIP pool member has been deleted
@@ -162,7 +158,7 @@ func resourceVIPPoolMemberRead(data *schema.ResourceData, provider interface{})
 	return nil
 }
 
-func resourceVIPPoolMemberUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPPoolMemberUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	log.Printf("Update VIP pool member '%s'...", id)
 
@@ -171,31 +167,31 @@ func resourceVIPPoolMemberUpdate(data *schema.ResourceData, provider interface{}
 	}
 
 	providerState := provider.(*providerState)
-	apiClient := providerState.Client()
 
-	status := data.Get(resourceKeyVIPPoolMemberStatus).(string)
+	oldStatus, newStatus := data.GetChange(resourceKeyVIPPoolMemberStatus)
+	status := newStatus.(string)
 
-	operationDescription := fmt.Sprintf("Edit VIP pool member '%s'", id)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
-		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
-		defer asyncLock.Release() // Released at the end of the current attempt.
+	disableDelay := data.Get(resourceKeyVIPPoolMemberDisableDelay).(int)
+	if disableDelay > 0 && isVIPPoolMemberDisableDelayRequired(oldStatus.(string), status) {
+		err := disableVIPPoolMemberWithDelay(ctx, providerState, id, disableDelay)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 
-		editError := apiClient.EditVIPPoolMember(id, status)
-		if compute.IsResourceBusyError(editError) {
-			context.Retry()
-		} else if editError != nil {
-			context.Fail(editError)
+		if status == compute.VIPNodeStatusDisabled {
+			return nil // Already done.
 		}
-		asyncLock.Release()
-	})
+	}
+
+	err := editVIPPoolMemberStatus(ctx, providerState, id, status)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceVIPPoolMemberDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceVIPPoolMemberDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	memberID := data.Id()
 	poolID := data.Get(resourceKeyVIPPoolMemberPoolID).(string)
 
@@ -204,8 +200,17 @@ func resourceVIPPoolMemberDelete(data *schema.ResourceData, provider interface{}
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
+	status := data.Get(resourceKeyVIPPoolMemberStatus).(string)
+	disableDelay := data.Get(resourceKeyVIPPoolMemberDisableDelay).(int)
+	if disableDelay > 0 && isVIPPoolMemberDisableDelayRequired(status, "") {
+		err := disableVIPPoolMemberWithDelay(ctx, providerState, memberID, disableDelay)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	operationDescription := fmt.Sprintf("Remove member '%s' from VIP pool '%s'", memberID, poolID)
-	err := providerState.RetryAction(operationDescription, func(context retry.Context) {
+	err := providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
 
@@ -218,12 +223,65 @@ func resourceVIPPoolMemberDelete(data *schema.ResourceData, provider interface{}
 		asyncLock.Release()
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
+// Determine whether a pool member must be disabled (and the disable delay observed) before changing from the old status to the new status (an empty new status indicates that the member is being removed).
+//
+// Only enabled members accept new connections, so members that are not enabled are changed (or removed) immediately.
+func isVIPPoolMemberDisableDelayRequired(oldStatus string, newStatus string) bool {
+	return oldStatus == compute.VIPNodeStatusEnabled && newStatus != compute.VIPNodeStatusEnabled
+}
+
+// Disable a VIP pool member (so that it no longer accepts new connections), and then wait for the specified delay.
+//
+// CloudControl does not report a pool member's active connections, so this is a fixed delay (connections still open when it elapses are not waited for).
+func disableVIPPoolMemberWithDelay(ctx context.Context, providerState *providerState, id string, disableDelay int) error {
+	log.Printf("Disabling VIP pool member '%s' (then waiting %d seconds)...", id, disableDelay)
+
+	err := editVIPPoolMemberStatus(ctx, providerState, id, compute.VIPNodeStatusDisabled)
+	if err != nil {
+		return err
+	}
+
+	return waitForVIPPoolMemberDisableDelay(ctx, id, disableDelay)
+}
+
+// Wait for the specified delay after disabling a VIP pool member.
+func waitForVIPPoolMemberDisableDelay(ctx context.Context, id string, disableDelay int) error {
+	select {
+	case <-time.After(time.Duration(disableDelay) * time.Second):
+		log.Printf("Disable delay for VIP pool member '%s' has elapsed.", id)
+
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("cancelled while waiting after disabling VIP pool member '%s' (member has been disabled): %s", id, ctx.Err())
+	}
+}
+
+// Change the status of a VIP pool member.
+func editVIPPoolMemberStatus(ctx context.Context, providerState *providerState, id string, status string) error {
+	apiClient := providerState.Client()
+
+	operationDescription := fmt.Sprintf("Edit VIP pool member '%s'", id)
+
+	return providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release() // Released at the end of the current attempt.
+
+		editError := apiClient.EditVIPPoolMember(id, status)
+		if compute.IsResourceBusyError(editError) {
+			context.Retry()
+		} else if editError != nil {
+			context.Fail(editError)
+		}
+		asyncLock.Release()
+	})
+}
+
 func hashVIPPoolMember(item interface{}) int {
 	member, ok := item.(compute.VIPPoolMember)
 	if ok {
@@ -1,11 +1,14 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 /*
@@ -212,3 +215,30 @@ func testCheckDDCloudVIPPoolMemberDestroy(state *terraform.State) error {
 
 	return nil
 }
+
+/*
+ * Unit tests.
+ */
+
+// Unit test - only enabled pool members need to be disabled before they are forced offline or removed.
+func TestIsVIPPoolMemberDisableDelayRequired(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	assert.IsTrue("ENABLED -> DISABLED", isVIPPoolMemberDisableDelayRequired(compute.VIPNodeStatusEnabled, compute.VIPNodeStatusDisabled))
+	assert.IsTrue("ENABLED -> FORCED_OFFLINE", isVIPPoolMemberDisableDelayRequired(compute.VIPNodeStatusEnabled, compute.VIPNodeStatusForcedOffline))
+	assert.IsTrue("ENABLED -> removed", isVIPPoolMemberDisableDelayRequired(compute.VIPNodeStatusEnabled, ""))
+	assert.IsFalse("DISABLED -> FORCED_OFFLINE", isVIPPoolMemberDisableDelayRequired(compute.VIPNodeStatusDisabled, compute.VIPNodeStatusForcedOffline))
+	assert.IsFalse("DISABLED -> removed", isVIPPoolMemberDisableDelayRequired(compute.VIPNodeStatusDisabled, ""))
+	assert.IsFalse("DISABLED -> ENABLED", isVIPPoolMemberDisableDelayRequired(compute.VIPNodeStatusDisabled, compute.VIPNodeStatusEnabled))
+}
+
+// Unit test - waiting after disabling a pool member stops when the operation is cancelled.
+func TestWaitForVIPPoolMemberDisableDelayCancelled(test *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := waitForVIPPoolMemberDisableDelay(ctx, "member-1", 3600)
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("err != nil", err != nil)
+}
@@ -1,12 +1,15 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -30,11 +33,11 @@ const (
 
 func resourceVirtualListener() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVirtualListenerCreate,
-		Read:   resourceVirtualListenerRead,
-		Exists: resourceVirtualListenerExists,
-		Update: resourceVirtualListenerUpdate,
-		Delete: resourceVirtualListenerDelete,
+		CreateContext: resourceVirtualListenerCreate,
+		ReadContext:   resourceVirtualListenerRead,
+		UpdateContext: resourceVirtualListenerUpdate,
+		DeleteContext: resourceVirtualListenerDelete,
+		CustomizeDiff: resourceVirtualListenerCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			resourceKeyVirtualListenerName: &schema.Schema{
@@ -74,6 +77,7 @@ func resourceVirtualListener() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
+				ForceNew: true,
 				Default:  nil,
 			},
 			resourceKeyVirtualListenerPort: &schema.Schema{
@@ -170,7 +174,7 @@ func resourceVirtualListener() *schema.Resource {
 	}
 }
 
-func resourceVirtualListenerCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceVirtualListenerCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	networkDomainID := data.Get(resourceKeyVirtualListenerNetworkDomainID).(string)
 	name := data.Get(resourceKeyVirtualListenerName).(string)
 	description := data.Get(resourceKeyVirtualListenerDescription).(string)
@@ -182,70 +186,11 @@ func resourceVirtualListenerCreate(data *schema.ResourceData, provider interface
 
 	propertyHelper := propertyHelper(data)
 
-	var virtualListenerID string
-
-	operationDescription := fmt.Sprintf("Create virtual listener '%s' ", name)
-	operationError := providerState.RetryAction(operationDescription, func(context retry.Context) {
-		// Map from names to Ids, as required.
-		persistenceProfileID, err := propertyHelper.GetVirtualListenerPersistenceProfileID(apiClient)
-		if err != nil {
-			context.Fail(err)
-
-			return
-		}
-
-		iRuleIDs, err := propertyHelper.GetVirtualListenerIRuleIDs(apiClient)
-		if err != nil {
-			context.Fail(err)
-
-			return
-		}
-
-		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
-		defer asyncLock.Release()
-
-		virtualListenerID, err = apiClient.CreateVirtualListener(compute.NewVirtualListenerConfiguration{
-			Name:                   name,
-			Description:            description,
-			Type:                   data.Get(resourceKeyVirtualListenerType).(string),
-			Protocol:               data.Get(resourceKeyVirtualListenerProtocol).(string),
-			Port:                   data.Get(resourceKeyVirtualListenerPort).(int),
-			ListenerIPAddress:      propertyHelper.GetOptionalString(resourceKeyVirtualListenerIPv4Address, false),
-			Enabled:                data.Get(resourceKeyVirtualListenerEnabled).(bool),
-			ConnectionLimit:        data.Get(resourceKeyVirtualListenerConnectionLimit).(int),
-			ConnectionRateLimit:    data.Get(resourceKeyVirtualListenerConnectionRateLimit).(int),
-			SourcePortPreservation: data.Get(resourceKeyVirtualListenerSourcePortPreservation).(string),
-			PoolID:                 propertyHelper.GetOptionalString(resourceKeyVirtualListenerPoolID, false),
-			PersistenceProfileID:   persistenceProfileID,
-			SSLOffloadProfileID:    propertyHelper.GetOptionalString(resourceKeyVirtualListenerSSLOffloadProfileID, false),
-			IRuleIDs:               iRuleIDs,
-			OptimizationProfile:    propertyHelper.GetOptionalString(resourceKeyVirtualListenerOptimizationProfile, false),
-			NetworkDomainID:        networkDomainID,
-		})
-		if err != nil {
-			if compute.IsResourceBusyError(err) {
-				context.Retry()
-			} else if compute.IsNoIPAddressAvailableError(err) {
-				log.Printf("There are no free public IPv4 addresses in network domain '%s'; requesting allocation of a new address block...", networkDomainID)
-
-				publicIPBlock, err := addPublicIPBlock(networkDomainID, apiClient)
-				if err != nil {
-					context.Fail(err)
-
-					return
-				}
-				log.Printf("Allocated a new public IPv4 address block '%s' (%d addresses, starting at '%s').",
-					publicIPBlock.ID, publicIPBlock.Size, publicIPBlock.BaseIP,
-				)
-
-				context.Retry() // We'll use the new block next time around.
-			} else {
-				context.Fail(err)
-			}
-		}
-	})
-	if operationError != nil {
-		return operationError
+	virtualListenerID, err := createVirtualListener(ctx, data, providerState,
+		propertyHelper.GetOptionalString(resourceKeyVirtualListenerIPv4Address, false),
+	)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	data.SetId(virtualListenerID)
@@ -254,10 +199,10 @@ func resourceVirtualListenerCreate(data *schema.ResourceData, provider interface
 
 	virtualListener, err := apiClient.GetVirtualListener(virtualListenerID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if virtualListener == nil {
-		return fmt.Errorf("cannot find newly-created virtual listener with Id '%s'", virtualListenerID)
+		return diag.Errorf("cannot find newly-created virtual listener with Id '%s'", virtualListenerID)
 	}
 
 	data.Set(resourceKeyVirtualListenerIPv4Address, virtualListener.ListenerIPAddress)
@@ -265,27 +210,7 @@ func resourceVirtualListenerCreate(data *schema.ResourceData, provider interface
 	return nil
 }
 
-func resourceVirtualListenerExists(data *schema.ResourceData, provider interface{}) (bool, error) {
-	id := data.Id()
-
-	log.Printf("Check if virtual listener '%s' exists...", id)
-
-	providerState := provider.(*providerState)
-	apiClient := providerState.Client()
-
-	vipPool, err := apiClient.GetVirtualListener(id)
-	if err != nil {
-		return false, err
-	}
-
-	exists := vipPool != nil
-
-	log.Printf("virtual listener '%s' exists: %t.", id, exists)
-
-	return exists, nil
-}
-
-func resourceVirtualListenerRead(data *schema.ResourceData, provider interface{}) error {
+func resourceVirtualListenerRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 
 	log.Printf("Read virtual listener '%s'...", id)
@@ -295,7 +220,7 @@ func resourceVirtualListenerRead(data *schema.ResourceData, provider interface{}
 
 	virtualListener, err := apiClient.GetVirtualListener(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if virtualListener == nil {
 		data.SetId("") // Virtual listener has been deleted
@@ -320,7 +245,7 @@ func resourceVirtualListenerRead(data *schema.ResourceData, provider interface{}
 	return nil
 }
 
-func resourceVirtualListenerUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceVirtualListenerUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	log.Printf("Update virtual listener '%s'...", id)
 
@@ -355,28 +280,40 @@ func resourceVirtualListenerUpdate(data *schema.ResourceData, provider interface
 		configuration.SSLOffloadProfileID = propertyHelper.GetOptionalString(resourceKeyVirtualListenerSSLOffloadProfileID, false)
 	}
 
+	if data.HasChange(resourceKeyVirtualListenerOptimizationProfile) {
+		configuration.OptimizationProfile = propertyHelper.GetOptionalString(resourceKeyVirtualListenerOptimizationProfile, false)
+	}
+
 	if data.HasChange(resourceKeyVirtualListenerPersistenceProfileName) {
 		persistenceProfile, err := propertyHelper.GetVirtualListenerPersistenceProfile(apiClient)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
-		configuration.PersistenceProfileID = &persistenceProfile.ID
+		if persistenceProfile != nil {
+			configuration.PersistenceProfileID = &persistenceProfile.ID
+		} else {
+			// Persistence profile has been removed (an empty Id clears it, in the same way as for pool).
+			log.Printf("Remove persistence profile from virtual listener '%s'.", id)
+
+			noPersistenceProfileID := ""
+			configuration.PersistenceProfileID = &noPersistenceProfileID
+		}
 	}
 
 	if data.HasChange(resourceKeyVirtualListenerIRuleNames) {
 		iRuleIDs, err := propertyHelper.GetVirtualListenerIRuleIDs(apiClient)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		configuration.IRuleIDs = &iRuleIDs
 	}
 
-	return apiClient.EditVirtualListener(id, *configuration)
+	return diag.FromErr(apiClient.EditVirtualListener(id, *configuration))
 }
 
-func resourceVirtualListenerDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceVirtualListenerDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	name := data.Get(resourceKeyVirtualListenerName).(string)
 	networkDomainID := data.Get(resourceKeyVirtualListenerNetworkDomainID)
@@ -384,11 +321,236 @@ func resourceVirtualListenerDelete(data *schema.ResourceData, provider interface
 	log.Printf("Delete virtual listener '%s' ('%s') from network domain '%s'...", name, id, networkDomainID)
 
 	providerState := provider.(*providerState)
+
+	return diag.FromErr(deleteVirtualListener(ctx, providerState, id))
+}
+
+// Plan changes to a virtual listener.
+//
+// CloudControl cannot change a listener's name, type, protocol, or port, so changing any of these replaces the listener.
+// Unless ipv4 is configured, the replacement is planned with the IPv4 address of the listener that it replaces.
+func resourceVirtualListenerCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, provider interface{}) error {
+	providerState := provider.(*providerState)
+
+	err := validateVirtualListenerProfilesForDiff(diff, providerState)
+	if err != nil {
+		return err
+	}
+
+	if diff.Id() == "" {
+		// Terraform plans the replacement for an existing listener as a new listener (i.e. without the existing listener's state).
+		if diff.Get(resourceKeyVirtualListenerIPv4Address).(string) != "" {
+			return nil // Address is configured.
+		}
+
+		ipv4Address, ok := recallReplacedVirtualListenerIPv4Address(providerState,
+			diff.Get(resourceKeyVirtualListenerNetworkDomainID).(string),
+			diff.Get(resourceKeyVirtualListenerName).(string),
+		)
+		if !ok {
+			return nil
+		}
+
+		log.Printf("Virtual listener '%s' replaces an existing listener; it will use the existing listener's IPv4 address (%s).",
+			diff.Get(resourceKeyVirtualListenerName).(string),
+			ipv4Address,
+		)
+
+		return diff.SetNew(resourceKeyVirtualListenerIPv4Address, ipv4Address)
+	}
+
+	isReplacement := diff.HasChange(resourceKeyVirtualListenerName) ||
+		diff.HasChange(resourceKeyVirtualListenerType) ||
+		diff.HasChange(resourceKeyVirtualListenerProtocol) ||
+		diff.HasChange(resourceKeyVirtualListenerPort)
+	if !isReplacement || diff.HasChange(resourceKeyVirtualListenerNetworkDomainID) || diff.HasChange(resourceKeyVirtualListenerIPv4Address) {
+		return nil
+	}
+
+	oldIPv4Address, _ := diff.GetChange(resourceKeyVirtualListenerIPv4Address)
+	ipv4Address := oldIPv4Address.(string)
+	if ipv4Address == "" {
+		return nil
+	}
+
+	rememberReplacedVirtualListenerIPv4Address(providerState,
+		diff.Get(resourceKeyVirtualListenerNetworkDomainID).(string),
+		diff.Get(resourceKeyVirtualListenerName).(string),
+		ipv4Address,
+	)
+
+	return diff.SetNew(resourceKeyVirtualListenerIPv4Address, ipv4Address)
+}
+
+// Remember the IPv4 address of a virtual listener that is being replaced, so that it can be used when planning the listener that replaces it.
+func rememberReplacedVirtualListenerIPv4Address(providerState *providerState, networkDomainID string, name string, ipv4Address string) {
+	providerState.stateLock.Lock()
+	defer providerState.stateLock.Unlock()
+
+	providerState.replacedVirtualListenerIPv4Addresses[networkDomainID+"/"+name] = ipv4Address
+}
+
+// Retrieve the IPv4 address of the virtual listener (if any) that is being replaced by a virtual listener with the specified name.
+//
+// The address is not forgotten once retrieved, because Terraform may plan the replacement more than once (e.g. during apply).
+func recallReplacedVirtualListenerIPv4Address(providerState *providerState, networkDomainID string, name string) (ipv4Address string, ok bool) {
+	providerState.stateLock.Lock()
+	defer providerState.stateLock.Unlock()
+
+	ipv4Address, ok = providerState.replacedVirtualListenerIPv4Addresses[networkDomainID+"/"+name]
+
+	return
+}
+
+// Validate a virtual listener's persistence profile and iRules (if any) against those available in its network domain.
+//
+// Values that are not yet known are not validated.
+func validateVirtualListenerProfilesForDiff(diff *schema.ResourceDiff, providerState *providerState) error {
+	isNew := diff.Id() == ""
+	listenerChanged := isNew || diff.HasChange(resourceKeyVirtualListenerType) || diff.HasChange(resourceKeyVirtualListenerProtocol)
+
+	persistenceProfileName := ""
+	if diff.NewValueKnown(resourceKeyVirtualListenerPersistenceProfileName) && (listenerChanged || diff.HasChange(resourceKeyVirtualListenerPersistenceProfileName)) {
+		persistenceProfileName = diff.Get(resourceKeyVirtualListenerPersistenceProfileName).(string)
+	}
+
+	var iRuleNames []string
+	if diff.NewValueKnown(resourceKeyVirtualListenerIRuleNames) && (listenerChanged || diff.HasChange(resourceKeyVirtualListenerIRuleNames)) {
+		for _, iRuleName := range diff.Get(resourceKeyVirtualListenerIRuleNames).(*schema.Set).List() {
+			iRuleNames = append(iRuleNames, iRuleName.(string))
+		}
+	}
+
+	if persistenceProfileName == "" && len(iRuleNames) == 0 {
+		return nil
+	}
+
+	if !diff.NewValueKnown(resourceKeyVirtualListenerNetworkDomainID) || !diff.NewValueKnown(resourceKeyVirtualListenerType) || !diff.NewValueKnown(resourceKeyVirtualListenerProtocol) {
+		return nil
+	}
+
+	name := diff.Get(resourceKeyVirtualListenerName).(string)
+	networkDomainID := diff.Get(resourceKeyVirtualListenerNetworkDomainID).(string)
+	listenerType := diff.Get(resourceKeyVirtualListenerType).(string)
+	listenerProtocol := diff.Get(resourceKeyVirtualListenerProtocol).(string)
+
+	apiClient := providerState.Client()
+
+	var problems []string
+	if persistenceProfileName != "" {
+		persistenceProfiles, err := listPersistenceProfiles(networkDomainID, apiClient)
+		if err != nil {
+			return err
+		}
+
+		err = validateVirtualListenerPersistenceProfile(persistenceProfileName, persistenceProfiles, listenerType, listenerProtocol)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(iRuleNames) > 0 {
+		iRules, err := listIRules(networkDomainID, apiClient)
+		if err != nil {
+			return err
+		}
+
+		for _, err = range validateVirtualListenerIRules(iRuleNames, iRules, listenerType, listenerProtocol) {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		log.Printf("Planned configuration for virtual listener '%s' is invalid (%d problem(s) found).", name, len(problems))
+
+		return fmt.Errorf("invalid configuration for virtual listener '%s':\n  - %s",
+			name,
+			strings.Join(problems, "\n  - "),
+		)
+	}
+
+	return nil
+}
+
+// Create a new virtual listener using the specified listener IPv4 address (if nil, CloudControl will allocate a public IPv4 address).
+func createVirtualListener(ctx context.Context, data *schema.ResourceData, providerState *providerState, listenerIPAddress *string) (virtualListenerID string, err error) {
+	networkDomainID := data.Get(resourceKeyVirtualListenerNetworkDomainID).(string)
+	name := data.Get(resourceKeyVirtualListenerName).(string)
+
+	apiClient := providerState.Client()
+	propertyHelper := propertyHelper(data)
+
+	operationDescription := fmt.Sprintf("Create virtual listener '%s' ", name)
+	err = providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
+		// Map from names to Ids, as required.
+		persistenceProfileID, err := propertyHelper.GetVirtualListenerPersistenceProfileID(apiClient)
+		if err != nil {
+			context.Fail(err)
+
+			return
+		}
+
+		iRuleIDs, err := propertyHelper.GetVirtualListenerIRuleIDs(apiClient)
+		if err != nil {
+			context.Fail(err)
+
+			return
+		}
+
+		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
+		defer asyncLock.Release()
+
+		virtualListenerID, err = apiClient.CreateVirtualListener(compute.NewVirtualListenerConfiguration{
+			Name:                   name,
+			Description:            data.Get(resourceKeyVirtualListenerDescription).(string),
+			Type:                   data.Get(resourceKeyVirtualListenerType).(string),
+			Protocol:               data.Get(resourceKeyVirtualListenerProtocol).(string),
+			Port:                   data.Get(resourceKeyVirtualListenerPort).(int),
+			ListenerIPAddress:      listenerIPAddress,
+			Enabled:                data.Get(resourceKeyVirtualListenerEnabled).(bool),
+			ConnectionLimit:        data.Get(resourceKeyVirtualListenerConnectionLimit).(int),
+			ConnectionRateLimit:    data.Get(resourceKeyVirtualListenerConnectionRateLimit).(int),
+			SourcePortPreservation: data.Get(resourceKeyVirtualListenerSourcePortPreservation).(string),
+			PoolID:                 propertyHelper.GetOptionalString(resourceKeyVirtualListenerPoolID, false),
+			PersistenceProfileID:   persistenceProfileID,
+			SSLOffloadProfileID:    propertyHelper.GetOptionalString(resourceKeyVirtualListenerSSLOffloadProfileID, false),
+			IRuleIDs:               iRuleIDs,
+			OptimizationProfile:    propertyHelper.GetOptionalString(resourceKeyVirtualListenerOptimizationProfile, false),
+			NetworkDomainID:        networkDomainID,
+		})
+		if err != nil {
+			if compute.IsResourceBusyError(err) {
+				context.Retry()
+			} else if compute.IsNoIPAddressAvailableError(err) {
+				log.Printf("There are no free public IPv4 addresses in network domain '%s'; requesting allocation of a new address block...", networkDomainID)
+
+				publicIPBlock, err := addPublicIPBlock(networkDomainID, apiClient)
+				if err != nil {
+					context.Fail(err)
+
+					return
+				}
+				log.Printf("Allocated a new public IPv4 address block '%s' (%d addresses, starting at '%s').",
+					publicIPBlock.ID, publicIPBlock.Size, publicIPBlock.BaseIP,
+				)
+
+				context.Retry() // We'll use the new block next time around.
+			} else {
+				context.Fail(err)
+			}
+		}
+	})
+
+	return
+}
+
+// Delete an existing virtual listener.
+func deleteVirtualListener(ctx context.Context, providerState *providerState, id string) error {
 	apiClient := providerState.Client()
 
 	operationDescription := fmt.Sprintf("Delete virtual listener '%s", id)
 
-	return providerState.RetryAction(operationDescription, func(context retry.Context) {
+	return providerState.RetryAction(ctx, operationDescription, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
@@ -1,12 +1,15 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 /*
@@ -40,6 +43,37 @@ func testAccDDCloudVirtualListenerBasic(name string, listenerIPAddress string, e
 	`, name, listenerIPAddress, enabled)
 }
 
+// A virtual listener on a specific address and port that is replaced using create-before-destroy (and the network domain that contains it).
+func testAccDDCloudVirtualListenerPort(name string, listenerIPAddress string, port int) string {
+	return fmt.Sprintf(`
+		provider "ddcloud" {
+			region		= "AU"
+		}
+
+		resource "ddcloud_networkdomain" "acc_test_domain" {
+			name		= "acc-test-networkdomain"
+			description	= "Network domain for Terraform acceptance test."
+			datacenter	= "AU9"
+
+			plan		= "ADVANCED"
+		}
+
+		resource "ddcloud_virtual_listener" "acc_test_listener" {
+			name                 	= "%s"
+			protocol             	= "HTTP"
+			optimization_profile 	= "TCP"
+			ipv4                	= "%s"
+			port                	= %d
+
+			networkdomain 		 	= "${ddcloud_networkdomain.acc_test_domain.id}"
+
+			lifecycle {
+				create_before_destroy = true
+			}
+		}
+	`, name, listenerIPAddress, port)
+}
+
 /*
  * Acceptance tests.
  */
@@ -170,6 +204,212 @@ func TestAccVirtualListenerBasicUpdateName(t *testing.T) {
 	})
 }
 
+// Acceptance test for ddcloud_virtual_listener (changing port with create_before_destroy):
+//
+// Create a virtual listener, then change its port, and verify that its replacement uses the same IPv4 address.
+func TestAccVirtualListenerCreateBeforeDestroyPort(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testCheckDDCloudVirtualListenerDestroy,
+			testCheckDDCloudNetworkDomainDestroy,
+		),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDDCloudVirtualListenerPort("AccTestListener", "192.168.18.10", 80),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckDDCloudVirtualListenerExists("acc_test_listener", true),
+				),
+			},
+			resource.TestStep{
+				Config: testAccDDCloudVirtualListenerPort("AccTestListener", "192.168.18.10", 8080),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckDDCloudVirtualListenerExists("acc_test_listener", true),
+					resource.TestCheckResourceAttr("ddcloud_virtual_listener.acc_test_listener", resourceKeyVirtualListenerIPv4Address, "192.168.18.10"),
+					resource.TestCheckResourceAttr("ddcloud_virtual_listener.acc_test_listener", resourceKeyVirtualListenerPort, "8080"),
+				),
+			},
+		},
+	})
+}
+
+/*
+ * Unit tests.
+ */
+
+// Unit test - changing the port of an existing listener forces a new resource.
+func TestVirtualListenerDiffPortChange(test *testing.T) {
+	diff := testVirtualListenerChangeDiff(test, map[string]interface{}{
+		resourceKeyVirtualListenerPort: 8080,
+	})
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("Diff.RequiresNew", diff.RequiresNew())
+	assert.IsTrue("Diff.Attributes[port].RequiresNew", diff.Attributes[resourceKeyVirtualListenerPort].RequiresNew)
+}
+
+// Unit test - replacing an existing listener without a configured ipv4 address keeps the existing listener's address.
+//
+// Terraform plans the replacement as a new resource (i.e. without prior state) after planning the change to the existing one.
+func TestVirtualListenerDiffPortChangeKeepsIPv4Address(test *testing.T) {
+	provider := newProvider(nil, &ProviderSettings{})
+	changes := map[string]interface{}{
+		resourceKeyVirtualListenerPort: 8080,
+	}
+
+	diff := testVirtualListenerChangeDiffWithProvider(test, provider, changes)
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("Diff.RequiresNew", diff.RequiresNew())
+
+	replacementDiff, err := resourceVirtualListener().Diff(context.Background(), nil,
+		terraform.NewResourceConfigRaw(testVirtualListenerConfiguration(changes)),
+		provider,
+	)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if replacementDiff == nil {
+		test.Fatal("Replacement diff is nil.")
+	}
+
+	ipv4Diff := replacementDiff.Attributes[resourceKeyVirtualListenerIPv4Address]
+	assert.IsTrue("ReplacementDiff.Attributes[ipv4] != nil", ipv4Diff != nil)
+	assert.EqualsString("ReplacementDiff.Attributes[ipv4].New", "10.0.0.1", ipv4Diff.New)
+	assert.IsFalse("ReplacementDiff.Attributes[ipv4].NewComputed", ipv4Diff.NewComputed)
+}
+
+// Unit test - replacing an existing listener with a configured ipv4 address uses the configured address.
+func TestVirtualListenerDiffPortChangeConfiguredIPv4Address(test *testing.T) {
+	diff := testVirtualListenerChangeDiff(test, map[string]interface{}{
+		resourceKeyVirtualListenerPort:        8080,
+		resourceKeyVirtualListenerIPv4Address: "10.0.0.2",
+	})
+
+	assert := assert.ForTest(test)
+	assert.IsTrue("Diff.RequiresNew", diff.RequiresNew())
+
+	ipv4Diff := diff.Attributes[resourceKeyVirtualListenerIPv4Address]
+	assert.IsTrue("Diff.Attributes[ipv4] != nil", ipv4Diff != nil)
+	assert.EqualsString("Diff.Attributes[ipv4].New", "10.0.0.2", ipv4Diff.New)
+}
+
+// Unit test - changing the optimization profile of an existing listener does not force a new resource.
+func TestVirtualListenerDiffOptimizationProfileChange(test *testing.T) {
+	diff := testVirtualListenerChangeDiff(test, map[string]interface{}{
+		resourceKeyVirtualListenerOptimizationProfile: "TCP",
+	})
+
+	assert := assert.ForTest(test)
+	assert.IsFalse("Diff.RequiresNew", diff.RequiresNew())
+	assert.IsTrue("Diff.Attributes[optimization_profile] != nil", diff.Attributes[resourceKeyVirtualListenerOptimizationProfile] != nil)
+}
+
+// Unit test - removing the persistence profile from an existing listener clears it in CloudControl.
+func TestVirtualListenerUpdateRemovePersistenceProfile(test *testing.T) {
+	standIn := newTestCloudControlStandIn()
+	defer standIn.Close()
+
+	state := &terraform.InstanceState{
+		ID: "listener-1",
+		Attributes: map[string]string{
+			"id":                                             "listener-1",
+			resourceKeyVirtualListenerName:                   "listener-1",
+			resourceKeyVirtualListenerType:                   compute.VirtualListenerTypeStandard,
+			resourceKeyVirtualListenerProtocol:               "HTTP",
+			resourceKeyVirtualListenerPort:                   "80",
+			resourceKeyVirtualListenerIPv4Address:            "10.0.0.1",
+			resourceKeyVirtualListenerEnabled:                "true",
+			resourceKeyVirtualListenerConnectionLimit:        "20000",
+			resourceKeyVirtualListenerConnectionRateLimit:    "2000",
+			resourceKeyVirtualListenerSourcePortPreservation: compute.SourcePortPreservationEnabled,
+			resourceKeyVirtualListenerPersistenceProfileName: "CCDEFAULT.Cookie",
+			resourceKeyVirtualListenerNetworkDomainID:        "networkdomain-1",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		resourceKeyVirtualListenerName:            "listener-1",
+		resourceKeyVirtualListenerProtocol:        "HTTP",
+		resourceKeyVirtualListenerPort:            80,
+		resourceKeyVirtualListenerNetworkDomainID: "networkdomain-1",
+	})
+
+	listenerResource := resourceVirtualListener()
+	diff, err := listenerResource.Diff(context.Background(), state, config, &providerState{})
+	if err != nil {
+		test.Fatal(err)
+	}
+	data, err := schema.InternalMap(listenerResource.Schema).Data(state, diff)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	diagnostics := resourceVirtualListenerUpdate(context.Background(), data, standIn.NewProviderState())
+	if diagnostics.HasError() {
+		test.Fatal(diagnostics)
+	}
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("len(EditVirtualListenerRequests)", 1, len(standIn.EditVirtualListenerRequests))
+
+	persistenceProfileID, ok := standIn.EditVirtualListenerRequests[0]["persistenceProfileId"]
+	assert.IsTrue("persistenceProfileId is present", ok)
+	assert.Equals("persistenceProfileId", "", persistenceProfileID)
+}
+
+// Compute the diff for changing an existing virtual listener (listening on port 80) to the specified configuration.
+func testVirtualListenerChangeDiff(test *testing.T, changes map[string]interface{}) *terraform.InstanceDiff {
+	return testVirtualListenerChangeDiffWithProvider(test, newProvider(nil, &ProviderSettings{}), changes)
+}
+
+// Compute the diff (using the specified provider state) for changing an existing virtual listener (listening on port 80) to the specified configuration.
+func testVirtualListenerChangeDiffWithProvider(test *testing.T, provider *providerState, changes map[string]interface{}) *terraform.InstanceDiff {
+	state := &terraform.InstanceState{
+		ID: "listener-1",
+		Attributes: map[string]string{
+			"id":                                             "listener-1",
+			resourceKeyVirtualListenerName:                   "listener-1",
+			resourceKeyVirtualListenerDescription:            "",
+			resourceKeyVirtualListenerType:                   compute.VirtualListenerTypeStandard,
+			resourceKeyVirtualListenerProtocol:               "HTTP",
+			resourceKeyVirtualListenerPort:                   "80",
+			resourceKeyVirtualListenerIPv4Address:            "10.0.0.1",
+			resourceKeyVirtualListenerEnabled:                "true",
+			resourceKeyVirtualListenerConnectionLimit:        "20000",
+			resourceKeyVirtualListenerConnectionRateLimit:    "2000",
+			resourceKeyVirtualListenerSourcePortPreservation: compute.SourcePortPreservationEnabled,
+			resourceKeyVirtualListenerPersistenceProfileName: "",
+			resourceKeyVirtualListenerNetworkDomainID:        "networkdomain-1",
+		},
+	}
+	configuration := testVirtualListenerConfiguration(changes)
+
+	diff, err := resourceVirtualListener().Diff(context.Background(), state, terraform.NewResourceConfigRaw(configuration), provider)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if diff == nil {
+		test.Fatal("Diff is nil.")
+	}
+
+	return diff
+}
+
+// Build the configuration for a virtual listener (listening on port 80) with the specified changes.
+func testVirtualListenerConfiguration(changes map[string]interface{}) map[string]interface{} {
+	configuration := map[string]interface{}{
+		resourceKeyVirtualListenerName:            "listener-1",
+		resourceKeyVirtualListenerProtocol:        "HTTP",
+		resourceKeyVirtualListenerPort:            80,
+		resourceKeyVirtualListenerNetworkDomainID: "networkdomain-1",
+	}
+	for key, value := range changes {
+		configuration[key] = value
+	}
+
+	return configuration
+}
+
 /*
  * Acceptance-test checks.
  */
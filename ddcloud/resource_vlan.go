@@ -1,13 +1,15 @@
 package ddcloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/retry"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -34,12 +36,12 @@ const (
 
 func resourceVLAN() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVLANCreate,
-		Read:   resourceVLANRead,
-		Update: resourceVLANUpdate,
-		Delete: resourceVLANDelete,
+		CreateContext: resourceVLANCreate,
+		ReadContext:   resourceVLANRead,
+		UpdateContext: resourceVLANUpdate,
+		DeleteContext: resourceVLANDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVLANImport,
+			StateContext: resourceVLANImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -109,7 +111,7 @@ func resourceVLAN() *schema.Resource {
 }
 
 // Create a VLAN resource.
-func resourceVLANCreate(data *schema.ResourceData, provider interface{}) error {
+func resourceVLANCreate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	var (
 		networkDomainID, name, description, ipv4BaseAddress, gatewayAddressing, gatewayAddress string
 		ipv4PrefixSize                                                                         int
@@ -134,7 +136,7 @@ func resourceVLANCreate(data *schema.ResourceData, provider interface{}) error {
 		err    error
 	)
 	operationDescription := fmt.Sprintf("Create VLAN '%s'", name)
-	err = retry.Action(operationDescription, deployTimeoutVLAN, func(context retry.Context) {
+	err = retry.Action(ctx, operationDescription, deployTimeoutVLAN, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
@@ -152,7 +154,7 @@ func resourceVLANCreate(data *schema.ResourceData, provider interface{}) error {
 		asyncLock.Release()
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	data.SetId(vlanID)
@@ -161,43 +163,32 @@ func resourceVLANCreate(data *schema.ResourceData, provider interface{}) error {
 
 	deployedResource, err := apiClient.WaitForDeploy(compute.ResourceTypeVLAN, vlanID, resourceCreateTimeoutVLAN)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	vlan := deployedResource.(*compute.VLAN)
 	//data.Set(resourceKeyVLANIPv6BaseAddress, vlan.IPv6Range.BaseAddress)
 	//data.Set(resourceKeyVLANIPv6PrefixSize, vlan.IPv6Range.PrefixSize)
-	data.Partial(true)
 	data.Set(resourceKeyVLANName, vlan.Name)
-	data.SetPartial(resourceKeyVLANName)
 	data.Set(resourceKeyVLANDescription, vlan.Description)
-	data.SetPartial(resourceKeyVLANDescription)
 	data.Set(resourceKeyVLANIPv4BaseAddress, vlan.IPv4Range.BaseAddress)
-	data.SetPartial(resourceKeyVLANIPv4BaseAddress)
 	data.Set(resourceKeyVLANIPv4PrefixSize, vlan.IPv4Range.PrefixSize)
-	data.SetPartial(resourceKeyVLANIPv4PrefixSize)
 	data.Set(resourceKeyVLANIPv6BaseAddress, vlan.IPv6Range.BaseAddress)
-	data.SetPartial(resourceKeyVLANIPv6BaseAddress)
 	data.Set(resourceKeyVLANIPv6PrefixSize, vlan.IPv6Range.PrefixSize)
-	data.SetPartial(resourceKeyVLANIPv6PrefixSize)
 	data.Set(resourceKeyVLANIPv4GatewayAddress, vlan.IPv4GatewayAddress)
-	data.SetPartial(resourceKeyVLANIPv4GatewayAddress)
 	data.Set(resourceKeyVLANIPv6GatewayAddress, vlan.IPv6GatewayAddress)
-	data.SetPartial(resourceKeyVLANIPv6GatewayAddress)
 
 	// Tags
 	err = applyTags(data, apiClient, compute.AssetTypeVLAN, providerState.Settings())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	data.SetPartial(resourceKeyTag)
-	data.Partial(false)
 
 	return nil
 }
 
 // Read a VLAN resource.
-func resourceVLANRead(data *schema.ResourceData, provider interface{}) error {
+func resourceVLANRead(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	networkDomainID := data.Get(resourceKeyVLANNetworkDomainID).(string)
 	name := data.Get(resourceKeyVLANName).(string)
@@ -213,7 +204,7 @@ func resourceVLANRead(data *schema.ResourceData, provider interface{}) error {
 
 	vlan, err := apiClient.GetVLAN(id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if vlan != nil {
@@ -228,7 +219,7 @@ func resourceVLANRead(data *schema.ResourceData, provider interface{}) error {
 
 		err = readTags(data, apiClient, compute.AssetTypeVLAN)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 	} else {
@@ -239,7 +230,7 @@ func resourceVLANRead(data *schema.ResourceData, provider interface{}) error {
 }
 
 // Update a VLAN resource.
-func resourceVLANUpdate(data *schema.ResourceData, provider interface{}) error {
+func resourceVLANUpdate(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	var (
 		id, ipv4BaseAddress, name, description string
 		newName, newDescription                *string
@@ -266,10 +257,9 @@ func resourceVLANUpdate(data *schema.ResourceData, provider interface{}) error {
 	if data.HasChange(resourceKeyTag) {
 		err := applyTags(data, apiClient, compute.AssetTypeVLAN, providerState.Settings())
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
-		data.SetPartial(resourceKeyTag)
 	}
 
 	if newName == nil && newDescription == nil {
@@ -278,7 +268,7 @@ func resourceVLANUpdate(data *schema.ResourceData, provider interface{}) error {
 
 	operationDescription := fmt.Sprintf("Edit VLAN '%s'", name)
 
-	return retry.Action(operationDescription, deployTimeoutVLAN, func(context retry.Context) {
+	return diag.FromErr(retry.Action(ctx, operationDescription, deployTimeoutVLAN, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released at the end of the current attempt.
@@ -293,11 +283,11 @@ func resourceVLANUpdate(data *schema.ResourceData, provider interface{}) error {
 		}
 
 		asyncLock.Release()
-	})
+	}))
 }
 
 // Delete a VLAN resource.
-func resourceVLANDelete(data *schema.ResourceData, provider interface{}) error {
+func resourceVLANDelete(ctx context.Context, data *schema.ResourceData, provider interface{}) diag.Diagnostics {
 	id := data.Id()
 	name := data.Get(resourceKeyVLANName).(string)
 	networkDomainID := data.Get(resourceKeyVLANNetworkDomainID).(string)
@@ -308,7 +298,7 @@ func resourceVLANDelete(data *schema.ResourceData, provider interface{}) error {
 	apiClient := providerState.Client()
 
 	operationDescription := fmt.Sprintf("Delete VLAN '%s'", id)
-	err := providerState.Retry().Action(operationDescription, deleteTimeoutVLAN, func(context retry.Context) {
+	err := providerState.Retry().Action(ctx, operationDescription, deleteTimeoutVLAN, func(context retry.Context) {
 		// CloudControl has issues if more than one asynchronous operation is initated at a time (returns UNEXPECTED_ERROR).
 		asyncLock := providerState.AcquireAsyncOperationLock(operationDescription)
 		defer asyncLock.Release() // Released once the current attempt is complete.
@@ -325,16 +315,16 @@ func resourceVLANDelete(data *schema.ResourceData, provider interface{}) error {
 		asyncLock.Release()
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("VLAN '%s' is being deleted...", id)
 
-	return apiClient.WaitForDelete(compute.ResourceTypeVLAN, id, resourceDeleteTimeoutServer)
+	return diag.FromErr(apiClient.WaitForDelete(compute.ResourceTypeVLAN, id, resourceDeleteTimeoutServer))
 }
 
 // Import data for an existing VLAN.
-func resourceVLANImport(data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
+func resourceVLANImport(ctx context.Context, data *schema.ResourceData, provider interface{}) (importedData []*schema.ResourceData, err error) {
 	providerState := provider.(*providerState)
 	apiClient := providerState.Client()
 
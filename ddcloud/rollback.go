@@ -0,0 +1,53 @@
+package ddcloud
+
+import (
+	"fmt"
+	"log"
+)
+
+// rollbackActions tracks the actions required to undo the steps of a multi-step operation.
+//
+// Actions are run in the reverse order to that in which they were added.
+type rollbackActions struct {
+	actions []rollbackAction
+}
+
+// A single rollback action.
+type rollbackAction struct {
+	Description string
+	Undo        func() error
+}
+
+// Add an action to undo a step that has completed successfully.
+func (rollback *rollbackActions) Add(description string, undo func() error) {
+	rollback.actions = append(rollback.actions, rollbackAction{
+		Description: description,
+		Undo:        undo,
+	})
+}
+
+// IsEmpty determines whether there are no actions to roll back.
+func (rollback *rollbackActions) IsEmpty() bool {
+	return len(rollback.actions) == 0
+}
+
+// Run all rollback actions (most-recently-added first).
+//
+// A failed action does not prevent the remaining actions from running; the errors from any failed actions are returned.
+func (rollback *rollbackActions) Run() (errors []error) {
+	for index := len(rollback.actions) - 1; index >= 0; index-- {
+		action := rollback.actions[index]
+
+		log.Printf("Rolling back: %s...", action.Description)
+
+		err := action.Undo()
+		if err != nil {
+			log.Printf("Failed to roll back (%s): %s", action.Description, err)
+
+			errors = append(errors, fmt.Errorf("%s: %s", action.Description, err))
+		}
+	}
+	rollback.actions = nil
+
+	return
+}
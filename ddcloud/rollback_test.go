@@ -0,0 +1,46 @@
+package ddcloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+)
+
+// Unit test - rollback actions are run in reverse order, and a failed action does not prevent the remaining actions from running.
+func TestRollbackActionsRun(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	var undone []string
+	rollback := &rollbackActions{}
+	assert.IsTrue("IsEmpty (before Add)", rollback.IsEmpty())
+
+	rollback.Add("delete 'first'", func() error {
+		undone = append(undone, "first")
+
+		return nil
+	})
+	rollback.Add("delete 'second'", func() error {
+		undone = append(undone, "second")
+
+		return fmt.Errorf("resource is busy")
+	})
+	rollback.Add("delete 'third'", func() error {
+		undone = append(undone, "third")
+
+		return nil
+	})
+	assert.IsFalse("IsEmpty (after Add)", rollback.IsEmpty())
+
+	errors := rollback.Run()
+	assert.EqualsInt("len(undone)", 3, len(undone))
+	assert.EqualsString("undone[0]", "third", undone[0])
+	assert.EqualsString("undone[1]", "second", undone[1])
+	assert.EqualsString("undone[2]", "first", undone[2])
+
+	assert.EqualsInt("len(errors)", 1, len(errors))
+	assert.EqualsString("errors[0]", "delete 'second': resource is busy", errors[0].Error())
+
+	assert.IsTrue("IsEmpty (after Run)", rollback.IsEmpty())
+	assert.EqualsInt("len(rollback.Run())", 0, len(rollback.Run()))
+}
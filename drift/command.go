@@ -0,0 +1,106 @@
+package drift
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Exit codes for the drift command (these follow the convention used by "terraform plan -detailed-exitcode").
+const (
+	// ExitCodeNoDrift indicates that no drift was detected.
+	ExitCodeNoDrift = 0
+
+	// ExitCodeError indicates that the drift report could not be produced.
+	ExitCodeError = 1
+
+	// ExitCodeDrift indicates that drift was detected.
+	ExitCodeDrift = 2
+)
+
+// CommandName is the name of the provider sub-command that produces drift reports.
+const CommandName = "drift"
+
+// RunCommand runs the drift command with the specified command-line arguments (excluding the command name).
+//
+// The user name is taken from the MCP_USER environment variable unless supplied as an argument; the password is only ever taken from the MCP_PASSWORD environment variable (so that it does not appear in the process list).
+func RunCommand(provider *schema.Provider, args []string, stdout io.Writer, stderr io.Writer) int {
+	commandLine := flag.NewFlagSet(CommandName, flag.ContinueOnError)
+	commandLine.SetOutput(stderr)
+	commandLine.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: terraform-provider-ddcloud %s [options]\n\n", CommandName)
+		fmt.Fprintln(stderr, "Compares the ddcloud resources in a Terraform state file with their current configuration in CloudControl.")
+		fmt.Fprintln(stderr, "")
+		commandLine.PrintDefaults()
+	}
+
+	stateFileName := commandLine.String("state", "terraform.tfstate", "The Terraform state file to examine.")
+	format := commandLine.String("format", "text", "The report format (text or json).")
+	region := commandLine.String("region", "", "The region code that identifies the target end-point for the CloudControl API.")
+	endPoint := commandLine.String("cloudcontrol-endpoint", "", "The base URL of a custom target end-point for the CloudControl API.")
+	username := commandLine.String("username", "", "The user name used to authenticate to CloudControl (defaults to MCP_USER).")
+
+	err := commandLine.Parse(args)
+	if err != nil {
+		return ExitCodeError
+	}
+
+	fail := func(format string, formatArgs ...interface{}) int {
+		fmt.Fprintf(stderr, "Error: "+format+"\n", formatArgs...)
+
+		return ExitCodeError
+	}
+
+	if *format != "text" && *format != "json" {
+		return fail("unsupported report format '%s' (expected 'text' or 'json')", *format)
+	}
+
+	stateFile, err := os.Open(*stateFileName)
+	if err != nil {
+		return fail("%s", err)
+	}
+	defer stateFile.Close()
+
+	instances, err := ReadState(stateFile)
+	if err != nil {
+		return fail("%s: %s", *stateFileName, err)
+	}
+
+	providerConfig := map[string]interface{}{
+		"username": *username,
+	}
+	if *region != "" {
+		providerConfig["region"] = *region
+	}
+	if *endPoint != "" {
+		providerConfig["cloudcontrol_endpoint"] = *endPoint
+	}
+	ctx := context.Background()
+	diagnostics := provider.Configure(ctx,
+		terraform.NewResourceConfigRaw(providerConfig),
+	)
+	if diagnostics.HasError() {
+		return fail("failed to configure provider: %s", diagnosticsError(diagnostics))
+	}
+
+	report := NewDetector(provider).Detect(ctx, instances)
+	if *format == "json" {
+		err = report.WriteJSON(stdout)
+	} else {
+		err = report.WriteText(stdout)
+	}
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	if report.HasDrift() {
+		return ExitCodeDrift
+	}
+
+	return ExitCodeNoDrift
+}
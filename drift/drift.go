@@ -0,0 +1,193 @@
+// Package drift compares the resources recorded in a Terraform state file with their current configuration in CloudControl.
+package drift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/maps"
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Detector detects drift between Terraform state and CloudControl.
+type Detector struct {
+	provider *schema.Provider
+}
+
+// NewDetector creates a new drift Detector using the specified (configured) provider.
+func NewDetector(provider *schema.Provider) *Detector {
+	return &Detector{
+		provider: provider,
+	}
+}
+
+// Detect re-reads each of the specified instances from CloudControl and reports any attributes that have changed.
+func (detector *Detector) Detect(ctx context.Context, instances []StateInstance) *Report {
+	report := &Report{
+		Resources: make([]ResourceDrift, 0, len(instances)),
+	}
+	for _, instance := range instances {
+		report.Resources = append(report.Resources,
+			detector.detectInstance(ctx, instance),
+		)
+	}
+
+	return report
+}
+
+// Detect drift for a single resource instance.
+func (detector *Detector) detectInstance(ctx context.Context, instance StateInstance) (resourceDrift ResourceDrift) {
+	resourceDrift = ResourceDrift{
+		Address: instance.Address,
+		Type:    instance.Type,
+		Status:  StatusUnchanged,
+	}
+
+	fail := func(err error) ResourceDrift {
+		log.Printf("Failed to detect drift for '%s': %s", instance.Address, err)
+
+		resourceDrift.Status = StatusError
+		resourceDrift.Error = err.Error()
+
+		return resourceDrift
+	}
+
+	resource, ok := detector.provider.ResourcesMap[instance.Type]
+	if !ok {
+		return fail(fmt.Errorf("resource type '%s' is not supported by this version of the provider", instance.Type))
+	}
+	if instance.SchemaVersion < resource.SchemaVersion {
+		return fail(fmt.Errorf("state uses schema version %d of '%s' (the current version is %d); run 'terraform-provider-ddcloud migrate-state' to upgrade it",
+			instance.SchemaVersion,
+			instance.Type,
+			resource.SchemaVersion,
+		))
+	}
+	resourceType := resource.CoreConfigSchema().ImpliedType()
+
+	priorState, err := newInstanceState(instance, resourceType)
+	if err != nil {
+		return fail(err)
+	}
+	resourceDrift.ID = priorState.ID
+
+	log.Printf("Reading '%s' ('%s') from CloudControl...", instance.Address, priorState.ID)
+
+	// Refresh modifies the state it is given, so we capture prior attributes before calling it.
+	before, err := flattenState(priorState, resourceType)
+	if err != nil {
+		return fail(err)
+	}
+
+	currentState, diagnostics := resource.RefreshWithoutUpgrade(ctx, priorState.DeepCopy(), detector.provider.Meta())
+	if diagnostics.HasError() {
+		return fail(diagnosticsError(diagnostics))
+	}
+	if currentState == nil || currentState.ID == "" {
+		resourceDrift.Status = StatusDeleted
+
+		return
+	}
+
+	after, err := flattenState(currentState, resourceType)
+	if err != nil {
+		return fail(err)
+	}
+
+	resourceDrift.Changes = compareAttributes(before, after, func(attribute string) bool {
+		attributeName := strings.SplitN(attribute, ".", 2)[0]
+		attributeSchema, ok := resource.Schema[attributeName]
+
+		return ok && attributeSchema.Sensitive
+	})
+	if len(resourceDrift.Changes) > 0 {
+		resourceDrift.Status = StatusChanged
+	}
+
+	return
+}
+
+// Create legacy (flatmap) instance state from a state file instance.
+func newInstanceState(instance StateInstance, resourceType cty.Type) (*terraform.InstanceState, error) {
+	if instance.IsLegacy() {
+		attributes := make(map[string]string, len(instance.FlatAttributes))
+		for key, value := range instance.FlatAttributes {
+			attributes[key] = value
+		}
+
+		return &terraform.InstanceState{
+			ID:         attributes["id"],
+			Attributes: attributes,
+			Meta: map[string]interface{}{
+				"schema_version": instance.SchemaVersion,
+			},
+		}, nil
+	}
+
+	value, err := ctyjson.Unmarshal(instance.JSONAttributes, resourceType)
+	if err != nil {
+		return nil, fmt.Errorf("state for '%s' does not match the current schema for '%s' (schema version %d): %s",
+			instance.Address,
+			instance.Type,
+			instance.SchemaVersion,
+			err,
+		)
+	}
+
+	return terraform.NewInstanceStateShimmedFromValue(value, instance.SchemaVersion), nil
+}
+
+// Flatten instance state into a map of attribute paths to values.
+//
+// The state is normalised (via the resource schema's implied type) so that attributes from state files and attributes read from CloudControl can be compared directly.
+func flattenState(state *terraform.InstanceState, resourceType cty.Type) (map[string]string, error) {
+	value, err := state.AttrsAsObjectValue(resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	valueJSON, err := ctyjson.Marshal(value, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(valueJSON))
+	decoder.UseNumber()
+
+	var rawValue interface{}
+	err = decoder.Decode(&rawValue)
+	if err != nil {
+		return nil, err
+	}
+
+	flattened := make(map[string]string)
+	maps.Flatten("", rawValue, flattened)
+
+	return flattened, nil
+}
+
+// Combine the errors from a set of diagnostics into a single error.
+func diagnosticsError(diagnostics diag.Diagnostics) error {
+	var messages []string
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity != diag.Error {
+			continue
+		}
+
+		message := diagnostic.Summary
+		if diagnostic.Detail != "" {
+			message += ": " + diagnostic.Detail
+		}
+		messages = append(messages, message)
+	}
+
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
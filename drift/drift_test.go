@@ -0,0 +1,250 @@
+package drift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testStateV4 = `{
+	"version": 4,
+	"resources": [
+		{
+			"mode": "managed",
+			"type": "ddcloud_vlan",
+			"name": "web",
+			"instances": [
+				{
+					"schema_version": 0,
+					"attributes": { "id": "vlan-1", "name": "web", "description": "Web tier", "secret": "s3cret", "tags": ["a", "b"] }
+				}
+			]
+		},
+		{
+			"mode": "data",
+			"type": "ddcloud_networkdomain",
+			"name": "domain",
+			"instances": [ { "attributes": { "id": "domain-1" } } ]
+		},
+		{
+			"module": "module.app",
+			"mode": "managed",
+			"type": "ddcloud_vlan",
+			"name": "app",
+			"instances": [
+				{ "index_key": 1, "attributes": { "id": "vlan-2", "name": "app", "description": "App tier", "secret": "", "tags": [] } }
+			]
+		},
+		{
+			"mode": "managed",
+			"type": "random_id",
+			"name": "suffix",
+			"instances": [ { "attributes": { "id": "xyz" } } ]
+		}
+	]
+}`
+
+const testStateLegacy = `{
+	"version": 3,
+	"modules": [
+		{
+			"path": ["root"],
+			"resources": {
+				"ddcloud_vlan.web": {
+					"type": "ddcloud_vlan",
+					"primary": {
+						"id": "vlan-1",
+						"attributes": { "name": "web", "description": "Web tier", "tags.#": "0" },
+						"meta": { "schema_version": "1" }
+					}
+				},
+				"data.ddcloud_networkdomain.domain": {
+					"type": "ddcloud_networkdomain",
+					"primary": { "id": "domain-1", "attributes": {} }
+				}
+			}
+		}
+	]
+}`
+
+// Unit test - read ddcloud resource instances from v4 state.
+func TestReadStateV4(test *testing.T) {
+	instances, err := ReadState(strings.NewReader(testStateV4))
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Instances.Length", 2, len(instances))
+	assert.EqualsString("Instances[0].Address", "ddcloud_vlan.web", instances[0].Address)
+	assert.EqualsString("Instances[1].Address", "module.app.ddcloud_vlan.app[1]", instances[1].Address)
+	assert.IsFalse("Instances[0].IsLegacy", instances[0].IsLegacy())
+}
+
+// Unit test - read ddcloud resource instances from legacy state.
+func TestReadStateLegacy(test *testing.T) {
+	instances, err := ReadState(strings.NewReader(testStateLegacy))
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Instances.Length", 1, len(instances))
+	assert.EqualsString("Instances[0].Address", "ddcloud_vlan.web", instances[0].Address)
+	assert.EqualsInt("Instances[0].SchemaVersion", 1, instances[0].SchemaVersion)
+	assert.EqualsString("Instances[0].FlatAttributes[id]", "vlan-1", instances[0].FlatAttributes["id"])
+	assert.IsTrue("Instances[0].IsLegacy", instances[0].IsLegacy())
+}
+
+// Unit test - state without version information is rejected.
+func TestReadStateUnknownFormat(test *testing.T) {
+	_, err := ReadState(strings.NewReader(`{ "resources": [] }`))
+	if err == nil {
+		test.Fatal("Expected an error for state data with no version.")
+	}
+}
+
+// Unit test - detect changed, deleted, and unchanged resources using a stand-in provider.
+func TestDetectDrift(test *testing.T) {
+	actualVLANs := map[string]map[string]interface{}{
+		"vlan-1": {
+			"name":        "web",
+			"description": "Changed in the portal",
+			"secret":      "n3w-s3cret",
+			"tags":        []interface{}{"a", "b", "c"},
+		},
+	}
+	provider := newTestProvider(actualVLANs)
+
+	instances, err := ReadState(strings.NewReader(testStateV4))
+	if err != nil {
+		test.Fatal(err)
+	}
+	report := NewDetector(provider).Detect(context.Background(), instances)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Resources.Length", 2, len(report.Resources))
+	assert.IsTrue("HasDrift", report.HasDrift())
+
+	changed := report.Resources[0]
+	assert.EqualsString("Resources[0].Status", StatusChanged, changed.Status)
+	assert.EqualsString("Resources[0].ID", "vlan-1", changed.ID)
+
+	changesByAttribute := make(map[string]AttributeChange)
+	for _, change := range changed.Changes {
+		changesByAttribute[change.Attribute] = change
+	}
+	assert.EqualsInt("Resources[0].Changes.Length", 4, len(changed.Changes))
+	assert.EqualsString("description.After", "Changed in the portal", *changesByAttribute["description"].After)
+	assert.EqualsString("secret.After", sensitiveValue, *changesByAttribute["secret"].After)
+	assert.EqualsString("tags.#.After", "3", *changesByAttribute["tags.#"].After)
+	assert.IsTrue("tags.2.Before", changesByAttribute["tags.2"].Before == nil)
+
+	assert.EqualsString("Resources[1].Status", StatusDeleted, report.Resources[1].Status)
+
+	var textReport bytes.Buffer
+	err = report.WriteText(&textReport)
+	if err != nil {
+		test.Fatal(err)
+	}
+	assert.IsTrue("TextReport contains description",
+		strings.Contains(textReport.String(), `~ description: "Web tier" => "Changed in the portal"`),
+	)
+
+	var jsonReport bytes.Buffer
+	err = report.WriteJSON(&jsonReport)
+	if err != nil {
+		test.Fatal(err)
+	}
+	var decodedReport Report
+	err = json.Unmarshal(jsonReport.Bytes(), &decodedReport)
+	if err != nil {
+		test.Fatal(err)
+	}
+	assert.EqualsInt("JSONReport.Resources.Length", 2, len(decodedReport.Resources))
+}
+
+// Unit test - legacy state with no changes produces no drift.
+func TestDetectNoDriftLegacy(test *testing.T) {
+	actualVLANs := map[string]map[string]interface{}{
+		"vlan-1": {
+			"name":        "web",
+			"description": "Web tier",
+			"tags":        []interface{}{},
+		},
+	}
+	provider := newTestProvider(actualVLANs)
+
+	instances, err := ReadState(strings.NewReader(testStateLegacy))
+	if err != nil {
+		test.Fatal(err)
+	}
+	report := NewDetector(provider).Detect(context.Background(), instances)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Resources.Length", 1, len(report.Resources))
+	assert.EqualsString("Resources[0].Status", StatusUnchanged, report.Resources[0].Status)
+	assert.IsFalse("HasDrift", report.HasDrift())
+}
+
+// Create a provider with a single resource type ("ddcloud_vlan") that reads from the specified map.
+func newTestProvider(actualVLANs map[string]map[string]interface{}) *schema.Provider {
+	provider := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"ddcloud_vlan": &schema.Resource{
+				ReadContext: func(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+					vlan, ok := actualVLANs[data.Id()]
+					if !ok {
+						data.SetId("")
+
+						return nil
+					}
+
+					for key, value := range vlan {
+						err := data.Set(key, value)
+						if err != nil {
+							return diag.FromErr(err)
+						}
+					}
+
+					return nil
+				},
+
+				Schema: map[string]*schema.Schema{
+					"name": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"description": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"secret": &schema.Schema{
+						Type:      schema.TypeString,
+						Optional:  true,
+						Sensitive: true,
+					},
+					"tags": &schema.Schema{
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		},
+	}
+
+	diagnostics := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{}))
+	if diagnostics.HasError() {
+		panic(diagnosticsError(diagnostics))
+	}
+
+	return provider
+}
@@ -0,0 +1,175 @@
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Resource drift statuses.
+const (
+	// StatusUnchanged indicates that the resource in CloudControl matches its state.
+	StatusUnchanged = "unchanged"
+
+	// StatusChanged indicates that one or more of the resource's attributes differ from its state.
+	StatusChanged = "changed"
+
+	// StatusDeleted indicates that the resource no longer exists in CloudControl.
+	StatusDeleted = "deleted"
+
+	// StatusError indicates that the resource could not be read from CloudControl.
+	StatusError = "error"
+)
+
+// sensitiveValue is displayed in place of the values of sensitive attributes.
+const sensitiveValue = "(sensitive)"
+
+// Report is a drift report for the resources in a Terraform state file.
+type Report struct {
+	Resources []ResourceDrift `json:"resources"`
+}
+
+// ResourceDrift describes the drift (if any) between a resource's state and CloudControl.
+type ResourceDrift struct {
+	Address string            `json:"address"`
+	Type    string            `json:"type"`
+	ID      string            `json:"id"`
+	Status  string            `json:"status"`
+	Changes []AttributeChange `json:"changes,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// AttributeChange represents a single attribute whose value differs between state and CloudControl.
+//
+// Before / After are nil if the attribute is not present in state / CloudControl (respectively).
+type AttributeChange struct {
+	Attribute string  `json:"attribute"`
+	Before    *string `json:"before"`
+	After     *string `json:"after"`
+}
+
+// HasDrift determines whether any resource in the report has drifted (or could not be read).
+func (report *Report) HasDrift() bool {
+	for _, resource := range report.Resources {
+		if resource.Status != StatusUnchanged {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteText writes the report in human-readable form.
+func (report *Report) WriteText(writer io.Writer) error {
+	driftCount := 0
+	for _, resource := range report.Resources {
+		var err error
+		switch resource.Status {
+		case StatusUnchanged:
+			continue
+		case StatusDeleted:
+			_, err = fmt.Fprintf(writer, "%s (%s): deleted\n", resource.Address, resource.ID)
+		case StatusError:
+			_, err = fmt.Fprintf(writer, "%s (%s): error: %s\n", resource.Address, resource.ID, resource.Error)
+		case StatusChanged:
+			_, err = fmt.Fprintf(writer, "%s (%s): %d attribute(s) changed\n", resource.Address, resource.ID, len(resource.Changes))
+			for _, change := range resource.Changes {
+				if err != nil {
+					break
+				}
+				_, err = fmt.Fprintf(writer, "  %s %s: %s => %s\n",
+					change.symbol(),
+					change.Attribute,
+					formatValue(change.Before),
+					formatValue(change.After),
+				)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		driftCount++
+	}
+
+	_, err := fmt.Fprintf(writer, "\n%d of %d resource(s) have drifted.\n", driftCount, len(report.Resources))
+
+	return err
+}
+
+// WriteJSON writes the report in JSON format.
+func (report *Report) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+// The symbol used to represent the change in text reports.
+func (change AttributeChange) symbol() string {
+	switch {
+	case change.Before == nil:
+		return "+"
+	case change.After == nil:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+func formatValue(value *string) string {
+	if value == nil {
+		return "(none)"
+	}
+	if *value == sensitiveValue {
+		return *value
+	}
+
+	return strconv.Quote(*value)
+}
+
+// compareAttributes produces a sorted list of changes between 2 sets of flattened attributes.
+//
+// isSensitive determines whether the value of a given attribute should be masked.
+func compareAttributes(before map[string]string, after map[string]string, isSensitive func(attribute string) bool) (changes []AttributeChange) {
+	keys := make(map[string]bool)
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		beforeValue, haveBefore := before[key]
+		afterValue, haveAfter := after[key]
+		if haveBefore && haveAfter && beforeValue == afterValue {
+			continue
+		}
+
+		change := AttributeChange{Attribute: key}
+		if haveBefore {
+			change.Before = maskValue(beforeValue, isSensitive(key))
+		}
+		if haveAfter {
+			change.After = maskValue(afterValue, isSensitive(key))
+		}
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(index1 int, index2 int) bool {
+		return changes[index1].Attribute < changes[index2].Attribute
+	})
+
+	return
+}
+
+func maskValue(value string, isSensitive bool) *string {
+	if isSensitive {
+		value = sensitiveValue
+	}
+
+	return &value
+}
@@ -0,0 +1,182 @@
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// resourcePrefix is the prefix shared by all resource types exposed by the ddcloud provider.
+const resourcePrefix = "ddcloud_"
+
+// StateInstance represents a single ddcloud resource instance read from a Terraform state file.
+type StateInstance struct {
+	// The instance's address (e.g. "module.web.ddcloud_server.frontend[0]").
+	Address string
+
+	// The resource type (e.g. "ddcloud_server").
+	Type string
+
+	// The schema version recorded for the instance's state.
+	SchemaVersion int
+
+	// The instance attributes, in legacy (flatmap) format.
+	//
+	// Only populated for state files written by Terraform v0.11 and earlier.
+	FlatAttributes map[string]string
+
+	// The instance attributes, in JSON format.
+	//
+	// Only populated for state files written by Terraform v0.12 and later.
+	JSONAttributes json.RawMessage
+}
+
+// IsLegacy determines whether the instance was read from a legacy (pre-v4) state file.
+func (instance StateInstance) IsLegacy() bool {
+	return instance.FlatAttributes != nil
+}
+
+// ReadState reads all ddcloud resource instances from the specified Terraform state.
+//
+// Both the legacy (v3, "modules") and current (v4, "resources") state formats are supported.
+func ReadState(reader io.Reader) (instances []StateInstance, err error) {
+	var stateData stateFile
+	err = json.NewDecoder(reader).Decode(&stateData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state data: %s", err)
+	}
+
+	switch {
+	case stateData.Version >= 4:
+		instances, err = readStateV4(stateData)
+	case stateData.Version > 0:
+		instances, err = readStateLegacy(stateData)
+	default:
+		err = fmt.Errorf("unrecognised state file format (no version information)")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(instances, func(index1 int, index2 int) bool {
+		return instances[index1].Address < instances[index2].Address
+	})
+
+	return instances, nil
+}
+
+// Read instances from v4 (Terraform 0.12+) state data.
+func readStateV4(stateData stateFile) (instances []StateInstance, err error) {
+	for _, resource := range stateData.Resources {
+		if resource.Mode != "managed" || !strings.HasPrefix(resource.Type, resourcePrefix) {
+			continue
+		}
+
+		for _, instance := range resource.Instances {
+			address := resource.Type + "." + resource.Name
+			if resource.Module != "" {
+				address = resource.Module + "." + address
+			}
+			if len(instance.IndexKey) > 0 {
+				address += "[" + string(instance.IndexKey) + "]"
+			}
+
+			instances = append(instances, StateInstance{
+				Address:        address,
+				Type:           resource.Type,
+				SchemaVersion:  instance.SchemaVersion,
+				JSONAttributes: instance.Attributes,
+			})
+		}
+	}
+
+	return
+}
+
+// Read instances from legacy (Terraform 0.11 and earlier) state data.
+func readStateLegacy(stateData stateFile) (instances []StateInstance, err error) {
+	for _, module := range stateData.Modules {
+		var modulePrefix string
+		for _, moduleName := range module.Path {
+			if moduleName == "root" {
+				continue
+			}
+			modulePrefix += "module." + moduleName + "."
+		}
+
+		for resourceKey, resource := range module.Resources {
+			if !strings.HasPrefix(resource.Type, resourcePrefix) || strings.HasPrefix(resourceKey, "data.") {
+				continue
+			}
+			if resource.Primary == nil {
+				continue
+			}
+
+			schemaVersion := 0
+			if rawSchemaVersion, ok := resource.Primary.Meta["schema_version"]; ok {
+				_, err = fmt.Sscan(fmt.Sprint(rawSchemaVersion), &schemaVersion)
+				if err != nil {
+					return nil, fmt.Errorf("invalid schema version for '%s': %s", resourceKey, err)
+				}
+			}
+
+			attributes := make(map[string]string, len(resource.Primary.Attributes)+1)
+			for key, value := range resource.Primary.Attributes {
+				attributes[key] = value
+			}
+			attributes["id"] = resource.Primary.ID
+
+			instances = append(instances, StateInstance{
+				Address:        modulePrefix + resourceKey,
+				Type:           resource.Type,
+				SchemaVersion:  schemaVersion,
+				FlatAttributes: attributes,
+			})
+		}
+	}
+
+	return
+}
+
+// stateFile is the subset of the Terraform state file format (legacy or v4) used by the drift report.
+type stateFile struct {
+	Version int `json:"version"`
+
+	// v4
+	Resources []stateResourceV4 `json:"resources"`
+
+	// Legacy
+	Modules []stateModuleLegacy `json:"modules"`
+}
+
+type stateResourceV4 struct {
+	Module    string            `json:"module"`
+	Mode      string            `json:"mode"`
+	Type      string            `json:"type"`
+	Name      string            `json:"name"`
+	Instances []stateInstanceV4 `json:"instances"`
+}
+
+type stateInstanceV4 struct {
+	IndexKey      json.RawMessage `json:"index_key"`
+	SchemaVersion int             `json:"schema_version"`
+	Attributes    json.RawMessage `json:"attributes"`
+}
+
+type stateModuleLegacy struct {
+	Path      []string                       `json:"path"`
+	Resources map[string]stateResourceLegacy `json:"resources"`
+}
+
+type stateResourceLegacy struct {
+	Type    string                       `json:"type"`
+	Primary *stateResourceInstanceLegacy `json:"primary"`
+}
+
+type stateResourceInstanceLegacy struct {
+	ID         string                 `json:"id"`
+	Attributes map[string]string      `json:"attributes"`
+	Meta       map[string]interface{} `json:"meta"`
+}
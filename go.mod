@@ -3,26 +3,14 @@ module github.com/DimensionDataResearch/dd-cloud-compute-terraform
 go 1.12
 
 require (
-	cloud.google.com/go v0.51.0 // indirect
-	cloud.google.com/go/storage v1.5.0 // indirect
 	github.com/DimensionDataResearch/go-dd-cloud-compute v1.13.0
 	github.com/aws/aws-sdk-go v1.27.3 // indirect
 	github.com/fatih/color v1.9.0 // indirect
-	github.com/google/go-cmp v0.4.0 // indirect
-	github.com/hashicorp/go-getter v1.4.1 // indirect
-	github.com/hashicorp/go-hclog v0.10.1 // indirect
-	github.com/hashicorp/hcl/v2 v2.3.0 // indirect
-	github.com/hashicorp/terraform-plugin-sdk v1.4.1
-	github.com/hashicorp/terraform-svchost v0.0.0-20191119180714-d2e4933b9136 // indirect
+	github.com/hashicorp/go-cty v1.4.1-0.20200414143053-d3edf31b6320
+	github.com/hashicorp/terraform-plugin-sdk/v2 v2.10.1
 	github.com/hashicorp/yamux v0.0.0-20190923154419-df201c70410d // indirect
+	github.com/jlaffaye/ftp v0.1.0
 	github.com/oklog/run v1.1.0 // indirect
 	github.com/pkg/errors v0.9.1
-	github.com/posener/complete v1.2.3 // indirect
-	github.com/ulikunitz/xz v0.5.6 // indirect
-	github.com/vmihailenco/msgpack v4.0.4+incompatible // indirect
-	golang.org/x/crypto v0.0.0-20200108215511-5d647ca15757
-	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d // indirect
-	golang.org/x/sys v0.0.0-20200107162124-548cf772de50 // indirect
-	golang.org/x/tools v0.0.0-20200108203644-89082a384178 // indirect
-	google.golang.org/genproto v0.0.0-20200108215221-bd8f9a0ef82f // indirect
+	software.sslmate.com/src/go-pkcs12 v0.4.0
 )
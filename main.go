@@ -1,8 +1,12 @@
 package main
 
 import (
+	"os"
+
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/ddcloud"
-	"github.com/hashicorp/terraform-plugin-sdk/plugin"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/drift"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/migration"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
 
 // The main program entry-point.
@@ -13,6 +17,22 @@ func main() {
 	//	return
 	//}
 
+	// Sub-commands (when not being run as a plugin by Terraform).
+	if len(os.Args) > 1 {
+		provider := ddcloud.Provider()
+
+		switch os.Args[1] {
+		case drift.CommandName:
+			os.Exit(
+				drift.RunCommand(provider, os.Args[2:], os.Stdout, os.Stderr),
+			)
+		case migration.CommandName:
+			os.Exit(
+				migration.RunCommand(provider, os.Args[2:], os.Stdout, os.Stderr),
+			)
+		}
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: ddcloud.Provider,
 	})
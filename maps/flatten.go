@@ -0,0 +1,40 @@
+package maps
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Flatten flattens a JSON-compatible attribute value into legacy (flatmap) format, adding the resulting attribute paths and values to flattened.
+//
+// Lists and sets produce a "path.#" count entry and maps produce a "path.%" count entry (in the same style as Terraform's legacy state format). Null values are omitted.
+func Flatten(prefix string, value interface{}, flattened map[string]string) {
+	switch typedValue := value.(type) {
+	case nil:
+		return
+	case map[string]interface{}:
+		if prefix != "" {
+			flattened[prefix+".%"] = strconv.Itoa(len(typedValue))
+			prefix += "."
+		}
+		for key, item := range typedValue {
+			Flatten(prefix+key, item, flattened)
+		}
+	case []interface{}:
+		flattened[prefix+".#"] = strconv.Itoa(len(typedValue))
+		for index, item := range typedValue {
+			Flatten(prefix+"."+strconv.Itoa(index), item, flattened)
+		}
+	case string:
+		flattened[prefix] = typedValue
+	case bool:
+		flattened[prefix] = strconv.FormatBool(typedValue)
+	case json.Number:
+		flattened[prefix] = typedValue.String()
+	case float64:
+		flattened[prefix] = strconv.FormatFloat(typedValue, 'f', -1, 64)
+	default:
+		flattened[prefix] = fmt.Sprint(typedValue)
+	}
+}
@@ -0,0 +1,123 @@
+package migration
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// CommandName is the name of the provider sub-command that migrates state.
+const CommandName = "migrate-state"
+
+// RunCommand runs the state-migration command with the specified command-line arguments (excluding the command name).
+//
+// Returns the process exit code.
+func RunCommand(provider *schema.Provider, args []string, stdout io.Writer, stderr io.Writer) int {
+	commandLine := flag.NewFlagSet(CommandName, flag.ContinueOnError)
+	commandLine.SetOutput(stderr)
+	commandLine.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: terraform-provider-ddcloud %s [options]\n\n", CommandName)
+		fmt.Fprintln(stderr, "Migrates ddcloud resources in a Terraform state file to the current version of the provider.")
+		fmt.Fprintln(stderr, "")
+		commandLine.PrintDefaults()
+	}
+
+	stateFileName := commandLine.String("state", "terraform.tfstate", "The Terraform state file to migrate.")
+	outputFileName := commandLine.String("out", "", "Write migrated state to this file instead of updating the state file in-place ('-' for standard output).")
+	backupFileName := commandLine.String("backup", "", "The file where a backup of the original state is written (default: STATE.TIMESTAMP.backup).")
+	dryRun := commandLine.Bool("dry-run", false, "Only list the changes that would be made.")
+
+	err := commandLine.Parse(args)
+	if err != nil {
+		return 1
+	}
+
+	fail := func(format string, formatArgs ...interface{}) int {
+		fmt.Fprintf(stderr, "Error: "+format+"\n", formatArgs...)
+
+		return 1
+	}
+
+	originalStateData, err := ioutil.ReadFile(*stateFileName)
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	state, err := ReadState(bytes.NewReader(originalStateData))
+	if err != nil {
+		return fail("%s: %s", *stateFileName, err)
+	}
+
+	changes, err := Apply(state, Migrations(provider))
+	if err != nil {
+		return fail("%s: %s", *stateFileName, err)
+	}
+
+	// When writing state to standard output, the list of changes goes to standard error.
+	changeLog := stdout
+	if *outputFileName == "-" {
+		changeLog = stderr
+	}
+	for _, change := range changes {
+		fmt.Fprintln(changeLog, change)
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(changeLog, "%s: no migration required.\n", *stateFileName)
+
+		return 0
+	}
+	if *dryRun {
+		return 0
+	}
+
+	if *outputFileName == "-" {
+		err = state.Write(stdout)
+		if err != nil {
+			return fail("%s", err)
+		}
+
+		return 0
+	}
+
+	var migratedStateData bytes.Buffer
+	err = state.Write(&migratedStateData)
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	if *outputFileName != "" {
+		err = ioutil.WriteFile(*outputFileName, migratedStateData.Bytes(), 0644)
+		if err != nil {
+			return fail("%s", err)
+		}
+		fmt.Fprintf(changeLog, "Wrote migrated state to '%s'.\n", *outputFileName)
+
+		return 0
+	}
+
+	if *backupFileName == "" {
+		*backupFileName = fmt.Sprintf("%s.%d.backup", *stateFileName, time.Now().Unix())
+	}
+	if _, err = os.Stat(*backupFileName); err == nil {
+		return fail("backup file '%s' already exists", *backupFileName)
+	}
+	err = ioutil.WriteFile(*backupFileName, originalStateData, 0644)
+	if err != nil {
+		return fail("failed to write backup: %s", err)
+	}
+	fmt.Fprintf(changeLog, "Wrote backup of original state to '%s'.\n", *backupFileName)
+
+	err = ioutil.WriteFile(*stateFileName, migratedStateData.Bytes(), 0644)
+	if err != nil {
+		return fail("%s", err)
+	}
+	fmt.Fprintf(changeLog, "Wrote migrated state to '%s'.\n", *stateFileName)
+
+	return 0
+}
@@ -0,0 +1,221 @@
+// Package migration migrates Terraform state data between versions of the ddcloud provider.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Migration is a single migration step that is applied to Terraform state data.
+type Migration interface {
+	// Name is a short, unique name for the migration.
+	Name() string
+
+	// Apply the migration to the specified state data.
+	//
+	// Returns a description of each change that was made.
+	Apply(state *State) (changes []string, err error)
+}
+
+// Migrations returns the registry of all known migrations, in the order they should be applied.
+//
+// provider is the ddcloud provider (used to obtain resource schemas and state upgraders).
+func Migrations(provider *schema.Provider) []Migration {
+	return []Migration{
+		// v1.1 -> v1.2
+		&renameResourceTypeMigration{
+			name:    "rename-server-nic",
+			oldType: "ddcloud_server_nic",
+			newType: "ddcloud_network_adapter",
+			renamedAttributes: map[string]string{
+				"private_ipv4": "ipv4",
+				"private_ipv6": "ipv6",
+				"adapter_type": "type",
+			},
+		},
+
+		// Upgrade to the current schema version for each resource type.
+		&schemaUpgradeMigration{
+			provider: provider,
+		},
+	}
+}
+
+// Apply the specified migrations to state data.
+//
+// Returns a description of each change that was made (prefixed with the name of the migration that made it).
+func Apply(state *State, migrations []Migration) (changes []string, err error) {
+	for _, migration := range migrations {
+		log.Printf("Applying migration '%s'...", migration.Name())
+
+		var migrationChanges []string
+		migrationChanges, err = migration.Apply(state)
+		if err != nil {
+			return nil, fmt.Errorf("migration '%s' failed: %s", migration.Name(), err)
+		}
+
+		for _, change := range migrationChanges {
+			changes = append(changes, fmt.Sprintf("%s: %s", migration.Name(), change))
+		}
+	}
+
+	if len(changes) > 0 {
+		state.IncrementSerial()
+	}
+
+	return
+}
+
+// renameResourceTypeMigration renames a resource type (and, optionally, some of its attributes).
+type renameResourceTypeMigration struct {
+	name              string
+	oldType           string
+	newType           string
+	renamedAttributes map[string]string
+}
+
+var _ Migration = &renameResourceTypeMigration{}
+
+// Name is a short, unique name for the migration.
+func (migration *renameResourceTypeMigration) Name() string {
+	return migration.name
+}
+
+// Apply the migration to the specified state data.
+func (migration *renameResourceTypeMigration) Apply(state *State) (changes []string, err error) {
+	var oldAttributeNames []string
+	for oldAttributeName := range migration.renamedAttributes {
+		oldAttributeNames = append(oldAttributeNames, oldAttributeName)
+	}
+	sort.Strings(oldAttributeNames)
+
+	for _, instance := range state.Instances() {
+		if instance.Type() != migration.oldType {
+			continue
+		}
+
+		for _, oldAttributeName := range oldAttributeNames {
+			newAttributeName := migration.renamedAttributes[oldAttributeName]
+			if instance.RenameAttribute(oldAttributeName, newAttributeName) {
+				changes = append(changes, fmt.Sprintf("%s: renamed attribute '%s' to '%s'",
+					instance.Address, oldAttributeName, newAttributeName,
+				))
+			}
+		}
+	}
+
+	renamedCount := state.RenameResourceType(migration.oldType, migration.newType)
+	if renamedCount > 0 {
+		changes = append(changes, fmt.Sprintf("renamed %d resource(s) of type '%s' to '%s'",
+			renamedCount, migration.oldType, migration.newType,
+		))
+	}
+
+	return
+}
+
+// schemaUpgradeMigration upgrades the state for each resource to the current schema version of its resource type.
+type schemaUpgradeMigration struct {
+	provider *schema.Provider
+}
+
+var _ Migration = &schemaUpgradeMigration{}
+
+// Name is a short, unique name for the migration.
+func (migration *schemaUpgradeMigration) Name() string {
+	return "upgrade-schema"
+}
+
+// Apply the migration to the specified state data.
+func (migration *schemaUpgradeMigration) Apply(state *State) (changes []string, err error) {
+	for _, instance := range state.Instances() {
+		resource, ok := migration.provider.ResourcesMap[instance.Type()]
+		if !ok {
+			continue
+		}
+
+		schemaVersion := instance.SchemaVersion()
+		if schemaVersion >= resource.SchemaVersion {
+			continue
+		}
+
+		err = upgradeInstanceState(instance, resource, schemaVersion)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", instance.Address, err)
+		}
+		instance.SetSchemaVersion(resource.SchemaVersion)
+
+		changes = append(changes, fmt.Sprintf("%s: upgraded from schema version %d to %d",
+			instance.Address, schemaVersion, resource.SchemaVersion,
+		))
+	}
+
+	return
+}
+
+// Upgrade instance state to the current schema version of its resource type.
+//
+// This follows the same process that Terraform uses: the resource's (legacy) MigrateState function is only used for schema versions that pre-date its first StateUpgrader.
+func upgradeInstanceState(instance *Instance, resource *schema.Resource, schemaVersion int) error {
+	if resource.MigrateState != nil && (len(resource.StateUpgraders) == 0 || schemaVersion < resource.StateUpgraders[0].Version) {
+		attributes := instance.FlatAttributes()
+		instanceState := &terraform.InstanceState{
+			ID:         attributes["id"],
+			Attributes: attributes,
+			Meta: map[string]interface{}{
+				"schema_version": schemaVersion,
+			},
+		}
+
+		migratedState, err := resource.MigrateState(schemaVersion, instanceState, nil)
+		if err != nil {
+			return err
+		}
+
+		schemaVersion = resource.SchemaVersion
+		if len(resource.StateUpgraders) > 0 {
+			schemaVersion = resource.StateUpgraders[0].Version
+		}
+
+		// Attributes are stored using the type of the current schema (or of the first upgrader's schema, if there are further upgrades to apply).
+		err = instance.SetFlatAttributes(migratedState.Attributes, stateTypeForVersion(resource, schemaVersion))
+		if err != nil {
+			return err
+		}
+	}
+
+	rawState, err := instance.Attributes(stateTypeForVersion(resource, schemaVersion))
+	if err != nil {
+		return err
+	}
+	for _, upgrader := range resource.StateUpgraders {
+		if upgrader.Version != schemaVersion {
+			continue
+		}
+
+		rawState, err = upgrader.Upgrade(context.Background(), rawState, nil)
+		if err != nil {
+			return fmt.Errorf("failed to upgrade from schema version %d: %s", schemaVersion, err)
+		}
+		schemaVersion++
+	}
+
+	return instance.SetAttributes(rawState, resource.CoreConfigSchema().ImpliedType())
+}
+
+// Get the state type for the specified schema version of a resource.
+func stateTypeForVersion(resource *schema.Resource, schemaVersion int) cty.Type {
+	for _, upgrader := range resource.StateUpgraders {
+		if upgrader.Version == schemaVersion {
+			return upgrader.Type
+		}
+	}
+
+	return resource.CoreConfigSchema().ImpliedType()
+}
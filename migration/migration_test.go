@@ -0,0 +1,144 @@
+package migration
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/ddcloud"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var updateGoldenFiles = flag.Bool("update", false, "Update golden files in testdata")
+
+// Unit test - migrate legacy (v1.1) state containing ddcloud_server_nic and v0 ddcloud_server.
+func TestMigrateLegacyV11(test *testing.T) {
+	testMigrateGoldenFile(test, "legacy-v1.1")
+}
+
+// Unit test - migrate v4 state containing v4 ddcloud_server and ddcloud_server_nic.
+func TestMigrateV4ServerV4(test *testing.T) {
+	testMigrateGoldenFile(test, "v4-server-v4")
+}
+
+// Unit test - state that is already current is not modified.
+func TestMigrateV4Current(test *testing.T) {
+	changes := testMigrateGoldenFile(test, "v4-current")
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("Changes.Length", 0, len(changes))
+}
+
+// Unit test - unsupported state versions are rejected.
+func TestReadStateUnsupportedVersion(test *testing.T) {
+	_, err := ReadState(strings.NewReader(`{ "version": 5 }`))
+	if err == nil {
+		test.Fatal("Expected an error for an unsupported state version.")
+	}
+}
+
+// Unit test - the migrate-state command writes a backup and updates the state file in-place.
+func TestRunCommandWritesBackup(test *testing.T) {
+	workingDirectory, err := ioutil.TempDir("", "ddcloud-migrate-state")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(workingDirectory)
+
+	originalStateData, err := ioutil.ReadFile(filepath.Join("testdata", "legacy-v1.1.tfstate"))
+	if err != nil {
+		test.Fatal(err)
+	}
+	stateFileName := filepath.Join(workingDirectory, "terraform.tfstate")
+	backupFileName := filepath.Join(workingDirectory, "terraform.tfstate.backup")
+	err = ioutil.WriteFile(stateFileName, originalStateData, 0644)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	exitCode := RunCommand(testProvider(), []string{"-state", stateFileName, "-backup", backupFileName}, &stdout, &stderr)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("ExitCode", 0, exitCode)
+
+	backupData, err := ioutil.ReadFile(backupFileName)
+	if err != nil {
+		test.Fatal(err)
+	}
+	assert.EqualsString("Backup", string(originalStateData), string(backupData))
+
+	migratedStateData, err := ioutil.ReadFile(stateFileName)
+	if err != nil {
+		test.Fatal(err)
+	}
+	assert.IsTrue("Migrated state contains ddcloud_network_adapter",
+		strings.Contains(string(migratedStateData), `"ddcloud_network_adapter.web_nic2"`),
+	)
+
+	// Running the command again must not overwrite the existing backup.
+	exitCode = RunCommand(testProvider(), []string{"-state", stateFileName, "-backup", backupFileName}, &stdout, &stderr)
+	assert.EqualsInt("ExitCode (second run)", 0, exitCode)
+	assert.IsTrue("Second run reports no migration",
+		strings.Contains(stdout.String(), "no migration required"),
+	)
+}
+
+// Migrate testdata/NAME.tfstate and compare the result with testdata/NAME.golden.tfstate.
+func testMigrateGoldenFile(test *testing.T, name string) (changes []string) {
+	inputFileName := filepath.Join("testdata", name+".tfstate")
+	goldenFileName := filepath.Join("testdata", name+".golden.tfstate")
+
+	inputFile, err := os.Open(inputFileName)
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer inputFile.Close()
+
+	state, err := ReadState(inputFile)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	changes, err = Apply(state, Migrations(testProvider()))
+	if err != nil {
+		test.Fatal(err)
+	}
+	for _, change := range changes {
+		test.Log(change)
+	}
+
+	var actual bytes.Buffer
+	err = state.Write(&actual)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if *updateGoldenFiles {
+		err = ioutil.WriteFile(goldenFileName, actual.Bytes(), 0644)
+		if err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	expected, err := ioutil.ReadFile(goldenFileName)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if !bytes.Equal(expected, actual.Bytes()) {
+		test.Fatalf("Migrated state for '%s' does not match '%s' (run 'go test ./migration -update' to update golden files).\nActual:\n%s",
+			inputFileName, goldenFileName, actual.String(),
+		)
+	}
+
+	return
+}
+
+func testProvider() *schema.Provider {
+	return ddcloud.Provider()
+}
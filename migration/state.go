@@ -0,0 +1,505 @@
+package migration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/maps"
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// State represents the contents of a Terraform state file.
+//
+// Both the legacy (v3, "modules") and current (v4, "resources") formats are supported; properties not understood by the migration tool are preserved as-is.
+type State struct {
+	// The state format version.
+	Version int
+
+	data map[string]interface{}
+}
+
+// IsLegacy determines whether the state uses the legacy (Terraform v0.11 and earlier) format.
+func (state *State) IsLegacy() bool {
+	return state.Version < 4
+}
+
+// ReadState reads Terraform state data.
+func ReadState(reader io.Reader) (*State, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	state := &State{}
+	err := decoder.Decode(&state.data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state data: %s", err)
+	}
+
+	rawVersion, ok := state.data["version"].(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("unrecognised state file format (no version information)")
+	}
+	version, err := rawVersion.Int64()
+	if err != nil {
+		return nil, fmt.Errorf("invalid state format version '%s'", rawVersion)
+	}
+	state.Version = int(version)
+
+	if state.Version < 1 || state.Version > 4 {
+		return nil, fmt.Errorf("unsupported state format version %d", state.Version)
+	}
+
+	return state, nil
+}
+
+// Write the state data.
+func (state *State) Write(writer io.Writer) error {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(state.data)
+	if err != nil {
+		return err
+	}
+
+	_, err = buffer.WriteTo(writer)
+
+	return err
+}
+
+// IncrementSerial increments the state's serial number (Terraform uses this to detect changes to state).
+func (state *State) IncrementSerial() {
+	serial := int64(0)
+	if rawSerial, ok := state.data["serial"].(json.Number); ok {
+		serial, _ = rawSerial.Int64()
+	}
+
+	state.data["serial"] = json.Number(strconv.FormatInt(serial+1, 10))
+}
+
+// Instances retrieves all managed resource instances in the state.
+func (state *State) Instances() (instances []*Instance) {
+	if state.IsLegacy() {
+		for _, module := range getMapSlice(state.data, "modules") {
+			resources := getMap(module, "resources")
+
+			resourceKeys := make([]string, 0, len(resources))
+			for resourceKey := range resources {
+				resourceKeys = append(resourceKeys, resourceKey)
+			}
+			sort.Strings(resourceKeys)
+
+			for _, resourceKey := range resourceKeys {
+				if strings.HasPrefix(resourceKey, "data.") {
+					continue
+				}
+
+				resource, ok := resources[resourceKey].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				primary, ok := resource["primary"].(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				instances = append(instances, &Instance{
+					Address:  legacyModulePrefix(module) + resourceKey,
+					resource: resource,
+					data:     primary,
+					isLegacy: true,
+				})
+			}
+		}
+
+		return
+	}
+
+	for _, resource := range getMapSlice(state.data, "resources") {
+		if resource["mode"] != "managed" {
+			continue
+		}
+
+		for _, instance := range getMapSlice(resource, "instances") {
+			address := fmt.Sprintf("%s.%s", resource["type"], resource["name"])
+			if module, ok := resource["module"].(string); ok && module != "" {
+				address = module + "." + address
+			}
+			if indexKey, ok := instance["index_key"]; ok {
+				indexKeyJSON, _ := json.Marshal(indexKey)
+				address += "[" + string(indexKeyJSON) + "]"
+			}
+
+			instances = append(instances, &Instance{
+				Address:  address,
+				resource: resource,
+				data:     instance,
+			})
+		}
+	}
+
+	return
+}
+
+// RenameResourceType changes the type of all resources of the specified type (including references to them from other resources' dependencies).
+//
+// Returns the number of resources that were renamed.
+func (state *State) RenameResourceType(oldType string, newType string) (renamedCount int) {
+	oldPrefix := oldType + "."
+	newPrefix := newType + "."
+	renameAddress := func(address string) string {
+		// Addresses may be qualified with a module path (e.g. "module.foo.type.name").
+		if strings.HasPrefix(address, oldPrefix) {
+			return newPrefix + strings.TrimPrefix(address, oldPrefix)
+		}
+		if index := strings.Index(address, "."+oldPrefix); index != -1 && strings.HasPrefix(address, "module.") {
+			return address[:index+1] + newPrefix + address[index+1+len(oldPrefix):]
+		}
+
+		return address
+	}
+
+	if state.IsLegacy() {
+		for _, module := range getMapSlice(state.data, "modules") {
+			resources := getMap(module, "resources")
+
+			resourceKeys := make([]string, 0, len(resources))
+			for resourceKey := range resources {
+				resourceKeys = append(resourceKeys, resourceKey)
+			}
+
+			for _, resourceKey := range resourceKeys {
+				resource, ok := resources[resourceKey].(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				renameDependencies(resource, "depends_on", renameAddress)
+
+				if !strings.HasPrefix(resourceKey, oldPrefix) {
+					continue
+				}
+
+				resource["type"] = newType
+				delete(resources, resourceKey)
+				resources[renameAddress(resourceKey)] = resource
+
+				renamedCount++
+			}
+		}
+
+		return
+	}
+
+	for _, resource := range getMapSlice(state.data, "resources") {
+		for _, instance := range getMapSlice(resource, "instances") {
+			renameDependencies(instance, "dependencies", renameAddress)
+		}
+
+		if resource["mode"] != "managed" || resource["type"] != oldType {
+			continue
+		}
+
+		resource["type"] = newType
+		renamedCount++
+	}
+
+	return
+}
+
+// Instance represents a single resource instance in Terraform state.
+type Instance struct {
+	// The instance address (e.g. "module.foo.ddcloud_server.bar").
+	Address string
+
+	resource map[string]interface{}
+	data     map[string]interface{}
+	isLegacy bool
+}
+
+// Type retrieves the instance's resource type.
+func (instance *Instance) Type() string {
+	resourceType, _ := instance.resource["type"].(string)
+
+	return resourceType
+}
+
+// SchemaVersion retrieves the schema version of the instance's attributes.
+func (instance *Instance) SchemaVersion() int {
+	var rawVersion interface{}
+	if instance.isLegacy {
+		rawVersion = getMap(instance.data, "meta")["schema_version"]
+	} else {
+		rawVersion = instance.data["schema_version"]
+	}
+	if rawVersion == nil {
+		return 0
+	}
+
+	version, err := strconv.Atoi(fmt.Sprint(rawVersion))
+	if err != nil {
+		return 0
+	}
+
+	return version
+}
+
+// SetSchemaVersion updates the schema version of the instance's attributes.
+func (instance *Instance) SetSchemaVersion(version int) {
+	if instance.isLegacy {
+		meta := getMap(instance.data, "meta")
+		meta["schema_version"] = strconv.Itoa(version)
+		instance.data["meta"] = meta
+
+		return
+	}
+
+	instance.data["schema_version"] = json.Number(strconv.Itoa(version))
+}
+
+// FlatAttributes retrieves the instance's attributes in legacy (flatmap) format.
+//
+// For v4 state, attributes are converted from their JSON representation.
+func (instance *Instance) FlatAttributes() map[string]string {
+	attributes := make(map[string]string)
+
+	if instance.isLegacy {
+		for key, value := range getMap(instance.data, "attributes") {
+			attributes[key] = fmt.Sprint(value)
+		}
+		if id, ok := instance.data["id"].(string); ok {
+			attributes["id"] = id
+		}
+
+		return attributes
+	}
+
+	maps.Flatten("", instance.data["attributes"], attributes)
+
+	return attributes
+}
+
+// SetFlatAttributes updates the instance's attributes in legacy (flatmap) format.
+//
+// For v4 state, attributes are converted to their JSON representation using stateType.
+func (instance *Instance) SetFlatAttributes(attributes map[string]string, stateType cty.Type) error {
+	if instance.isLegacy {
+		attributeData := make(map[string]interface{}, len(attributes))
+		for key, value := range attributes {
+			attributeData[key] = value
+		}
+		instance.data["attributes"] = attributeData
+		if id, ok := attributes["id"]; ok {
+			instance.data["id"] = id
+		}
+
+		return nil
+	}
+
+	value, err := (&terraform.InstanceState{
+		ID:         attributes["id"],
+		Attributes: attributes,
+	}).AttrsAsObjectValue(stateType)
+	if err != nil {
+		return err
+	}
+
+	attributeData, err := valueToJSONMap(value, stateType)
+	if err != nil {
+		return err
+	}
+	instance.data["attributes"] = attributeData
+
+	return nil
+}
+
+// Attributes retrieves the instance's attributes in JSON format.
+//
+// For legacy state, attributes are converted from their flatmap representation using stateType.
+func (instance *Instance) Attributes(stateType cty.Type) (map[string]interface{}, error) {
+	if !instance.isLegacy {
+		return getMap(instance.data, "attributes"), nil
+	}
+
+	attributes := instance.FlatAttributes()
+	value, err := (&terraform.InstanceState{
+		ID:         attributes["id"],
+		Attributes: attributes,
+	}).AttrsAsObjectValue(stateType)
+	if err != nil {
+		return nil, err
+	}
+
+	return valueToJSONMap(value, stateType)
+}
+
+// SetAttributes updates the instance's attributes from JSON format.
+//
+// Attributes that are not part of stateType are discarded, and the remaining attributes are normalised to match stateType.
+// For legacy state, attributes are converted to their flatmap representation.
+func (instance *Instance) SetAttributes(attributes map[string]interface{}, stateType cty.Type) error {
+	removeUnknownAttributes(attributes, stateType)
+
+	attributesJSON, err := json.Marshal(attributes)
+	if err != nil {
+		return err
+	}
+	value, err := ctyjson.Unmarshal(attributesJSON, stateType)
+	if err != nil {
+		return err
+	}
+
+	if instance.isLegacy {
+		flatAttributes := terraform.NewInstanceStateShimmedFromValue(value, 0).Attributes
+
+		return instance.SetFlatAttributes(flatAttributes, stateType)
+	}
+
+	attributeData, err := valueToJSONMap(value, stateType)
+	if err != nil {
+		return err
+	}
+	instance.data["attributes"] = attributeData
+
+	return nil
+}
+
+// RenameAttribute renames a top-level attribute of the instance.
+//
+// Returns false if the instance has no such attribute.
+func (instance *Instance) RenameAttribute(oldName string, newName string) bool {
+	attributes := getMap(instance.data, "attributes")
+
+	if !instance.isLegacy {
+		value, ok := attributes[oldName]
+		if !ok {
+			return false
+		}
+		delete(attributes, oldName)
+		attributes[newName] = value
+
+		return true
+	}
+
+	var keys []string
+	for key := range attributes {
+		if key == oldName || strings.HasPrefix(key, oldName+".") {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		value := attributes[key]
+		delete(attributes, key)
+		attributes[newName+strings.TrimPrefix(key, oldName)] = value
+	}
+
+	return len(keys) > 0
+}
+
+// Convert an attribute value to its JSON representation (numbers are represented as json.Number).
+func valueToJSONMap(value cty.Value, valueType cty.Type) (map[string]interface{}, error) {
+	valueJSON, err := ctyjson.Marshal(value, valueType)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(valueJSON))
+	decoder.UseNumber()
+
+	var attributeData map[string]interface{}
+	err = decoder.Decode(&attributeData)
+
+	return attributeData, err
+}
+
+// Remove attributes (including the attributes of nested blocks) that are not part of the specified type.
+func removeUnknownAttributes(value interface{}, valueType cty.Type) {
+	switch typedValue := value.(type) {
+	case []interface{}:
+		if valueType.IsListType() || valueType.IsSetType() {
+			for _, item := range typedValue {
+				removeUnknownAttributes(item, valueType.ElementType())
+			}
+		}
+	case map[string]interface{}:
+		if valueType.IsMapType() {
+			for _, item := range typedValue {
+				removeUnknownAttributes(item, valueType.ElementType())
+			}
+
+			return
+		}
+		if !valueType.IsObjectType() {
+			return
+		}
+
+		attributeTypes := valueType.AttributeTypes()
+		for attributeName, attributeValue := range typedValue {
+			attributeType, ok := attributeTypes[attributeName]
+			if !ok {
+				delete(typedValue, attributeName)
+
+				continue
+			}
+
+			removeUnknownAttributes(attributeValue, attributeType)
+		}
+	}
+}
+
+func renameDependencies(data map[string]interface{}, key string, renameAddress func(string) string) {
+	dependencies, ok := data[key].([]interface{})
+	if !ok {
+		return
+	}
+
+	for index, dependency := range dependencies {
+		if address, ok := dependency.(string); ok {
+			dependencies[index] = renameAddress(address)
+		}
+	}
+}
+
+func legacyModulePrefix(module map[string]interface{}) (prefix string) {
+	path, _ := module["path"].([]interface{})
+	for _, moduleName := range path {
+		if moduleName == "root" {
+			continue
+		}
+		prefix += fmt.Sprintf("module.%s.", moduleName)
+	}
+
+	return
+}
+
+func getMap(data map[string]interface{}, key string) map[string]interface{} {
+	value, ok := data[key].(map[string]interface{})
+	if !ok {
+		return make(map[string]interface{})
+	}
+
+	return value
+}
+
+func getMapSlice(data map[string]interface{}, key string) (maps []map[string]interface{}) {
+	slice, ok := data[key].([]interface{})
+	if !ok {
+		return
+	}
+
+	for _, item := range slice {
+		if itemMap, ok := item.(map[string]interface{}); ok {
+			maps = append(maps, itemMap)
+		}
+	}
+
+	return
+}
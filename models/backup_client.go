@@ -1,6 +1,8 @@
 package models
 
 import (
+	"sort"
+
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/maps"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
 )
@@ -15,6 +17,11 @@ type ServerBackupClient struct {
 	DownloadURL        string
 	Status             string
 	Alerting           *BackupClientAlerting
+
+	// Job information (as reported by Cloud Backup).
+	LastJobTime   string
+	LastJobStatus string
+	NextJobTime   string
 }
 
 // BackupClientAlerting represents the alerting configuration (if any) for a backup client.
@@ -23,6 +30,50 @@ type BackupClientAlerting struct {
 	Emails  []string
 }
 
+// Equals determines whether the BackupClientAlerting is equivalent to another BackupClientAlerting (the order of email addresses is not significant).
+func (alerting *BackupClientAlerting) Equals(other *BackupClientAlerting) bool {
+	if alerting == nil || other == nil {
+		return alerting == other
+	}
+
+	if alerting.Trigger != other.Trigger || len(alerting.Emails) != len(other.Emails) {
+		return false
+	}
+
+	emails := make([]string, len(alerting.Emails))
+	copy(emails, alerting.Emails)
+	sort.Strings(emails)
+
+	otherEmails := make([]string, len(other.Emails))
+	copy(otherEmails, other.Emails)
+	sort.Strings(otherEmails)
+
+	for index := range emails {
+		if emails[index] != otherEmails[index] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newBackupClientAlerting creates a BackupClientAlerting (with a copy of the specified email addresses).
+//
+// Returns nil if there is no trigger and no email addresses (i.e. alerting is not configured).
+func newBackupClientAlerting(trigger string, emails []string) *BackupClientAlerting {
+	if trigger == "" && len(emails) == 0 {
+		return nil
+	}
+
+	alerting := &BackupClientAlerting{
+		Trigger: trigger,
+		Emails:  make([]string, len(emails)),
+	}
+	copy(alerting.Emails, emails)
+
+	return alerting
+}
+
 // ReadMap populates the ServerBackupClient with values from the specified map.
 func (backupClient *ServerBackupClient) ReadMap(backupClientProperties map[string]interface{}) {
 	reader := maps.NewReader(backupClientProperties)
@@ -40,10 +91,10 @@ func (backupClient *ServerBackupClient) ReadMap(backupClientProperties map[strin
 	}
 
 	alertingReader := maps.NewReader(alertingProperties)
-	backupClient.Alerting = &BackupClientAlerting{
-		Trigger: alertingReader.GetString("trigger"),
-		Emails:  alertingReader.GetStringSlice("emails"),
-	}
+	backupClient.Alerting = newBackupClientAlerting(
+		alertingReader.GetString("trigger"),
+		alertingReader.GetStringSlice("emails"),
+	)
 }
 
 // ToMap creates a new map using the values from the ServerBackupClient.
@@ -65,6 +116,9 @@ func (backupClient *ServerBackupClient) UpdateMap(backupClientProperties map[str
 	writer.SetString("schedule_policy", backupClient.SchedulePolicyName)
 	writer.SetString("download_url", backupClient.DownloadURL)
 	writer.SetString("status", backupClient.Status)
+	writer.SetString("last_job_time", backupClient.LastJobTime)
+	writer.SetString("last_job_status", backupClient.LastJobStatus)
+	writer.SetString("next_job_time", backupClient.NextJobTime)
 
 	if backupClient.Alerting != nil {
 		alertingProperties := make(map[string]interface{})
@@ -87,10 +141,10 @@ func (backupClient *ServerBackupClient) ReadBackupClientDetail(backupClientDetai
 	backupClient.DownloadURL = backupClientDetail.DownloadURL
 	backupClient.Status = backupClientDetail.Status
 	if backupClientDetail.Alerting != nil {
-		backupClient.Alerting = &BackupClientAlerting{
-			Trigger: backupClientDetail.Alerting.Trigger,
-			Emails:  backupClientDetail.Alerting.EmailAddresses, // TODO: Make a copy
-		}
+		backupClient.Alerting = newBackupClientAlerting(
+			backupClientDetail.Alerting.Trigger,
+			backupClientDetail.Alerting.EmailAddresses,
+		)
 	} else {
 		backupClient.Alerting = nil
 	}
@@ -118,8 +172,9 @@ func (backupClient *ServerBackupClient) UpdateBackupClientDetail(backupClientDet
 	if backupClient.Alerting != nil {
 		backupClientDetail.Alerting = &compute.BackupClientAlerting{
 			Trigger:        backupClient.Alerting.Trigger,
-			EmailAddresses: backupClient.Alerting.Emails, // TODO: Make a copy
+			EmailAddresses: make([]string, len(backupClient.Alerting.Emails)),
 		}
+		copy(backupClientDetail.Alerting.EmailAddresses, backupClient.Alerting.Emails)
 	} else {
 		backupClientDetail.Alerting = nil
 	}
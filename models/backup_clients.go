@@ -1,7 +1,6 @@
 package models
 
 import (
-	"fmt"
 	"sort"
 
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
@@ -76,6 +75,20 @@ func (clients ServerBackupClients) CaptureIDs(actualServerBackupClients ServerBa
 	}
 }
 
+// CaptureAlertingEmailOrder updates the order of each ServerBackupClient's alert email addresses to match the previous (configured) clients.
+//
+// CloudControl does not preserve the order of alert email addresses, so the configured order is retained unless the alerting configuration has actually changed.
+func (clients ServerBackupClients) CaptureAlertingEmailOrder(previousServerBackupClients ServerBackupClients) {
+	previousServerBackupClientsByType := previousServerBackupClients.ByType()
+	for index := range clients {
+		client := &clients[index]
+		previousServerBackupClient, ok := previousServerBackupClientsByType[client.Type]
+		if ok && client.Alerting != nil && client.Alerting.Equals(previousServerBackupClient.Alerting) {
+			client.Alerting = newBackupClientAlerting(client.Alerting.Trigger, previousServerBackupClient.Alerting.Emails)
+		}
+	}
+}
+
 // ApplyCurrentConfiguration applies the current configuration, inline, to the old configuration.
 //
 // Call this function on the old ServerBackupClients, passing the new ServerBackupClients.
@@ -119,18 +132,12 @@ func (clients ServerBackupClients) SplitByAction(actualServerBackupClients Serve
 
 			changed = changed || configuredServerBackupClient.SchedulePolicyName != actualServerBackupClient.SchedulePolicyName
 			changed = changed || configuredServerBackupClient.StoragePolicyName != actualServerBackupClient.StoragePolicyName
-
-			if configuredServerBackupClient.Alerting != nil && actualServerBackupClient.Alerting != nil {
-				// Cheaty string comparison.
-				configuredAlerting := fmt.Sprintf("%#v", *configuredServerBackupClient.Alerting)
-				actualAlerting := fmt.Sprintf("%#v", *actualServerBackupClient.Alerting)
-
-				changed = changed || configuredAlerting != actualAlerting
-			} else {
-				changed = changed || configuredServerBackupClient.Alerting != actualServerBackupClient.Alerting
-			}
+			changed = changed || !configuredServerBackupClient.Alerting.Equals(actualServerBackupClient.Alerting)
 
 			if changed {
+				// Always modify the actual client (the Id in configuration may be missing or out-of-date).
+				configuredServerBackupClient.ID = actualServerBackupClient.ID
+
 				changeServerBackupClients = append(changeServerBackupClients, configuredServerBackupClient)
 			}
 		} else {
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/assert"
+)
+
+// Unit test - the order of alert email addresses is not significant.
+func TestBackupClientAlertingEquals(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	alerting := &BackupClientAlerting{Trigger: "ON_FAILURE", Emails: []string{"a@example.com", "b@example.com"}}
+
+	assert.IsTrue("Reordered emails", alerting.Equals(
+		&BackupClientAlerting{Trigger: "ON_FAILURE", Emails: []string{"b@example.com", "a@example.com"}},
+	))
+	assert.IsFalse("Different trigger", alerting.Equals(
+		&BackupClientAlerting{Trigger: "ON_SUCCESS", Emails: []string{"a@example.com", "b@example.com"}},
+	))
+	assert.IsFalse("Additional email", alerting.Equals(
+		&BackupClientAlerting{Trigger: "ON_FAILURE", Emails: []string{"a@example.com", "b@example.com", "c@example.com"}},
+	))
+	assert.IsFalse("Alerting removed", alerting.Equals(nil))
+
+	var noAlerting *BackupClientAlerting
+	assert.IsTrue("No alerting", noAlerting.Equals(nil))
+	assert.IsFalse("Alerting added", noAlerting.Equals(alerting))
+}
+
+// Unit test - removing alerting from a backup client is an in-place change to the existing client.
+func TestServerBackupClientsSplitByActionAlertingRemoved(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	actualClients := ServerBackupClients{
+		ServerBackupClient{
+			ID:                 "client-1",
+			Type:               "FA.Linux",
+			StoragePolicyName:  "14 Day Storage Policy",
+			SchedulePolicyName: "6AM - 12PM",
+			Alerting:           &BackupClientAlerting{Trigger: "ON_FAILURE", Emails: []string{"a@example.com", "b@example.com"}},
+		},
+	}
+	configuredClients := ServerBackupClients{
+		ServerBackupClient{
+			Type:               "FA.Linux",
+			StoragePolicyName:  "14 Day Storage Policy",
+			SchedulePolicyName: "6AM - 12PM",
+		},
+	}
+
+	addClients, changeClients, removeClients := configuredClients.SplitByAction(actualClients)
+	assert.EqualsInt("AddClients.Length", 0, len(addClients))
+	assert.EqualsInt("RemoveClients.Length", 0, len(removeClients))
+	assert.EqualsInt("ChangeClients.Length", 1, len(changeClients))
+	assert.EqualsString("ChangeClients[0].ID", "client-1", changeClients[0].ID)
+	assert.IsTrue("ChangeClients[0].Alerting == nil", changeClients[0].Alerting == nil)
+}
+
+// Unit test - reordering alert email addresses does not change a backup client.
+func TestServerBackupClientsSplitByActionAlertingReordered(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	actualClients := ServerBackupClients{
+		ServerBackupClient{
+			ID:       "client-1",
+			Type:     "FA.Linux",
+			Alerting: &BackupClientAlerting{Trigger: "ON_FAILURE", Emails: []string{"a@example.com", "b@example.com"}},
+		},
+	}
+	configuredClients := ServerBackupClients{
+		ServerBackupClient{
+			ID:       "client-1",
+			Type:     "FA.Linux",
+			Alerting: &BackupClientAlerting{Trigger: "ON_FAILURE", Emails: []string{"b@example.com", "a@example.com"}},
+		},
+	}
+
+	addClients, changeClients, removeClients := configuredClients.SplitByAction(actualClients)
+	assert.EqualsInt("AddClients.Length", 0, len(addClients))
+	assert.EqualsInt("ChangeClients.Length", 0, len(changeClients))
+	assert.EqualsInt("RemoveClients.Length", 0, len(removeClients))
+}
+
+// Unit test - multiple alert email addresses are read from state and written to CloudControl.
+func TestServerBackupClientAlertingMultipleEmails(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	backupClient := NewServerBackupClientFromMap(map[string]interface{}{
+		"type":            "FA.Linux",
+		"storage_policy":  "14 Day Storage Policy",
+		"schedule_policy": "6AM - 12PM",
+		"alert": []interface{}{
+			map[string]interface{}{
+				"trigger": "ON_SUCCESS_OR_FAILURE",
+				"emails":  []interface{}{"a@example.com", "b@example.com"},
+			},
+		},
+	})
+
+	backupClientDetail := backupClient.ToBackupClientDetail()
+	if backupClientDetail.Alerting == nil {
+		test.Fatal("BackupClientDetail.Alerting is nil")
+	}
+	assert.EqualsString("Alerting.Trigger", "ON_SUCCESS_OR_FAILURE", backupClientDetail.Alerting.Trigger)
+	assert.Equals("Alerting.EmailAddresses", []string{"a@example.com", "b@example.com"}, backupClientDetail.Alerting.EmailAddresses)
+
+	roundTripped := NewServerBackupClientFromBackupClientDetail(backupClientDetail)
+	assert.IsTrue("Round-tripped alerting", backupClient.Alerting.Equals(roundTripped.Alerting))
+}
+
+// Unit test - the configured order of alert email addresses is retained unless the alerting configuration has changed.
+func TestServerBackupClientsCaptureAlertingEmailOrder(test *testing.T) {
+	assert := assert.ForTest(test)
+
+	configuredClients := ServerBackupClients{
+		ServerBackupClient{
+			Type:     "FA.Linux",
+			Alerting: &BackupClientAlerting{Trigger: "ON_FAILURE", Emails: []string{"b@example.com", "a@example.com"}},
+		},
+		ServerBackupClient{
+			Type:     "MySQL",
+			Alerting: &BackupClientAlerting{Trigger: "ON_FAILURE", Emails: []string{"b@example.com", "a@example.com"}},
+		},
+	}
+	actualClients := ServerBackupClients{
+		ServerBackupClient{
+			Type:     "FA.Linux",
+			Alerting: &BackupClientAlerting{Trigger: "ON_FAILURE", Emails: []string{"a@example.com", "b@example.com"}},
+		},
+		ServerBackupClient{
+			Type:     "MySQL",
+			Alerting: &BackupClientAlerting{Trigger: "ON_FAILURE", Emails: []string{"a@example.com", "c@example.com"}},
+		},
+	}
+
+	actualClients.CaptureAlertingEmailOrder(configuredClients)
+	assert.Equals("FA.Linux emails", []string{"b@example.com", "a@example.com"}, actualClients[0].Alerting.Emails)
+	assert.Equals("MySQL emails", []string{"a@example.com", "c@example.com"}, actualClients[1].Alerting.Emails)
+}
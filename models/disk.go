@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/DimensionDataResearch/dd-cloud-compute-terraform/maps"
 	"github.com/DimensionDataResearch/go-dd-cloud-compute/compute"
@@ -13,14 +14,38 @@ func SCSIPath(busNumber int, unitID int) string {
 	return fmt.Sprintf("%d:%d", busNumber, unitID)
 }
 
+// Storage controller types.
+const (
+	// DiskControllerTypeSCSI represents a SCSI controller (the default).
+	DiskControllerTypeSCSI = "SCSI"
+
+	// DiskControllerTypeSATA represents a SATA controller.
+	DiskControllerTypeSATA = "SATA"
+
+	// DiskControllerTypeIDE represents an IDE controller.
+	DiskControllerTypeIDE = "IDE"
+)
+
 // Disk represents the Terraform configuration for a ddcloud_server disk.
+//
+// For disks attached to SATA or IDE controllers, SCSIBusNumber and SCSIUnitID represent the controller's bus number (or IDE channel) and the disk's unit Id (or IDE drive number).
 type Disk struct {
-	ID            string
-	SCSIBusNumber int
-	SCSIUnitID    int
-	SizeGB        int
-	Speed         string
-	Iops          int
+	ID             string
+	ControllerType string
+	SCSIBusNumber  int
+	SCSIUnitID     int
+	SizeGB         int
+	Speed          string
+	Iops           int
+}
+
+// GetControllerType gets the type of controller that the disk is attached to (SCSI, unless otherwise specified).
+func (disk Disk) GetControllerType() string {
+	if disk.ControllerType == "" {
+		return DiskControllerTypeSCSI
+	}
+
+	return disk.ControllerType
 }
 
 // SCSIPath builds a path representing the disk's SCSI bus number and logical unit ID.
@@ -28,6 +53,20 @@ func (disk Disk) SCSIPath() string {
 	return fmt.Sprintf("%d:%d", disk.SCSIBusNumber, disk.SCSIUnitID)
 }
 
+// RequiresPowerCycle determines whether changing a disk from its current configuration (actualDisk) to this configuration requires its server to be powered off.
+//
+// CloudControl only permits moving a disk to or from provisioned-IOPS storage while the server is stopped.
+// Other changes (expanding a disk, changing its IOPS, or changing between other speeds) can be made while the server is running.
+func (disk Disk) RequiresPowerCycle(actualDisk Disk) bool {
+	speed := strings.ToUpper(disk.Speed)
+	actualSpeed := strings.ToUpper(actualDisk.Speed)
+	if speed == actualSpeed {
+		return false
+	}
+
+	return speed == compute.ServerDiskSpeedProvisionedIops || actualSpeed == compute.ServerDiskSpeedProvisionedIops
+}
+
 // ReadMap populates the Disk with values from the specified map.
 func (disk *Disk) ReadMap(diskProperties map[string]interface{}) {
 	reader := maps.NewReader(diskProperties)
@@ -103,6 +103,25 @@ func (disks *Disks) ApplyCurrentConfiguration(currentDisks Disks) {
 	*disks = appliedDisks
 }
 
+// Managed returns the disks that are managed by a ddcloud_server or ddcloud_storage_controller resource.
+//
+// Disks at the specified SCSI paths are managed by ddcloud_disk resources, so they are excluded (and will not be picked up by SplitByAction).
+// All other disks (including those added outside of Terraform) are included.
+func (disks Disks) Managed(diskResourceSCSIPaths map[string]bool) (managedDisks Disks) {
+	managedDisks = make(Disks, 0, len(disks))
+	for _, disk := range disks {
+		if diskResourceSCSIPaths[disk.SCSIPath()] {
+			log.Printf("Ignoring disk '%s' (SCSI path %s) because it is managed by a ddcloud_disk resource.", disk.ID, disk.SCSIPath())
+
+			continue
+		}
+
+		managedDisks = append(managedDisks, disk)
+	}
+
+	return
+}
+
 // SplitByInitialType splits the (initially-configured) disks by whether they represent image disks or additional disks.
 //
 // configuredDisks represents the disks currently specified in configuration.
@@ -166,6 +185,21 @@ func (disks Disks) SplitByAction(actualDisks Disks) (addDisks Disks, changeDisks
 	return
 }
 
+// RequirePowerCycle determines whether changing the actual disks to match the (configured) disks requires the server to be powered off.
+//
+// Only disks that appear in both (by SCSI path) are considered; adding and removing disks does not require a power cycle.
+func (disks Disks) RequirePowerCycle(actualDisks Disks) bool {
+	actualDisksBySCSIPath := actualDisks.BySCSIPath()
+	for _, disk := range disks {
+		actualDisk, ok := actualDisksBySCSIPath[disk.SCSIPath()]
+		if ok && disk.RequiresPowerCycle(actualDisk) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // NewDisksFromStateData creates Disks from an array of Terraform state data.
 //
 // The values in the diskPropertyList are expected to be map[string]interface{}.
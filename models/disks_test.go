@@ -187,3 +187,85 @@ func TestSplitConfiguredDisksByActionNew1Changed2(test *testing.T) {
 
 	assert.EqualsInt("RemoveDisks.Length", 0, len(removeDisks))
 }
+
+// Unit test - Disks.Managed with no disks managed by ddcloud_disk resources.
+func TestManagedDisksNoDiskResources(test *testing.T) {
+	actualDisks := Disks{
+		Disk{ID: "disk0", SCSIUnitID: 0, SizeGB: 10, Speed: "STANDARD"},
+		Disk{ID: "disk1", SCSIUnitID: 1, SizeGB: 20, Speed: "STANDARD"},
+	}
+
+	managedDisks := actualDisks.Managed(nil)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("ManagedDisks.Length", 2, len(managedDisks))
+	assert.EqualsString("ManagedDisks[0].ID", "disk0", managedDisks[0].ID)
+	assert.EqualsString("ManagedDisks[1].ID", "disk1", managedDisks[1].ID)
+}
+
+// Unit test - Disks.Managed excludes disks managed by ddcloud_disk resources (so SplitByAction does not remove them), but not disks added outside of Terraform.
+func TestManagedDisksExcludesDiskResources(test *testing.T) {
+	configuredDisks := Disks{
+		Disk{ID: "disk0", SCSIUnitID: 0, SizeGB: 10, Speed: "STANDARD"},
+		Disk{SCSIUnitID: 3, SizeGB: 30, Speed: "STANDARD"},
+	}
+	actualDisks := Disks{
+		Disk{ID: "disk0", SCSIUnitID: 0, SizeGB: 10, Speed: "STANDARD"},
+		Disk{ID: "disk-resource", SCSIUnitID: 1, SizeGB: 20, Speed: "STANDARD"},
+		Disk{ID: "external", SCSIUnitID: 2, SizeGB: 50, Speed: "STANDARD"},
+		Disk{ID: "disk-resource-bus1", SCSIBusNumber: 1, SCSIUnitID: 0, SizeGB: 40, Speed: "STANDARD"},
+	}
+	diskResourceSCSIPaths := map[string]bool{
+		"0:1": true,
+		"1:0": true,
+	}
+
+	managedDisks := actualDisks.Managed(diskResourceSCSIPaths)
+
+	assert := assert.ForTest(test)
+	assert.EqualsInt("ManagedDisks.Length", 2, len(managedDisks))
+	assert.EqualsString("ManagedDisks[0].ID", "disk0", managedDisks[0].ID)
+	assert.EqualsString("ManagedDisks[1].ID", "external", managedDisks[1].ID)
+
+	addDisks, changeDisks, removeDisks := configuredDisks.SplitByAction(managedDisks)
+	assert.EqualsInt("AddDisks.Length", 1, len(addDisks))
+	assert.EqualsInt("AddDisks[0].SCSIUnitID", 3, addDisks[0].SCSIUnitID)
+	assert.EqualsInt("ChangeDisks.Length", 0, len(changeDisks))
+	assert.EqualsInt("RemoveDisks.Length", 1, len(removeDisks))
+	assert.EqualsString("RemoveDisks[0].ID", "external", removeDisks[0].ID)
+}
+
+// Unit test - Disks.RequirePowerCycle only when moving a disk to or from provisioned IOPS.
+func TestDisksRequirePowerCycle(test *testing.T) {
+	actualDisks := Disks{
+		Disk{ID: "disk0", SCSIUnitID: 0, SizeGB: 10, Speed: "STANDARD"},
+		Disk{ID: "disk1", SCSIUnitID: 1, SizeGB: 20, Speed: "PROVISIONEDIOPS", Iops: 100},
+	}
+
+	assert := assert.ForTest(test)
+
+	expandAndChangeIops := Disks{
+		Disk{ID: "disk0", SCSIUnitID: 0, SizeGB: 20, Speed: "STANDARD"},
+		Disk{ID: "disk1", SCSIUnitID: 1, SizeGB: 20, Speed: "PROVISIONEDIOPS", Iops: 200},
+		Disk{SCSIUnitID: 2, SizeGB: 20, Speed: "PROVISIONEDIOPS", Iops: 100},
+	}
+	assert.IsFalse("expandAndChangeIops.RequirePowerCycle", expandAndChangeIops.RequirePowerCycle(actualDisks))
+
+	changeStandardSpeed := Disks{
+		Disk{ID: "disk0", SCSIUnitID: 0, SizeGB: 10, Speed: "economy"},
+		Disk{ID: "disk1", SCSIUnitID: 1, SizeGB: 20, Speed: "PROVISIONEDIOPS", Iops: 100},
+	}
+	assert.IsFalse("changeStandardSpeed.RequirePowerCycle", changeStandardSpeed.RequirePowerCycle(actualDisks))
+
+	changeToProvisionedIops := Disks{
+		Disk{ID: "disk0", SCSIUnitID: 0, SizeGB: 10, Speed: "PROVISIONEDIOPS", Iops: 30},
+		Disk{ID: "disk1", SCSIUnitID: 1, SizeGB: 20, Speed: "PROVISIONEDIOPS", Iops: 100},
+	}
+	assert.IsTrue("changeToProvisionedIops.RequirePowerCycle", changeToProvisionedIops.RequirePowerCycle(actualDisks))
+
+	changeFromProvisionedIops := Disks{
+		Disk{ID: "disk0", SCSIUnitID: 0, SizeGB: 10, Speed: "STANDARD"},
+		Disk{ID: "disk1", SCSIUnitID: 1, SizeGB: 20, Speed: "STANDARD"},
+	}
+	assert.IsTrue("changeFromProvisionedIops.RequirePowerCycle", changeFromProvisionedIops.RequirePowerCycle(actualDisks))
+}
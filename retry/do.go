@@ -1,6 +1,7 @@
 package retry
 
 import (
+	"context"
 	"log"
 	"sync"
 	"time"
@@ -18,14 +19,15 @@ type Do interface {
 	// This determines the *maximum* period that the Do will wait between retries.
 	SetRetryPeriod(retryPeriod time.Duration)
 
-	// DoAction performs the specified action until it succeeds or times out.
+	// DoAction performs the specified action until it succeeds, times out, or is cancelled.
 	//
+	// ctx is the context for the operation (if ctx is cancelled, no further attempts will be made).
 	// description is a short description of the function used for logging.
 	// timeout is the period of time before the process
 	// action is the action function to invoke
 	//
-	// Returns the error (if any) passed to Context.Fail or caused by the operation timing out.
-	Action(description string, timeout time.Duration, action ActionFunc) error
+	// Returns the error (if any) passed to Context.Fail or caused by the operation timing out or being cancelled.
+	Action(ctx context.Context, description string, timeout time.Duration, action ActionFunc) error
 }
 
 // NewDo creates a new Do.
@@ -67,14 +69,15 @@ func (do *doWithRetry) SetRetryPeriod(retryPeriod time.Duration) {
 	do.retryPeriod = retryPeriod
 }
 
-// DoAction performs the specified action until it succeeds or times out.
+// DoAction performs the specified action until it succeeds, times out, or is cancelled.
 //
+// ctx is the context for the operation (if ctx is cancelled, no further attempts will be made).
 // description is a short description of the function used for logging.
 // timeout is the period of time before the action fails automatically
 // action is the action function to invoke
 //
-// Returns the error (if any) passed to Context.Fail or caused by the operation timing out.
-func (do *doWithRetry) Action(description string, timeout time.Duration, action ActionFunc) error {
+// Returns the error (if any) passed to Context.Fail or caused by the operation timing out or being cancelled.
+func (do *doWithRetry) Action(ctx context.Context, description string, timeout time.Duration, action ActionFunc) error {
 	log.Printf("Do.Action - stateLock.Lock()")
 
 	// Capture current configuration
@@ -103,6 +106,19 @@ func (do *doWithRetry) Action(description string, timeout time.Duration, action
 	context := newDoContext(description)
 	for {
 		select {
+		case <-ctx.Done():
+			log.Printf("%s - operation cancelled after %d attempts: %s",
+				description,
+				context.IterationCount,
+				ctx.Err(),
+			)
+
+			return &OperationCancelledError{
+				OperationDescription: description,
+				Attempts:             context.IterationCount,
+				Cause:                ctx.Err(),
+			}
+
 		case <-waitTimeout.C:
 			log.Printf("%s - operation timed out after %d seconds (%d attempts)",
 				description,
@@ -34,3 +34,33 @@ func (timeoutError *OperationTimeoutError) Error() string {
 }
 
 var _ error = &OperationTimeoutError{}
+
+// IsCancelledError determines whether the specified error represents a cancelled operation.
+func IsCancelledError(err error) bool {
+	_, ok := err.(*OperationCancelledError)
+
+	return ok
+}
+
+// OperationCancelledError is raised when an operation is cancelled (e.g. because Terraform was interrupted) before it could complete.
+type OperationCancelledError struct {
+	// The operation description.
+	OperationDescription string
+
+	// The number of attempts that were made to perform the operation.
+	Attempts int
+
+	// The reason the operation was cancelled.
+	Cause error
+}
+
+// Error creates a string representation of the OperationCancelledError.
+func (cancelledError *OperationCancelledError) Error() string {
+	return fmt.Sprintf("%s - operation cancelled after %d attempts (%s)",
+		cancelledError.OperationDescription,
+		cancelledError.Attempts,
+		cancelledError.Cause,
+	)
+}
+
+var _ error = &OperationCancelledError{}